@@ -5,63 +5,56 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/onosproject/helmit/internal/control"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
-	"time"
 )
 
-const readyFile = "/tmp/bin-ready"
+const holdEnv = "HELMIT_HOLD"
 
 func main() {
-	awaitReady()
-	if err := run(); err != nil {
+	server, err := control.Listen()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-}
-
-// awaitReady waits for the job to become ready
-func awaitReady() {
-	for {
-		if isReady() {
-			return
-		}
-		time.Sleep(time.Second)
-	}
-}
-
-// isReady checks if the job is ready
-func isReady() bool {
-	info, err := os.Stat(readyFile)
-	return err == nil && !info.IsDir()
-}
 
-// getBinaryFile returns the binary file name
-func getBinaryFile() (string, error) {
-	bytes, err := os.ReadFile(readyFile)
-	if err != nil {
-		return "", err
+	code := run(server)
+	if os.Getenv(holdEnv) != "" {
+		server.Exit(code)
+		_ = server.AwaitShutdown(context.Background())
 	}
-	fileName := strings.TrimSpace(string(bytes))
-	return fileName, nil
+	server.Stop()
+	os.Exit(code)
 }
 
-// run runs the main
-func run() error {
-	fileName, err := getBinaryFile()
+// run runs the main and returns the exit code
+func run(server *control.Server) int {
+	fileName, err := server.AwaitExecutable(context.Background())
 	if err != nil {
-		return err
+		fmt.Println(err)
+		return 1
 	}
 	absPath, err := filepath.Abs(fileName)
 	if err != nil {
-		return err
+		fmt.Println(err)
+		return 1
 	}
 	cmd := exec.Command(absPath)
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 	cmd.Env = os.Environ()
-	return cmd.Run()
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Println(err)
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/cli"
+	"os"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+)
+
+func main() {
+	cmd := cli.GetPluginCommand()
+	if err := cmd.Execute(); err != nil {
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -7,6 +7,7 @@ package build
 import (
 	"github.com/onosproject/helmit/internal/logging"
 	"github.com/onosproject/helmit/pkg/benchmark"
+	"go/types"
 	"reflect"
 )
 
@@ -36,5 +37,14 @@ func Benchmarks(log logging.Logger, suiteMatchers ...string) *Builder {
 	if len(suiteMatchers) == 0 {
 		suiteMatchers = []string{defaultBenchmarkSuiteMatcher}
 	}
-	return newBuilder(reflect.TypeOf(benchmark.Suite{}), suiteMatchers, benchmarkMainTpl, log)
+	return newBuilder(reflect.TypeOf(benchmark.Suite{}), suiteMatchers, isBenchmarkMethod, benchmarkMainTpl, log)
+}
+
+// isBenchmarkMethod returns whether the given method signature is runnable as a benchmark
+func isBenchmarkMethod(signature *types.Signature) bool {
+	if signature.Params().Len() != 1 || signature.Results().Len() > 1 {
+		return false
+	}
+	named, ok := signature.Params().At(0).Type().(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
 }
@@ -16,3 +16,13 @@ func TestBuildBenchmarks(t *testing.T) {
 	assert.NoError(t, Benchmarks(logging.NewLogger(os.Stdout)).
 		Build("test-benchmarks", "github.com/onosproject/helmit/test/..."))
 }
+
+func TestBenchmarkSuites(t *testing.T) {
+	suites, err := Benchmarks(logging.NewLogger(os.Stdout)).Suites("github.com/onosproject/helmit/test/...")
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "ChartBenchmarkSuite", suites[0].Name)
+	assert.Contains(t, suites[0].Methods, "BenchmarkFoo")
+	assert.Contains(t, suites[0].Methods, "BenchmarkBar")
+	assert.Contains(t, suites[0].Methods, "BenchmarkBaz")
+}
@@ -8,23 +8,27 @@ import (
 	"errors"
 	"fmt"
 	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/match"
 	"go/types"
 	"golang.org/x/tools/go/packages"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"reflect"
-	"regexp"
 	"strings"
 	"text/template"
 )
 
-func newBuilder(suiteType reflect.Type, suiteMatchers []string, template string, log logging.Logger) *Builder {
+// generatedDir is the directory within a module in which mains are generated
+const generatedDir = ".helmit"
+
+func newBuilder(suiteType reflect.Type, suiteMatchers []string, isMethod func(*types.Signature) bool, template string, log logging.Logger) *Builder {
 	return &Builder{
 		log:           log,
 		template:      template,
 		suiteType:     suiteType,
 		suiteMatchers: suiteMatchers,
+		isMethod:      isMethod,
 	}
 }
 
@@ -34,35 +38,150 @@ type Builder struct {
 	template      string
 	suiteType     reflect.Type
 	suiteMatchers []string
+	isMethod      func(*types.Signature) bool
+	local         bool
+	arch          string
+	options       Options
+}
+
+// Architectures is the set of CPU architectures for which binaries can be built for Kubernetes job pods
+var Architectures = []string{"amd64", "arm64"}
+
+// Local configures the builder to build binaries for the local platform rather than for Kubernetes job pods
+func (b *Builder) Local() *Builder {
+	b.local = true
+	return b
+}
+
+// Arch configures the CPU architecture for which to build binaries for Kubernetes job pods
+func (b *Builder) Arch(arch string) *Builder {
+	b.arch = arch
+	return b
+}
+
+// Options configures the builder with additional go build flags
+func (b *Builder) Options(options Options) *Builder {
+	b.options = options
+	return b
+}
+
+// Suite describes a suite located by the builder
+type Suite struct {
+	Package string   `json:"package"`
+	Name    string   `json:"name"`
+	Methods []string `json:"methods"`
+}
+
+// Suites parses the given pkgPaths to locate matching test/benchmark suites, returning the suites along with
+// the names of their exported methods with test/benchmark signatures.
+func (b *Builder) Suites(pkgPaths ...string) ([]Suite, error) {
+	info, err := b.getBuildInfo(pkgPaths...)
+	if err != nil {
+		return nil, err
+	}
+	suites := make([]Suite, 0, len(info.Suites))
+	for _, suite := range info.Suites {
+		suites = append(suites, Suite{
+			Package: suite.Import.Path,
+			Name:    suite.Name,
+			Methods: suite.Methods,
+		})
+	}
+	return suites, nil
 }
 
 // Build parses the given pkgPaths to locate test/benchmark suites, generates a main to run the
 // matching suites, and builds a binary from the main, outputting the resulting executable to binPath.
 func (b *Builder) Build(binPath string, pkgPaths ...string) error {
-	info, err := b.getBuildInfo(pkgPaths...)
+	source, err := b.Generate(pkgPaths...)
 	if err != nil {
 		return err
 	}
+	defer source.Remove()
+	return b.buildBinary(source.Dir, filepath.Join(source.Dir, source.Main), binPath)
+}
+
+// Source is a main package within a Go module from which a binary can be built
+type Source struct {
+	// Dir is the root directory of the module
+	Dir string
+	// Main is the path of the main package relative to Dir
+	Main string
+	// generated indicates whether the main package was generated by the builder
+	generated bool
+}
+
+// Remove removes the main package if it was generated by the builder
+func (s Source) Remove() error {
+	if !s.generated {
+		return nil
+	}
+	return os.RemoveAll(filepath.Join(s.Dir, s.Main))
+}
+
+// Generate parses the given pkgPaths to locate test/benchmark suites and generates a main to run the
+// matching suites within the suites' module, returning the source from which to build a binary.
+// The main is generated alongside the suites' packages, so it's built with the module's go.mod, including its
+// replace directives. If the only package is a command package, e.g. one with a hand-written main, the package
+// is returned as is. The generated main should be removed with Remove once the binary has been built.
+func (b *Builder) Generate(pkgPaths ...string) (Source, error) {
+	info, err := b.getBuildInfo(pkgPaths...)
+	if err != nil {
+		return Source{}, err
+	}
+
+	// Command packages already have a main, which is built as is
+	if info.Main != "" {
+		main, err := filepath.Rel(info.Module.Dir, info.Main)
+		if err != nil {
+			return Source{}, err
+		}
+		return Source{
+			Dir:  info.Module.Dir,
+			Main: main,
+		}, nil
+	}
 
 	if len(info.Suites) == 0 {
-		return fmt.Errorf("no matching suites found in packages %s", strings.Join(pkgPaths, ","))
+		return Source{}, fmt.Errorf("no matching suites found in packages %s", strings.Join(pkgPaths, ","))
 	}
 
-	mainDir := filepath.Join(info.Module.Dir, ".helmit")
+	mainDir := filepath.Join(info.Module.Dir, generatedDir)
 	if err := os.MkdirAll(mainDir, os.ModePerm); err != nil {
-		return err
+		return Source{}, err
+	}
+	source := Source{
+		Dir:       info.Module.Dir,
+		Main:      generatedDir,
+		generated: true,
 	}
-	mainFile := filepath.Join(mainDir, "main.go")
-	defer os.RemoveAll(mainDir)
 
+	mainFile := filepath.Join(mainDir, "main.go")
 	if err := b.applyTemplate(mainFile, info); err != nil {
-		return err
+		_ = source.Remove()
+		return Source{}, err
 	}
+	return source, nil
+}
 
-	if err := b.buildBinary(mainDir, binPath); err != nil {
-		return err
+// GetSource returns the source of the given main package
+func GetSource(pkgPath string) (Source, error) {
+	output, err := exec.Command("go", "list", "-f", "{{.Module.Dir}}\n{{.Dir}}", pkgPath).Output()
+	if err != nil {
+		return Source{}, fmt.Errorf("failed to locate module for package %s: %w", pkgPath, err)
 	}
-	return nil
+	dirs := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(dirs) != 2 {
+		return Source{}, fmt.Errorf("failed to locate module for package %s", pkgPath)
+	}
+	main, err := filepath.Rel(dirs[0], dirs[1])
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{
+		Dir:  dirs[0],
+		Main: main,
+	}, nil
 }
 
 // getBuildInfo parses the given Go package paths to locate matching suites within those packages, returning
@@ -72,7 +191,7 @@ func (b *Builder) getBuildInfo(pkgPaths ...string) (buildInfo, error) {
 
 	var build buildInfo
 	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule,
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule,
 	}
 	pkgs, err := packages.Load(cfg, pkgPaths...)
 	if err != nil {
@@ -82,9 +201,21 @@ func (b *Builder) getBuildInfo(pkgPaths ...string) (buildInfo, error) {
 		return build, errors.New("failed to parse packages")
 	}
 
+	// A single command package is built as is, while command packages matched along with other packages are
+	// skipped, since they cannot be imported by a generated main
+	if len(pkgs) == 1 && pkgs[0].Name == "main" && len(pkgs[0].GoFiles) > 0 {
+		build.Module.Path = pkgs[0].Module.Path
+		build.Module.Dir = pkgs[0].Module.Dir
+		build.Main = filepath.Dir(pkgs[0].GoFiles[0])
+		return build, nil
+	}
+
 	imports := make(map[string]importInfo)
 	aliases := make(map[string]bool)
 	for _, pkg := range pkgs {
+		if pkg.Name == "main" {
+			continue
+		}
 		if build.Module.Path != "" && build.Module.Path != pkg.Module.Path {
 			return build, errors.New("all suites must be under the same Go module")
 		}
@@ -121,8 +252,9 @@ func (b *Builder) getBuildInfo(pkgPaths ...string) (buildInfo, error) {
 			}
 
 			build.Suites = append(build.Suites, suiteInfo{
-				Name:   obj.Name(),
-				Import: imp,
+				Name:    obj.Name(),
+				Import:  imp,
+				Methods: b.getMethods(obj),
 			})
 		}
 	}
@@ -133,14 +265,14 @@ func (b *Builder) isMatch(obj types.Object) (bool, error) {
 	if !obj.Exported() {
 		return false, nil
 	}
-	for _, suiteMatcher := range b.suiteMatchers {
-		if ok, err := regexp.MatchString(suiteMatcher, obj.Name()); err != nil {
-			return false, err
-		} else if ok {
-			return b.isSuite(obj), nil
-		}
+	matcher, err := match.New(b.suiteMatchers...)
+	if err != nil {
+		return false, err
+	}
+	if !matcher.Match(obj.Name()) {
+		return false, nil
 	}
-	return false, nil
+	return b.isSuite(obj), nil
 }
 
 func (b *Builder) isSuite(obj types.Object) bool {
@@ -167,6 +299,24 @@ func (b *Builder) isSuite(obj types.Object) bool {
 	return false
 }
 
+// getMethods returns the names of the exported methods of the given suite with test/benchmark signatures
+func (b *Builder) getMethods(obj types.Object) []string {
+	var methods []string
+	methodSet := types.NewMethodSet(types.NewPointer(obj.Type()))
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		if !method.Exported() {
+			continue
+		}
+		signature, ok := method.Type().(*types.Signature)
+		if !ok || !b.isMethod(signature) {
+			continue
+		}
+		methods = append(methods, method.Name())
+	}
+	return methods
+}
+
 func (b *Builder) applyTemplate(path string, info buildInfo) error {
 	b.log.Logf("Generating %s", path)
 	tpl, err := template.New("main").Parse(b.template)
@@ -181,19 +331,91 @@ func (b *Builder) applyTemplate(path string, info buildInfo) error {
 	return tpl.Execute(file, info)
 }
 
-func (b *Builder) buildBinary(mainDir, binPath string) error {
-	b.log.Logf("Building binary %s", binPath)
-	build := exec.Command("go", "build", "-mod=readonly", "-trimpath", "-o", binPath, mainDir)
+func (b *Builder) buildBinary(moduleDir, mainDir, binPath string) error {
+	if b.local {
+		return buildBinary(b.log, moduleDir, mainDir, binPath, "", true, b.options)
+	}
+	return buildBinary(b.log, moduleDir, mainDir, binPath, b.arch, false, b.options)
+}
+
+// Binary builds the main package at the given path for the given CPU architecture, outputting the resulting
+// executable to binPath.
+func Binary(log logging.Logger, binPath string, pkgPath string, arch string, options Options) error {
+	source, err := GetSource(pkgPath)
+	if err != nil {
+		return err
+	}
+	return buildBinary(log, source.Dir, pkgPath, binPath, arch, false, options)
+}
+
+func buildBinary(log logging.Logger, moduleDir, mainDir, binPath string, arch string, local bool, options Options) error {
+	if arch != "" && !isSupportedArch(arch) {
+		return fmt.Errorf("unsupported architecture %s: must be one of %s", arch, strings.Join(Architectures, ", "))
+	}
+
+	flags := append([]string{"-mod=readonly", "-trimpath"}, options.Flags()...)
+
+	env := os.Environ()
+	if !local {
+		env = append(env, "GOOS=linux")
+		// The race detector requires cgo, so leave cgo enabled for race builds
+		if !options.Race {
+			env = append(env, "CGO_ENABLED=0")
+		}
+		if arch != "" {
+			env = append(env, "GOARCH="+arch)
+		}
+	}
+
+	var cachePath string
+	if !options.NoCache {
+		// The binary is built without the cache if its key can't be computed, leaving go build to report any errors
+		key, err := getCacheKey(moduleDir, mainDir, flags, env)
+		if err != nil {
+			log.Logf("Failed to compute the binary cache key: %s", err)
+		} else {
+			cachePath, err = getCachePath(key)
+			if err != nil {
+				return err
+			}
+			if _, err := os.Stat(cachePath); err == nil {
+				log.Logf("Using cached binary %s", cachePath)
+				return copyFile(cachePath, binPath)
+			}
+		}
+	}
+
+	log.Logf("Building binary %s", binPath)
+	args := append([]string{"build"}, flags...)
+	args = append(args, "-o", binPath, mainDir)
+	build := exec.Command("go", args...)
 	build.Stderr = os.Stderr
 	build.Stdout = os.Stdout
-	env := os.Environ()
-	env = append(env, "GOOS=linux", "CGO_ENABLED=0")
 	build.Env = env
-	return build.Run()
+	if err := build.Run(); err != nil {
+		return err
+	}
+
+	if cachePath != "" {
+		if err := copyFile(binPath, cachePath); err != nil {
+			log.Logf("Failed to cache binary: %s", err)
+		}
+	}
+	return nil
+}
+
+func isSupportedArch(arch string) bool {
+	for _, supported := range Architectures {
+		if arch == supported {
+			return true
+		}
+	}
+	return false
 }
 
 type buildInfo struct {
 	Module  moduleInfo
+	Main    string
 	Imports []importInfo
 	Suites  []suiteInfo
 }
@@ -209,6 +431,7 @@ type importInfo struct {
 }
 
 type suiteInfo struct {
-	Name   string
-	Import importInfo
+	Name    string
+	Import  importInfo
+	Methods []string
 }
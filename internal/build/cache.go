@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Options are additional options for building binaries
+type Options struct {
+	// Tags is a list of build tags to pass to go build
+	Tags []string
+	// LDFlags are linker flags to pass to go build
+	LDFlags string
+	// Race enables the race detector
+	Race bool
+	// NoCache disables the binary cache
+	NoCache bool
+}
+
+// Flags returns the go build flags for the options
+func (o Options) Flags() []string {
+	var args []string
+	if len(o.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(o.Tags, ","))
+	}
+	if o.LDFlags != "" {
+		args = append(args, "-ldflags", o.LDFlags)
+	}
+	if o.Race {
+		args = append(args, "-race")
+	}
+	return args
+}
+
+// cacheEnv is the set of environment variables that affect the output of go build
+var cacheEnv = []string{"GOOS", "GOARCH", "GOAMD64", "GOARM64", "CGO_ENABLED", "GOFLAGS", "GOEXPERIMENT"}
+
+// listedPackage is a package listed by go list -json
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Standard   bool
+	Module     *listedModule
+	GoFiles    []string
+	CgoFiles   []string
+	CFiles     []string
+	CXXFiles   []string
+	HFiles     []string
+	SFiles     []string
+	SysoFiles  []string
+	EmbedFiles []string
+}
+
+// files returns the paths of the files in the package that are used by the build
+func (p listedPackage) files() []string {
+	var files []string
+	for _, names := range [][]string{p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.HFiles, p.SFiles, p.SysoFiles, p.EmbedFiles} {
+		for _, name := range names {
+			files = append(files, filepath.Join(p.Dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// listedModule is a module listed by go list -json
+type listedModule struct {
+	Path    string
+	Version string
+	GoMod   string
+	Replace *listedModule
+}
+
+// getCacheKey returns a key identifying the binary built from the main package at mainDir in the given module with
+// the given go build flags and environment, derived from the Go version, the build configuration, and the packages
+// the build depends on
+// Dependencies resolved from the module cache are identified by their module versions. The files used by the build
+// from packages in local directories, i.e. the main module, go.work modules, and replace directories, are hashed
+// along with their go.mod files.
+func getCacheKey(moduleDir string, mainDir string, flags []string, env []string) (string, error) {
+	goEnv := exec.Command("go", "env", "GOVERSION", "GOWORK")
+	goEnv.Dir = moduleDir
+	goEnv.Env = env
+	output, err := goEnv.Output()
+	if err != nil {
+		return "", err
+	}
+	version, workFile, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "version %s\n", version)
+	fmt.Fprintf(hash, "flags %q\n", flags)
+
+	values := make(map[string]string)
+	for _, entry := range env {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			values[name] = value
+		}
+	}
+	for _, name := range cacheEnv {
+		fmt.Fprintf(hash, "env %s=%s\n", name, values[name])
+	}
+
+	// VCS information is not needed to list the dependencies, and the stamped binary is not keyed by the commit
+	list := exec.Command("go", append(append([]string{"list", "-deps", "-json", "-buildvcs=false"}, flags...), mainDir)...)
+	list.Dir = moduleDir
+	list.Env = env
+	var stderr bytes.Buffer
+	list.Stderr = &stderr
+	output, err = list.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list the dependencies of %s: %s", mainDir, strings.TrimSpace(stderr.String()))
+	}
+
+	if workFile != "" && workFile != "off" {
+		if err := hashFile(hash, "go.work", workFile); err != nil {
+			return "", err
+		}
+	}
+	if err := hashFile(hash, "go.sum", filepath.Join(moduleDir, "go.sum")); err != nil {
+		return "", err
+	}
+
+	modules := make(map[string]bool)
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for decoder.More() {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err != nil {
+			return "", err
+		}
+		if pkg.Standard {
+			continue
+		}
+
+		module := pkg.Module
+		if module != nil && module.Replace != nil {
+			module = module.Replace
+		}
+		if module != nil && module.Version != "" {
+			fmt.Fprintf(hash, "package %s %s@%s\n", pkg.ImportPath, module.Path, module.Version)
+			continue
+		}
+
+		if module != nil && module.GoMod != "" && !modules[module.GoMod] {
+			modules[module.GoMod] = true
+			if err := hashFile(hash, module.Path+"/go.mod", module.GoMod); err != nil {
+				return "", err
+			}
+		}
+		for _, path := range pkg.files() {
+			rel, err := filepath.Rel(pkg.Dir, path)
+			if err != nil {
+				return "", err
+			}
+			if err := hashFile(hash, pkg.ImportPath+"/"+filepath.ToSlash(rel), path); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFile writes the given name and the contents of the file at the given path to the hash
+// Files that do not exist are skipped.
+func hashFile(hash io.Writer, name string, path string) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+	fmt.Fprintf(hash, "file %s\n", name)
+	_, err = io.Copy(hash, file)
+	return err
+}
+
+// getCachePath returns the path at which the binary with the given key is cached
+func getCachePath(key string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "helmit", "bin", key), nil
+}
+
+// copyFile copies the executable at src to dst, writing to a temporary file first so partially written
+// files are never observed at dst
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	if err := os.Chmod(out.Name(), 0755); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	return os.Rename(out.Name(), dst)
+}
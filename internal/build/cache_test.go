@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOptions(t *testing.T) {
+	assert.Empty(t, Options{NoCache: true}.Flags())
+	assert.Equal(t, []string{"-tags", "foo,bar", "-ldflags", "-s -w", "-race"},
+		Options{Tags: []string{"foo", "bar"}, LDFlags: "-s -w", Race: true}.Flags())
+}
+
+func TestCacheKey(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "foo")
+	depDir := filepath.Join(root, "dep")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "charts"), os.ModePerm))
+	assert.NoError(t, os.MkdirAll(depDir, os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"),
+		[]byte("module example.com/foo\n\ngo 1.19\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ../dep\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"),
+		[]byte("package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(depDir, "go.mod"), []byte("module example.com/dep\n\ngo 1.19\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(depDir, "dep.go"), []byte("package dep\n"), 0644))
+
+	flags := []string{"-mod=readonly", "-trimpath"}
+	newEnv := func(env ...string) []string {
+		return append(append(os.Environ(), "GOWORK=off", "GOFLAGS="), env...)
+	}
+	env := newEnv("GOOS=linux", "GOARCH=amd64", "HOME=/foo")
+	key, err := getCacheKey(dir, ".", flags, env)
+	assert.NoError(t, err)
+
+	same, err := getCacheKey(dir, ".", flags, newEnv("GOOS=linux", "GOARCH=amd64", "HOME=/bar"))
+	assert.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	// Files that are not used by the build do not change the key
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("foo"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "charts", "values.yaml"), []byte("foo: bar\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "unused.go"), []byte("//go:build ignore\n\npackage main\n"), 0644))
+	same, err = getCacheKey(dir, ".", flags, env)
+	assert.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	other, err := getCacheKey(dir, ".", append(flags, "-tags", "foo"), env)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	other, err = getCacheKey(dir, ".", flags, newEnv("GOOS=linux", "GOARCH=arm64"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	// Changes to replaced modules outside the main module change the key
+	assert.NoError(t, os.WriteFile(filepath.Join(depDir, "dep.go"), []byte("package dep\n\nconst Foo = 1\n"), 0644))
+	other, err = getCacheKey(dir, ".", flags, env)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	key = other
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"),
+		[]byte("package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {\n}\n"), 0644))
+	other, err = getCacheKey(dir, ".", flags, env)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
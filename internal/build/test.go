@@ -7,6 +7,7 @@ package build
 import (
 	"github.com/onosproject/helmit/internal/logging"
 	"github.com/onosproject/helmit/pkg/test"
+	"go/types"
 	"reflect"
 )
 
@@ -36,5 +37,10 @@ func Tests(log logging.Logger, suiteMatchers ...string) *Builder {
 	if len(suiteMatchers) == 0 {
 		suiteMatchers = []string{defaultTestSuiteMatcher}
 	}
-	return newBuilder(reflect.TypeOf(test.Suite{}), suiteMatchers, testMainTpl, log)
+	return newBuilder(reflect.TypeOf(test.Suite{}), suiteMatchers, isTestMethod, testMainTpl, log)
+}
+
+// isTestMethod returns whether the given method signature is runnable as a test
+func isTestMethod(signature *types.Signature) bool {
+	return signature.Params().Len() == 0 && signature.Results().Len() == 0
 }
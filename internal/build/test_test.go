@@ -8,6 +8,7 @@ import (
 	"github.com/onosproject/helmit/internal/logging"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -16,3 +17,24 @@ func TestBuildTests(t *testing.T) {
 	assert.NoError(t, Tests(logging.NewLogger(os.Stdout)).
 		Build("test-tests", "github.com/onosproject/helmit/test/..."))
 }
+
+func TestTestSuites(t *testing.T) {
+	suites, err := Tests(logging.NewLogger(os.Stdout)).Suites("github.com/onosproject/helmit/test/...")
+	assert.NoError(t, err)
+	assert.Len(t, suites, 1)
+	assert.Equal(t, "github.com/onosproject/helmit/test", suites[0].Package)
+	assert.Equal(t, "ChartTestSuite", suites[0].Name)
+	assert.Contains(t, suites[0].Methods, "TestLocalInstall")
+	assert.Contains(t, suites[0].Methods, "TestFailure")
+	assert.Contains(t, suites[0].Methods, "TestRemoteInstall")
+	assert.NotContains(t, suites[0].Methods, "Helm")
+}
+
+func TestGenerateCommand(t *testing.T) {
+	source, err := Tests(logging.NewLogger(os.Stdout)).Generate("github.com/onosproject/helmit/cmd/helmit-runner")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("cmd", "helmit-runner"), source.Main)
+	assert.NoError(t, source.Remove())
+	_, err = os.Stat(filepath.Join(source.Dir, source.Main, "main.go"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package chart
+
+import (
+	"fmt"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repository is a chart repository from which dependencies are downloaded with the given credentials
+// Repositories with an oci:// URL are registries, to which the credentials are used to log in.
+type Repository struct {
+	Name     string
+	URL      string
+	Username string
+	Password string
+}
+
+// FindUnresolved returns the paths, relative to the given directory, of the charts in the directory that declare
+// dependencies which are missing from their charts/ directory
+// Charts are not searched for in the directories of other charts, and charts that fail to load are skipped and
+// left for Helm to report once they're installed.
+func FindUnresolved(dir string) ([]string, error) {
+	var charts []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if _, err := os.Stat(filepath.Join(path, chartutil.ChartfileName)); os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !isResolved(path) {
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			charts = append(charts, relPath)
+		}
+		return filepath.SkipDir
+	})
+	return charts, err
+}
+
+// isResolved returns whether all the dependencies of the chart in the given directory are present
+func isResolved(path string) bool {
+	c, err := loader.LoadDir(path)
+	if err != nil {
+		return true
+	}
+	if len(c.Metadata.Dependencies) == 0 {
+		return true
+	}
+	return action.CheckDependencies(c, c.Metadata.Dependencies) == nil
+}
+
+// Vendor copies the given directory to a new temporary directory and builds the dependencies of the given charts
+// in the copy, returning the path of the copy
+// The directory itself is left untouched, and the caller is responsible for removing the copy. Dependencies are
+// resolved like 'helm dependency build', from the chart's Chart.lock if it has one, with the repositories and
+// registry credentials configured for the local Helm client in addition to the given repositories.
+func Vendor(dir string, charts []string, repos []Repository, out io.Writer) (string, error) {
+	configDir, err := os.MkdirTemp("", "helmit-repositories-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(configDir)
+
+	settings := cli.New()
+	repoConfig, err := writeRepositoryConfig(settings.RepositoryConfig, configDir, repos)
+	if err != nil {
+		return "", err
+	}
+	registryClient, err := newRegistryClient(settings.RegistryConfig, configDir, repos, out)
+	if err != nil {
+		return "", err
+	}
+
+	contextDir, err := os.MkdirTemp("", "helmit-context-")
+	if err != nil {
+		return "", err
+	}
+	if err := copyTree(dir, contextDir); err != nil {
+		os.RemoveAll(contextDir)
+		return "", err
+	}
+
+	for _, chart := range charts {
+		fmt.Fprintf(out, "Building dependencies of chart %s\n", chart)
+		manager := &downloader.Manager{
+			Out:              out,
+			ChartPath:        filepath.Join(contextDir, chart),
+			Getters:          getter.All(settings),
+			RegistryClient:   registryClient,
+			RepositoryConfig: repoConfig,
+			RepositoryCache:  settings.RepositoryCache,
+		}
+		if err := manager.Build(); err != nil {
+			os.RemoveAll(contextDir)
+			return "", fmt.Errorf("failed to build the dependencies of chart %s: %w", chart, err)
+		}
+	}
+	return contextDir, nil
+}
+
+// writeRepositoryConfig writes the local Helm repositories merged with the given repositories to a repositories
+// file in the given directory, returning the path of the file
+func writeRepositoryConfig(path string, dir string, repos []Repository) (string, error) {
+	file := repo.NewFile()
+	if _, err := os.Stat(path); err == nil {
+		if file, err = repo.LoadFile(path); err != nil {
+			return "", err
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, r := range repos {
+		if registry.IsOCI(r.URL) {
+			continue
+		}
+		file.Update(&repo.Entry{
+			Name:     r.Name,
+			URL:      strings.TrimSuffix(r.URL, "/"),
+			Username: r.Username,
+			Password: r.Password,
+		})
+	}
+	repoConfig := filepath.Join(dir, "repositories.yaml")
+	if err := file.WriteFile(repoConfig, 0600); err != nil {
+		return "", err
+	}
+	return repoConfig, nil
+}
+
+// newRegistryClient returns a registry client logged in to the given OCI repositories
+// Logging in writes the credentials to the client's credentials file, so when logging in, the local Helm
+// credentials are copied to the given directory rather than modified.
+func newRegistryClient(path string, dir string, repos []Repository, out io.Writer) (*registry.Client, error) {
+	var registries []Repository
+	for _, r := range repos {
+		if registry.IsOCI(r.URL) && r.Username != "" {
+			registries = append(registries, r)
+		}
+	}
+	credentials := path
+	if len(registries) > 0 {
+		credentials = filepath.Join(dir, "registry.json")
+		if _, err := os.Stat(path); err == nil {
+			if err := copyFile(path, credentials, 0600); err != nil {
+				return nil, err
+			}
+		}
+	}
+	client, err := registry.NewClient(registry.ClientOptCredentialsFile(credentials), registry.ClientOptWriter(out))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range registries {
+		if err := client.Login(getRegistryHost(r.URL), registry.LoginOptBasicAuth(r.Username, r.Password)); err != nil {
+			return nil, fmt.Errorf("failed to log in to registry %s: %w", r.URL, err)
+		}
+	}
+	return client, nil
+}
+
+// getRegistryHost returns the host of the given oci:// repository URL
+func getRegistryHost(url string) string {
+	host := strings.TrimPrefix(url, fmt.Sprintf("%s://", registry.OCIScheme))
+	if i := strings.Index(host, "/"); i != -1 {
+		host = host[:i]
+	}
+	return host
+}
+
+// copyTree copies the contents of the src directory to the dst directory, preserving symlinks
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode())
+		}
+		return nil
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package chart
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeChart(t *testing.T, dir string, chartfile string) {
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(chartfile), 0644))
+}
+
+func TestFindUnresolved(t *testing.T) {
+	dir := t.TempDir()
+	writeChart(t, filepath.Join(dir, "common"), "apiVersion: v2\nname: common\nversion: 0.1.0\n")
+	writeChart(t, filepath.Join(dir, "app"), `apiVersion: v2
+name: app
+version: 0.1.0
+dependencies:
+- name: common
+  version: 0.1.0
+  repository: file://../common
+`)
+	writeChart(t, filepath.Join(dir, "vendored"), `apiVersion: v2
+name: vendored
+version: 0.1.0
+dependencies:
+- name: common
+  version: 0.1.0
+  repository: file://../common
+`)
+	writeChart(t, filepath.Join(dir, "vendored", "charts", "common"), "apiVersion: v2\nname: common\nversion: 0.1.0\n")
+	writeChart(t, filepath.Join(dir, ".hidden"), `apiVersion: v2
+name: hidden
+version: 0.1.0
+dependencies:
+- name: common
+  version: 0.1.0
+  repository: file://../common
+`)
+
+	charts, err := FindUnresolved(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, charts)
+
+	charts, err = FindUnresolved(filepath.Join(dir, "common"))
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+}
+
+func TestVendor(t *testing.T) {
+	helmDir := t.TempDir()
+	t.Setenv("HELM_REPOSITORY_CONFIG", filepath.Join(helmDir, "repositories.yaml"))
+	t.Setenv("HELM_REPOSITORY_CACHE", filepath.Join(helmDir, "cache"))
+	t.Setenv("HELM_REGISTRY_CONFIG", filepath.Join(helmDir, "registry.json"))
+
+	dir := t.TempDir()
+	writeChart(t, filepath.Join(dir, "common"), "apiVersion: v2\nname: common\nversion: 0.1.0\n")
+	writeChart(t, filepath.Join(dir, "app"), `apiVersion: v2
+name: app
+version: 0.1.0
+dependencies:
+- name: common
+  version: 0.1.0
+  repository: file://../common
+`)
+
+	vendored, err := Vendor(dir, []string{"app"}, nil, io.Discard)
+	require.NoError(t, err)
+	defer os.RemoveAll(vendored)
+
+	_, err = os.Stat(filepath.Join(vendored, "app", "charts", "common-0.1.0.tgz"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "app", "charts"))
+	assert.True(t, os.IsNotExist(err))
+
+	charts, err := FindUnresolved(vendored)
+	assert.NoError(t, err)
+	assert.Empty(t, charts)
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	assert.Equal(t, "registry.example.com", getRegistryHost("oci://registry.example.com/charts"))
+	assert.Equal(t, "localhost:5000", getRegistryHost("oci://localhost:5000"))
+}
@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// significanceLevel is the p-value below which a difference between the sides of an A/B benchmark is reported
+// as significant
+const significanceLevel = 0.05
+
+// abVariant is one side of an A/B benchmark, set up in its own namespace with its own chart values
+type abVariant struct {
+	name string
+	job  job.Job[benchmark.Config]
+}
+
+// newABVariants returns the A and B sides of a benchmark, each with the given values files and overrides
+// applied on top of those shared by both sides
+func newABVariants(base job.Job[benchmark.Config], files [2][]string, sets [2][]string) ([]abVariant, error) {
+	var variants []abVariant
+	for i, name := range []string{"a", "b"} {
+		valueFiles, err := parseFiles(files[i])
+		if err != nil {
+			return nil, err
+		}
+		values, err := parseOverrides(sets[i])
+		if err != nil {
+			return nil, err
+		}
+
+		variant := base
+		variant.ID = fmt.Sprintf("%s-%s", base.ID, name)
+		variant.Namespace = fmt.Sprintf("%s-%s", base.Namespace, name)
+		variant.CreateNamespace = true
+		variant.DeleteNamespace = !base.Config.NoTeardown
+		variant.ValueFiles = mergeReleaseValues(base.ValueFiles, valueFiles)
+		variant.Config.Namespace = variant.Namespace
+		variant.Config.Values = mergeReleaseValues(base.Config.Values, values)
+		variant.Config.ValueFiles = getConfigValueFiles(variant.ValueFiles)
+		variants = append(variants, abVariant{
+			name: name,
+			job:  variant,
+		})
+	}
+	return variants, nil
+}
+
+// newABBenchmark returns the comparison of the sides of an A/B benchmark run by the given number of workers, each
+// side with the given values files and overrides, and the jobs setting up each side
+func newABBenchmark(base job.Job[benchmark.Config], files [2][]string, sets [2][]string, workers int) (*abComparison, []job.Job[benchmark.Config], error) {
+	variants, err := newABVariants(base, files, sets)
+	if err != nil {
+		return nil, nil, err
+	}
+	comparison, err := newABComparison(variants, workers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return comparison, getSetupJobs(base, variants), nil
+}
+
+// mergeReleaseValues returns a copy of the given per-release values with the overrides appended
+func mergeReleaseValues(values, overrides map[string][]string) map[string][]string {
+	merged := make(map[string][]string)
+	for release, releaseValues := range values {
+		merged[release] = append([]string{}, releaseValues...)
+	}
+	for release, releaseValues := range overrides {
+		merged[release] = append(merged[release], releaseValues...)
+	}
+	return merged
+}
+
+// getConfigValueFiles returns the paths to which the given values files are copied in the job's pods
+func getConfigValueFiles(valueFiles map[string][]string) map[string][]string {
+	if len(valueFiles) == 0 {
+		return nil
+	}
+	configFiles := make(map[string][]string)
+	for release, releaseFiles := range valueFiles {
+		var absFiles []string
+		for _, releaseFile := range releaseFiles {
+			absFiles = append(absFiles, filepath.Join(job.HomeDir, filepath.Base(releaseFile)))
+		}
+		configFiles[release] = absFiles
+	}
+	return configFiles
+}
+
+// newABComparison returns a comparison of the variants of an A/B benchmark run by the given number of workers
+// The workers are split evenly between the variants in order, so with four workers, workers 0 and 1 run against
+// the A side and workers 2 and 3 against the B side.
+func newABComparison(variants []abVariant, workers int) (*abComparison, error) {
+	if workers < len(variants) || workers%len(variants) != 0 {
+		return nil, fmt.Errorf("A/B benchmarks require an even number of workers, got %d", workers)
+	}
+	return &abComparison{
+		variants: variants,
+		workers:  workers,
+		samples:  make([]abSamples, len(variants)),
+	}, nil
+}
+
+// abComparison collects the interval reports of the workers of an A/B benchmark to compare the variants
+type abComparison struct {
+	variants []abVariant
+	workers  int
+	samples  []abSamples
+}
+
+// abSamples are the interval reports received from the workers of a single variant
+type abSamples struct {
+	throughput  []float64
+	meanLatency []float64
+	p99Latency  []float64
+	iterations  int
+	errors      int
+}
+
+// getVariant returns the index of the variant the given worker runs against
+func (c *abComparison) getVariant(worker int) int {
+	return worker * len(c.variants) / c.workers
+}
+
+// getJob returns the job for the given worker
+func (c *abComparison) getJob(worker int) job.Job[benchmark.Config] {
+	return c.variants[c.getVariant(worker)].job
+}
+
+// record adds the given interval report to the samples of the worker's variant
+func (c *abComparison) record(report workerReport) {
+	if report.Duration == 0 {
+		return
+	}
+	samples := &c.samples[c.getVariant(report.worker)]
+	samples.iterations += report.Iterations
+	samples.errors += report.Errors
+	samples.throughput = append(samples.throughput, getThroughput(report.Report))
+	if report.Iterations > 0 {
+		samples.meanLatency = append(samples.meanLatency, float64(report.MeanLatency))
+		samples.p99Latency = append(samples.p99Latency, float64(report.P99Latency))
+	}
+}
+
+// write writes a table comparing the throughput and latency of the two variants
+// Differences are tested with Welch's t-test over the interval reports of each variant's workers.
+func (c *abComparison) write(out io.Writer) {
+	workersPerVariant := c.workers / len(c.variants)
+	for i, variant := range c.variants {
+		fmt.Fprintf(out, "%s: workers %d-%d in namespace %s\n", strings.ToUpper(variant.name),
+			i*workersPerVariant, (i+1)*workersPerVariant-1, variant.job.Namespace)
+	}
+	fmt.Fprintln(out)
+
+	a, b := c.samples[0], c.samples[1]
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', tabwriter.FilterHTML)
+	fmt.Fprintln(writer, "METRIC\tA\tB\tCHANGE\tP-VALUE\tRESULT")
+	writeRow := func(metric string, a, b []float64, scale float64, format func(float64) string) {
+		meanA, meanB := mean(a)*scale, mean(b)*scale
+		if len(a) == 0 || len(b) == 0 {
+			fmt.Fprintf(writer, "%s\t-\t-\t-\t-\tno samples\n", metric)
+			return
+		}
+		p, err := welchTTest(a, b)
+		if err != nil {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t-\t%s\n", metric, format(meanA), format(meanB), formatChange(meanA, meanB), err)
+			return
+		}
+		result := "not significant"
+		if p < significanceLevel {
+			result = "significant"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%.4f\t%s\n", metric, format(meanA), format(meanB), formatChange(meanA, meanB), p, result)
+	}
+	formatThroughput := func(value float64) string {
+		return fmt.Sprintf("%f/sec", value)
+	}
+	formatLatency := func(value float64) string {
+		return time.Duration(value).String()
+	}
+	// Throughput samples are per worker, so the mean is scaled to the throughput of all the variant's workers
+	writeRow("THROUGHPUT", a.throughput, b.throughput, float64(workersPerVariant), formatThroughput)
+	writeRow("MEAN LATENCY", a.meanLatency, b.meanLatency, 1, formatLatency)
+	writeRow("99% LATENCY", a.p99Latency, b.p99Latency, 1, formatLatency)
+	fmt.Fprintf(writer, "ERRORS\t%s\t%s\t-\t-\t-\n", formatErrors(a.iterations, a.errors), formatErrors(b.iterations, b.errors))
+	writer.Flush()
+}
+
+// abComparisonUI records the reports displayed by a benchmark UI for an A/B comparison
+type abComparisonUI struct {
+	benchmarkUI
+	comparison *abComparison
+}
+
+func (ui *abComparisonUI) Update(reports []*workerReport, report workerReport) {
+	ui.comparison.record(report)
+	ui.benchmarkUI.Update(reports, report)
+}
+
+// formatChange formats the relative change from a to b as a percentage
+func formatChange(a, b float64) string {
+	if a == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f%%", (b-a)/a*100)
+}
+
+// mean returns the mean of the given samples
+func mean(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, sample := range samples {
+		sum += sample
+	}
+	return sum / float64(len(samples))
+}
+
+// variance returns the unbiased sample variance of the given samples
+func variance(samples []float64) float64 {
+	m := mean(samples)
+	var sum float64
+	for _, sample := range samples {
+		sum += (sample - m) * (sample - m)
+	}
+	return sum / float64(len(samples)-1)
+}
+
+// welchTTest returns the two-tailed p-value of Welch's t-test for the difference between the means of two
+// samples with possibly unequal variances
+func welchTTest(a, b []float64) (float64, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, errors.New("too few samples")
+	}
+	va, vb := variance(a)/float64(len(a)), variance(b)/float64(len(b))
+	if va+vb == 0 {
+		return 0, errors.New("no variance")
+	}
+	t := (mean(a) - mean(b)) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/float64(len(a)-1) + vb*vb/float64(len(b)-1))
+	return regularizedIncompleteBeta(df/2, 0.5, df/(df+t*t)), nil
+}
+
+// regularizedIncompleteBeta returns the regularized incomplete beta function I_x(a, b)
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lbetaA, _ := math.Lgamma(a)
+	lbetaB, _ := math.Lgamma(b)
+	lbetaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lbetaAB - lbetaA - lbetaB + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly for x < (a+1)/(a+b+2); otherwise use the symmetry relation
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction for the incomplete beta function using the modified
+// Lentz method
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		m := float64(m)
+		numerator := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		numerator = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWelchTTest(t *testing.T) {
+	p, err := welchTTest([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.0010528, p, 1e-6)
+
+	p, err = welchTTest([]float64{1, 2, 3}, []float64{3, 2, 1})
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, p, 1e-9)
+
+	_, err = welchTTest([]float64{1}, []float64{1, 2})
+	assert.Error(t, err)
+	_, err = welchTTest([]float64{1, 1}, []float64{1, 1})
+	assert.Error(t, err)
+
+	assert.InDelta(t, 0.3, regularizedIncompleteBeta(1, 1, 0.3), 1e-12)
+	assert.InDelta(t, 0.5, regularizedIncompleteBeta(4, 4, 0.5), 1e-12)
+	assert.InDelta(t, 0.008, regularizedIncompleteBeta(3, 1, 0.2), 1e-12)
+}
+
+func TestABVariants(t *testing.T) {
+	base := job.Job[benchmark.Config]{
+		ID:         "happy-panda",
+		Namespace:  "happy-panda",
+		ValueFiles: map[string][]string{"store": {"/src/values.yaml"}},
+		Config: benchmark.Config{
+			Namespace: "happy-panda",
+			Values:    map[string][]string{"store": {"replicas=3"}},
+		},
+	}
+	variants, err := newABVariants(base, [2][]string{}, [2][]string{{"store.image.tag=v1"}, {"store.image.tag=v2"}})
+	assert.NoError(t, err)
+	assert.Len(t, variants, 2)
+	assert.Equal(t, "happy-panda-a", variants[0].job.ID)
+	assert.Equal(t, "happy-panda-b", variants[1].job.Namespace)
+	assert.Equal(t, "happy-panda-b", variants[1].job.Config.Namespace)
+	assert.True(t, variants[1].job.CreateNamespace)
+	assert.Equal(t, []string{"replicas=3", "image.tag=v2"}, variants[1].job.Config.Values["store"])
+	assert.Equal(t, []string{"/home/helmit/values.yaml"}, variants[0].job.Config.ValueFiles["store"])
+	assert.Equal(t, []string{"replicas=3"}, base.Config.Values["store"])
+
+	_, err = newABComparison(variants, 3)
+	assert.Error(t, err)
+
+	comparison, err := newABComparison(variants, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "happy-panda-a", comparison.getJob(1).ID)
+	assert.Equal(t, "happy-panda-b", comparison.getJob(2).ID)
+
+	for i := 0; i < 5; i++ {
+		for worker := 0; worker < 4; worker++ {
+			latency := time.Duration(10+i) * time.Millisecond
+			iterations := 1000 + i*10
+			if worker >= 2 {
+				latency /= 2
+				iterations *= 2
+			}
+			comparison.record(workerReport{
+				Report: benchmark.Report{
+					Iterations:  iterations,
+					Duration:    time.Second,
+					MeanLatency: latency,
+					P99Latency:  latency * 2,
+				},
+				worker: worker,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	comparison.write(&buf)
+	output := buf.String()
+	assert.Contains(t, output, "A: workers 0-1 in namespace happy-panda-a")
+	assert.Contains(t, output, "B: workers 2-3 in namespace happy-panda-b")
+	assert.Contains(t, output, "2040.000000/sec")
+	assert.Contains(t, output, "+100.00%")
+	assert.Contains(t, output, "-50.00%")
+	assert.Contains(t, output, " significant")
+}
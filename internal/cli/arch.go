@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	"time"
+)
+
+// defaultArch is the CPU architecture for which jobs are built when the cluster's architecture is not detected
+const defaultArch = "amd64"
+
+// getArch returns the given CPU architecture, or the architecture of the cluster's nodes if none is given
+// The default architecture is returned without accessing the cluster if detect is false, e.g. for a dry run, or if
+// the nodes' architecture cannot be detected, e.g. because the user is not permitted to list nodes.
+func getArch(step *logging.Step, arch string, detect bool) string {
+	if arch != "" {
+		return arch
+	}
+	if !detect {
+		return defaultArch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	detected, err := k8s.DetectArch(ctx)
+	if err != nil {
+		step.Logf("%s; building for %s (use --arch to set the architecture)", err, defaultArch)
+		return defaultArch
+	}
+	return detected
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const attachExamples = `
+  # Start a benchmark in the cluster and exit.
+  helmit bench ./cmd/benchmarks --suite my-benchmarks --duration 2h --detach
+
+  # Follow the progress of the detached benchmark from another session.
+  helmit attach happy-panda
+`
+
+func getAttachCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "attach <id>",
+		Short:   "Follow the progress of a detached benchmark",
+		Example: attachExamples,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runAttachCommand,
+	}
+	cmd.Flags().Bool("keep", false, "do not delete the benchmark coordinator once the benchmark has completed")
+	return cmd
+}
+
+func runAttachCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	benchID := args[0]
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	monitor, err := job.NewMonitor()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	coordinatorID := benchID + coordinatorSuffix
+	store, err := findStateStore(ctx, benchID)
+	if err != nil {
+		return err
+	} else if store == nil {
+		return fmt.Errorf("no detached benchmark found for %s", benchID)
+	}
+	state, err := store.get(ctx)
+	if err != nil {
+		return err
+	} else if state == nil {
+		return fmt.Errorf("no detached benchmark found for %s", benchID)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Benchmark %s is %s (updated %s ago)\n\n", benchID, state.Phase, time.Since(state.Updated).Round(time.Second))
+
+	// Replay the coordinator's output, following it until the coordinator exits. If the coordinator pod
+	// has been deleted, the results are restored from the stored state instead.
+	statuses, err := monitor.Find(ctx, coordinatorID)
+	if err != nil {
+		return err
+	}
+	var coordinator *job.Status
+	for i, status := range statuses {
+		if status.ID == coordinatorID {
+			coordinator = &statuses[i]
+		}
+	}
+	if coordinator != nil {
+		out := &lineWriter{out: cmd.OutOrStdout()}
+		if err := copyLogs(ctx, monitor, *coordinator, !coordinator.Terminated, out, false); err != nil && ctx.Err() == nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if state, err = store.get(ctx); err != nil {
+			return err
+		} else if state == nil {
+			return fmt.Errorf("the state of benchmark %s was deleted", benchID)
+		}
+	} else if state.Phase == startingPhase {
+		return fmt.Errorf("benchmark coordinator %s has not started yet", coordinatorID)
+	} else if len(state.Reports) > 0 {
+		writeReports(cmd.OutOrStdout(), state.getWorkerReports())
+	}
+
+	if !state.isDone() {
+		return fmt.Errorf("benchmark coordinator %s exited before the benchmark completed", coordinatorID)
+	}
+
+	if !keep {
+		step := logging.NewStep(benchID, "Deleting benchmark coordinator")
+		step.Start()
+		coordinatorJob := job.Job[benchmark.Config]{
+			ID:        coordinatorID,
+			Namespace: store.namespace,
+		}
+		if err := coordinatorJob.Delete(ctx, step); err != nil {
+			step.Fail(err)
+			return err
+		}
+		if err := store.delete(ctx); err != nil {
+			step.Fail(err)
+			return err
+		}
+		step.Complete()
+	}
+
+	if state.Phase == failedPhase {
+		return errors.New(state.Error)
+	}
+	return nil
+}
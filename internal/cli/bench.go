@@ -11,15 +11,20 @@ import (
 	"errors"
 	"fmt"
 	petname "github.com/dustinkirkland/golang-petname"
-	"github.com/gosuri/uilive"
 	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/images"
 	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/report"
 	"github.com/onosproject/helmit/pkg/benchmark"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"io"
 	"os"
-	"os/signal"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"text/tabwriter"
 	"time"
 
@@ -33,6 +38,12 @@ const benchExamples = `
   # Run benchmarks packaged in a Docker image.
   helmit bench --image atomix/kubernetes-benchmarks:latest --duration 1m
 
+  # Run the benchmarks built into an image without copying anything to the benchmark pods.
+  helmit bench --image atomix/kubernetes-benchmarks:latest --no-copy --entrypoint /usr/local/bin/benchmarks --duration 1m
+
+  # Run the worker pods from a separate image with the benchmarks built in.
+  helmit bench --image atomix/kubernetes-benchmarks:latest --worker-image atomix/kubernetes-benchmark-workers:latest --duration 1m
+
   # Run benchmarks by referencing a command package and providing a context.
   # The specified context will be loaded into the benchmark pods as the current working directory.
   helmit bench ./cmd/benchmarks --context ./charts --iterations 1000
@@ -61,8 +72,6 @@ const benchExamples = `
   helmit bench ./cmd/benchmarks -c ./charts -f atomix-controller=./atomix-controller.yaml --suite atomix --duration 1m
 `
 
-const shutdownFile = "/tmp/shutdown"
-
 func getBenchCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "bench",
@@ -75,26 +84,83 @@ func getBenchCommand() *cobra.Command {
 	cmd.Flags().StringP("namespace", "n", "", "the namespace in which to run the benchmarks")
 	cmd.Flags().Bool("create-namespace", false, "whether to create the namespace when running the test")
 	cmd.Flags().String("service-account", "", "the name of the service account to use to run worker pods")
+	cmd.Flags().String("rbac-rules", "", "a YAML file containing RBAC policy rules to grant the worker pods in place of cluster-admin")
+	cmd.Flags().Bool("namespaced-rbac", false, "whether to grant the RBAC rules with a namespaced Role rather than a ClusterRole")
 	cmd.Flags().StringToStringP("label", "l", map[string]string{}, "labels to apply to the worker pods")
 	cmd.Flags().StringToStringP("annotation", "a", map[string]string{}, "annotations to apply to the worker pods")
+	cmd.Flags().String("sidecar-manifest", "", "a YAML file containing sidecar containers and volumes to add to the worker pods")
 	cmd.Flags().StringP("context", "c", "", "the benchmark context")
 	cmd.Flags().StringP("image", "i", "", "the benchmark image to run")
 	cmd.Flags().String("image-pull-policy", string(corev1.PullIfNotPresent), "the Docker image pull policy")
+	cmd.Flags().String("worker-image", "", "the image with which to run the worker pods (defaults to the benchmark image)")
+	cmd.Flags().String("worker-image-pull-policy", "", "the Docker image pull policy of the worker pods (defaults to --image-pull-policy)")
+	cmd.Flags().String("arch", "", "the CPU architecture for which to build the benchmarks (defaults to the architecture of the cluster's nodes)")
 	cmd.Flags().StringArrayP("values", "f", []string{}, "release values paths")
 	cmd.Flags().StringArray("set", []string{}, "cluster argument overrides")
+	cmd.Flags().StringArray("set-secret", []string{}, "release value overrides passed to the pod in a Kubernetes Secret rather than in the job configuration, in the format {release}.{path}={value}")
+	cmd.Flags().StringArray("values-a", []string{}, "release values paths for the A side of an A/B benchmark")
+	cmd.Flags().StringArray("set-a", []string{}, "cluster argument overrides for the A side of an A/B benchmark")
+	cmd.Flags().StringArray("values-b", []string{}, "release values paths for the B side of an A/B benchmark")
+	cmd.Flags().StringArray("set-b", []string{}, "cluster argument overrides for the B side of an A/B benchmark")
 	cmd.Flags().StringP("suite", "s", "", "the benchmark suite to run")
-	cmd.Flags().StringP("benchmark", "b", "BenchmarkSuite$", "the name of the benchmark to run")
+	cmd.Flags().StringP("benchmark", "b", "", "the name of the benchmark to run, or a regular expression matching the benchmarks to run (defaults to all the suite's benchmarks)")
 	cmd.Flags().IntP("workers", "w", 1, "the number of workers to run")
 	cmd.Flags().Int("parallel", 1, "the number of concurrent goroutines per client")
+	cmd.Flags().Float64("rate", 0, "the target number of requests per second across all workers (fixed-rate mode)")
+	cmd.Flags().Duration("think-time", 0, "the time for which each goroutine pauses between iterations to model a client")
+	cmd.Flags().String("jitter", "0%", "the percentage by which to randomize the think time in either direction")
 	cmd.Flags().IntP("iterations", "", 0, "the number of iterations to run")
 	cmd.Flags().DurationP("duration", "d", 0, "the duration for which to run the test")
+	cmd.Flags().Duration("warmup", 0, "the duration for which to run the benchmark before recording results")
 	cmd.Flags().DurationP("report-interval", "r", 5*time.Second, "the interval at which to report benchmark results")
 	cmd.Flags().StringToString("arg", map[string]string{}, "a mapping of named benchmark arguments")
+	cmd.Flags().StringArray("matrix", []string{}, "a benchmark argument and the comma-separated values for which to run the benchmark in the format {name}={value},... (repeat for a parameter matrix)")
+	cmd.Flags().Duration("target-p99", 0, "add workers until the 99th percentile latency exceeds the given target (adaptive mode)")
+	cmd.Flags().Int("max-workers", 10, "the maximum number of workers to run in adaptive mode")
+	cmd.Flags().Float64("max-error-rate", 0, "the maximum fraction of iterations that may fail before the benchmark fails")
+	cmd.Flags().String("stop-on-slo-breach", "", "stop the benchmark and fail once the aggregated metrics breach the given comma-separated SLO expressions, e.g. p99<100ms,errorRate<1%")
+	cmd.Flags().Duration("slo-window", 0, "the window over which metrics are aggregated for --stop-on-slo-breach (defaults to the report interval)")
+	cmd.Flags().Int("worker-start-batch", 0, "the maximum number of workers to start at once, starting the next batch once the workers in the current batch have started (0 starts all workers at once)")
+	cmd.Flags().Duration("worker-start-timeout", 0, "the time allowed for each batch of workers to start when --worker-start-batch is set (0 for no limit)")
+	cmd.Flags().Int("worker-gomaxprocs", 0, "the GOMAXPROCS of each worker (defaults to the worker's CPU limit when it's lower than the node's CPUs)")
+	cmd.Flags().Int("stall-intervals", 0, "flag workers that complete no iterations for the given number of report intervals as stalled (disabled if 0)")
+	cmd.Flags().Bool("restart-stalled", false, "delete and recreate workers flagged as stalled")
+	cmd.Flags().StringSlice("profile", []string{}, "runtime profiles to capture from each worker in the middle of the run, e.g. cpu=30s,heap (cpu, heap, allocs, or goroutine)")
+	cmd.Flags().String("profile-dir", "profiles", "the directory to which to save the profiles captured with --profile")
+	cmd.Flags().Bool("durable-workers", false, "run workers under StatefulSets that recreate their pods, e.g. when nodes are drained, and recover restarted workers for long soak runs")
 	cmd.Flags().Duration("timeout", 10*time.Minute, "benchmark timeout")
 	cmd.Flags().Bool("no-teardown", false, "do not tear down clusters following benchmarks")
+	cmd.Flags().Bool("fail-on-leak", false, "fail if resources labeled with the job or belonging to uninstalled releases are left behind after teardown")
 	cmd.Flags().StringSlice("secret", []string{}, "secrets to pass to the kubernetes pod")
-	_ = cmd.MarkFlagRequired("suite")
-	_ = cmd.MarkFlagRequired("benchmark")
+	cmd.Flags().StringSlice("secret-from", []string{}, "existing Kubernetes secrets in the format [{namespace}/]{name} whose keys to pass to the kubernetes pod")
+	cmd.Flags().String("log-file", "", "a file to which to write the raw output of worker pods")
+	cmd.Flags().String("samples-file", "", "a .csv or .jsonl file to which to write the iterations sampled by workers for offline analysis")
+	cmd.Flags().Float64("sample-rate", 1, "the fraction of iterations to sample when --samples-file is set")
+	cmd.Flags().Int("max-samples", 1000, "the maximum number of iterations each worker samples per report interval when --samples-file is set")
+	cmd.Flags().String("console-record", "", "a file to which to record the console events of the run for replay with helmit replay")
+	cmd.Flags().Bool("no-interleave", false, "buffer the output of each worker and print it per worker once the benchmark completes")
+	cmd.Flags().String("ui", plainUI, "the benchmark progress display (plain or interactive)")
+	cmd.Flags().Bool("detach", false, "run the benchmark from a coordinator pod in the cluster and exit once it has started")
+	cmd.Flags().String("id", "", "the benchmark ID")
+	cmd.Flags().String("resume", "", "the ID of a running benchmark whose session was lost to reconnect to")
+	cmd.Flags().String("coordinator-namespace", "", "the namespace of the coordinator running a detached benchmark")
+	cmd.Flags().Bool("await-executable", false, "wait for the benchmark executable to be copied to the coordinator")
+	cmd.Flags().String("git-commit", "", "the git commit of the benchmark package")
+	_ = cmd.Flags().MarkHidden("id")
+	_ = cmd.Flags().MarkHidden("coordinator-namespace")
+	_ = cmd.Flags().MarkHidden("await-executable")
+	_ = cmd.Flags().MarkHidden("git-commit")
+	addBuildFlags(cmd)
+	addNoCopyFlags(cmd)
+	addReportFlags(cmd)
+	addSchedulingFlags(cmd, "worker pods")
+	addNamespaceFlags(cmd)
+	addReadinessFlags(cmd)
+	addTeardownFlags(cmd)
+	addChartDependencyFlags(cmd)
+	addHookFlags(cmd)
+	addUploadFlags(cmd)
+	cmd.AddCommand(getBenchTrendsCommand())
 	return cmd
 }
 
@@ -102,28 +168,207 @@ func runBenchCommand(cmd *cobra.Command, args []string) error {
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 
+	// Detached benchmarks are passed the project defaults on the coordinator's command line
+	coordinatorNamespace, _ := cmd.Flags().GetString("coordinator-namespace")
+	if coordinatorNamespace == "" {
+		if err := applyProjectFile(cmd); err != nil {
+			return err
+		}
+	}
+
 	namespace, _ := cmd.Flags().GetString("namespace")
 	createNamespace, _ := cmd.Flags().GetBool("create-namespace")
 	serviceAccount, _ := cmd.Flags().GetString("service-account")
+	rbacRules, _ := cmd.Flags().GetString("rbac-rules")
+	namespacedRBAC, _ := cmd.Flags().GetBool("namespaced-rbac")
 	labels, _ := cmd.Flags().GetStringToString("label")
 	annotations, _ := cmd.Flags().GetStringToString("annotation")
+	sidecarManifest, _ := cmd.Flags().GetString("sidecar-manifest")
 	contextPath, _ := cmd.Flags().GetString("context")
 	image, _ := cmd.Flags().GetString("image")
 	suite, _ := cmd.Flags().GetString("suite")
 	benchmarkName, _ := cmd.Flags().GetString("benchmark")
 	workers, _ := cmd.Flags().GetInt("workers")
 	parallelism, _ := cmd.Flags().GetInt("parallel")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	thinkTime, _ := cmd.Flags().GetDuration("think-time")
+	jitterValue, _ := cmd.Flags().GetString("jitter")
 	iterations, _ := cmd.Flags().GetInt("iterations")
 	duration, _ := cmd.Flags().GetDuration("duration")
+	warmup, _ := cmd.Flags().GetDuration("warmup")
 	reportInterval, _ := cmd.Flags().GetDuration("report-interval")
 	files, _ := cmd.Flags().GetStringArray("values")
 	sets, _ := cmd.Flags().GetStringArray("set")
-	benchArgs, _ := cmd.Flags().GetStringToString("args")
+	setSecrets, _ := cmd.Flags().GetStringArray("set-secret")
+	filesA, _ := cmd.Flags().GetStringArray("values-a")
+	setsA, _ := cmd.Flags().GetStringArray("set-a")
+	filesB, _ := cmd.Flags().GetStringArray("values-b")
+	setsB, _ := cmd.Flags().GetStringArray("set-b")
+	benchArgs, _ := cmd.Flags().GetStringToString("arg")
+	matrixParams, _ := cmd.Flags().GetStringArray("matrix")
+	maxErrorRate, _ := cmd.Flags().GetFloat64("max-error-rate")
+	sloValue, _ := cmd.Flags().GetString("stop-on-slo-breach")
+	sloWindow, _ := cmd.Flags().GetDuration("slo-window")
+	workerStartBatch, _ := cmd.Flags().GetInt("worker-start-batch")
+	workerStartTimeout, _ := cmd.Flags().GetDuration("worker-start-timeout")
+	workerGOMAXPROCS, _ := cmd.Flags().GetInt("worker-gomaxprocs")
+	stallIntervals, _ := cmd.Flags().GetInt("stall-intervals")
+	restartStalled, _ := cmd.Flags().GetBool("restart-stalled")
+	durableWorkers, _ := cmd.Flags().GetBool("durable-workers")
+	profileValues, _ := cmd.Flags().GetStringSlice("profile")
+	profileDir, _ := cmd.Flags().GetString("profile-dir")
+	targetP99, _ := cmd.Flags().GetDuration("target-p99")
+	maxWorkers, _ := cmd.Flags().GetInt("max-workers")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
 	imagePullPolicy, _ := cmd.Flags().GetString("image-pull-policy")
 	pullPolicy := corev1.PullPolicy(imagePullPolicy)
+	workerImage, _ := cmd.Flags().GetString("worker-image")
+	workerImagePullPolicy, _ := cmd.Flags().GetString("worker-image-pull-policy")
+	workerPullPolicy := corev1.PullPolicy(workerImagePullPolicy)
+	arch, _ := cmd.Flags().GetString("arch")
 	noTeardown, _ := cmd.Flags().GetBool("no-teardown")
+	failOnLeak, _ := cmd.Flags().GetBool("fail-on-leak")
 	secretsArray, _ := cmd.Flags().GetStringSlice("secret")
+	secretsFrom, _ := cmd.Flags().GetStringSlice("secret-from")
+	logFile, _ := cmd.Flags().GetString("log-file")
+	samplesFile, _ := cmd.Flags().GetString("samples-file")
+	consoleRecord, _ := cmd.Flags().GetString("console-record")
+	sampleRate, _ := cmd.Flags().GetFloat64("sample-rate")
+	maxSamples, _ := cmd.Flags().GetInt("max-samples")
+	noInterleave, _ := cmd.Flags().GetBool("no-interleave")
+	uiType, _ := cmd.Flags().GetString("ui")
+	buildInCluster, _ := cmd.Flags().GetBool("build-in-cluster")
+	detach, _ := cmd.Flags().GetBool("detach")
+	benchID, _ := cmd.Flags().GetString("id")
+	resumeID, _ := cmd.Flags().GetString("resume")
+	awaitExecutable, _ := cmd.Flags().GetBool("await-executable")
+
+	if suite == "" {
+		return errors.New("--suite must be set on the command line or in the project file")
+	}
+	abMode := len(filesA) > 0 || len(setsA) > 0 || len(filesB) > 0 || len(setsB) > 0
+	if abMode && len(matrixParams) > 0 {
+		return errors.New("A/B benchmarks cannot be run with --matrix")
+	}
+	if abMode && targetP99 > 0 {
+		return errors.New("A/B benchmarks cannot be run with --target-p99")
+	}
+	if abMode && detach {
+		return errors.New("A/B benchmarks cannot be run with --detach")
+	}
+	if resumeID != "" && (abMode || len(matrixParams) > 0 || detach) {
+		return errors.New("--resume cannot be used with --matrix, --detach, or A/B benchmarks")
+	}
+	if resumeID != "" && benchmarkName == "" {
+		return errors.New("--resume requires the --benchmark that was resumed")
+	}
+	reportOpts, err := getReportOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if detach && reportOpts != nil {
+		return errors.New("reports cannot be rendered for benchmarks run with --detach")
+	}
+	uploads, err := getResultsUpload(cmd)
+	if err != nil {
+		return err
+	}
+	if detach && uploads != nil {
+		return errors.New("--detach cannot be used with --upload-url")
+	}
+	if detach && buildInCluster {
+		return errors.New("--detach cannot be used with --build-in-cluster")
+	}
+	if workerImage != "" && buildInCluster {
+		return errors.New("--worker-image cannot be used with --build-in-cluster")
+	}
+	if detach && uiType == interactiveUI {
+		return errors.New("--detach cannot be used with the interactive UI")
+	}
+	if detach && len(setSecrets) > 0 {
+		return errors.New("--detach cannot be used with --set-secret")
+	}
+	if detach && samplesFile != "" {
+		return errors.New("--detach cannot be used with --samples-file")
+	}
+	if detach && consoleRecord != "" {
+		return errors.New("--detach cannot be used with --console-record")
+	}
+	if detach && len(profileValues) > 0 {
+		return errors.New("--detach cannot be used with --profile")
+	}
+	if thinkTime < 0 {
+		return errors.New("--think-time must not be negative")
+	}
+	if thinkTime > 0 && rate > 0 {
+		return errors.New("--think-time cannot be used with --rate")
+	}
+	jitter, err := parsePercent(jitterValue)
+	if err != nil {
+		return fmt.Errorf("invalid --jitter: %w", err)
+	}
+	if jitter > 1 {
+		return errors.New("--jitter must be at most 100%")
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		return errors.New("--sample-rate must be greater than 0 and at most 1")
+	}
+	if maxSamples <= 0 {
+		return errors.New("--max-samples must be positive")
+	}
+	if sloWindow < 0 {
+		return errors.New("--slo-window must not be negative")
+	}
+	if sloWindow > 0 && sloValue == "" {
+		return errors.New("--slo-window requires --stop-on-slo-breach")
+	}
+	var objectives []sloObjective
+	if sloValue != "" {
+		if objectives, err = parseSLO(sloValue); err != nil {
+			return fmt.Errorf("invalid --stop-on-slo-breach: %w", err)
+		}
+	}
+	if workerStartBatch < 0 {
+		return errors.New("--worker-start-batch must not be negative")
+	}
+	if workerStartTimeout < 0 {
+		return errors.New("--worker-start-timeout must not be negative")
+	}
+	if workerStartTimeout > 0 && workerStartBatch == 0 {
+		return errors.New("--worker-start-timeout requires --worker-start-batch")
+	}
+	if workerGOMAXPROCS < 0 {
+		return errors.New("--worker-gomaxprocs must not be negative")
+	}
+	if stallIntervals < 0 {
+		return errors.New("--stall-intervals must not be negative")
+	}
+	if restartStalled && stallIntervals == 0 {
+		return errors.New("--restart-stalled requires --stall-intervals")
+	}
+	if detach {
+		// Files read by these flags are not copied to the coordinator pod
+		for _, name := range []string{"rbac-rules", "sidecar-manifest", "affinity"} {
+			if value, _ := cmd.Flags().GetString(name); value != "" {
+				return fmt.Errorf("--%s cannot be used with --detach", name)
+			}
+		}
+	}
+	if coordinatorNamespace != "" {
+		// The coordinator's output is read from its logs, so progress is always displayed as plain text
+		uiType = plainUI
+	}
+	if uiType != plainUI && uiType != interactiveUI {
+		return fmt.Errorf("unknown UI %q", uiType)
+	}
+	if uiType == interactiveUI {
+		if err := checkTerminal(); err != nil {
+			return err
+		}
+		if noInterleave {
+			return errors.New("--no-interleave cannot be used with the interactive UI")
+		}
+	}
 
 	// Either a command package or image must be specified
 	pkgPaths := args
@@ -131,12 +376,50 @@ func runBenchCommand(cmd *cobra.Command, args []string) error {
 		return errors.New("must specify either a benchmark package or --image to run")
 	}
 
-	// Generate a unique benchmark ID
-	benchID := petname.Generate(2, "-")
+	// The commit of the benchmark package is recorded in the report metadata, and forwarded to the coordinator
+	// of a detached benchmark, which runs the benchmark without the package
+	if len(pkgPaths) > 0 {
+		if commit := getGitCommit(pkgPaths[0]); commit != "" {
+			_ = cmd.Flags().Set("git-commit", commit)
+		}
+	}
+
+	matrix, err := parseMatrix(matrixParams)
+	if err != nil {
+		return err
+	}
+
+	if targetP99 > 0 && maxWorkers < workers {
+		return errors.New("--max-workers must be greater than or equal to --workers")
+	}
+	profiles, err := parseProfiles(profileValues)
+	if err != nil {
+		return err
+	}
+	profiler := newWorkerProfiler(profiles, profileDir)
+
+	// Generate a unique benchmark ID unless the benchmark is being run by a coordinator or resumed
+	if resumeID != "" {
+		benchID = resumeID
+	} else if benchID == "" {
+		benchID = petname.Generate(2, "-")
+	}
+
+	// A resumed benchmark continues in the namespace and with the workers of the session that started it
+	var snapshots *snapshotStore
+	var snapshot *benchmarkSnapshot
+	if resumeID != "" {
+		if snapshots, snapshot, err = findSnapshot(resumeID, timeout); err != nil {
+			return err
+		}
+		namespace = snapshot.Namespace
+		workers = len(snapshot.Workers)
+	}
 
 	// If the create-namespace is enabled, generate a default namespace if not specified.
+	// The sides of an A/B benchmark are always run in namespaces created for them, suffixed with the side.
 	if namespace == "" {
-		if createNamespace {
+		if createNamespace || abMode {
 			namespace = benchID
 		} else {
 			namespace = "default"
@@ -167,27 +450,169 @@ func runBenchCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	secretValues, valueSecrets, err := parseSecretOverrides(setSecrets)
+	if err != nil {
+		return err
+	}
+	for name, value := range valueSecrets {
+		secrets[name] = value
+	}
+	if coordinatorNamespace != "" {
+		// Secrets passed to a detached benchmark are mounted in the coordinator pod
+		coordinatorSecrets, err := job.LoadSecrets()
+		if err != nil {
+			return err
+		}
+		for key, value := range coordinatorSecrets {
+			secrets[key] = value
+		}
+	}
+
+	rules, err := parseRules(rbacRules)
+	if err != nil {
+		return err
+	}
+
+	scheduling, err := getScheduling(cmd)
+	if err != nil {
+		return err
+	}
+
+	namespaceMeta, err := getNamespaceMetadata(cmd, createNamespace || abMode)
+	if err != nil {
+		return err
+	}
+
+	readiness, err := getReadiness(cmd)
+	if err != nil {
+		return err
+	}
+
+	teardown, err := getTeardown(cmd)
+	if err != nil {
+		return err
+	}
+
+	// The after-cluster hooks of a detached benchmark cannot be run, since the session exits once it has started
+	hooks, err := getHooks(cmd, nil)
+	if err != nil {
+		return err
+	}
+	if detach && hooks.hasAfterCluster() {
+		return errors.New("--detach cannot be used with after-cluster hooks")
+	}
+
+	noCopy, err := getNoCopy(cmd, image, pkgPaths)
+	if err != nil {
+		return err
+	}
+
+	chartDeps, err := getChartDependencies(cmd)
+	if err != nil {
+		return err
+	}
+
+	sidecars, sidecarVolumes, err := parseSidecars(sidecarManifest)
+	if err != nil {
+		return err
+	}
+
+	logs, err := parseLogFile(logFile)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	var samples *samplesWriter
+	if samplesFile != "" {
+		if samples, err = newSamplesWriter(samplesFile); err != nil {
+			return err
+		}
+		defer samples.Close()
+	}
+
+	var recorder *consoleRecorder
+	if consoleRecord != "" {
+		if recorder, err = newConsoleRecorder(consoleRecord); err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to record console: %s\n", err)
+			}
+		}()
+	}
+
+	// Hooks provisioning the cluster are run before the cluster is used and once the benchmark is torn down
+	defer hooks.afterCluster(true)
+	if err := hooks.beforeCluster(benchID, namespace); err != nil {
+		return err
+	}
+	if err := confirmCluster(cmd); err != nil {
+		return err
+	}
+
 	var executable string
+	var source *job.Source
 	if len(pkgPaths) > 0 {
 		step := logging.NewStep(benchID, "Preparing artifacts")
 		step.Start()
-		executable = filepath.Join(os.TempDir(), "helmit", benchID)
-		defer os.RemoveAll(executable)
-		image = defaultRunnerImage
-		if err := build.Benchmarks(step, suite).Build(executable, pkgPaths...); err != nil {
-			step.Fail(err)
-			return err
+		arch = getArch(step, arch, true)
+		options := getBuildOptions(cmd)
+		builder := build.Benchmarks(step, suite).Arch(arch).Options(options)
+		if buildInCluster {
+			image = images.Builder(arch)
+			generated, err := builder.Generate(pkgPaths...)
+			if err != nil {
+				step.Fail(err)
+				return err
+			}
+			defer generated.Remove()
+			source = newJobSource(generated, options)
+		} else {
+			image = images.Runner(arch)
+			executable = filepath.Join(os.TempDir(), "helmit", benchID)
+			defer os.RemoveAll(executable)
+			if err := builder.Build(executable, pkgPaths...); err != nil {
+				step.Fail(err)
+				return err
+			}
 		}
 		step.Complete()
+	} else if awaitExecutable {
+		server, err := job.ServeControl()
+		if err != nil {
+			return err
+		}
+		defer server.Stop()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		executable, err = server.AwaitExecutable(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Missing chart dependencies are built once into a copy of the context shared by all the benchmark's jobs
+	vendoredContext, err := chartDeps.vendor(benchID, namespace, contextPath)
+	if err != nil {
+		return err
 	}
+	defer removeVendoredContext(vendoredContext, contextPath)
 
 	config := benchmark.Config{
 		Namespace:      namespace,
 		Suite:          suite,
 		Benchmark:      benchmarkName,
 		Parallelism:    parallelism,
+		Rate:           rate / float64(workers),
+		ThinkTime:      thinkTime,
+		Jitter:         jitter,
+		GOMAXPROCS:     workerGOMAXPROCS,
 		Values:         values,
+		SecretValues:   secretValues,
 		ReportInterval: reportInterval,
+		Warmup:         warmup,
 		Timeout:        timeout,
 		Args:           benchArgs,
 		NoTeardown:     noTeardown,
@@ -197,98 +622,563 @@ func runBenchCommand(cmd *cobra.Command, args []string) error {
 		config.Context = filepath.Join(job.HomeDir, job.ContextDir)
 	}
 
-	if len(valueFiles) > 0 {
-		config.ValueFiles = make(map[string][]string)
-		for release, releaseFiles := range valueFiles {
-			var absFiles []string
-			for _, releaseFile := range releaseFiles {
-				absFiles = append(absFiles, filepath.Join(job.HomeDir, filepath.Base(releaseFile)))
+	config.ValueFiles = getConfigValueFiles(valueFiles)
+	if samples != nil {
+		config.SampleRate = sampleRate
+		config.MaxSamples = maxSamples
+	}
+
+	job := job.Job[benchmark.Config]{
+		ID:                   benchID,
+		Namespace:            namespace,
+		Labels:               labels,
+		Annotations:          annotations,
+		CreateNamespace:      createNamespace,
+		DeleteNamespace:      createNamespace && !noTeardown,
+		NamespaceLabels:      namespaceMeta.labels,
+		NamespaceAnnotations: namespaceMeta.annotations,
+		ServiceAccount:       serviceAccount,
+		Rules:                rules,
+		NamespacedRBAC:       namespacedRBAC,
+		Image:                image,
+		ImagePullPolicy:      pullPolicy,
+		NodeSelector:         scheduling.nodeSelector,
+		Tolerations:          scheduling.tolerations,
+		Affinity:             scheduling.affinity,
+		PriorityClassName:    scheduling.priorityClassName,
+		Sidecars:             sidecars,
+		SidecarVolumes:       sidecarVolumes,
+		Command:              noCopy.command,
+		NoCopy:               noCopy.enabled,
+		Executable:           executable,
+		Source:               source,
+		Context:              vendoredContext,
+		ValueFiles:           valueFiles,
+		Secrets:              secrets,
+		SecretsFrom:          secretsFrom,
+		PollInterval:         readiness.pollInterval,
+		ReadyTimeout:         readiness.readyTimeout,
+		Config:               config,
+	}
+
+	if snapshot != nil {
+		// The namespace of a resumed benchmark was created by the session that started it
+		job.CreateNamespace = false
+		job.DeleteNamespace = snapshot.DeleteNamespace && !noTeardown
+	}
+
+	if detach {
+		if len(pkgPaths) == 0 {
+			arch = getArch(logging.NewStep(benchID, "Detecting architecture"), arch, true)
+		}
+		coordinator := newCoordinatorJob(cmd.Flags(), job, arch, image, executable, contextPath, valueFiles, createNamespace)
+		return runDetachedBenchmark(coordinator, benchID, timeout)
+	}
+
+	// The sides of an A/B benchmark are set up and torn down separately, each with half the workers
+	var comparison *abComparison
+	getWorkerJob := newWorkerJobs(job)
+	setupJobs := getSetupJobs(job, nil)
+	if abMode {
+		if comparison, setupJobs, err = newABBenchmark(job, [2][]string{filesA, filesB}, [2][]string{setsA, setsB}, workers); err != nil {
+			return err
+		}
+		getWorkerJob = comparison.getJob
+	}
+
+	var state *stateStore
+	if coordinatorNamespace != "" {
+		if state, err = newStateStore(coordinatorNamespace, benchID); err != nil {
+			return err
+		}
+	}
+
+	interrupt := newInterruptHandler(os.Stderr)
+	defer interrupt.stop()
+
+	runner := &benchmarkRunner{
+		benchID:          benchID,
+		uiType:           uiType,
+		noInterleave:     noInterleave,
+		reportInterval:   reportInterval,
+		recorder:         recorder,
+		samples:          samples,
+		workerImage:      workerImage,
+		workerPullPolicy: workerPullPolicy,
+		durableWorkers:   durableWorkers,
+		targetP99:        targetP99,
+		maxWorkers:       maxWorkers,
+		objectives:       objectives,
+		sloWindow:        sloWindow,
+		options: benchmarkOptions{
+			logs:      logs,
+			interrupt: interrupt,
+			startup: workerStartup{
+				batch:   workerStartBatch,
+				timeout: workerStartTimeout,
+			},
+			stalls:       newStallDetector(stallIntervals, restartStalled),
+			profiler:     profiler,
+			workers:      workers,
+			iterations:   iterations,
+			duration:     duration,
+			maxErrorRate: maxErrorRate,
+			timeout:      timeout,
+		},
+	}
+
+	// The output of the jobs setting up and tearing down the benchmark is written to the console
+	jobLogs := logging.NewTeeSink(logging.NewConsoleSink(os.Stdout, logging.InfoLevel), logs)
+
+	// The setup jobs report the benchmarks matching the --benchmark pattern
+	plan := &benchmarkPlan{}
+	state.update(setupPhase, nil, nil)
+	for i, setupJob := range setupJobs {
+		// A resumed benchmark was set up by the session that started it
+		if snapshot != nil {
+			break
+		}
+		if err := setupBenchmark(setupJob, logging.NewTeeSink(jobLogs, plan), interrupt, timeout); err != nil {
+			if interrupt.interrupted() {
+				// Tear down the benchmarks set up so far, including the partial setup
+				setupJobs = setupJobs[:i+1]
+				break
 			}
-			config.ValueFiles[release] = absFiles
+			state.update(failedPhase, nil, err)
+			return err
 		}
 	}
+	if interrupt.interrupted() {
+		state.update(tearDownPhase, nil, errInterrupted)
+		if err := tearDownBenchmarks(setupJobs, jobLogs, interrupt, timeout); err != nil {
+			state.update(failedPhase, nil, err)
+			return err
+		}
+		interrupt.writeSummary(os.Stdout, benchID)
+		state.update(failedPhase, nil, errInterrupted)
+		return errInterrupted
+	}
 
-	job := job.Job[benchmark.Config]{
-		ID:              benchID,
-		Namespace:       namespace,
-		Labels:          labels,
-		Annotations:     annotations,
-		CreateNamespace: createNamespace,
-		DeleteNamespace: createNamespace && !noTeardown,
-		ServiceAccount:  serviceAccount,
-		Image:           image,
-		ImagePullPolicy: pullPolicy,
-		Executable:      executable,
-		Context:         contextPath,
-		ValueFiles:      valueFiles,
-		Secrets:         secrets,
-		Config:          config,
+	// Benchmarks built with a version of helmit that does not report the matched benchmarks are run by name
+	benchmarks := plan.getBenchmarks(benchmarkName)
+	if len(benchmarks) == 1 {
+		job.Config.Benchmark = benchmarks[0]
 	}
 
-	if err := setupBenchmark(job, timeout); err != nil {
-		return err
+	state.update(runningPhase, nil, nil)
+	start := time.Now()
+	var reports []*workerReport
+	var runs []report.BenchmarkRun
+	var benchErr error
+	if len(benchmarks) == 0 {
+		benchErr = errors.New("no benchmarks to run")
+	} else if len(benchmarks) > 1 && comparison != nil {
+		benchErr = fmt.Errorf("A/B benchmarks must run a single benchmark, but %q matches %s", benchmarkName, strings.Join(benchmarks, ", "))
+	} else if len(matrix) == 0 && len(benchmarks) == 1 {
+		reports, runs, benchErr = runner.run(job, runner.getWorkerJobs(getWorkerJob), comparison, snapshots, snapshot)
+	} else {
+		reports, runs, benchErr = runner.runMatrix(job, benchmarks, matrix, benchArgs)
 	}
-	if err := runBenchmark(job, workers, iterations, duration, timeout); err != nil {
-		return err
+
+	benchReport := report.BenchmarkReport{
+		ID:        benchID,
+		Suite:     suite,
+		Benchmark: strings.Join(benchmarks, ", "),
+		Start:     start,
+		Duration:  time.Since(start),
+		Runs:      runs,
+		Metadata:  getSetupMetadata(cmd.Flags(), pkgPaths, setupJobs),
+	}
+	renderReport := func(renderer report.Renderer, out io.Writer) error {
+		return renderer.RenderBenchmark(out, benchReport)
+	}
+	if err := reportOpts.write(os.Stdout, renderReport); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write benchmark report: %s\n", err)
 	}
-	if err := tearDownBenchmark(job, timeout); err != nil {
+	uploadFiles := make(map[string]string)
+	for _, file := range []string{logFile, samplesFile} {
+		if file != "" {
+			uploadFiles[filepath.Base(file)] = file
+		}
+	}
+	if profiler != nil {
+		uploadFiles["profiles"] = profileDir
+	}
+	uploads.upload(benchID, renderReport, uploadFiles)
+
+	// The session that took the benchmark over tears it down once it completes
+	var lostErr *leaseLostError
+	if errors.As(benchErr, &lostErr) {
+		fmt.Fprintf(os.Stderr, "Benchmark %s was taken over by %s, exiting without tearing it down\n", benchID, lostErr.holder)
+		state.update(failedPhase, reports, benchErr)
+		return exit(cmd, 1)
+	}
+
+	state.update(tearDownPhase, reports, benchErr)
+	if err := tearDownBenchmarks(setupJobs, jobLogs, interrupt, timeout); err != nil {
+		state.update(failedPhase, reports, err)
 		return err
 	}
-	return nil
+	if interrupt.interrupted() {
+		interrupt.writeSummary(os.Stdout, benchID)
+	} else if !noTeardown {
+		for _, setupJob := range setupJobs {
+			if setupJob.DeleteNamespace {
+				_ = awaitTeardown(os.Stdout, setupJob.ID, setupJob.Namespace, teardown)
+			}
+			if err := checkLeaks(os.Stdout, setupJob.ID, setupJob.Namespace, failOnLeak); err != nil && benchErr == nil {
+				benchErr = err
+			}
+		}
+	}
+	if err := hooks.afterCluster(benchErr != nil); err != nil && benchErr == nil {
+		benchErr = err
+	}
+	if benchErr != nil {
+		state.update(failedPhase, reports, benchErr)
+	} else {
+		state.update(completePhase, reports, nil)
+	}
+	return benchErr
 }
 
-func runJob(ctx context.Context, job job.Job[benchmark.Config], log logging.Logger) error {
-	if err := job.Create(ctx, log); err != nil {
-		return err
+// benchmarkRunner runs the benchmarks of a bench command, each run with its own UI
+type benchmarkRunner struct {
+	benchID        string
+	uiType         string
+	noInterleave   bool
+	reportInterval time.Duration
+	recorder       *consoleRecorder
+	samples        *samplesWriter
+	// workerImage, workerPullPolicy and durableWorkers configure the workers' jobs
+	workerImage      string
+	workerPullPolicy corev1.PullPolicy
+	durableWorkers   bool
+	// targetP99, maxWorkers, objectives and sloWindow configure the scaler and SLO monitor created for each run
+	targetP99  time.Duration
+	maxWorkers int
+	objectives []sloObjective
+	sloWindow  time.Duration
+	// options are the options shared by all runs
+	options benchmarkOptions
+}
+
+// run runs a single benchmark, returning the final report of each worker and the run for the benchmark report
+func (r *benchmarkRunner) run(job job.Job[benchmark.Config], getWorkerJob workerJobs, comparison *abComparison, snapshots *snapshotStore, snapshot *benchmarkSnapshot) ([]*workerReport, []report.BenchmarkRun, error) {
+	ui, history, err := r.newUI(r.benchID, job.Config, "", comparison)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	stream, err := job.GetLogs(ctx)
+	var reports []*workerReport
+	progress, err := r.newProgress(job, ui, comparison, snapshots, snapshot)
 	if err != nil {
-		return err
+		_ = ui.Close()
+	} else {
+		options := r.newOptions(getWorkerJob, ui, progress)
+		reports, err = runBenchmark(job, options)
+		if err := progress.delete(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete benchmark progress: %s\n", err)
+		}
+		options.scaler.writeResult(os.Stdout)
+	}
+	if err == errBenchmarkInterrupted {
+		err = nil
+	}
+	runs := []report.BenchmarkRun{newBenchmarkRun("", reports, history, err)}
+	if comparison != nil {
+		fmt.Println()
+		comparison.write(os.Stdout)
 	}
-	defer stream.Close()
+	return reports, runs, err
+}
 
-	scanner := bufio.NewScanner(stream)
-	for scanner.Scan() {
-		fmt.Fprintf(os.Stdout, "    %s\n", scanner.Text())
+// runMatrix runs each of the given benchmarks for every combination of the matrix values, returning the final
+// report of each worker of the last run and the runs for the benchmark report
+// A failed run does not stop the remaining runs, but an interrupted run does.
+func (r *benchmarkRunner) runMatrix(job job.Job[benchmark.Config], benchmarks []string, m matrix, args map[string]string) ([]*workerReport, []report.BenchmarkRun, error) {
+	var reports []*workerReport
+	var runs []report.BenchmarkRun
+	var benchErr error
+	var results []matrixResult
+loop:
+	for _, name := range benchmarks {
+		for _, params := range m.combinations() {
+			paramsJob := job
+			paramsJob.ID = fmt.Sprintf("%s-%d", r.benchID, len(results))
+			paramsJob.Config.Benchmark = name
+			paramsJob.Config.Args = params.apply(args)
+
+			result := matrixResult{params: params}
+			if len(benchmarks) > 1 {
+				result.benchmark = name
+			}
+			step := logging.NewStep(r.benchID, "Running %s", result)
+			step.Start()
+			ui, history, err := r.newUI(paramsJob.ID, paramsJob.Config, result.label(), nil)
+			if err != nil {
+				step.Fail(err)
+				benchErr = err
+				break loop
+			}
+			options := r.newOptions(r.getWorkerJobs(newWorkerJobs(paramsJob)), ui, nil)
+			result.reports, result.err = runBenchmark(paramsJob, options)
+			options.scaler.writeResult(os.Stdout)
+			results = append(results, result)
+			runs = append(runs, newBenchmarkRun(result.label(), result.reports, history, result.err))
+			reports = result.reports
+			if result.err == errBenchmarkInterrupted {
+				step.Fail(result.err)
+				break loop
+			} else if result.err != nil {
+				step.Fail(result.err)
+				if benchErr == nil {
+					benchErr = result.err
+				}
+			} else {
+				step.Complete()
+			}
+		}
 	}
+	writeMatrixResults(os.Stdout, m, results)
+	return reports, runs, benchErr
+}
+
+// newUI returns the UI of a run of the benchmark with the given job ID, and the history the UI records
+// The samples of the run are labeled with the given run label when samples are written.
+func (r *benchmarkRunner) newUI(id string, config benchmark.Config, run string, comparison *abComparison) (benchmarkUI, *benchmarkHistory, error) {
+	ui, err := newBenchmarkUI(r.uiType, id, r.options.workers, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.noInterleave {
+		ui = newBufferedLogUI(ui, logging.NewConsoleSink(os.Stdout, logging.VerboseLevel))
+	}
+	history := newBenchmarkHistory(r.reportInterval)
+	ui = &historyUI{benchmarkUI: ui, history: history}
+	if comparison != nil {
+		ui = &abComparisonUI{benchmarkUI: ui, comparison: comparison}
+	}
+	ui = r.recorder.wrap(ui, id, r.options.workers, config)
+	if r.samples != nil {
+		ui = &samplesUI{benchmarkUI: ui, writer: r.samples, job: id, run: run}
+	}
+	return ui, history, nil
+}
+
+// newProgress returns the progress of the given benchmark, stored in the given store or a new store in the
+// benchmark's namespace, and resumed from the given snapshot if not nil
+// The progress of A/B benchmarks is not stored, since they cannot be resumed.
+func (r *benchmarkRunner) newProgress(job job.Job[benchmark.Config], ui benchmarkUI, comparison *abComparison, snapshots *snapshotStore, snapshot *benchmarkSnapshot) (*benchmarkProgress, error) {
+	if comparison != nil {
+		return nil, nil
+	}
+	if snapshots == nil {
+		var err error
+		if snapshots, err = newSnapshotStore(job.Namespace, r.benchID); err != nil {
+			return nil, err
+		}
+	}
+	return newBenchmarkProgress(snapshots, snapshot, r.options.workers, job.DeleteNamespace, func(message string) {
+		ui.Log(r.benchID, message)
+	}), nil
+}
 
-	if err := job.Delete(ctx, log); err != nil {
+// newOptions returns the options for a run of the benchmark by the given workers, with a new scaler and SLO
+// monitor so each run is scaled and monitored independently
+func (r *benchmarkRunner) newOptions(getWorkerJob workerJobs, ui benchmarkUI, progress *benchmarkProgress) benchmarkOptions {
+	options := r.options
+	options.getWorkerJob = getWorkerJob
+	options.ui = ui
+	options.progress = progress
+	if r.targetP99 > 0 {
+		options.scaler = newAdaptiveScaler(r.targetP99, r.maxWorkers)
+	}
+	options.slo = newSLOMonitor(r.objectives, r.sloWindow, r.reportInterval)
+	return options
+}
+
+// getWorkerJobs returns workerJobs creating the workers of the given workerJobs with the command's worker image
+// and durability
+func (r *benchmarkRunner) getWorkerJobs(getWorkerJob workerJobs) workerJobs {
+	return withDurableWorkers(withWorkerImage(getWorkerJob, r.workerImage, r.workerPullPolicy), r.durableWorkers)
+}
+
+// runJob runs the given job to completion, writing its logs to the given sink
+// The job is deleted once it completes, even if the context is canceled by an interrupt.
+func runJob(ctx context.Context, job job.Job[benchmark.Config], sink logging.Sink, log logging.Logger, interrupt *interruptHandler, timeout time.Duration) error {
+	if err := createJob(ctx, job, log, interrupt, timeout); err != nil {
 		return err
 	}
-	return nil
+
+	stream, err := job.GetLogs(ctx)
+	if err == nil {
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			_ = sink.Write(job.ID, scanner.Text())
+		}
+		stream.Close()
+	}
+
+	if deleteErr := deleteJob(job, log, interrupt, timeout); err == nil {
+		err = deleteErr
+	}
+	return err
 }
 
-func setupBenchmark(job job.Job[benchmark.Config], timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+func setupBenchmark(job job.Job[benchmark.Config], logs logging.Sink, interrupt *interruptHandler, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(interrupt.ctx, timeout)
 	defer cancel()
 	job.Config.Type = benchmark.SetupType
 	job.DeleteNamespace = false
 	step := logging.NewStep(job.ID, "Setting up benchmark")
 	step.Start()
-	if err := runJob(ctx, job, step); err != nil {
+	if err := runJob(ctx, job, logs, step, interrupt, timeout); err != nil {
 		step.Fail(err)
 		return err
 	}
+	if interrupt.interrupted() {
+		step.Fail(errInterrupted)
+		return errInterrupted
+	}
 	step.Complete()
 	return nil
 }
 
-func runBenchmark(job job.Job[benchmark.Config], workers int, maxIterations int, maxDuration time.Duration, timeout time.Duration) error {
-	ctx, cancel := context.WithCancel(context.Background())
-	if maxDuration > 0 {
-		ctx, cancel = context.WithTimeout(ctx, maxDuration)
+// workerJobs returns the job from which the given worker's job is created
+type workerJobs func(worker int) job.Job[benchmark.Config]
+
+// newWorkerJobs returns workerJobs creating all workers from the given job
+func newWorkerJobs(j job.Job[benchmark.Config]) workerJobs {
+	return func(int) job.Job[benchmark.Config] {
+		return j
+	}
+}
+
+// withWorkerImage returns workerJobs creating the workers of the given workerJobs with the given image and pull
+// policy, if set, rather than those of the job that sets up the benchmark
+func withWorkerImage(getWorkerJob workerJobs, image string, pullPolicy corev1.PullPolicy) workerJobs {
+	if image == "" && pullPolicy == "" {
+		return getWorkerJob
+	}
+	return func(worker int) job.Job[benchmark.Config] {
+		j := getWorkerJob(worker)
+		if image != "" {
+			j.Image = image
+		}
+		if pullPolicy != "" {
+			j.ImagePullPolicy = pullPolicy
+		}
+		return j
+	}
+}
+
+// getSetupJobs returns the jobs that set up and tear down the benchmark, one per side of an A/B benchmark
+func getSetupJobs(j job.Job[benchmark.Config], variants []abVariant) []job.Job[benchmark.Config] {
+	if len(variants) == 0 {
+		return []job.Job[benchmark.Config]{j}
+	}
+	jobs := make([]job.Job[benchmark.Config], 0, len(variants))
+	for _, variant := range variants {
+		jobs = append(jobs, variant.job)
+	}
+	return jobs
+}
+
+// errBenchmarkInterrupted is returned by runBenchmark when the benchmark is interrupted by a signal
+var errBenchmarkInterrupted = errors.New("benchmark interrupted")
+
+// benchmarkOptions are the options with which runBenchmark runs the workers of a benchmark
+type benchmarkOptions struct {
+	// getWorkerJob returns the job from which each worker's job is created
+	getWorkerJob workerJobs
+	// logs is the sink to which the output of the workers is written
+	logs      logging.Sink
+	ui        benchmarkUI
+	interrupt *interruptHandler
+	// startup configures the batches in which workers are brought up
+	startup workerStartup
+	// scaler adds workers until the latency target is exceeded if not nil
+	scaler *adaptiveScaler
+	// stalls flags workers that stall, and restarts them if enabled, if not nil
+	stalls *stallDetector
+	// profiler captures runtime profiles from each worker in the middle of the run if not nil
+	profiler *workerProfiler
+	// slo stops the benchmark once the reports breach its objectives if not nil
+	slo *sloMonitor
+	// progress stores the progress of the workers so the benchmark can be resumed if not nil
+	progress *benchmarkProgress
+	workers  int
+	// iterations and duration stop the benchmark once exceeded, if not 0
+	iterations   int
+	duration     time.Duration
+	maxErrorRate float64
+	timeout      time.Duration
+}
+
+// runBenchmark runs the benchmark workers, returning the final report of each worker
+// If the benchmark is interrupted by a signal, errBenchmarkInterrupted is returned with the reports received.
+// If the benchmark's progress is stored, workers started by a previous session for a resumed benchmark are
+// reconnected to rather than created. If the benchmark is taken over by another session, the workers are left
+// running for that session and a *leaseLostError is returned.
+// If the SLO monitor stops the benchmark, an *sloBreach is returned with the reports received.
+func runBenchmark(job job.Job[benchmark.Config], options benchmarkOptions) ([]*workerReport, error) {
+	ctx, cancel := context.WithCancel(options.interrupt.ctx)
+	if options.duration > 0 {
+		// Extend the duration by the warm-up period so the measured window matches the requested duration
+		ctx, cancel = context.WithTimeout(ctx, options.progress.getRemaining(job.Config.Warmup+options.duration))
 	}
 	defer cancel()
+	options.progress.save()
+	// The lease is renewed until the workers have been torn down, so the benchmark is not taken over during teardown
+	renewCtx, stopRenew := context.WithCancel(context.Background())
+	defer stopRenew()
+	go options.progress.renew(renewCtx)
 
+	// Workers reconnected to for a resumed benchmark are already running
+	var starting int
+	for i := 0; i < options.workers; i++ {
+		if !options.progress.isRunning(i) {
+			starting++
+		}
+	}
+	starter := newWorkerStarter(options.startup, starting, func(message string) {
+		options.ui.Log(job.ID, message)
+	})
 	reportCh := make(chan workerReport)
+	recovery := &workerRecovery{}
+	profiler := options.profiler.schedule(time.Now(), job.Config.Warmup, options.duration)
 	wg := &sync.WaitGroup{}
-	for i := 0; i < workers; i++ {
+	startWorker := func(worker int) {
 		wg.Add(1)
-		go func(worker int) {
-			_ = runBenchmarkWorker(ctx, job, worker, reportCh, timeout)
+		go func() {
+			// The worker is profiled once, even if it's recreated, and profiling stops once the worker is done
+			profileJob := options.getWorkerJob(worker)
+			profileJob.ID = getWorkerID(profileJob.ID, worker)
+			if options.progress.isRunning(worker) {
+				profileJob = options.progress.getWorkerJob(worker, options.getWorkerJob(worker))
+			}
+			profileCtx, cancelProfile := context.WithCancel(ctx)
+			profileCh := profiler.profile(profileCtx, profileJob, options.ui, worker)
+
+			var err error
+			if options.progress.isRunning(worker) {
+				err = resumeBenchmarkWorker(ctx, options.progress.getWorkerJob(worker, options.getWorkerJob(worker)), options.logs, options.ui, options.interrupt, options.stalls, recovery, options.progress, worker, options.progress.getResumed(), reportCh, options.timeout)
+			} else {
+				options.progress.start(worker, options.getWorkerJob(worker))
+				err = runBenchmarkWorker(ctx, options.getWorkerJob(worker), options.logs, options.ui, options.interrupt, starter, options.stalls, recovery, options.progress, worker, reportCh, options.timeout)
+			}
+			// Stalled workers are recreated until the benchmark is done
+			for err == errWorkerStalled && ctx.Err() == nil {
+				err = runBenchmarkWorker(ctx, options.getWorkerJob(worker), options.logs, options.ui, options.interrupt, starter, options.stalls, recovery, options.progress, worker, reportCh, options.timeout)
+			}
+			cancelProfile()
+			<-profileCh
 			wg.Done()
-		}(i)
+		}()
+	}
+	for i := 0; i < options.workers; i++ {
+		startWorker(i)
 	}
 
 	go func() {
@@ -296,119 +1186,387 @@ func runBenchmark(job job.Job[benchmark.Config], workers int, maxIterations int,
 		close(reportCh)
 	}()
 
-	uiwriter := uilive.New()
-	uiwriter.Out = os.Stdout
-
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	interruptCh := options.interrupt.ctx.Done()
+	lostCh := options.progress.lost()
 
-	reports := make([]*workerReport, workers)
-	var canceled bool
-	var iterations int
+	reports := make([]*workerReport, options.workers)
+	stallCounts := make(stallCounts)
+	var canceled, interrupted bool
+	var breach *sloBreach
+	// A resumed benchmark continues from the iterations completed before its session was lost
+	totalIterations, totalErrors := options.progress.getTotals()
+	iterations := totalIterations
 	for {
 		select {
 		case report, ok := <-reportCh:
 			if !ok {
-				return nil
+				if err := options.ui.Close(); err != nil {
+					return reports, err
+				}
+				if err := options.progress.leaseErr(); err != nil {
+					return reports, err
+				}
+				if options.maxErrorRate > 0 {
+					if errorRate := getErrorRate(totalIterations, totalErrors); errorRate > options.maxErrorRate {
+						return reports, fmt.Errorf("benchmark error rate %.2f%% exceeded the maximum error rate %.2f%%", errorRate*100, options.maxErrorRate*100)
+					}
+				}
+				if interrupted {
+					return reports, errBenchmarkInterrupted
+				}
+				if breach != nil {
+					return reports, breach
+				}
+				return reports, nil
 			}
 			if canceled {
 				continue
 			}
 
+			stallCounts.apply(&report)
 			reports[report.worker] = &report
-
-			writer := new(tabwriter.Writer)
-			writer.Init(uiwriter, 0, 0, 3, ' ', tabwriter.FilterHTML)
-
-			fmt.Fprintln(writer, "WORKER\tITERATIONS\tDURATION\tTHROUGHPUT\tMEAN LATENCY\tMEDIAN LATENCY\t75% LATENCY\t95% LATENCY\t99% LATENCY")
-			var count int
-			var total benchmark.Report
-			for worker, report := range reports {
+			options.progress.record(report)
+			totalIterations += report.Iterations
+			totalErrors += report.Errors
+			for _, report := range reports {
 				if report != nil {
-					fmt.Fprintf(writer, "%d\t%d\t%s\t%f/sec\t%s\t%s\t%s\t%s\t%s\n",
-						worker, report.Iterations, report.Duration,
-						float64(report.Iterations)/(float64(report.Duration)/float64(time.Second)),
-						report.MeanLatency, report.P50Latency, report.P75Latency, report.P95Latency, report.P99Latency)
 					iterations += report.Iterations
-					total.Iterations += report.Iterations
-					total.Duration += report.Duration
-					total.MeanLatency += report.MeanLatency
-					total.P50Latency += report.P50Latency
-					total.P75Latency += report.P75Latency
-					total.P95Latency += report.P95Latency
-					total.P99Latency += report.P99Latency
-					count++
 				}
 			}
-			fmt.Fprintf(writer, "TOTAL\t%d\t%s\t%f/sec\t%s\t%s\t%s\t%s\t%s\n", total.Iterations, total.Duration,
-				float64(total.Iterations)/(float64(total.Duration)/float64(time.Second)),
-				total.MeanLatency/time.Duration(count), report.P50Latency/time.Duration(count),
-				report.P75Latency/time.Duration(count), report.P95Latency/time.Duration(count),
-				report.P99Latency/time.Duration(count))
-			writer.Flush()
-			uiwriter.Flush()
-
-			if !canceled && maxIterations > 0 && iterations > maxIterations {
+			options.ui.Update(reports, report)
+
+			if !canceled && options.iterations > 0 && iterations > options.iterations {
 				cancel()
 				canceled = true
 			}
-		case <-signalCh:
+
+			if !canceled {
+				if breach = options.slo.observe(report, time.Now()); breach != nil {
+					options.ui.Log(job.ID, fmt.Sprintf("Stopping benchmark: %s", breach))
+					cancel()
+					canceled = true
+				}
+			}
+
+			if !canceled && options.scaler != nil {
+				switch options.scaler.observe(reports, report) {
+				case scaleUp:
+					options.ui.Log(job.ID, fmt.Sprintf("Scaling up to %d options.workers", len(reports)+1))
+					reports = append(reports, nil)
+					startWorker(len(reports) - 1)
+				case scaleStop:
+					cancel()
+					canceled = true
+				}
+			}
+		case stall := <-options.stalls.stalls():
+			options.ui.Log(job.ID, formatStall(stall))
+			stallCounts.record(stall)
+			if report := reports[stall.worker]; report != nil {
+				stallCounts.apply(report)
+			}
+		case config := <-options.ui.Configured():
+			if !canceled {
+				recovery.configure(config)
+				for worker := range reports {
+					go func(worker int) {
+						if err := configureWorker(ctx, options.getWorkerJob(worker), worker, config); err != nil {
+							options.ui.Log(job.ID, fmt.Sprintf("Failed to configure worker %d: %s", worker, err))
+						}
+					}(worker)
+				}
+			}
+		case <-options.ui.Stopped():
 			if !canceled {
 				cancel()
 				canceled = true
 			}
+		case <-lostCh:
+			lostCh = nil
+			options.ui.Log(job.ID, fmt.Sprintf("Stopping benchmark: %s", options.progress.leaseErr()))
+			if !canceled {
+				cancel()
+				canceled = true
+			}
+		case <-interruptCh:
+			// The workers' context is canceled with the interrupt, so only stop listening for it
+			interruptCh = nil
+			interrupted = true
+			if !canceled {
+				cancel()
+				canceled = true
+			}
+		}
+	}
+}
+
+// writeReports writes a table of the given worker reports and their totals to the given writer
+func writeReports(out io.Writer, reports []*workerReport) {
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', tabwriter.FilterHTML)
+
+	counters, gauges := getMetricNames(reports)
+	fmt.Fprintf(writer, "WORKER\tITERATIONS\tERRORS\tDURATION\tTARGET\tTHROUGHPUT\tMEAN LATENCY\tMEDIAN LATENCY\t75%% LATENCY\t95%% LATENCY\t99%% LATENCY%s\n",
+		formatMetricNames(counters, gauges))
+	for worker, report := range reports {
+		if report != nil {
+			fmt.Fprintf(writer, "%d\t%d\t%s\t%s\t%s\t%f/sec\t%s\t%s\t%s\t%s\t%s%s\n",
+				worker, report.Iterations, formatErrors(report.Iterations, report.Errors), report.Duration, formatRate(report.TargetRate),
+				getThroughput(report.Report),
+				report.MeanLatency, report.P50Latency, report.P75Latency, report.P95Latency, report.P99Latency,
+				formatMetrics(report.Counters, report.Gauges, counters, gauges))
+		}
+	}
+	total, count := sumReports(reports)
+	if count == 0 {
+		writer.Flush()
+		return
+	}
+	fmt.Fprintf(writer, "TOTAL\t%d\t%s\t%s\t%s\t%f/sec\t%s\t%s\t%s\t%s\t%s%s\n", total.Iterations,
+		formatErrors(total.Iterations, total.Errors), total.Duration,
+		formatRate(total.TargetRate),
+		getThroughput(total),
+		total.MeanLatency, total.P50Latency, total.P75Latency, total.P95Latency, total.P99Latency,
+		formatMetrics(total.Counters, total.Gauges, counters, gauges))
+	writer.Flush()
+	writeStalls(out, reports)
+	writeProcs(out, reports)
+}
+
+// writeProcs writes the effective GOMAXPROCS of the given workers to the given writer
+// Workers are summarized together when they all report the same GOMAXPROCS and number of CPUs.
+func writeProcs(out io.Writer, reports []*workerReport) {
+	var procs []*workerReport
+	for _, report := range reports {
+		if report != nil && report.GOMAXPROCS > 0 {
+			procs = append(procs, report)
+		}
+	}
+	if len(procs) == 0 {
+		return
+	}
+	uniform := true
+	for _, report := range procs {
+		if report.GOMAXPROCS != procs[0].GOMAXPROCS || report.NumCPU != procs[0].NumCPU {
+			uniform = false
+		}
+	}
+	if uniform {
+		fmt.Fprintf(out, "Workers ran with GOMAXPROCS=%d on %d CPU(s)\n", procs[0].GOMAXPROCS, procs[0].NumCPU)
+		return
+	}
+	for _, report := range procs {
+		fmt.Fprintf(out, "Worker %d ran with GOMAXPROCS=%d on %d CPU(s)\n", report.worker, report.GOMAXPROCS, report.NumCPU)
+	}
+}
+
+// sumReports returns the total of the given worker reports, with latencies and gauges averaged across workers,
+// and the number of workers that reported
+func sumReports(reports []*workerReport) (benchmark.Report, int) {
+	var count int
+	var total benchmark.Report
+	total.Counters = make(map[string]float64)
+	total.Gauges = make(map[string]float64)
+	gaugeCounts := make(map[string]int)
+	for _, report := range reports {
+		if report != nil {
+			total.Iterations += report.Iterations
+			total.Errors += report.Errors
+			total.TargetRate += report.TargetRate
+			total.Duration += report.Duration
+			total.MeanLatency += report.MeanLatency
+			total.P50Latency += report.P50Latency
+			total.P75Latency += report.P75Latency
+			total.P95Latency += report.P95Latency
+			total.P99Latency += report.P99Latency
+			for name, value := range report.Counters {
+				total.Counters[name] += value
+			}
+			for name, value := range report.Gauges {
+				total.Gauges[name] += value
+				gaugeCounts[name]++
+			}
+			count++
 		}
 	}
+	if count == 0 {
+		return total, 0
+	}
+	for name, value := range total.Gauges {
+		total.Gauges[name] = value / float64(gaugeCounts[name])
+	}
+	total.MeanLatency /= time.Duration(count)
+	total.P50Latency /= time.Duration(count)
+	total.P75Latency /= time.Duration(count)
+	total.P95Latency /= time.Duration(count)
+	total.P99Latency /= time.Duration(count)
+	return total, count
+}
+
+// getThroughput returns the number of iterations per second in the given report
+func getThroughput(report benchmark.Report) float64 {
+	return float64(report.Iterations) / (float64(report.Duration) / float64(time.Second))
+}
+
+// getWorkerID returns the ID of the job of the given worker of the benchmark job with the given ID
+func getWorkerID(jobID string, worker int) string {
+	return fmt.Sprintf("%s-worker-%d", jobID, worker)
 }
 
-func runBenchmarkWorker(ctx context.Context, job job.Job[benchmark.Config], worker int, ch chan<- workerReport, timeout time.Duration) error {
-	job.ID = fmt.Sprintf("%s-worker-%d", job.ID, worker)
+// runBenchmarkWorker creates a worker and streams its reports to the given channel until the context is done
+// If starter is not nil, the worker waits for its batch to be started before it's created.
+func runBenchmarkWorker(ctx context.Context, job job.Job[benchmark.Config], logs logging.Sink, ui benchmarkUI, interrupt *interruptHandler, starter *workerStarter, stalls *stallDetector, recovery *workerRecovery, progress *benchmarkProgress, worker int, ch chan<- workerReport, timeout time.Duration) error {
+	job.Config.RunID = job.ID
+	job.ID = getWorkerID(job.ID, worker)
 	job.Config.Type = benchmark.WorkerType
 	job.CreateNamespace = false
 	job.DeleteNamespace = false
+	job.ResourceEnv = map[string]string{benchmark.CPULimitEnv: "limits.cpu"}
 
+	startCtx, started, err := starter.acquire(ctx)
+	if err != nil {
+		return err
+	}
 	step := logging.NewStep(job.ID, "Setting up worker %d", worker)
 	step.Start()
-	if err := job.Create(ctx, step); err != nil {
+	if err := createJob(startCtx, job, step, interrupt, timeout); err != nil {
+		started(err)
 		step.Fail(err)
+		// A worker that timed out starting with its batch is deleted so it does not start late
+		if ctx.Err() == nil && !interrupt.interrupted() {
+			_ = deleteJob(job, step, interrupt, timeout)
+		}
 		return err
 	}
+	started(nil)
 	step.Complete()
+	return streamBenchmarkWorker(ctx, job, logs, ui, interrupt, stalls, recovery, progress, worker, time.Time{}, ch, timeout)
+}
+
+// resumeBenchmarkWorker reconnects to the given job of a worker started by the session that started a resumed
+// benchmark, reading the reports written by the worker since the given time
+func resumeBenchmarkWorker(ctx context.Context, job job.Job[benchmark.Config], logs logging.Sink, ui benchmarkUI, interrupt *interruptHandler, stalls *stallDetector, recovery *workerRecovery, progress *benchmarkProgress, worker int, since time.Time, ch chan<- workerReport, timeout time.Duration) error {
+	job.Config.Type = benchmark.WorkerType
+	job.CreateNamespace = false
+	job.DeleteNamespace = false
+	return streamBenchmarkWorker(ctx, job, logs, ui, interrupt, stalls, recovery, progress, worker, since, ch, timeout)
+}
 
-	step = logging.NewStep(job.ID, "Running worker %d", worker)
+// streamBenchmarkWorker sends the reports written by a running worker since the given time to the given channel
+// until the context is done, and then tears down the worker
+// If the worker stalls and is to be restarted, the worker is deleted and errWorkerStalled is returned.
+// If the pod of a durable worker is restarted, the worker is recovered and the reports it writes once recovered
+// are sent to the channel, so they're merged with those written before the restart.
+// Workers of a benchmark taken over by another session are left running for that session.
+func streamBenchmarkWorker(ctx context.Context, job job.Job[benchmark.Config], logs logging.Sink, ui benchmarkUI, interrupt *interruptHandler, stalls *stallDetector, recovery *workerRecovery, progress *benchmarkProgress, worker int, since time.Time, ch chan<- workerReport, timeout time.Duration) error {
+	step := logging.NewStep(job.ID, "Running worker %d", worker)
 	step.Start()
-	stream, err := job.GetLogs(ctx)
+	for {
+		stalled, err := streamWorkerReports(ctx, job, logs, ui, stalls, worker, since, ch)
+		if progress.leaseErr() != nil {
+			step.Complete()
+			return nil
+		}
+		if err != nil {
+			step.Fail(err)
+			_ = tearDownBenchmarkWorker(job, interrupt, worker, timeout)
+			return err
+		}
+		if stalled {
+			step.Fail(errWorkerStalled)
+			return deleteStalledWorker(job, interrupt, worker, timeout)
+		}
+		if !recovery.restarted(ctx, job) {
+			break
+		}
+		ui.Log(job.ID, fmt.Sprintf("Worker %d restarted", worker))
+		if err := recovery.recover(ctx, &job, worker, timeout); err != nil {
+			step.Fail(err)
+			_ = tearDownBenchmarkWorker(job, interrupt, worker, timeout)
+			return err
+		}
+		// The logs of the restarted worker begin once it's recovered
+		since = time.Time{}
+	}
+	step.Complete()
+	return tearDownBenchmarkWorker(job, interrupt, worker, timeout)
+}
+
+// streamWorkerReports sends the reports written by a running worker since the given time to the given channel
+// until its log stream is closed, returning whether the stream was closed because the worker stalled
+func streamWorkerReports(ctx context.Context, job job.Job[benchmark.Config], logs logging.Sink, ui benchmarkUI, stalls *stallDetector, worker int, since time.Time, ch chan<- workerReport) (bool, error) {
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	defer cancelStream()
+	stream, err := job.GetLogsSince(streamCtx, since)
 	if err != nil {
-		step.Fail(err)
-		return err
+		return false, err
 	}
-	defer stream.Close()
+
+	// A stalled worker is restarted by closing its log stream
+	restart := &atomic.Bool{}
+	monitorCh := stalls.monitor(streamCtx, job, worker, func() {
+		restart.Store(true)
+		cancelStream()
+	})
 
 	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReportSize)
 	for scanner.Scan() {
+		_ = logs.Write(job.ID, scanner.Text())
 		var report benchmark.Report
 		if err := json.Unmarshal(scanner.Bytes(), &report); err == nil {
 			ch <- workerReport{
 				Report: report,
 				worker: worker,
 			}
+		} else {
+			ui.Log(job.ID, tagWorkerLine(worker, scanner.Text()))
 		}
 	}
+	stream.Close()
+	cancelStream()
+	<-monitorCh
+	return restart.Load(), nil
+}
+
+// deleteStalledWorker deletes the given stalled worker so it can be recreated, returning errWorkerStalled once
+// its pod is gone
+// Stalled workers are not shut down gracefully, since they may never finish their in-flight iterations.
+func deleteStalledWorker(job job.Job[benchmark.Config], interrupt *interruptHandler, worker int, timeout time.Duration) error {
+	step := logging.NewStep(job.ID, "Deleting stalled worker %d", worker)
+	step.Start()
+	if err := deleteJob(job, step, interrupt, timeout); err != nil {
+		step.Fail(err)
+		return err
+	}
+	ctx, cancel := newCleanupContext(timeout)
+	defer cancel()
+	if err := job.AwaitDeleted(ctx); err != nil {
+		step.Fail(err)
+		return err
+	}
 	step.Complete()
+	return errWorkerStalled
+}
 
-	step = logging.NewStep(job.ID, "Tearing down worker %d", worker)
+// tearDownBenchmarkWorker stops the given worker and deletes its job
+func tearDownBenchmarkWorker(job job.Job[benchmark.Config], interrupt *interruptHandler, worker int, timeout time.Duration) error {
+	step := logging.NewStep(job.ID, "Tearing down worker %d", worker)
 	step.Start()
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := newCleanupContext(timeout)
 	defer cancel()
-	if err := job.Echo(ctx, shutdownFile, []byte(job.ID)); err != nil {
+	if err := shutdownWorker(ctx, job); err != nil {
 		step.Fail(err)
+		_ = deleteJob(job, step, interrupt, timeout)
 		return err
 	}
+	interrupt.record("Stopped worker %s", job.ID)
 	if _, _, err := job.GetStatus(ctx); err != nil {
 		step.Fail(err)
+		_ = deleteJob(job, step, interrupt, timeout)
 		return err
 	}
-	if err := job.Delete(ctx, step); err != nil {
+	if err := deleteJob(job, step, interrupt, timeout); err != nil {
 		step.Fail(err)
 		return err
 	}
@@ -416,14 +1574,63 @@ func runBenchmarkWorker(ctx context.Context, job job.Job[benchmark.Config], work
 	return nil
 }
 
-func tearDownBenchmark(job job.Job[benchmark.Config], timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// shutdownWorker stops the worker via its shutdown RPC, falling back to releasing it through the control endpoint
+func shutdownWorker(ctx context.Context, job job.Job[benchmark.Config]) error {
+	if err := shutdownWorkerRPC(ctx, job); err == nil {
+		return nil
+	}
+	return job.Release(ctx)
+}
+
+func shutdownWorkerRPC(ctx context.Context, job job.Job[benchmark.Config]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	conn, err := dialWorker(ctx, job)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return benchmark.ShutdownWorker(ctx, conn)
+}
+
+// configureWorker pushes the given configuration to a running worker
+func configureWorker(ctx context.Context, job job.Job[benchmark.Config], worker int, config benchmark.WorkerConfig) error {
+	job.ID = getWorkerID(job.ID, worker)
+	return pushWorkerConfig(ctx, job, config)
+}
+
+// dialWorker connects to the worker's gRPC services through a port forwarded until the context is done
+func dialWorker(ctx context.Context, job job.Job[benchmark.Config]) (*grpc.ClientConn, error) {
+	port, err := job.Forward(ctx, benchmark.WorkerPort)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.DialContext(ctx, fmt.Sprintf("localhost:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// tearDownBenchmarks runs the teardown job for each of the given setup jobs unless teardown is disabled
+func tearDownBenchmarks(jobs []job.Job[benchmark.Config], logs logging.Sink, interrupt *interruptHandler, timeout time.Duration) error {
+	for _, job := range jobs {
+		if job.Config.NoTeardown {
+			keepNamespace(job, interrupt)
+			continue
+		}
+		if err := tearDownBenchmark(job, logs, interrupt, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tearDownBenchmark(job job.Job[benchmark.Config], logs logging.Sink, interrupt *interruptHandler, timeout time.Duration) error {
+	ctx, cancel := newCleanupContext(timeout)
 	defer cancel()
 	job.Config.Type = benchmark.TearDownType
+	job.Config.RunID = job.ID
 	job.CreateNamespace = false
 	step := logging.NewStep(job.ID, "Tearing down benchmark")
 	step.Start()
-	if err := runJob(ctx, job, step); err != nil {
+	if err := runJob(ctx, job, logs, step, interrupt, timeout); err != nil {
 		step.Fail(err)
 		return err
 	}
@@ -431,7 +1638,78 @@ func tearDownBenchmark(job job.Job[benchmark.Config], timeout time.Duration) err
 	return nil
 }
 
+// getErrorRate returns the fraction of iterations that failed
+func getErrorRate(iterations, errors int) float64 {
+	if iterations+errors == 0 {
+		return 0
+	}
+	return float64(errors) / float64(iterations+errors)
+}
+
+// formatErrors formats an error count and error rate for display
+func formatErrors(iterations, errors int) string {
+	return fmt.Sprintf("%d (%.2f%%)", errors, getErrorRate(iterations, errors)*100)
+}
+
+// formatRate formats a target rate for display, or "-" if the benchmark is not rate limited
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%f/sec", rate)
+}
+
+// getMetricNames returns the sorted names of all custom counters and gauges in the given reports
+func getMetricNames(reports []*workerReport) ([]string, []string) {
+	counterNames := make(map[string]bool)
+	gaugeNames := make(map[string]bool)
+	for _, report := range reports {
+		if report != nil {
+			for name := range report.Counters {
+				counterNames[name] = true
+			}
+			for name := range report.Gauges {
+				gaugeNames[name] = true
+			}
+		}
+	}
+	var counters, gauges []string
+	for name := range counterNames {
+		counters = append(counters, name)
+	}
+	for name := range gaugeNames {
+		gauges = append(gauges, name)
+	}
+	sort.Strings(counters)
+	sort.Strings(gauges)
+	return counters, gauges
+}
+
+// formatMetricNames formats custom metric names as additional table columns
+func formatMetricNames(counters, gauges []string) string {
+	var columns string
+	for _, name := range append(counters, gauges...) {
+		columns += "\t" + strings.ToUpper(name)
+	}
+	return columns
+}
+
+// formatMetrics formats custom metric values as additional table columns
+func formatMetrics(counterValues, gaugeValues map[string]float64, counters, gauges []string) string {
+	var columns string
+	for _, name := range counters {
+		columns += fmt.Sprintf("\t%g", counterValues[name])
+	}
+	for _, name := range gauges {
+		columns += fmt.Sprintf("\t%g", gaugeValues[name])
+	}
+	return columns
+}
+
 type workerReport struct {
 	benchmark.Report
 	worker int
+	// stalls and restarts are the number of times the worker stalled and was restarted
+	stalls   int
+	restarts int
 }
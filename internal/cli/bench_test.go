@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func TestWithWorkerImage(t *testing.T) {
+	j := job.Job[benchmark.Config]{
+		ID:              "bench",
+		Image:           "onosproject/helmit-runner:latest",
+		ImagePullPolicy: corev1.PullIfNotPresent,
+	}
+
+	worker := withWorkerImage(newWorkerJobs(j), "", "")(0)
+	assert.Equal(t, "onosproject/helmit-runner:latest", worker.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, worker.ImagePullPolicy)
+
+	worker = withWorkerImage(newWorkerJobs(j), "atomix/benchmark-workers:latest", "")(1)
+	assert.Equal(t, "atomix/benchmark-workers:latest", worker.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, worker.ImagePullPolicy)
+
+	worker = withWorkerImage(newWorkerJobs(j), "atomix/benchmark-workers:latest", corev1.PullAlways)(1)
+	assert.Equal(t, "atomix/benchmark-workers:latest", worker.Image)
+	assert.Equal(t, corev1.PullAlways, worker.ImagePullPolicy)
+
+	// The job that sets up the benchmark keeps its image
+	assert.Equal(t, "onosproject/helmit-runner:latest", j.Image)
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"sync"
+)
+
+// benchmarkPlan is a logging.Sink that reads the benchmarks matching the --benchmark pattern from the output of
+// the setup jobs
+type benchmarkPlan struct {
+	benchmarks []string
+	mu         sync.Mutex
+}
+
+// Write parses a line of setup job output
+func (p *benchmarkPlan) Write(_ string, line string) error {
+	var plan benchmark.Plan
+	if err := json.Unmarshal([]byte(line), &plan); err == nil && len(plan.Benchmarks) > 0 {
+		p.mu.Lock()
+		p.benchmarks = plan.Benchmarks
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+func (p *benchmarkPlan) Close() error {
+	return nil
+}
+
+// getBenchmarks returns the benchmarks reported by the setup jobs
+// If no benchmarks were reported, e.g. because the benchmark was resumed, the given name is returned if set.
+func (p *benchmarkPlan) getBenchmarks(name string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.benchmarks) > 0 {
+		return p.benchmarks
+	}
+	if name != "" {
+		return []string{name}
+	}
+	return nil
+}
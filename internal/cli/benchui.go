@@ -0,0 +1,524 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/console"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	plainUI       = "plain"
+	interactiveUI = "interactive"
+)
+
+const (
+	// rateStep is the fraction by which the rate is changed by the rate key bindings
+	rateStep       = 0.1
+	sparklineWidth = 30
+	maxLogLines    = 1000
+)
+
+var sparklineChars = []rune("▁▂▃▄▅▆▇█")
+
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// benchmarkUI displays the progress of a running benchmark
+type benchmarkUI interface {
+	// Update updates the display with the latest reports from all workers and the report that was just received
+	Update(reports []*workerReport, report workerReport)
+	// Log displays a line of output from the given worker job
+	Log(job string, line string)
+	// Stopped returns a channel that is closed when the user requests that the benchmark be stopped
+	Stopped() <-chan struct{}
+	// Configured returns a channel on which changes to the worker configuration requested by the user are sent
+	Configured() <-chan benchmark.WorkerConfig
+	// Close closes the display
+	Close() error
+}
+
+// newBenchmarkUI creates a new benchmark UI of the given type
+func newBenchmarkUI(uiType string, benchID string, workers int, config benchmark.Config) (benchmarkUI, error) {
+	switch uiType {
+	case plainUI:
+		return newPlainBenchmarkUI(benchID), nil
+	case interactiveUI:
+		return newInteractiveBenchmarkUI(benchID, workers, config)
+	}
+	return nil, fmt.Errorf("unknown UI %q", uiType)
+}
+
+// checkTerminal returns an error if the interactive UI cannot be used on the current console
+func checkTerminal() error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return errors.New("the interactive UI requires a terminal")
+	}
+	return nil
+}
+
+// newPlainBenchmarkUI returns a plain UI for the given benchmark
+// When stdout is not a terminal, e.g. in CI, the reports are logged as they're received rather than rewritten in
+// place.
+func newPlainBenchmarkUI(benchID string) benchmarkUI {
+	sink := logging.NewConsoleSink(os.Stdout, logging.VerboseLevel)
+	if !console.IsTerminal(os.Stdout) {
+		return &logBenchmarkUI{
+			benchID: benchID,
+			out:     os.Stdout,
+			console: sink,
+		}
+	}
+	return &plainBenchmarkUI{
+		writer:  console.NewReporter(os.Stdout, benchID),
+		console: sink,
+	}
+}
+
+// plainBenchmarkUI rewrites a table of the worker reports in place on the console
+type plainBenchmarkUI struct {
+	writer  console.Reporter
+	console logging.Sink
+}
+
+func (ui *plainBenchmarkUI) Update(reports []*workerReport, _ workerReport) {
+	writeReports(ui.writer, reports)
+	_ = ui.writer.Flush()
+}
+
+func (ui *plainBenchmarkUI) Log(job string, line string) {
+	_ = ui.console.Write(job, line)
+}
+
+func (ui *plainBenchmarkUI) Stopped() <-chan struct{} {
+	return nil
+}
+
+func (ui *plainBenchmarkUI) Configured() <-chan benchmark.WorkerConfig {
+	return nil
+}
+
+func (ui *plainBenchmarkUI) Close() error {
+	return nil
+}
+
+// logBenchmarkUI logs a line for each worker report, headed by a timestamp and the benchmark ID, and writes the
+// table of the latest worker reports once the benchmark is done
+type logBenchmarkUI struct {
+	benchID string
+	out     io.Writer
+	console logging.Sink
+	reports []*workerReport
+}
+
+func (ui *logBenchmarkUI) Update(reports []*workerReport, report workerReport) {
+	ui.reports = reports
+	fmt.Fprintf(ui.out, "%s %s worker %d: %d iterations, %s errors, %f/sec, %s mean latency, %s 99%% latency\n",
+		time.Now().Format(time.RFC3339), ui.benchID, report.worker, report.Iterations,
+		formatErrors(report.Iterations, report.Errors), getThroughput(report.Report), report.MeanLatency, report.P99Latency)
+}
+
+func (ui *logBenchmarkUI) Log(job string, line string) {
+	_ = ui.console.Write(job, line)
+}
+
+func (ui *logBenchmarkUI) Stopped() <-chan struct{} {
+	return nil
+}
+
+func (ui *logBenchmarkUI) Configured() <-chan benchmark.WorkerConfig {
+	return nil
+}
+
+func (ui *logBenchmarkUI) Close() error {
+	if ui.reports != nil {
+		writeReports(ui.out, ui.reports)
+	}
+	return nil
+}
+
+func newInteractiveBenchmarkUI(benchID string, workers int, config benchmark.Config) (benchmarkUI, error) {
+	if err := checkTerminal(); err != nil {
+		return nil, err
+	}
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	ui := &interactiveBenchmarkUI{
+		benchID:     benchID,
+		out:         os.Stdout,
+		state:       state,
+		reports:     make([]*workerReport, workers),
+		throughput:  make([][]float64, workers),
+		latency:     make([][]float64, workers),
+		stoppedCh:   make(chan struct{}),
+		configCh:    make(chan benchmark.WorkerConfig, 1),
+		parallelism: config.Parallelism,
+		rate:        config.Rate,
+	}
+	ui.prevWriter = logging.SetWriter(&uiLogWriter{ui: ui})
+	fmt.Fprint(ui.out, "\x1b[?1049h\x1b[?25l")
+	go ui.readInput(os.Stdin)
+	ui.render()
+	return ui, nil
+}
+
+// interactiveBenchmarkUI is a full screen terminal UI displaying per-worker throughput and latency
+// sparklines along with a scrollable pane of worker logs
+type interactiveBenchmarkUI struct {
+	benchID    string
+	out        io.Writer
+	state      *term.State
+	prevWriter io.Writer
+	reports    []*workerReport
+	throughput [][]float64
+	latency    [][]float64
+	logs       []string
+	scroll     int
+	paused     bool
+	stopping   bool
+	closed     bool
+	stoppedCh  chan struct{}
+	configCh   chan benchmark.WorkerConfig
+	// parallelism and rate are the worker configuration, which can be changed while the benchmark is running
+	parallelism int
+	rate        float64
+	mu          sync.Mutex
+}
+
+func (ui *interactiveBenchmarkUI) Update(reports []*workerReport, report workerReport) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	// The worker set may grow while the benchmark is running in adaptive mode
+	for len(ui.reports) < len(reports) {
+		ui.reports = append(ui.reports, nil)
+		ui.throughput = append(ui.throughput, nil)
+		ui.latency = append(ui.latency, nil)
+	}
+	copy(ui.reports, reports)
+	ui.throughput[report.worker] = appendSample(ui.throughput[report.worker], getThroughput(report.Report))
+	ui.latency[report.worker] = appendSample(ui.latency[report.worker], float64(report.P99Latency))
+	ui.renderLocked()
+}
+
+func (ui *interactiveBenchmarkUI) Log(job string, line string) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.appendLogLocked(fmt.Sprintf("%s %s", job, line))
+	ui.renderLocked()
+}
+
+func (ui *interactiveBenchmarkUI) Stopped() <-chan struct{} {
+	return ui.stoppedCh
+}
+
+func (ui *interactiveBenchmarkUI) Configured() <-chan benchmark.WorkerConfig {
+	return ui.configCh
+}
+
+func (ui *interactiveBenchmarkUI) Close() error {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	if ui.closed {
+		return nil
+	}
+	ui.closed = true
+	logging.SetWriter(ui.prevWriter)
+	fmt.Fprint(ui.out, "\x1b[?25h\x1b[?1049l")
+	if err := term.Restore(int(os.Stdin.Fd()), ui.state); err != nil {
+		return err
+	}
+	// Leave the final results on the console once the full screen UI has been closed
+	writeReports(ui.out, ui.reports)
+	return nil
+}
+
+func (ui *interactiveBenchmarkUI) appendLogLocked(line string) {
+	ui.logs = append(ui.logs, ansiRegex.ReplaceAllString(line, ""))
+	if len(ui.logs) > maxLogLines {
+		ui.logs = ui.logs[len(ui.logs)-maxLogLines:]
+	}
+	if ui.scroll > 0 {
+		// Keep the scrolled view stable as new lines arrive
+		ui.scroll++
+	}
+}
+
+// readInput handles key bindings read from the given reader
+func (ui *interactiveBenchmarkUI) readInput(in io.Reader) {
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		ui.handleKey(string(buf[:n]))
+	}
+}
+
+func (ui *interactiveBenchmarkUI) handleKey(key string) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	if ui.closed {
+		return
+	}
+	_, height := ui.getSize()
+	page := height / 2
+	switch key {
+	case "p", " ":
+		ui.paused = !ui.paused
+	case "q", "\x03":
+		if !ui.stopping {
+			ui.stopping = true
+			close(ui.stoppedCh)
+		}
+	case "k", "\x1b[A":
+		ui.scrollLocked(1)
+	case "j", "\x1b[B":
+		ui.scrollLocked(-1)
+	case "\x1b[5~":
+		ui.scrollLocked(page)
+	case "\x1b[6~":
+		ui.scrollLocked(-page)
+	case "G", "\x1b[F":
+		ui.scroll = 0
+	case "+", "=":
+		ui.configureLocked(benchmark.WorkerConfig{Parallelism: ui.parallelism + 1})
+	case "-", "_":
+		if ui.parallelism > 1 {
+			ui.configureLocked(benchmark.WorkerConfig{Parallelism: ui.parallelism - 1})
+		}
+	case "]":
+		if ui.rate > 0 {
+			ui.configureLocked(benchmark.WorkerConfig{Rate: ui.rate * (1 + rateStep)})
+		}
+	case "[":
+		if ui.rate > 0 {
+			ui.configureLocked(benchmark.WorkerConfig{Rate: ui.rate * (1 - rateStep)})
+		}
+	default:
+		return
+	}
+	ui.paused = ui.paused && !ui.stopping
+	ui.renderLocked()
+}
+
+// configureLocked requests that the given configuration be pushed to the running workers
+// Requests are dropped while a previous request is pending.
+func (ui *interactiveBenchmarkUI) configureLocked(config benchmark.WorkerConfig) {
+	if ui.stopping {
+		return
+	}
+	select {
+	case ui.configCh <- config:
+	default:
+		return
+	}
+	if config.Parallelism > 0 {
+		ui.parallelism = config.Parallelism
+	}
+	if config.Rate > 0 {
+		ui.rate = config.Rate
+	}
+	ui.appendLogLocked(fmt.Sprintf("%s Configuring workers with parallelism %d%s", ui.benchID, ui.parallelism, ui.getRateLocked()))
+}
+
+func (ui *interactiveBenchmarkUI) getRateLocked() string {
+	if ui.rate == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" and rate %s", formatRate(ui.rate))
+}
+
+func (ui *interactiveBenchmarkUI) scrollLocked(lines int) {
+	ui.scroll += lines
+	if ui.scroll > len(ui.logs)-1 {
+		ui.scroll = len(ui.logs) - 1
+	}
+	if ui.scroll < 0 {
+		ui.scroll = 0
+	}
+}
+
+func (ui *interactiveBenchmarkUI) getSize() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 120, 40
+	}
+	return width, height
+}
+
+func (ui *interactiveBenchmarkUI) render() {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	ui.renderLocked()
+}
+
+func (ui *interactiveBenchmarkUI) renderLocked() {
+	if ui.closed {
+		return
+	}
+	if ui.paused {
+		// Only the status line is updated while paused
+		ui.renderHeaderLocked()
+		return
+	}
+
+	width, height := ui.getSize()
+	var lines []string
+	lines = append(lines, ui.getHeaderLocked())
+	lines = append(lines, "")
+
+	var table bytes.Buffer
+	writer := new(tabwriter.Writer)
+	writer.Init(&table, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "WORKER\tITERATIONS\tERRORS\tTHROUGHPUT\t\t99% LATENCY\t")
+	for worker, report := range ui.reports {
+		if report == nil {
+			fmt.Fprintf(writer, "%d\t-\t-\t-\t\t-\t\n", worker)
+			continue
+		}
+		fmt.Fprintf(writer, "%d\t%d\t%s\t%.2f/sec\t%s\t%s\t%s\n",
+			worker, report.Iterations, formatErrors(report.Iterations, report.Errors),
+			getThroughput(report.Report), sparkline(ui.throughput[worker]),
+			report.P99Latency, sparkline(ui.latency[worker]))
+	}
+	writer.Flush()
+	lines = append(lines, strings.Split(strings.TrimRight(table.String(), "\n"), "\n")...)
+	lines = append(lines, "")
+
+	title := " logs "
+	if ui.scroll > 0 {
+		title = fmt.Sprintf(" logs (scrolled %d) ", ui.scroll)
+	}
+	rule := width - len(title) - 2
+	if rule < 0 {
+		rule = 0
+	}
+	lines = append(lines, "──"+title+strings.Repeat("─", rule))
+
+	logHeight := height - len(lines)
+	if logHeight > 0 {
+		end := len(ui.logs) - ui.scroll
+		start := end - logHeight
+		if start < 0 {
+			start = 0
+		}
+		lines = append(lines, ui.logs[start:end]...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	for i, line := range lines {
+		if i >= height {
+			break
+		}
+		if i > 0 {
+			buf.WriteString("\r\n")
+		}
+		buf.WriteString(truncate(line, width))
+	}
+	_, _ = ui.out.Write(buf.Bytes())
+}
+
+func (ui *interactiveBenchmarkUI) renderHeaderLocked() {
+	width, _ := ui.getSize()
+	fmt.Fprintf(ui.out, "\x1b[H\x1b[2K%s", truncate(ui.getHeaderLocked(), width))
+}
+
+func (ui *interactiveBenchmarkUI) getHeaderLocked() string {
+	status := "running"
+	if ui.stopping {
+		status = "stopping"
+	} else if ui.paused {
+		status = "paused"
+	}
+	keys := "p: pause  +/-: parallelism  ↑/↓ PgUp/PgDn: scroll logs  q: stop"
+	if ui.rate > 0 {
+		keys = "p: pause  +/-: parallelism  [/]: rate  ↑/↓ PgUp/PgDn: scroll logs  q: stop"
+	}
+	return fmt.Sprintf("helmit bench %s [%s] %s   parallelism %d%s   %s",
+		ui.benchID, status, time.Now().Format(time.Kitchen), ui.parallelism, ui.getRateLocked(), keys)
+}
+
+// uiLogWriter writes step output to the interactive UI log pane
+type uiLogWriter struct {
+	ui  *interactiveBenchmarkUI
+	buf []byte
+	mu  sync.Mutex
+}
+
+func (w *uiLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := strings.TrimSpace(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+		w.ui.mu.Lock()
+		w.ui.appendLogLocked(line)
+		w.ui.renderLocked()
+		w.ui.mu.Unlock()
+	}
+}
+
+// appendSample appends a sample to the given history, retaining only enough samples for a sparkline
+func appendSample(samples []float64, sample float64) []float64 {
+	samples = append(samples, sample)
+	if len(samples) > sparklineWidth {
+		samples = samples[len(samples)-sparklineWidth:]
+	}
+	return samples
+}
+
+// sparkline renders the given samples as a sparkline scaled between the minimum and maximum sample
+func sparkline(samples []float64) string {
+	if len(samples) == 0 {
+		return ""
+	}
+	minSample, maxSample := samples[0], samples[0]
+	for _, sample := range samples {
+		if sample < minSample {
+			minSample = sample
+		}
+		if sample > maxSample {
+			maxSample = sample
+		}
+	}
+	var sb strings.Builder
+	for _, sample := range samples {
+		i := 0
+		if maxSample > minSample {
+			i = int((sample - minSample) / (maxSample - minSample) * float64(len(sparklineChars)-1))
+		}
+		sb.WriteRune(sparklineChars[i])
+	}
+	return sb.String()
+}
+
+// truncate truncates the given line to the given number of runes
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogBenchmarkUI(t *testing.T) {
+	out := &bytes.Buffer{}
+	ui := &logBenchmarkUI{
+		benchID: "bench-1",
+		out:     out,
+		console: logging.NewConsoleSink(out, logging.VerboseLevel),
+	}
+
+	// Nothing is written on close until a report has been received
+	assert.NoError(t, ui.Close())
+	assert.Empty(t, out.String())
+
+	report := &workerReport{
+		Report: benchmark.Report{
+			Iterations: 100,
+			Duration:   time.Second,
+			P99Latency: time.Millisecond,
+		},
+		worker: 0,
+	}
+	ui.Update([]*workerReport{report, nil}, *report)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], " bench-1 worker 0: 100 iterations")
+
+	// The final table of worker reports is written when the UI is closed
+	out.Reset()
+	assert.NoError(t, ui.Close())
+	assert.True(t, strings.HasPrefix(out.String(), "WORKER"))
+	assert.Contains(t, out.String(), "TOTAL")
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/spf13/cobra"
+	"io"
+	"time"
+)
+
+const cleanupExamples = `
+  # Delete all resources left behind by helmit runs.
+  helmit cleanup
+
+  # Delete resources left behind by helmit runs started more than two hours ago.
+  helmit cleanup --older-than 2h
+
+  # List the resources that would be deleted without deleting them.
+  helmit cleanup --older-than 2h --dry-run
+
+  # Resume the teardown of namespaces stuck terminating, removing the finalizers blocking their deletion.
+  helmit cleanup --force-teardown --teardown-grace-period 0s
+`
+
+func getCleanupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cleanup",
+		Short:   "Delete Kubernetes resources left behind by helmit runs",
+		Example: cleanupExamples,
+		Args:    cobra.NoArgs,
+		RunE:    runCleanupCommand,
+	}
+	cmd.Flags().Duration("older-than", 0, "only delete resources created more than the given duration ago")
+	cmd.Flags().Bool("dry-run", false, "list the resources that would be deleted without deleting them")
+	addTeardownFlags(cmd)
+	return cmd
+}
+
+func runCleanupCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	olderThan, _ := cmd.Flags().GetDuration("older-than")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	teardown, err := getTeardown(cmd)
+	if err != nil {
+		return err
+	}
+
+	cleaner, err := job.NewCleaner()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	step := logging.NewStep("cleanup", "Finding helmit resources")
+	step.Start()
+	resources, err := cleaner.Find(ctx, olderThan)
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+	subjects, err := cleaner.FindStaleSubjects(ctx, resources)
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+	step.Complete()
+
+	if len(resources) == 0 && len(subjects) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No resources found")
+		return nil
+	}
+
+	if dryRun {
+		now := time.Now()
+		for _, resource := range resources {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (job %s, created %s ago)\n",
+				resource, resource.Job, now.Sub(resource.Created).Round(time.Second))
+		}
+		for _, subject := range subjects {
+			fmt.Fprintf(cmd.OutOrStdout(), "ClusterRoleBinding subject ServiceAccount %s/%s\n", subject.Namespace, subject.Name)
+		}
+		return nil
+	}
+
+	if err := confirmCluster(cmd); err != nil {
+		return err
+	}
+
+	step = logging.NewStep("cleanup", "Deleting %d helmit resources", len(resources))
+	step.Start()
+	for _, resource := range resources {
+		if err := cleaner.Delete(ctx, resource, step); err != nil {
+			step.Fail(err)
+			return err
+		}
+	}
+	if err := cleaner.RemoveSubjects(ctx, subjects, step); err != nil {
+		step.Fail(err)
+		return err
+	}
+	step.Complete()
+
+	// Namespaces that were already terminating, e.g. because a previous teardown was stuck, are awaited as well,
+	// so the teardown resumes where it left off
+	for _, resource := range resources {
+		if resource.Kind == "Namespace" {
+			if err := awaitTeardown(cmd.OutOrStdout(), "cleanup", resource.Name, teardown); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var errLeaks = errors.New("resources were left behind after teardown")
+
+// checkLeaks reports the resources left behind after the given job was torn down
+// If leaks are found and failOnLeak is set, errLeaks is returned. Failures to scan for leaks are reported but
+// do not fail the run.
+func checkLeaks(out io.Writer, jobID string, namespace string, failOnLeak bool) error {
+	step := logging.NewStep(jobID, "Checking for leaked resources")
+	step.Start()
+	cleaner, err := job.NewCleaner()
+	if err != nil {
+		step.Fail(err)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	leaks, err := cleaner.FindLeaks(ctx, jobID, namespace)
+	if err != nil {
+		step.Fail(err)
+		return nil
+	}
+	if len(leaks) == 0 {
+		step.Complete()
+		return nil
+	}
+	step.Fail(fmt.Errorf("found %d leaked resources", len(leaks)))
+	writeLeaks(out, leaks)
+	if failOnLeak {
+		return errLeaks
+	}
+	return nil
+}
+
+// writeLeaks prints the given leaked resources
+func writeLeaks(out io.Writer, leaks []job.Leak) {
+	fmt.Fprintln(out, "Resources left behind after teardown:")
+	for _, leak := range leaks {
+		fmt.Fprintf(out, "  %s\n", leak)
+	}
+}
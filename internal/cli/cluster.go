@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+)
+
+var errNotConfirmed = errors.New("aborted: the target cluster was not confirmed")
+
+// confirmCluster prints the cluster targeted by the command and, unless --yes is set, asks the user to confirm
+// the cluster before the command modifies it
+// Confirmation is only requested when the input is a terminal so non-interactive runs are not blocked.
+func confirmCluster(cmd *cobra.Command) error {
+	info, err := k8s.GetClusterInfo()
+	if err != nil {
+		return fmt.Errorf("failed to load the Kubernetes configuration: %w", err)
+	}
+	yes, _ := cmd.Flags().GetBool("yes")
+	prompt := !yes && term.IsTerminal(int(os.Stdin.Fd()))
+	return confirmClusterInfo(cmd.InOrStdin(), cmd.ErrOrStderr(), info, prompt)
+}
+
+func confirmClusterInfo(in io.Reader, out io.Writer, info k8s.ClusterInfo, prompt bool) error {
+	if info.Context != "" {
+		fmt.Fprintf(out, "Target cluster: %s (context %s)\n", info.Server, info.Context)
+	} else {
+		fmt.Fprintf(out, "Target cluster: %s (in-cluster configuration)\n", info.Server)
+	}
+	if !prompt {
+		return nil
+	}
+	fmt.Fprint(out, "Continue? [y/N] ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errNotConfirmed
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestConfirmClusterInfo(t *testing.T) {
+	info := k8s.ClusterInfo{Context: "staging", Server: "https://10.0.0.1:6443"}
+
+	var out bytes.Buffer
+	assert.NoError(t, confirmClusterInfo(strings.NewReader(""), &out, info, false))
+	assert.Equal(t, "Target cluster: https://10.0.0.1:6443 (context staging)\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, confirmClusterInfo(strings.NewReader("y\n"), &out, info, true))
+	assert.Equal(t, "Target cluster: https://10.0.0.1:6443 (context staging)\nContinue? [y/N] ", out.String())
+
+	assert.NoError(t, confirmClusterInfo(strings.NewReader("Yes\n"), &out, info, true))
+	assert.Equal(t, errNotConfirmed, confirmClusterInfo(strings.NewReader("\n"), &out, info, true))
+	assert.Equal(t, errNotConfirmed, confirmClusterInfo(strings.NewReader(""), &out, info, true))
+
+	out.Reset()
+	assert.NoError(t, confirmClusterInfo(strings.NewReader(""), &out, k8s.ClusterInfo{Server: "https://10.96.0.1:443"}, false))
+	assert.Equal(t, "Target cluster: https://10.96.0.1:443 (in-cluster configuration)\n", out.String())
+}
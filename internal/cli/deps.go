@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/chart"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// chartRepoURLKey is the key of the repository URL in a --chart-repo-secret
+	chartRepoURLKey = "url"
+	// chartRepoUsernameKey is the key of the repository username in a --chart-repo-secret
+	chartRepoUsernameKey = "username"
+	// chartRepoPasswordKey is the key of the repository password in a --chart-repo-secret
+	chartRepoPasswordKey = "password"
+	// dependencyTimeout is the time allowed to load the credentials of chart repositories
+	dependencyTimeout = time.Minute
+)
+
+// addChartDependencyFlags adds the flags for building the dependencies of the charts in the --context to the
+// given command
+func addChartDependencyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("chart-repo-secret", []string{}, "an existing Kubernetes secret in the format [{namespace}/]{name} with the url, username, and password of a private chart repository or OCI registry from which to download chart dependencies")
+	cmd.Flags().Bool("no-dependency-build", false, "copy the --context as is, without building the missing dependencies of the charts in it")
+}
+
+// chartDependencies builds the dependencies of the charts in the context set by the flags added with
+// addChartDependencyFlags
+type chartDependencies struct {
+	repoSecrets []string
+	out         io.Writer
+}
+
+// getChartDependencies returns the chart dependency configuration set by the flags added with
+// addChartDependencyFlags, or nil if dependencies are not built
+func getChartDependencies(cmd *cobra.Command) (*chartDependencies, error) {
+	repoSecrets, _ := cmd.Flags().GetStringArray("chart-repo-secret")
+	noBuild, _ := cmd.Flags().GetBool("no-dependency-build")
+	if noBuild {
+		if len(repoSecrets) > 0 {
+			return nil, errors.New("--chart-repo-secret cannot be used with --no-dependency-build")
+		}
+		return nil, nil
+	}
+	return &chartDependencies{
+		repoSecrets: repoSecrets,
+		out:         cmd.OutOrStdout(),
+	}, nil
+}
+
+// vendor builds the missing dependencies of the charts in the given context directory for the run with the given
+// ID, returning the directory to copy in place of the context
+// If any dependencies are built, they're built in a temporary copy of the context which the caller must remove
+// once the context has been copied, so the user's charts are left untouched. Otherwise, the context is returned
+// as is, and vendoring with nil options is a no-op.
+func (d *chartDependencies) vendor(id string, namespace string, contextPath string) (string, error) {
+	if d == nil || contextPath == "" {
+		return contextPath, nil
+	}
+	charts, err := chart.FindUnresolved(contextPath)
+	if err != nil {
+		return "", err
+	}
+	if len(charts) == 0 {
+		return contextPath, nil
+	}
+
+	step := logging.NewStep(id, "Building chart dependencies")
+	step.Start()
+	repos, err := d.loadRepositories(namespace)
+	if err != nil {
+		step.Fail(err)
+		return "", err
+	}
+	out := logging.NewSinkWriter(id, logging.NewConsoleSink(d.out, logging.VerboseLevel))
+	vendored, err := chart.Vendor(contextPath, charts, repos, out)
+	_ = out.Close()
+	if err != nil {
+		step.Fail(err)
+		return "", err
+	}
+	step.Complete()
+	return vendored, nil
+}
+
+// loadRepositories loads the chart repositories from the --chart-repo-secret secrets, which default to the
+// given namespace
+func (d *chartDependencies) loadRepositories(namespace string) ([]chart.Repository, error) {
+	if len(d.repoSecrets) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyTimeout)
+	defer cancel()
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	var repos []chart.Repository
+	for _, ref := range d.repoSecrets {
+		secret, err := job.LoadSecret(ctx, client, namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		repo, err := newChartRepository(ref, secret)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// newChartRepository returns the chart repository configured by the keys of the referenced secret
+// Repositories are named for the secret, which must include the repository URL.
+func newChartRepository(ref string, secret map[string]string) (chart.Repository, error) {
+	url := secret[chartRepoURLKey]
+	if url == "" {
+		return chart.Repository{}, fmt.Errorf("--chart-repo-secret %s has no %s key", ref, chartRepoURLKey)
+	}
+	return chart.Repository{
+		Name:     "helmit-" + strings.ReplaceAll(ref, "/", "-"),
+		URL:      url,
+		Username: secret[chartRepoUsernameKey],
+		Password: secret[chartRepoPasswordKey],
+	}, nil
+}
+
+// removeVendoredContext removes the given context directory if it's a copy vendored from the given context
+func removeVendoredContext(vendored string, contextPath string) {
+	if vendored != contextPath {
+		_ = os.RemoveAll(vendored)
+	}
+}
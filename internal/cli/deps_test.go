@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetChartDependencies(t *testing.T) {
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--chart-repo-secret", "charts/private"}))
+	deps, err := getChartDependencies(cmd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"charts/private"}, deps.repoSecrets)
+
+	cmd = getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--no-dependency-build"}))
+	deps, err = getChartDependencies(cmd)
+	assert.NoError(t, err)
+	assert.Nil(t, deps)
+
+	vendored, err := deps.vendor("test", "default", "charts")
+	assert.NoError(t, err)
+	assert.Equal(t, "charts", vendored)
+
+	cmd = getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--no-dependency-build", "--chart-repo-secret", "private"}))
+	_, err = getChartDependencies(cmd)
+	assert.Error(t, err)
+}
+
+func TestNewChartRepository(t *testing.T) {
+	repo, err := newChartRepository("charts/private", map[string]string{
+		chartRepoURLKey:      "https://charts.example.com",
+		chartRepoUsernameKey: "user",
+		chartRepoPasswordKey: "secret",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "helmit-charts-private", repo.Name)
+	assert.Equal(t, "https://charts.example.com", repo.URL)
+	assert.Equal(t, "user", repo.Username)
+	assert.Equal(t, "secret", repo.Password)
+
+	_, err = newChartRepository("private", map[string]string{chartRepoUsernameKey: "user"})
+	assert.Error(t, err)
+}
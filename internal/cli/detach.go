@@ -0,0 +1,347 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/helmit/internal/images"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// coordinatorSuffix is appended to the benchmark ID to name the job coordinating a detached benchmark
+	coordinatorSuffix = "-coordinator"
+	// stateSuffix is appended to the coordinator job ID to name the ConfigMap storing the benchmark state
+	stateSuffix = "-state"
+	stateKey    = "state"
+)
+
+// coordinatorFlags are the bench flags that are not forwarded to the coordinator, either because they only
+// apply to the local session or because the coordinator is passed its own values for them
+var coordinatorFlags = map[string]bool{
+	"detach":                true,
+	"coordinator-namespace": true,
+	"id":                    true,
+	"await-executable":      true,
+	"ui":                    true,
+	"log-file":              true,
+	"image":                 true,
+	"arch":                  true,
+	"context":               true,
+	"values":                true,
+	"secret":                true,
+	"secret-from":           true,
+	"tags":                  true,
+	"ldflags":               true,
+	"race":                  true,
+	"no-build-cache":        true,
+	"build-in-cluster":      true,
+	"kubeconfig":            true,
+	"kube-context":          true,
+	"yes":                   true,
+	"before-cluster":        true,
+	"after-cluster":         true,
+	"hook-plugin":           true,
+	"hook-timeout":          true,
+	"chart-repo-secret":     true,
+	"no-dependency-build":   true,
+}
+
+// getCoordinatorArgs returns the command run by the coordinator of a detached benchmark
+// Flags set locally are forwarded to the coordinator, with paths rewritten to the files copied to its pod.
+func getCoordinatorArgs(flags *pflag.FlagSet, benchID string, namespace string, image string, executable string, contextPath string, valueFiles map[string][]string) []string {
+	args := []string{
+		"helmit", "bench",
+		"--id=" + benchID,
+		"--coordinator-namespace=" + namespace,
+		"--image=" + image,
+	}
+	if executable != "" {
+		args = append(args, "--await-executable")
+	}
+	if contextPath != "" {
+		args = append(args, "--context="+filepath.Join(job.HomeDir, job.ContextDir))
+	}
+	for _, release := range sortedKeys(valueFiles) {
+		for _, file := range valueFiles[release] {
+			args = append(args, fmt.Sprintf("--values=%s=%s", release, filepath.Join(job.HomeDir, filepath.Base(file))))
+		}
+	}
+
+	// Flags that differ from their defaults are forwarded, including defaults applied from a project file
+	return append(args, getFlagArgs(flags, func(flag *pflag.Flag) bool {
+		return !coordinatorFlags[flag.Name]
+	})...)
+}
+
+// getFlagArgs returns the command line arguments setting the flags that differ from their defaults and are
+// accepted by the given filter
+func getFlagArgs(flags *pflag.FlagSet, filter func(flag *pflag.Flag) bool) []string {
+	var args []string
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !filter(flag) || flag.Value.String() == flag.DefValue {
+			return
+		}
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range sliceValue.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+		if flag.Value.Type() == "stringToString" {
+			values, _ := flags.GetStringToString(flag.Name)
+			for _, key := range sortedKeys(values) {
+				args = append(args, fmt.Sprintf("--%s=%s=%s", flag.Name, key, values[key]))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	return args
+}
+
+// newCoordinatorJob returns the job of the coordinator that runs the given benchmark job in the cluster
+// The coordinator runs the benchmark with the local flags using the runner image, which includes helmit, so the
+// settings applied to the benchmark's own pods are not applied to the coordinator.
+func newCoordinatorJob(flags *pflag.FlagSet, j job.Job[benchmark.Config], arch string, image string, executable string, contextPath string, valueFiles map[string][]string, createNamespace bool) job.Job[benchmark.Config] {
+	coordinator := j
+	coordinator.ID = j.ID + coordinatorSuffix
+	if createNamespace {
+		coordinator.Namespace = metav1.NamespaceDefault
+	}
+	coordinator.CreateNamespace = false
+	coordinator.DeleteNamespace = false
+	coordinator.NamespaceLabels = nil
+	coordinator.NamespaceAnnotations = nil
+	coordinator.Labels = nil
+	coordinator.Annotations = nil
+	coordinator.Rules = nil
+	coordinator.NodeSelector = nil
+	coordinator.Tolerations = nil
+	coordinator.Affinity = nil
+	coordinator.PriorityClassName = ""
+	coordinator.Sidecars = nil
+	coordinator.SidecarVolumes = nil
+	// The coordinator runs helmit from the runner image, while the --no-copy image is run by the workers
+	coordinator.NoCopy = false
+	coordinator.Image = images.Runner(arch)
+	coordinator.Command = getCoordinatorArgs(flags, j.ID, coordinator.Namespace, image, executable, contextPath, valueFiles)
+	return coordinator
+}
+
+// runDetachedBenchmark starts a coordinator job that runs the benchmark in the cluster and returns once the
+// coordinator is running
+func runDetachedBenchmark(coordinator job.Job[benchmark.Config], benchID string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	step := logging.NewStep(benchID, "Starting benchmark coordinator")
+	step.Start()
+	state, err := newStateStore(coordinator.Namespace, benchID)
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+	// Store the initial state first so the benchmark can be attached to as soon as this command returns
+	if err := state.put(ctx, benchmarkState{Phase: startingPhase, Updated: time.Now()}); err != nil {
+		step.Fail(err)
+		return err
+	}
+	if err := coordinator.Create(ctx, step); err != nil {
+		step.Fail(err)
+		return err
+	}
+	step.Complete()
+
+	fmt.Printf("Benchmark %s is running in namespace %s. To follow its progress, run:\n\n", benchID, coordinator.Namespace)
+	fmt.Printf("  helmit attach %s\n\n", benchID)
+	return nil
+}
+
+// benchmarkPhase is the phase of a detached benchmark
+type benchmarkPhase string
+
+const (
+	startingPhase benchmarkPhase = "Starting"
+	setupPhase    benchmarkPhase = "SettingUp"
+	runningPhase  benchmarkPhase = "Running"
+	tearDownPhase benchmarkPhase = "TearingDown"
+	completePhase benchmarkPhase = "Complete"
+	failedPhase   benchmarkPhase = "Failed"
+)
+
+// benchmarkState is the state of a detached benchmark, persisted by the coordinator so the benchmark can be
+// attached to from another session
+type benchmarkState struct {
+	Phase   benchmarkPhase      `json:"phase"`
+	Reports []*benchmark.Report `json:"reports,omitempty"`
+	Error   string              `json:"error,omitempty"`
+	Updated time.Time           `json:"updated"`
+}
+
+// isDone returns whether the benchmark has completed
+func (s *benchmarkState) isDone() bool {
+	return s.Phase == completePhase || s.Phase == failedPhase
+}
+
+// getWorkerReports returns the reports in the state as worker reports
+func (s *benchmarkState) getWorkerReports() []*workerReport {
+	reports := make([]*workerReport, len(s.Reports))
+	for worker, report := range s.Reports {
+		if report != nil {
+			reports[worker] = &workerReport{
+				Report: *report,
+				worker: worker,
+			}
+		}
+	}
+	return reports
+}
+
+// newStateStore returns a store for the state of the given detached benchmark
+func newStateStore(namespace string, benchID string) (*stateStore, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	return &stateStore{
+		client:    client,
+		namespace: namespace,
+		benchID:   benchID,
+	}, nil
+}
+
+// findStateStore finds the namespace in which the state of the given detached benchmark is stored, returning
+// nil if the benchmark's state was not found
+func findStateStore(ctx context.Context, benchID string) (*stateStore, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	configMaps, err := client.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: job.JobLabel + "=" + benchID + coordinatorSuffix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		store := &stateStore{
+			client:    client,
+			namespace: configMap.Namespace,
+			benchID:   benchID,
+		}
+		if configMap.Name == store.getName() {
+			return store, nil
+		}
+	}
+	return nil, nil
+}
+
+func newClient() (kubernetes.Interface, error) {
+	config, err := k8s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// stateStore stores the state of a detached benchmark in a ConfigMap in the coordinator's namespace
+// The ConfigMap is labeled with the coordinator's job ID, so it's removed by the cleanup command.
+type stateStore struct {
+	client    kubernetes.Interface
+	namespace string
+	benchID   string
+}
+
+func (s *stateStore) getName() string {
+	return s.benchID + coordinatorSuffix + stateSuffix
+}
+
+// update records the benchmark phase and worker reports
+// Failures to store the state are logged rather than failing the benchmark. Updates to a nil store are ignored,
+// so the store is only used when the benchmark is run by a coordinator.
+func (s *stateStore) update(phase benchmarkPhase, reports []*workerReport, err error) {
+	if s == nil {
+		return
+	}
+	state := benchmarkState{
+		Phase:   phase,
+		Updated: time.Now(),
+	}
+	for _, report := range reports {
+		if report != nil {
+			state.Reports = append(state.Reports, &report.Report)
+		} else {
+			state.Reports = append(state.Reports, nil)
+		}
+	}
+	if err != nil {
+		state.Error = err.Error()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := s.put(ctx, state); err != nil {
+		fmt.Printf("Failed to store benchmark state: %s\n", err)
+	}
+}
+
+func (s *stateStore) put(ctx context.Context, state benchmarkState) error {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.getName(),
+			Namespace: s.namespace,
+			Labels:    job.NewLabels(s.benchID + coordinatorSuffix),
+		},
+		Data: map[string]string{
+			stateKey: string(bytes),
+		},
+	}
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	}
+	return err
+}
+
+// get returns the stored benchmark state, or nil if no state has been stored
+func (s *stateStore) get(ctx context.Context) (*benchmarkState, error) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.getName(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	state := &benchmarkState{}
+	if err := json.Unmarshal([]byte(configMap.Data[stateKey]), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// delete deletes the stored benchmark state
+func (s *stateStore) delete(ctx context.Context) error {
+	err := s.client.CoreV1().ConfigMaps(s.namespace).Delete(ctx, s.getName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestCoordinatorArgs(t *testing.T) {
+	cmd := getBenchCommand()
+	assert.NoError(t, cmd.Flags().Parse([]string{
+		"--suite=my-benchmarks",
+		"--workers=3",
+		"--set=store.replicas=3",
+		"--arg=keys=100",
+		"--arg=mode=sync",
+		"--matrix=payloadSize=128,1024",
+		"--ui=plain",
+		"--log-file=bench.log",
+		"--secret=token=secret",
+		"--detach",
+	}))
+
+	args := getCoordinatorArgs(cmd.Flags(), "happy-panda", "default", "onosproject/helmit-runner:latest-amd64",
+		"/tmp/helmit/happy-panda", "/src/context", map[string][]string{"store": {"/src/values.yaml"}})
+	assert.Equal(t, []string{
+		"helmit", "bench",
+		"--id=happy-panda",
+		"--coordinator-namespace=default",
+		"--image=onosproject/helmit-runner:latest-amd64",
+		"--await-executable",
+		"--context=/home/helmit/context",
+		"--values=store=/home/helmit/values.yaml",
+		"--arg=keys=100",
+		"--arg=mode=sync",
+		"--matrix=payloadSize=128,1024",
+		"--set=store.replicas=3",
+		"--suite=my-benchmarks",
+		"--workers=3",
+	}, args)
+}
+
+func TestStateStore(t *testing.T) {
+	ctx := context.Background()
+	store := &stateStore{
+		client:    fake.NewSimpleClientset(),
+		namespace: "default",
+		benchID:   "happy-panda",
+	}
+
+	state, err := store.get(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	store.update(runningPhase, nil, nil)
+	state, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, runningPhase, state.Phase)
+	assert.False(t, state.isDone())
+
+	reports := []*workerReport{{Report: benchmark.Report{Iterations: 10}, worker: 0}, nil}
+	store.update(failedPhase, reports, errors.New("benchmark error rate exceeded"))
+	state, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.True(t, state.isDone())
+	assert.Equal(t, "benchmark error rate exceeded", state.Error)
+	restored := state.getWorkerReports()
+	assert.Len(t, restored, 2)
+	assert.Equal(t, 10, restored[0].Iterations)
+	assert.Nil(t, restored[1])
+
+	assert.NoError(t, store.delete(ctx))
+	state, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, state)
+
+	var nilStore *stateStore
+	nilStore.update(completePhase, nil, nil)
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/pkg/hooks"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	kindProvider     = "kind"
+	minikubeProvider = "minikube"
+	// ephemeralClusterPrefix is prepended to the test ID to name ephemeral clusters
+	ephemeralClusterPrefix = "helmit-"
+	// kindNodeImage is the image of the nodes of kind clusters running a specific Kubernetes version
+	kindNodeImage = "kindest/node"
+)
+
+// addEphemeralClusterFlags adds the flags for running the command against a throwaway cluster to the given command
+func addEphemeralClusterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("ephemeral-cluster", "", "create a throwaway cluster with kind or minikube to run against, and delete it once the run is torn down")
+	cmd.Flags().Int("ephemeral-nodes", 1, "the number of nodes in the --ephemeral-cluster")
+	cmd.Flags().String("ephemeral-k8s-version", "", "the Kubernetes version of the --ephemeral-cluster, e.g. v1.27.3 (defaults to the provider's default)")
+}
+
+// ephemeralCluster is a throwaway cluster created for a run
+type ephemeralCluster struct {
+	provider     string
+	nodes        int
+	version      string
+	artifactsDir string
+	keep         bool
+}
+
+// getEphemeralCluster returns the throwaway cluster configured by the flags added with addEphemeralClusterFlags,
+// or nil if no ephemeral cluster is configured
+// Clusters are kept after the run when keep is set, e.g. with --no-teardown, and the logs of the cluster are
+// collected to the given artifacts directory if the run fails.
+func getEphemeralCluster(cmd *cobra.Command, artifactsDir string, keep bool) (*ephemeralCluster, error) {
+	provider, _ := cmd.Flags().GetString("ephemeral-cluster")
+	nodes, _ := cmd.Flags().GetInt("ephemeral-nodes")
+	version, _ := cmd.Flags().GetString("ephemeral-k8s-version")
+	if provider == "" {
+		if cmd.Flags().Changed("ephemeral-nodes") || version != "" {
+			return nil, errors.New("--ephemeral-nodes and --ephemeral-k8s-version require --ephemeral-cluster")
+		}
+		return nil, nil
+	}
+	if provider != kindProvider && provider != minikubeProvider {
+		return nil, fmt.Errorf("--ephemeral-cluster must be one of %s, %s", kindProvider, minikubeProvider)
+	}
+	if nodes < 1 {
+		return nil, errors.New("--ephemeral-nodes must be positive")
+	}
+	if cmd.Flags().Changed("kubeconfig") || cmd.Flags().Changed("kube-context") {
+		return nil, errors.New("--ephemeral-cluster cannot be used with --kubeconfig or --kube-context")
+	}
+	if version != "" && !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return &ephemeralCluster{
+		provider:     provider,
+		nodes:        nodes,
+		version:      version,
+		artifactsDir: artifactsDir,
+		keep:         keep,
+	}, nil
+}
+
+// getName returns the name of the cluster created for the run with the given ID
+func (c *ephemeralCluster) getName(id string) string {
+	return ephemeralClusterPrefix + id
+}
+
+// getKubeconfig returns the path of the kubeconfig file for the cluster created for the run with the given ID
+// Clusters are written to their own kubeconfig file so the user's configuration is left untouched.
+func (c *ephemeralCluster) getKubeconfig(id string) string {
+	return filepath.Join(os.TempDir(), "helmit", id+".kubeconfig")
+}
+
+// createHook returns the hook creating the cluster and selecting it for the run
+func (c *ephemeralCluster) createHook() hook {
+	return hook{
+		name: c.provider + " cluster",
+		step: fmt.Sprintf("Creating %s cluster", c.provider),
+		run: func(ctx context.Context, env hooks.Env) error {
+			kubeconfig := c.getKubeconfig(env.ID)
+			if err := os.MkdirAll(filepath.Dir(kubeconfig), 0755); err != nil {
+				return err
+			}
+			command, err := c.newCommand(ctx, env, c.getCreateArgs(env.ID)...)
+			if err != nil {
+				return err
+			}
+			if c.provider == kindProvider {
+				command.Stdin = strings.NewReader(getKindConfig(c.nodes))
+			}
+			if err := command.Run(); err != nil {
+				return fmt.Errorf("failed to create %s cluster %s: %w", c.provider, c.getName(env.ID), err)
+			}
+			return k8s.SetConfig(kubeconfig, "")
+		},
+	}
+}
+
+// deleteHook returns the hook deleting the cluster, collecting its logs first if the run failed
+func (c *ephemeralCluster) deleteHook() hook {
+	return hook{
+		name: c.provider + " cluster",
+		step: fmt.Sprintf("Deleting %s cluster", c.provider),
+		run: func(ctx context.Context, env hooks.Env) error {
+			if env.Failed && c.artifactsDir != "" {
+				if command, err := c.newCommand(ctx, env, c.getExportLogsArgs(env.ID)...); err == nil {
+					if err := os.MkdirAll(c.artifactsDir, 0755); err != nil {
+						return err
+					}
+					if err := command.Run(); err != nil {
+						fmt.Fprintf(env.Out, "Failed to collect the logs of cluster %s: %s\n", c.getName(env.ID), err)
+					}
+				}
+			}
+			if c.keep {
+				fmt.Fprintf(env.Out, "Kept %s cluster %s (--no-teardown), with kubeconfig %s\n", c.provider, c.getName(env.ID), c.getKubeconfig(env.ID))
+				return nil
+			}
+			command, err := c.newCommand(ctx, env, c.getDeleteArgs(env.ID)...)
+			if err != nil {
+				return err
+			}
+			if err := command.Run(); err != nil {
+				return fmt.Errorf("failed to delete %s cluster %s: %w", c.provider, c.getName(env.ID), err)
+			}
+			return os.RemoveAll(c.getKubeconfig(env.ID))
+		},
+	}
+}
+
+// newCommand returns a command running the provider's CLI with the given arguments
+// minikube writes to the kubeconfig file in KUBECONFIG, so the cluster's file is passed in the environment.
+func (c *ephemeralCluster) newCommand(ctx context.Context, env hooks.Env, args ...string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(c.provider)
+	if err != nil {
+		return nil, fmt.Errorf("--ephemeral-cluster %s requires %s to be installed: %w", c.provider, c.provider, err)
+	}
+	command := exec.CommandContext(ctx, path, args...)
+	command.Env = append(os.Environ(), "KUBECONFIG="+c.getKubeconfig(env.ID))
+	command.Stdout = env.Out
+	command.Stderr = env.Out
+	return command, nil
+}
+
+// getCreateArgs returns the arguments with which the provider's CLI creates the cluster
+func (c *ephemeralCluster) getCreateArgs(id string) []string {
+	switch c.provider {
+	case kindProvider:
+		args := []string{"create", "cluster", "--name", c.getName(id), "--kubeconfig", c.getKubeconfig(id), "--config", "-", "--wait", "5m"}
+		if c.version != "" {
+			args = append(args, "--image", kindNodeImage+":"+c.version)
+		}
+		return args
+	default:
+		args := []string{"start", "--profile", c.getName(id), "--nodes", strconv.Itoa(c.nodes), "--wait", "all"}
+		if c.version != "" {
+			args = append(args, "--kubernetes-version", c.version)
+		}
+		return args
+	}
+}
+
+// getDeleteArgs returns the arguments with which the provider's CLI deletes the cluster
+func (c *ephemeralCluster) getDeleteArgs(id string) []string {
+	switch c.provider {
+	case kindProvider:
+		return []string{"delete", "cluster", "--name", c.getName(id), "--kubeconfig", c.getKubeconfig(id)}
+	default:
+		return []string{"delete", "--profile", c.getName(id)}
+	}
+}
+
+// getExportLogsArgs returns the arguments with which the provider's CLI writes the cluster's logs to the
+// artifacts directory
+func (c *ephemeralCluster) getExportLogsArgs(id string) []string {
+	dir := filepath.Join(c.artifactsDir, c.getName(id))
+	switch c.provider {
+	case kindProvider:
+		return []string{"export", "logs", dir, "--name", c.getName(id)}
+	default:
+		return []string{"logs", "--profile", c.getName(id), "--file", dir + ".log"}
+	}
+}
+
+// getKindConfig returns the kind cluster configuration for a cluster with the given number of nodes
+// The first node runs the control plane and the remaining nodes are workers.
+func getKindConfig(nodes int) string {
+	var config strings.Builder
+	config.WriteString("kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n")
+	for i := 1; i < nodes; i++ {
+		config.WriteString("- role: worker\n")
+	}
+	return config.String()
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestEphemeralCluster(t *testing.T) {
+	cluster, err := getEphemeralCluster(getTestCommand(), "", false)
+	assert.NoError(t, err)
+	assert.Nil(t, cluster)
+
+	for _, args := range [][]string{
+		{"--ephemeral-cluster", "k3d"},
+		{"--ephemeral-cluster", "kind", "--ephemeral-nodes", "0"},
+		{"--ephemeral-nodes", "3"},
+		{"--ephemeral-k8s-version", "1.27.3"},
+	} {
+		cmd := getTestCommand()
+		assert.NoError(t, cmd.ParseFlags(args))
+		_, err := getEphemeralCluster(cmd, "", false)
+		assert.Error(t, err, args)
+	}
+
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--ephemeral-cluster", "kind", "--ephemeral-nodes", "3", "--ephemeral-k8s-version", "1.27.3"}))
+	cluster, err = getEphemeralCluster(cmd, "artifacts", false)
+	assert.NoError(t, err)
+	kubeconfig := cluster.getKubeconfig("happy-panda")
+	assert.Equal(t, []string{"create", "cluster", "--name", "helmit-happy-panda", "--kubeconfig", kubeconfig, "--config", "-", "--wait", "5m", "--image", "kindest/node:v1.27.3"},
+		cluster.getCreateArgs("happy-panda"))
+	assert.Equal(t, []string{"delete", "cluster", "--name", "helmit-happy-panda", "--kubeconfig", kubeconfig},
+		cluster.getDeleteArgs("happy-panda"))
+	assert.Equal(t, []string{"export", "logs", filepath.Join("artifacts", "helmit-happy-panda"), "--name", "helmit-happy-panda"},
+		cluster.getExportLogsArgs("happy-panda"))
+	assert.Equal(t, "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n- role: worker\n- role: worker\n",
+		getKindConfig(cluster.nodes))
+
+	cmd = getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--ephemeral-cluster", "minikube", "--ephemeral-nodes", "2"}))
+	cluster, err = getEphemeralCluster(cmd, "", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"start", "--profile", "helmit-happy-panda", "--nodes", "2", "--wait", "all"},
+		cluster.getCreateArgs("happy-panda"))
+	assert.Equal(t, []string{"delete", "--profile", "helmit-happy-panda"}, cluster.getDeleteArgs("happy-panda"))
+
+	// The cluster is created before and deleted after all other hooks
+	hooks, err := getHooks(cmd, cluster)
+	assert.NoError(t, err)
+	if assert.Len(t, hooks.before, 1) && assert.Len(t, hooks.after, 1) {
+		assert.Equal(t, "Creating minikube cluster", hooks.before[0].step)
+		assert.Equal(t, "Deleting minikube cluster", hooks.after[0].step)
+	}
+}
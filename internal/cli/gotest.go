@@ -0,0 +1,210 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// goTestKubeconfigPath is the path of the kubeconfig written in the test pod for go tests
+	goTestKubeconfigPath = job.HomeDir + "/.kube/config"
+	// goTestKubeContext is the name of the context in the kubeconfig written for go tests
+	goTestKubeContext = "helmit"
+	// goTestHelmDriver is the Helm storage driver with which helmit stores releases
+	goTestHelmDriver = "configmap"
+)
+
+// goTestKubeconfig is the kubeconfig written in the test pod for go tests, which authenticates as the pod's
+// service account and defaults to the test namespace
+const goTestKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: helmit
+  cluster:
+    server: https://kubernetes.default.svc
+    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+users:
+- name: helmit
+  user:
+    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token
+contexts:
+- name: helmit
+  context:
+    cluster: helmit
+    user: helmit
+    namespace: %s
+current-context: helmit
+`
+
+// goTestExcludedFlags are the test flags that only apply to helmit suites and cannot be used with --gotest
+var goTestExcludedFlags = []string{
+	"suite",
+	"method",
+	"test-tags",
+	"until-failure",
+	"values",
+	"set",
+	"set-secret",
+	"arg",
+	"context",
+	"artifacts-dir",
+	"collect-fixtures",
+	"local",
+	"no-copy",
+	"entrypoint",
+}
+
+// validateGoTestFlags returns an error if flags that only apply to helmit suites are set with --gotest
+func validateGoTestFlags(cmd *cobra.Command, pkgPaths []string) error {
+	if len(pkgPaths) == 0 {
+		return errors.New("must specify the packages to test with --gotest")
+	}
+	for _, name := range goTestExcludedFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s cannot be used with --gotest", name)
+		}
+	}
+	if tests, _ := cmd.Flags().GetStringSlice("test"); cmd.Flags().Changed("test") && len(tests) != 1 {
+		return errors.New("--test must be a single -run pattern with --gotest")
+	}
+	return nil
+}
+
+// getGoTestModule returns the root directory of the module containing the current directory and the path of the
+// current directory relative to it, from which go test is run in the module source copied to the test pod
+func getGoTestModule() (string, string, error) {
+	output, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to locate module: %w", err)
+	}
+	goMod := strings.TrimSpace(string(output))
+	if goMod == "" || goMod == os.DevNull {
+		return "", "", errors.New("--gotest must be run from within a Go module")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+	moduleDir := filepath.Dir(goMod)
+	dir, err := filepath.Rel(moduleDir, wd)
+	if err != nil {
+		return "", "", err
+	}
+	return moduleDir, dir, nil
+}
+
+// getGoTestArgs returns the arguments with which to run go test on the given packages
+func getGoTestArgs(pkgPaths []string, options build.Options, run string, iterations int, failFast bool, timeout time.Duration) []string {
+	args := []string{"test", "-json", "-count", strconv.Itoa(iterations), "-timeout", timeout.String()}
+	if run != "" {
+		args = append(args, "-run", run)
+	}
+	if failFast {
+		args = append(args, "-failfast")
+	}
+	args = append(args, options.Flags()...)
+	return append(args, pkgPaths...)
+}
+
+// getGoTestScript returns the script run in the test pod to run go test with the given arguments in the given
+// directory of the source copied to the pod
+func getGoTestScript(dir string, namespace string, args []string) string {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	script.WriteString("set -e\n")
+	script.WriteString("mkdir -p \"$(dirname \"$KUBECONFIG\")\"\n")
+	script.WriteString("cat > \"$KUBECONFIG\" <<'EOF'\n")
+	fmt.Fprintf(&script, goTestKubeconfig, namespace)
+	script.WriteString("EOF\n")
+	fmt.Fprintf(&script, "cd %s\n", quoteShell(path.Join(job.HomeDir, job.SourceDir, filepath.ToSlash(dir))))
+	script.WriteString("exec go")
+	for _, arg := range args {
+		script.WriteString(" " + quoteShell(arg))
+	}
+	script.WriteString("\n")
+	return script.String()
+}
+
+// getGoTestEnv returns the environment providing the Kubernetes and Helm context to go tests run in the given
+// namespace
+func getGoTestEnv(namespace string) map[string]string {
+	return map[string]string{
+		"KUBECONFIG":       goTestKubeconfigPath,
+		"HELM_KUBECONTEXT": goTestKubeContext,
+		"HELM_NAMESPACE":   namespace,
+		"HELM_DRIVER":      goTestHelmDriver,
+	}
+}
+
+// quoteShell quotes the given string for use as a single word in a shell command
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// goTestEvent is an event written by go test -json
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+}
+
+// newGoTestSink returns a logging.Sink that converts the events written by go test -json to verbose go test
+// output written to the given sink
+func newGoTestSink(sink logging.Sink) logging.Sink {
+	return &goTestSink{sink: sink}
+}
+
+// goTestSink is a logging.Sink that converts the events written by go test -json to verbose go test output
+// Test names are qualified with the names of their packages, like suites from multiple packages, so the results
+// are summarized by package. Lines that are not test events, e.g. the output of go downloading modules, are written as is.
+type goTestSink struct {
+	sink logging.Sink
+}
+
+func (s *goTestSink) Write(job string, line string) error {
+	var event goTestEvent
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+		return s.sink.Write(job, line)
+	}
+	if event.Action != "output" {
+		return nil
+	}
+	output := strings.TrimSuffix(event.Output, "\n")
+	if event.Package != "" {
+		output = qualifyTestOutput(output, path.Base(event.Package))
+	}
+	return s.sink.Write(job, output)
+}
+
+func (s *goTestSink) Close() error {
+	return s.sink.Close()
+}
+
+// qualifyTestOutput qualifies the name of the test in the given line of verbose go test output with the given
+// package
+func qualifyTestOutput(line string, pkg string) string {
+	for _, regex := range []*regexp.Regexp{testEventRegex, testResultRegex} {
+		if match := regex.FindStringSubmatchIndex(line); match != nil {
+			return line[:match[4]] + pkg + "." + line[match[4]:]
+		}
+	}
+	return line
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const goTestEvents = `go: downloading github.com/stretchr/testify v1.8.1
+{"Action":"start","Package":"example.com/tests/maps"}
+{"Action":"run","Package":"example.com/tests/maps","Test":"TestMap"}
+{"Action":"output","Package":"example.com/tests/maps","Test":"TestMap","Output":"=== RUN   TestMap\n"}
+{"Action":"output","Package":"example.com/tests/maps","Test":"TestMap","Output":"    map_test.go:42: key not found\n"}
+{"Action":"output","Package":"example.com/tests/maps","Test":"TestMap","Output":"--- FAIL: TestMap (0.25s)\n"}
+{"Action":"fail","Package":"example.com/tests/maps","Test":"TestMap","Elapsed":0.25}
+{"Action":"output","Package":"example.com/tests/maps","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/tests/maps","Elapsed":0.3}
+{"Action":"run","Package":"example.com/tests/locks","Test":"TestLock"}
+{"Action":"output","Package":"example.com/tests/locks","Test":"TestLock","Output":"=== RUN   TestLock\n"}
+{"Action":"output","Package":"example.com/tests/locks","Test":"TestLock","Output":"--- PASS: TestLock (1.50s)\n"}
+{"Action":"pass","Package":"example.com/tests/locks","Test":"TestLock","Elapsed":1.5}
+`
+
+func TestGoTestSink(t *testing.T) {
+	summary := newTestSummary()
+	var out bytes.Buffer
+	sink := newGoTestSink(logging.NewTeeSink(logging.NewConsoleSink(&out, logging.InfoLevel), summary))
+	for _, line := range strings.Split(strings.TrimSpace(goTestEvents), "\n") {
+		assert.NoError(t, sink.Write("test", line))
+	}
+
+	output := out.String()
+	assert.Contains(t, output, "go: downloading github.com/stretchr/testify v1.8.1")
+	assert.Contains(t, output, "=== RUN   maps.TestMap")
+	assert.Contains(t, output, "--- FAIL: maps.TestMap (0.25s)")
+	assert.NotContains(t, output, `"Action"`)
+
+	results := summary.getLeafResults()
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "maps.TestMap", results[0].name)
+		assert.Equal(t, testStatusFail, results[0].status)
+		assert.Equal(t, []string{"    map_test.go:42: key not found"}, results[0].output)
+		assert.Equal(t, "locks.TestLock", results[1].name)
+		assert.Equal(t, testStatusPass, results[1].status)
+	}
+
+	var summaryOut bytes.Buffer
+	summary.write(&summaryOut, 2*time.Second)
+	assert.Contains(t, summaryOut.String(), "maps    1 tests, 0 passed, 1 failed, 0 skipped")
+}
+
+func TestGoTestScript(t *testing.T) {
+	args := getGoTestArgs([]string{"./..."}, build.Options{Tags: []string{"integration"}}, "TestMap", 1, true, 5*time.Minute)
+	assert.Equal(t, []string{"test", "-json", "-count", "1", "-timeout", "5m0s", "-run", "TestMap", "-failfast", "-tags", "integration", "./..."}, args)
+
+	script := getGoTestScript("tests", "integration", args)
+	assert.True(t, strings.HasPrefix(script, "#!/bin/sh\n"))
+	assert.Contains(t, script, "namespace: integration\n")
+	assert.Contains(t, script, "cd '/home/helmit/src/tests'\n")
+	assert.Contains(t, script, "exec go 'test' '-json' '-count' '1' '-timeout' '5m0s' '-run' 'TestMap' '-failfast' '-tags' 'integration' './...'\n")
+	assert.Equal(t, `'it'\''s'`, quoteShell("it's"))
+
+	env := getGoTestEnv("tests")
+	assert.Equal(t, goTestKubeconfigPath, env["KUBECONFIG"])
+	assert.Equal(t, "tests", env["HELM_NAMESPACE"])
+	assert.Equal(t, "configmap", env["HELM_DRIVER"])
+}
+
+func TestValidateGoTestFlags(t *testing.T) {
+	cmd := getTestCommand()
+	assert.NoError(t, validateGoTestFlags(cmd, []string{"./..."}))
+	assert.EqualError(t, validateGoTestFlags(cmd, nil), "must specify the packages to test with --gotest")
+
+	assert.NoError(t, cmd.Flags().Set("test", "TestMap"))
+	assert.NoError(t, validateGoTestFlags(cmd, []string{"./..."}))
+	assert.NoError(t, cmd.Flags().Set("test", "TestLock"))
+	assert.EqualError(t, validateGoTestFlags(cmd, []string{"./..."}), "--test must be a single -run pattern with --gotest")
+
+	cmd = getTestCommand()
+	assert.NoError(t, cmd.Flags().Set("suite", "AtomixTestSuite"))
+	assert.EqualError(t, validateGoTestFlags(cmd, []string{"./..."}), "--suite cannot be used with --gotest")
+}
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/hooks"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"plugin"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultHookTimeout is the default time to wait for each provisioning hook to complete
+const defaultHookTimeout = 10 * time.Minute
+
+// addHookFlags adds the flags registering provisioning hooks run by the CLI around the run to the given command
+func addHookFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("before-cluster", []string{}, "a shell command to run before the cluster is used, e.g. to create the cluster or external resources")
+	cmd.Flags().StringArray("after-cluster", []string{}, "a shell command to run once the run has been torn down, whether or not it succeeded")
+	cmd.Flags().StringArray("hook-plugin", []string{}, "a Go plugin exporting BeforeCluster and/or AfterCluster hooks to run around the run")
+	cmd.Flags().Duration("hook-timeout", defaultHookTimeout, "the time to wait for each provisioning hook to complete")
+}
+
+// hook is a provisioning hook run by the CLI
+type hook struct {
+	name string
+	// step is the message logged while the hook runs, if it's not run as a named hook
+	step string
+	run  hooks.Func
+}
+
+// provisioningHooks runs the provisioning hooks registered with the flags added with addHookFlags
+// Before-cluster hooks are run in the order they're registered, and the run is aborted as soon as one fails.
+// After-cluster hooks are run in the reverse order once the run has been torn down, so resources are removed
+// before the resources they depend on. All after-cluster hooks are run, even if some fail.
+type provisioningHooks struct {
+	before  []hook
+	after   []hook
+	timeout time.Duration
+	out     io.Writer
+	env     hooks.Env
+	once    sync.Once
+	err     error
+}
+
+// getHooks returns the provisioning hooks registered with the flags added with addHookFlags, or nil if no hooks
+// are registered
+// Plugins are loaded eagerly so invalid plugins are reported before anything is run. If an ephemeral cluster is
+// given, it's created before all other hooks are run and deleted after.
+func getHooks(cmd *cobra.Command, cluster *ephemeralCluster) (*provisioningHooks, error) {
+	beforeCommands, _ := cmd.Flags().GetStringArray("before-cluster")
+	afterCommands, _ := cmd.Flags().GetStringArray("after-cluster")
+	plugins, _ := cmd.Flags().GetStringArray("hook-plugin")
+	timeout, _ := cmd.Flags().GetDuration("hook-timeout")
+	if timeout <= 0 {
+		return nil, errors.New("--hook-timeout must be positive")
+	}
+	if len(beforeCommands) == 0 && len(afterCommands) == 0 && len(plugins) == 0 && cluster == nil {
+		return nil, nil
+	}
+
+	h := &provisioningHooks{
+		timeout: timeout,
+		out:     cmd.OutOrStdout(),
+		env: hooks.Env{
+			Command: cmd.Name(),
+		},
+	}
+	if cluster != nil {
+		h.before = append(h.before, cluster.createHook())
+		h.after = append(h.after, cluster.deleteHook())
+	}
+	for _, command := range beforeCommands {
+		h.before = append(h.before, newCommandHook(command))
+	}
+	for _, path := range plugins {
+		before, after, err := loadHookPlugin(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		if before != nil {
+			h.before = append(h.before, hook{name: name, run: before})
+		}
+		if after != nil {
+			h.after = append(h.after, hook{name: name, run: after})
+		}
+	}
+	for _, command := range afterCommands {
+		h.after = append(h.after, newCommandHook(command))
+	}
+	return h, nil
+}
+
+// hasAfterCluster returns whether any after-cluster hooks are registered
+func (h *provisioningHooks) hasAfterCluster() bool {
+	return h != nil && len(h.after) > 0
+}
+
+// beforeCluster runs the before-cluster hooks for the run with the given ID and namespace
+func (h *provisioningHooks) beforeCluster(id string, namespace string) error {
+	if h == nil {
+		return nil
+	}
+	h.env.ID = id
+	h.env.Namespace = namespace
+	for _, hook := range h.before {
+		if err := h.run(hook, "before-cluster", h.env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterCluster runs the after-cluster hooks, returning the first error
+// The hooks are only run once, so the hooks can be run as soon as the run is torn down and deferred in case the
+// run fails before then.
+func (h *provisioningHooks) afterCluster(failed bool) error {
+	if h == nil {
+		return nil
+	}
+	h.once.Do(func() {
+		env := h.env
+		env.Failed = failed
+		for i := len(h.after) - 1; i >= 0; i-- {
+			if err := h.run(h.after[i], "after-cluster", env); err != nil && h.err == nil {
+				h.err = err
+			}
+		}
+	})
+	return h.err
+}
+
+// run runs the given hook, printing its output to the console
+func (h *provisioningHooks) run(hook hook, kind string, env hooks.Env) error {
+	step := logging.NewStep(env.ID, "Running %s hook %s", kind, hook.name)
+	if hook.step != "" {
+		step = logging.NewStep(env.ID, "%s", hook.step)
+	}
+	step.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	out := logging.NewSinkWriter(env.ID, logging.NewConsoleSink(h.out, logging.InfoLevel))
+	env.Out = out
+	err := hook.run(ctx, env)
+	_ = out.Close()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("%s hook %s timed out after %s", kind, hook.name, h.timeout)
+		}
+		step.Fail(err)
+		return err
+	}
+	step.Complete()
+	return nil
+}
+
+// newCommandHook returns a hook running the given shell command
+// The environment of the hook is passed to the command in HELMIT_* environment variables.
+func newCommandHook(command string) hook {
+	return hook{
+		name: command,
+		run: func(ctx context.Context, env hooks.Env) error {
+			cmd := exec.CommandContext(ctx, "sh", "-c", command)
+			cmd.Env = append(os.Environ(), getHookEnv(env)...)
+			cmd.Stdout = env.Out
+			cmd.Stderr = env.Out
+			return cmd.Run()
+		},
+	}
+}
+
+// getHookEnv returns the environment variables passed to hook commands
+// Commands are passed the kubeconfig file selected for the run, if any, so they target the same cluster as helmit.
+func getHookEnv(env hooks.Env) []string {
+	vars := []string{
+		"HELMIT_COMMAND=" + env.Command,
+		"HELMIT_ID=" + env.ID,
+		"HELMIT_NAMESPACE=" + env.Namespace,
+		"HELMIT_FAILED=" + strconv.FormatBool(env.Failed),
+	}
+	if kubeconfig := os.Getenv(k8s.KubeconfigEnv); kubeconfig != "" {
+		vars = append(vars, "KUBECONFIG="+kubeconfig)
+	}
+	return vars
+}
+
+// loadHookPlugin loads the before- and after-cluster hooks exported by the Go plugin at the given path
+func loadHookPlugin(path string) (hooks.Func, hooks.Func, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load hook plugin %s: %w", path, err)
+	}
+	before, err := lookupHook(p, path, hooks.BeforeCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := lookupHook(p, path, hooks.AfterCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if before == nil && after == nil {
+		return nil, nil, fmt.Errorf("hook plugin %s exports neither %s nor %s", path, hooks.BeforeCluster, hooks.AfterCluster)
+	}
+	return before, after, nil
+}
+
+// lookupHook returns the named hook exported by the given plugin, or nil if the plugin does not export it
+func lookupHook(p *plugin.Plugin, path string, name string) (hooks.Func, error) {
+	symbol, err := p.Lookup(name)
+	if err != nil {
+		return nil, nil
+	}
+	f, ok := getHookFunc(symbol)
+	if !ok {
+		return nil, fmt.Errorf("%s exported by hook plugin %s is not a hooks.Func", name, path)
+	}
+	return f, nil
+}
+
+// getHookFunc returns the hook for a symbol exported by a plugin
+// Plugins may export a function or a variable, which is looked up as a pointer to the variable.
+func getHookFunc(symbol plugin.Symbol) (hooks.Func, bool) {
+	switch f := symbol.(type) {
+	case func(context.Context, hooks.Env) error:
+		return f, true
+	case *hooks.Func:
+		return *f, *f != nil
+	case *func(context.Context, hooks.Env) error:
+		return *f, *f != nil
+	}
+	return nil, false
+}
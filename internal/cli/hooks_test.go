@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/onosproject/helmit/pkg/hooks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProvisioningHooks(t *testing.T) {
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{
+		"--before-cluster", "echo $HELMIT_COMMAND $HELMIT_ID $HELMIT_NAMESPACE",
+		"--after-cluster", "echo first failed=$HELMIT_FAILED",
+		"--after-cluster", "echo second failed=$HELMIT_FAILED",
+	}))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	h, err := getHooks(cmd, nil)
+	assert.NoError(t, err)
+	assert.True(t, h.hasAfterCluster())
+
+	assert.NoError(t, h.beforeCluster("happy-panda", "default"))
+	assert.Equal(t, "    test happy-panda default\n", out.String())
+
+	// After-cluster hooks are run in reverse order, and only once
+	out.Reset()
+	assert.NoError(t, h.afterCluster(true))
+	assert.NoError(t, h.afterCluster(false))
+	assert.Equal(t, "    second failed=true\n    first failed=true\n", out.String())
+
+	// Failing before-cluster hooks abort the remaining hooks
+	cmd = getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--before-cluster", "exit 1", "--before-cluster", "echo", "--after-cluster", "false"}))
+	cmd.SetOut(&out)
+	h, err = getHooks(cmd, nil)
+	assert.NoError(t, err)
+	out.Reset()
+	assert.Error(t, h.beforeCluster("happy-panda", "default"))
+	assert.Empty(t, out.String())
+	assert.Error(t, h.afterCluster(true))
+
+	// No hooks are run unless they're registered
+	h, err = getHooks(getTestCommand(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, h)
+	assert.False(t, h.hasAfterCluster())
+	assert.NoError(t, h.beforeCluster("happy-panda", "default"))
+	assert.NoError(t, h.afterCluster(true))
+}
+
+func TestHookFunc(t *testing.T) {
+	errHook := errors.New("hook failed")
+	f := func(context.Context, hooks.Env) error {
+		return errHook
+	}
+	hook, ok := getHookFunc(f)
+	assert.True(t, ok)
+	assert.Equal(t, errHook, hook(context.Background(), hooks.Env{}))
+
+	variable := hooks.Func(f)
+	hook, ok = getHookFunc(&variable)
+	assert.True(t, ok)
+	assert.Equal(t, errHook, hook(context.Background(), hooks.Env{}))
+
+	var unset hooks.Func
+	_, ok = getHookFunc(&unset)
+	assert.False(t, ok)
+	_, ok = getHookFunc(func() error { return nil })
+	assert.False(t, ok)
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/images"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/spf13/cobra"
+	"io"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+)
+
+const imagesExamples = `
+  # Print the runner and builder images used by this version of helmit.
+  helmit images
+
+  # Verify the images exist and print references pinned to their digests.
+  helmit images --verify
+
+  # Pull the amd64 images from a mirror, e.g. to load them into a kind cluster.
+  helmit images --pull --arch amd64 --runner-image registry.example.com/helmit-runner --builder-image registry.example.com/helmit-builder
+`
+
+func getImagesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "images",
+		Short:   "Print, pull, or verify the runner and builder images used by helmit",
+		Example: imagesExamples,
+		Args:    cobra.NoArgs,
+		RunE:    runImagesCommand,
+	}
+	cmd.Flags().StringSlice("arch", images.Archs, "the CPU architectures for which to print the images")
+	cmd.Flags().Bool("pull", false, "pull the images with docker")
+	cmd.Flags().Bool("verify", false, "verify the images exist in their registry and print references pinned to their digests")
+	return cmd
+}
+
+// image is a runner or builder image for a CPU architecture
+type image struct {
+	name      string
+	arch      string
+	reference string
+	digest    string
+}
+
+// getPinnedReference returns the reference to the image pinned to its digest, if known
+func (i image) getPinnedReference() string {
+	if i.digest == "" {
+		return i.reference
+	}
+	return images.GetRepository(i.reference) + "@" + i.digest
+}
+
+func runImagesCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	archs, _ := cmd.Flags().GetStringSlice("arch")
+	pull, _ := cmd.Flags().GetBool("pull")
+	verify, _ := cmd.Flags().GetBool("verify")
+	if len(archs) == 0 {
+		return errors.New("--arch must specify at least one architecture")
+	}
+
+	var refs []image
+	for _, arch := range archs {
+		refs = append(refs,
+			image{name: "runner", arch: arch, reference: images.Runner(arch)},
+			image{name: "builder", arch: arch, reference: images.Builder(arch)})
+	}
+
+	for i, image := range refs {
+		if pull {
+			step := logging.NewStep("images", "Pulling %s", image.reference)
+			step.Start()
+			if err := pullImage(image.reference, image.arch); err != nil {
+				step.Fail(err)
+				return err
+			}
+			step.Complete()
+		}
+		if verify {
+			step := logging.NewStep("images", "Verifying %s", image.reference)
+			step.Start()
+			digest, err := inspectImage(image.reference, image.arch)
+			if err != nil {
+				step.Fail(err)
+				return err
+			}
+			refs[i].digest = digest
+			step.Complete()
+		}
+	}
+	writeImages(cmd.OutOrStdout(), images.Version(), refs)
+	return nil
+}
+
+// writeImages prints the given images
+func writeImages(out io.Writer, version string, images []image) {
+	fmt.Fprintf(out, "Image version: %s\n", version)
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "IMAGE\tARCH\tREFERENCE")
+	for _, image := range images {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", image.name, image.arch, image.getPinnedReference())
+	}
+	_ = writer.Flush()
+}
+
+// pullImage pulls the given image for the given architecture with docker
+func pullImage(reference string, arch string) error {
+	output, err := exec.Command("docker", "pull", "--platform", "linux/"+arch, reference).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %s", reference, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// manifestDescriptor is the descriptor of a manifest printed by docker manifest inspect --verbose
+type manifestDescriptor struct {
+	Descriptor struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform"`
+	} `json:"Descriptor"`
+}
+
+// inspectImage verifies the given image exists in its registry for the given architecture and returns the
+// digest of the image for the architecture
+func inspectImage(reference string, arch string) (string, error) {
+	var stderr bytes.Buffer
+	command := exec.Command("docker", "manifest", "inspect", "--verbose", reference)
+	command.Stderr = &stderr
+	output, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %s", reference, strings.TrimSpace(stderr.String()))
+	}
+	return getManifestDigest(reference, output, arch)
+}
+
+// getManifestDigest returns the digest of the image for the given architecture in the output of docker manifest
+// inspect --verbose, which is a single descriptor for images and a list of descriptors for manifest lists
+func getManifestDigest(reference string, output []byte, arch string) (string, error) {
+	var descriptors []manifestDescriptor
+	if err := json.Unmarshal(output, &descriptors); err != nil {
+		var descriptor manifestDescriptor
+		if err := json.Unmarshal(output, &descriptor); err != nil {
+			return "", fmt.Errorf("failed to parse the manifest of %s: %w", reference, err)
+		}
+		// The platform of a single image may not be known, in which case it's assumed to match
+		if descriptor.Descriptor.Platform.Architecture == "" {
+			return descriptor.Descriptor.Digest, nil
+		}
+		descriptors = []manifestDescriptor{descriptor}
+	}
+	var archs []string
+	for _, descriptor := range descriptors {
+		platform := descriptor.Descriptor.Platform
+		if platform.Architecture == arch && (platform.OS == "" || platform.OS == "linux") {
+			return descriptor.Descriptor.Digest, nil
+		}
+		archs = append(archs, platform.Architecture)
+	}
+	return "", fmt.Errorf("%s is not available for %s (found %s)", reference, arch, strings.Join(archs, ", "))
+}
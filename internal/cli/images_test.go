@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const manifestList = `[
+  {"Ref": "onosproject/helmit-runner:latest", "Descriptor": {"digest": "sha256:aaa", "platform": {"architecture": "amd64", "os": "linux"}}},
+  {"Ref": "onosproject/helmit-runner:latest", "Descriptor": {"digest": "sha256:bbb", "platform": {"architecture": "arm64", "os": "linux"}}}
+]`
+
+func TestImages(t *testing.T) {
+	digest, err := getManifestDigest("onosproject/helmit-runner:latest", []byte(manifestList), "arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:bbb", digest)
+	_, err = getManifestDigest("onosproject/helmit-runner:latest", []byte(manifestList), "s390x")
+	assert.EqualError(t, err, "onosproject/helmit-runner:latest is not available for s390x (found amd64, arm64)")
+
+	manifest := `{"Ref": "onosproject/helmit-runner:latest-amd64", "Descriptor": {"digest": "sha256:ccc", "platform": {"architecture": "amd64", "os": "linux"}}}`
+	digest, err = getManifestDigest("onosproject/helmit-runner:latest-amd64", []byte(manifest), "amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "sha256:ccc", digest)
+	_, err = getManifestDigest("onosproject/helmit-runner:latest-amd64", []byte(manifest), "arm64")
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	writeImages(&out, "latest", []image{
+		{name: "runner", arch: "amd64", reference: "onosproject/helmit-runner:latest-amd64", digest: "sha256:ccc"},
+		{name: "builder", arch: "amd64", reference: "onosproject/helmit-builder:latest-amd64"},
+	})
+	assert.Contains(t, out.String(), "runner   amd64  onosproject/helmit-runner@sha256:ccc\n")
+	assert.Contains(t, out.String(), "builder  amd64  onosproject/helmit-builder:latest-amd64\n")
+}
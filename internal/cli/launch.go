@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/onosproject/helmit/pkg/test"
+	"io"
+	"strings"
+	"time"
+)
+
+// RunTests runs the tests of the given job, writing their output to the given writer and collecting their
+// artifacts to the given directory if the job holds them, and returns the exit code of the tests
+// RunTests runs the tests as the test command does, for programs starting jobs through pkg/launcher. If the
+// context is canceled before the tests complete, the job is deleted and the context's error is returned.
+func RunTests(ctx context.Context, job job.Job[test.Config], artifactsDir string, out io.Writer) (int, error) {
+	interrupt := newContextInterruptHandler(ctx)
+	defer interrupt.stop()
+	timeout := job.Config.Timeout
+
+	if err := setUpTestJob(job, interrupt, timeout); err != nil {
+		if interrupt.interrupted() {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	code, err := runTestJob(job, logging.NewConsoleSink(out, logging.InfoLevel), nil, interrupt, artifactsDir, timeout)
+	if err == errInterrupted {
+		return 0, ctx.Err()
+	}
+	return code, err
+}
+
+// BenchmarkOptions are the options with which RunBenchmark runs a benchmark
+type BenchmarkOptions struct {
+	// Workers is the number of workers to run
+	Workers int
+	// Iterations is the number of iterations after which to stop the benchmark, or 0 for no limit
+	Iterations int
+	// Duration is the duration after which to stop the benchmark, excluding the warm-up period, or 0 for no limit
+	Duration time.Duration
+	// MaxErrorRate is the maximum fraction of iterations that may fail before the benchmark fails, or 0 for no limit
+	MaxErrorRate float64
+	// TargetP99 is the 99th percentile latency up to which to add workers, or 0 to run a fixed number of workers
+	TargetP99 time.Duration
+	// MaxWorkers is the maximum number of workers to run when TargetP99 is set
+	MaxWorkers int
+	// SLO is a comma-separated list of SLO expressions, e.g. p99<100ms,errorRate<1%, on the breach of which to
+	// stop the benchmark and fail
+	SLO string
+	// SLOWindow is the window over which metrics are aggregated for the SLO, defaulting to the report interval
+	SLOWindow time.Duration
+	// WorkerStartBatch is the maximum number of workers to start at once, or 0 to start all workers at once
+	WorkerStartBatch int
+	// WorkerStartTimeout is the time allowed for each batch of workers to start, or 0 for no limit
+	WorkerStartTimeout time.Duration
+	// StallIntervals is the number of report intervals without iterations after which a worker is flagged as
+	// stalled, or 0 to disable stall detection
+	StallIntervals int
+	// RestartStalled indicates whether to delete and recreate workers flagged as stalled
+	RestartStalled bool
+	// Output is a writer to which to write the output of the benchmark's jobs, defaulting to io.Discard
+	Output io.Writer
+	// OnReport is an optional function called with each report received from a worker
+	OnReport func(worker int, report benchmark.Report)
+}
+
+// RunBenchmark sets up the benchmark of the given job, runs its workers until the benchmark is complete, and tears
+// it down, returning the last report received from each worker
+// RunBenchmark runs the benchmark as the bench command does, for programs starting jobs through pkg/launcher. The
+// benchmark must match a single benchmark of the suite. If the context is canceled, the workers are stopped and the
+// benchmark is torn down. If the benchmark fails, e.g. because it breached its SLO, the error is returned with the
+// reports received.
+func RunBenchmark(ctx context.Context, job job.Job[benchmark.Config], options BenchmarkOptions) ([]benchmark.Report, error) {
+	if options.Workers < 1 {
+		return nil, errors.New("the number of workers must be positive")
+	}
+	var scaler *adaptiveScaler
+	if options.TargetP99 > 0 {
+		if options.MaxWorkers < options.Workers {
+			return nil, errors.New("the maximum number of workers must be greater than or equal to the number of workers")
+		}
+		scaler = newAdaptiveScaler(options.TargetP99, options.MaxWorkers)
+	}
+	var objectives []sloObjective
+	if options.SLO != "" {
+		var err error
+		if objectives, err = parseSLO(options.SLO); err != nil {
+			return nil, fmt.Errorf("invalid SLO: %w", err)
+		}
+	}
+	if options.WorkerStartTimeout > 0 && options.WorkerStartBatch == 0 {
+		return nil, errors.New("the worker start timeout requires a worker start batch")
+	}
+	if options.RestartStalled && options.StallIntervals == 0 {
+		return nil, errors.New("restarting stalled workers requires stall intervals")
+	}
+	startup := workerStartup{
+		batch:   options.WorkerStartBatch,
+		timeout: options.WorkerStartTimeout,
+	}
+	stalls := newStallDetector(options.StallIntervals, options.RestartStalled)
+	slo := newSLOMonitor(objectives, options.SLOWindow, job.Config.ReportInterval)
+
+	out := options.Output
+	if out == nil {
+		out = io.Discard
+	}
+
+	interrupt := newContextInterruptHandler(ctx)
+	defer interrupt.stop()
+	timeout := job.Config.Timeout
+	jobLogs := logging.NewConsoleSink(out, logging.InfoLevel)
+	setupJobs := getSetupJobs(job, nil)
+
+	plan := &benchmarkPlan{}
+	if err := setupBenchmark(job, logging.NewTeeSink(jobLogs, plan), interrupt, timeout); err != nil {
+		if interrupt.interrupted() {
+			// Tear down the partial setup
+			_ = tearDownBenchmarks(setupJobs, jobLogs, interrupt, timeout)
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	var reports []*workerReport
+	var benchErr error
+	benchmarks := plan.getBenchmarks(job.Config.Benchmark)
+	if len(benchmarks) == 0 {
+		benchErr = errors.New("no benchmarks to run")
+	} else if len(benchmarks) > 1 {
+		benchErr = fmt.Errorf("must run a single benchmark, but %q matches %s", job.Config.Benchmark, strings.Join(benchmarks, ", "))
+	} else {
+		job.Config.Benchmark = benchmarks[0]
+		ui := &launcherUI{
+			console:  logging.NewConsoleSink(out, logging.VerboseLevel),
+			onReport: options.OnReport,
+		}
+		// The output of the workers is written through the UI, so it's not logged separately
+		logs := logging.NewTeeSink()
+		reports, benchErr = runBenchmark(job, benchmarkOptions{
+			getWorkerJob: newWorkerJobs(job),
+			logs:         logs,
+			ui:           ui,
+			interrupt:    interrupt,
+			startup:      startup,
+			scaler:       scaler,
+			stalls:       stalls,
+			slo:          slo,
+			workers:      options.Workers,
+			iterations:   options.Iterations,
+			duration:     options.Duration,
+			maxErrorRate: options.MaxErrorRate,
+			timeout:      timeout,
+		})
+		if benchErr == errBenchmarkInterrupted {
+			benchErr = nil
+		}
+		scaler.writeResult(out)
+	}
+
+	if err := tearDownBenchmarks(setupJobs, jobLogs, interrupt, timeout); err != nil && benchErr == nil {
+		benchErr = err
+	}
+
+	if reports == nil {
+		return nil, benchErr
+	}
+	results := make([]benchmark.Report, len(reports))
+	for i, report := range reports {
+		if report != nil {
+			results[i] = report.Report
+		}
+	}
+	return results, benchErr
+}
+
+// launcherUI passes the reports of a benchmark run through pkg/launcher to the program running the benchmark
+type launcherUI struct {
+	console  logging.Sink
+	onReport func(worker int, report benchmark.Report)
+}
+
+func (ui *launcherUI) Update(_ []*workerReport, report workerReport) {
+	if ui.onReport != nil {
+		ui.onReport(report.worker, report.Report)
+	}
+}
+
+func (ui *launcherUI) Log(job string, line string) {
+	_ = ui.console.Write(job, line)
+}
+
+func (ui *launcherUI) Stopped() <-chan struct{} {
+	return nil
+}
+
+func (ui *launcherUI) Configured() <-chan benchmark.WorkerConfig {
+	return nil
+}
+
+func (ui *launcherUI) Close() error {
+	return nil
+}
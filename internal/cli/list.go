@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/spf13/cobra"
+	"io"
+	"strings"
+)
+
+const listExamples = `
+  # List the test suites, tests, benchmark suites, and benchmarks in a package.
+  helmit list ./cmd/tests
+
+  # List the suites in all packages under a directory.
+  helmit list ./test/...
+
+  # Output the suites as JSON for use by other tools.
+  helmit list ./cmd/tests -o json
+`
+
+const (
+	textOutput = "text"
+	jsonOutput = "json"
+)
+
+const (
+	testPrefix      = "Test"
+	benchmarkPrefix = "Benchmark"
+)
+
+func getListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the test and benchmark suites in Go packages",
+		Example: listExamples,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runListCommand,
+	}
+	cmd.Flags().StringSliceP("suite", "s", []string{".*"}, "regular expressions to filter the names of suites")
+	cmd.Flags().StringP("output", "o", textOutput, "the output format (text or json)")
+	return cmd
+}
+
+type listOutput struct {
+	Tests      []build.Suite `json:"tests"`
+	Benchmarks []build.Suite `json:"benchmarks"`
+}
+
+func runListCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	suites, _ := cmd.Flags().GetStringSlice("suite")
+	output, _ := cmd.Flags().GetString("output")
+	if output != textOutput && output != jsonOutput {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	log := logging.NewLogger(io.Discard)
+	if logging.GetVerbose() && output == textOutput {
+		log = logging.NewLogger(cmd.ErrOrStderr())
+	}
+
+	tests, err := build.Tests(log, suites...).Suites(args...)
+	if err != nil {
+		return err
+	}
+	benchmarks, err := build.Benchmarks(log, suites...).Suites(args...)
+	if err != nil {
+		return err
+	}
+
+	list := listOutput{
+		Tests:      filterMethods(tests, testPrefix),
+		Benchmarks: filterMethods(benchmarks, benchmarkPrefix),
+	}
+
+	out := cmd.OutOrStdout()
+	if output == jsonOutput {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+
+	printSuiteList(out, "Test suites", list.Tests)
+	printSuiteList(out, "Benchmark suites", list.Benchmarks)
+	return nil
+}
+
+// filterMethods filters the methods of the given suites to those with the given prefix
+func filterMethods(suites []build.Suite, prefix string) []build.Suite {
+	filtered := make([]build.Suite, 0, len(suites))
+	for _, suite := range suites {
+		methods := make([]string, 0, len(suite.Methods))
+		for _, method := range suite.Methods {
+			if strings.HasPrefix(method, prefix) {
+				methods = append(methods, method)
+			}
+		}
+		suite.Methods = methods
+		filtered = append(filtered, suite)
+	}
+	return filtered
+}
+
+func printSuiteList(out io.Writer, title string, suites []build.Suite) {
+	if len(suites) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%s:\n", title)
+	for _, suite := range suites {
+		fmt.Fprintf(out, "  %s (%s)\n", suite.Name, suite.Package)
+		for _, method := range suite.Methods {
+			fmt.Fprintf(out, "    %s\n", method)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+const logsExamples = `
+  # Print the logs of the jobs started by a test or benchmark run.
+  helmit logs happy-panda
+
+  # Stream the logs of a running benchmark's workers until they complete.
+  helmit logs happy-panda --follow
+`
+
+func getLogsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "logs <id>",
+		Short:   "Print the logs of a test or benchmark running in the cluster",
+		Example: logsExamples,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runLogsCommand,
+	}
+	cmd.Flags().BoolP("follow", "f", false, "stream the logs until the jobs have completed")
+	return cmd
+}
+
+func runLogsCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	monitor, err := job.NewMonitor()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	statuses, err := monitor.Find(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if len(statuses) == 0 {
+		return fmt.Errorf("no jobs found for %s", args[0])
+	}
+
+	// Lines are prefixed with the job ID when the logs of more than one job are printed
+	out := &lineWriter{out: cmd.OutOrStdout()}
+	prefix := len(statuses) > 1
+	if !follow {
+		for _, status := range statuses {
+			if err := copyLogs(ctx, monitor, status, false, out, prefix); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, len(statuses))
+	for _, status := range statuses {
+		wg.Add(1)
+		go func(status job.Status) {
+			defer wg.Done()
+			if err := copyLogs(ctx, monitor, status, true, out, prefix); err != nil && ctx.Err() == nil {
+				errCh <- err
+			}
+		}(status)
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+// copyLogs copies the logs of the given job to the writer line by line
+func copyLogs(ctx context.Context, monitor *job.Monitor, status job.Status, follow bool, out *lineWriter, prefix bool) error {
+	stream, err := monitor.GetLogs(ctx, status, follow)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		if prefix {
+			out.writeLine(fmt.Sprintf("[%s] %s", status.ID, scanner.Text()))
+		} else {
+			out.writeLine(scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// lineWriter writes whole lines from concurrent streams without interleaving them
+type lineWriter struct {
+	out io.Writer
+	mu  sync.Mutex
+}
+
+func (w *lineWriter) writeLine(line string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.out, line)
+}
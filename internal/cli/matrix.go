@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// matrix is a set of benchmark arguments and the values for which to run the benchmark
+type matrix []matrixParam
+
+// matrixParam is a benchmark argument and the values for which to run the benchmark
+type matrixParam struct {
+	name   string
+	values []string
+}
+
+// parseMatrix parses matrix parameters in the format {name}={value},...
+func parseMatrix(params []string) (matrix, error) {
+	var m matrix
+	names := make(map[string]bool)
+	for _, param := range params {
+		index := strings.Index(param, "=")
+		if index <= 0 || index == len(param)-1 {
+			return nil, errors.New("matrix parameters must be in the format {name}={value},...")
+		}
+		name := param[:index]
+		if names[name] {
+			return nil, fmt.Errorf("duplicate matrix parameter %s", name)
+		}
+		names[name] = true
+		m = append(m, matrixParam{
+			name:   name,
+			values: strings.Split(param[index+1:], ","),
+		})
+	}
+	return m, nil
+}
+
+// combinations returns every combination of the matrix parameter values, varying the last parameter fastest
+func (m matrix) combinations() []matrixValues {
+	combinations := []matrixValues{nil}
+	for _, param := range m {
+		var next []matrixValues
+		for _, combination := range combinations {
+			for _, value := range param.values {
+				values := append(append(matrixValues{}, combination...), matrixValue{name: param.name, value: value})
+				next = append(next, values)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// matrixValues is a single combination of matrix parameter values
+type matrixValues []matrixValue
+
+type matrixValue struct {
+	name  string
+	value string
+}
+
+// apply returns a copy of the given benchmark arguments with the matrix values set
+func (v matrixValues) apply(args map[string]string) map[string]string {
+	applied := make(map[string]string)
+	for name, value := range args {
+		applied[name] = value
+	}
+	for _, value := range v {
+		applied[value.name] = value.value
+	}
+	return applied
+}
+
+func (v matrixValues) String() string {
+	values := make([]string, 0, len(v))
+	for _, value := range v {
+		values = append(values, fmt.Sprintf("%s=%s", value.name, value.value))
+	}
+	return strings.Join(values, ", ")
+}
+
+// matrixResult is the result of running a benchmark for a combination of matrix values
+// The benchmark is only set when multiple benchmarks are run.
+type matrixResult struct {
+	benchmark string
+	params    matrixValues
+	reports   []*workerReport
+	err       error
+}
+
+// label returns the label identifying the run in benchmark reports
+func (r matrixResult) label() string {
+	switch {
+	case r.benchmark == "":
+		return r.params.String()
+	case len(r.params) == 0:
+		return r.benchmark
+	default:
+		return fmt.Sprintf("%s (%s)", r.benchmark, r.params)
+	}
+}
+
+func (r matrixResult) String() string {
+	switch {
+	case r.benchmark == "":
+		return fmt.Sprintf("benchmark with %s", r.params)
+	case len(r.params) == 0:
+		return r.benchmark
+	default:
+		return fmt.Sprintf("%s with %s", r.benchmark, r.params)
+	}
+}
+
+// writeMatrixResults writes a table comparing the total reports for each benchmark and combination of matrix values
+func writeMatrixResults(out io.Writer, m matrix, results []matrixResult) {
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', tabwriter.FilterHTML)
+
+	benchmarks := len(results) > 0 && results[0].benchmark != ""
+	if benchmarks {
+		fmt.Fprint(writer, "BENCHMARK\t")
+	}
+	for _, param := range m {
+		fmt.Fprintf(writer, "%s\t", strings.ToUpper(param.name))
+	}
+	fmt.Fprintln(writer, "ITERATIONS\tERRORS\tTHROUGHPUT\tMEAN LATENCY\tMEDIAN LATENCY\t95% LATENCY\t99% LATENCY\tRESULT")
+	for _, result := range results {
+		if benchmarks {
+			fmt.Fprintf(writer, "%s\t", result.benchmark)
+		}
+		for _, value := range result.params {
+			fmt.Fprintf(writer, "%s\t", value.value)
+		}
+		status := "ok"
+		if result.err != nil {
+			status = result.err.Error()
+		}
+		total, count := sumReports(result.reports)
+		if count == 0 {
+			fmt.Fprintf(writer, "-\t-\t-\t-\t-\t-\t-\t%s\n", status)
+			continue
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%f/sec\t%s\t%s\t%s\t%s\t%s\n",
+			total.Iterations, formatErrors(total.Iterations, total.Errors), getThroughput(total),
+			total.MeanLatency, total.P50Latency, total.P95Latency, total.P99Latency, status)
+	}
+	writer.Flush()
+}
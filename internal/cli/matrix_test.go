@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatrix(t *testing.T) {
+	m, err := parseMatrix([]string{"payloadSize=128,1024", "keys=10,100,1000"})
+	assert.NoError(t, err)
+
+	combinations := m.combinations()
+	assert.Len(t, combinations, 6)
+	assert.Equal(t, "payloadSize=128, keys=10", combinations[0].String())
+	assert.Equal(t, "payloadSize=128, keys=100", combinations[1].String())
+	assert.Equal(t, "payloadSize=1024, keys=1000", combinations[5].String())
+
+	args := map[string]string{"keys": "1", "mode": "sync"}
+	assert.Equal(t, map[string]string{"keys": "10", "mode": "sync", "payloadSize": "128"}, combinations[0].apply(args))
+	assert.Equal(t, map[string]string{"keys": "1", "mode": "sync"}, args)
+
+	_, err = parseMatrix([]string{"payloadSize"})
+	assert.Error(t, err)
+	_, err = parseMatrix([]string{"payloadSize=1", "payloadSize=2"})
+	assert.Error(t, err)
+
+	var out bytes.Buffer
+	writeMatrixResults(&out, m[:1], []matrixResult{
+		{
+			params: matrixValues{{name: "payloadSize", value: "128"}},
+			reports: []*workerReport{
+				{Report: benchmark.Report{Iterations: 100, Duration: time.Second, MeanLatency: time.Millisecond}},
+				{Report: benchmark.Report{Iterations: 100, Duration: time.Second, MeanLatency: 3 * time.Millisecond}, worker: 1},
+			},
+		},
+		{
+			params: matrixValues{{name: "payloadSize", value: "1024"}},
+			err:    errBenchmarkInterrupted,
+		},
+	})
+	assert.Contains(t, out.String(), "PAYLOADSIZE")
+	assert.Contains(t, out.String(), "200")
+	assert.Contains(t, out.String(), "2ms")
+	assert.Contains(t, out.String(), "benchmark interrupted")
+}
+
+func TestBenchmarkResults(t *testing.T) {
+	params := matrixValues{{name: "keys", value: "100"}}
+	assert.Equal(t, "benchmark with keys=100", matrixResult{params: params}.String())
+	assert.Equal(t, "keys=100", matrixResult{params: params}.label())
+	assert.Equal(t, "BenchmarkGet", matrixResult{benchmark: "BenchmarkGet"}.String())
+	assert.Equal(t, "BenchmarkGet", matrixResult{benchmark: "BenchmarkGet"}.label())
+	assert.Equal(t, "BenchmarkGet with keys=100", matrixResult{benchmark: "BenchmarkGet", params: params}.String())
+	assert.Equal(t, "BenchmarkGet (keys=100)", matrixResult{benchmark: "BenchmarkGet", params: params}.label())
+
+	var out bytes.Buffer
+	writeMatrixResults(&out, nil, []matrixResult{
+		{
+			benchmark: "BenchmarkGet",
+			reports: []*workerReport{
+				{Report: benchmark.Report{Iterations: 300, Duration: time.Second, MeanLatency: time.Millisecond}},
+			},
+		},
+		{
+			benchmark: "BenchmarkPut",
+			err:       errors.New("worker failed"),
+		},
+	})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "BENCHMARK"))
+	assert.True(t, strings.HasPrefix(lines[1], "BenchmarkGet"))
+	assert.Contains(t, lines[1], "300")
+	assert.True(t, strings.HasPrefix(lines[2], "BenchmarkPut"))
+	assert.Contains(t, lines[2], "worker failed")
+}
+
+func TestBenchmarkPlan(t *testing.T) {
+	plan := &benchmarkPlan{}
+	assert.Nil(t, plan.getBenchmarks(""))
+	assert.Equal(t, []string{"BenchmarkGet"}, plan.getBenchmarks("BenchmarkGet"))
+
+	assert.NoError(t, plan.Write("test", "Installing chart"))
+	assert.NoError(t, plan.Write("test", `{"iterations":100}`))
+	assert.Nil(t, plan.getBenchmarks(""))
+	assert.NoError(t, plan.Write("test", `{"benchmarks":["BenchmarkGet","BenchmarkPut"]}`))
+	assert.Equal(t, []string{"BenchmarkGet", "BenchmarkPut"}, plan.getBenchmarks("Benchmark(Get|Put)"))
+}
@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/spf13/pflag"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// instanceTypeLabel is the well-known label identifying the instance type of a node
+const instanceTypeLabel = "node.kubernetes.io/instance-type"
+
+// metadataTimeout is the time allowed for reading the metadata of a benchmark from the cluster
+const metadataTimeout = 30 * time.Second
+
+// redacted replaces the values of secret flags in the arguments recorded in benchmark metadata
+const redacted = "<redacted>"
+
+// secretFlags are the bench flags whose values are redacted from the arguments recorded in benchmark metadata
+var secretFlags = map[string]bool{
+	"secret":     true,
+	"set-secret": true,
+}
+
+// getBenchmarkMetadata returns the metadata of a benchmark run with the given package paths and flags
+// The charts are read from the Helm releases in the given namespaces. Metadata that cannot be determined, e.g.
+// because the user is not allowed to list nodes, is left empty rather than failing the benchmark.
+func getBenchmarkMetadata(ctx context.Context, client kubernetes.Interface, pkgPaths []string, flags *pflag.FlagSet, namespaces []string) report.Metadata {
+	metadata := report.Metadata{
+		HelmitVersion: getHelmitVersion(),
+		Args:          getMetadataArgs(pkgPaths, flags),
+	}
+	metadata.GitCommit, _ = flags.GetString("git-commit")
+	if version, err := client.Discovery().ServerVersion(); err == nil {
+		metadata.KubernetesVersion = version.GitVersion
+	}
+	metadata.Nodes, _ = getNodeGroups(ctx, client)
+	metadata.Charts, _ = getCharts(client, namespaces)
+	return metadata
+}
+
+// getSetupMetadata returns the metadata of a benchmark run with the given package paths and flags in the cluster
+// in which the given jobs set up the benchmark
+func getSetupMetadata(flags *pflag.FlagSet, pkgPaths []string, setupJobs []job.Job[benchmark.Config]) report.Metadata {
+	client, err := newClient()
+	if err != nil {
+		return report.Metadata{
+			HelmitVersion: getHelmitVersion(),
+			Args:          getMetadataArgs(pkgPaths, flags),
+		}
+	}
+	namespaces := make([]string, 0, len(setupJobs))
+	for _, setupJob := range setupJobs {
+		namespaces = append(namespaces, setupJob.Namespace)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+	return getBenchmarkMetadata(ctx, client, pkgPaths, flags, namespaces)
+}
+
+// getHelmitVersion returns the version of the running helmit binary
+// Binaries built from a source checkout rather than installed at a version report the commit they were built from.
+func getHelmitVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	version := info.Main.Version
+	if version == "" || version == "(devel)" {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return "devel-" + setting.Value
+			}
+		}
+	}
+	return version
+}
+
+// getGitCommit returns the commit checked out in the directory of the given package path, suffixed with "-dirty"
+// if the working tree has uncommitted changes, or an empty string if the package is not in a git repository
+func getGitCommit(pkgPath string) string {
+	if info, err := os.Stat(pkgPath); err != nil || !info.IsDir() {
+		return ""
+	}
+	out, err := exec.Command("git", "-C", pkgPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	commit := strings.TrimSpace(string(out))
+	status, err := exec.Command("git", "-C", pkgPath, "status", "--porcelain").Output()
+	if err == nil && len(bytes.TrimSpace(status)) > 0 {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+// getMetadataArgs returns the package paths and the flags that differ from their defaults, with the values of
+// secret flags redacted
+// Hidden flags are internal to helmit and are not included.
+func getMetadataArgs(pkgPaths []string, flags *pflag.FlagSet) []string {
+	args := append([]string{}, pkgPaths...)
+	for _, arg := range getFlagArgs(flags, func(flag *pflag.Flag) bool { return !flag.Hidden }) {
+		name, value, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if secretFlags[name] {
+			key, _, _ := strings.Cut(value, "=")
+			arg = "--" + name + "=" + key + "=" + redacted
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+// getNodeGroups returns the nodes of the cluster grouped by their type
+func getNodeGroups(ctx context.Context, client kubernetes.Interface) ([]report.NodeGroup, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var groups []report.NodeGroup
+	counts := make(map[report.NodeGroup]int)
+	for _, node := range nodes.Items {
+		group := report.NodeGroup{
+			InstanceType: node.Labels[instanceTypeLabel],
+			Arch:         node.Labels[k8s.ArchLabel],
+			CPU:          node.Status.Capacity.Cpu().String(),
+			Memory:       node.Status.Capacity.Memory().String(),
+		}
+		if counts[group] == 0 {
+			groups = append(groups, group)
+		}
+		counts[group]++
+	}
+	for i, group := range groups {
+		groups[i].Count = counts[group]
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].InstanceType != groups[j].InstanceType {
+			return groups[i].InstanceType < groups[j].InstanceType
+		}
+		return groups[i].Arch < groups[j].Arch
+	})
+	return groups, nil
+}
+
+// getCharts returns the charts of the Helm releases deployed in the given namespaces
+func getCharts(client kubernetes.Interface, namespaces []string) ([]report.Chart, error) {
+	var charts []report.Chart
+	seen := make(map[string]bool)
+	for _, namespace := range namespaces {
+		if seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		releases, err := driver.NewConfigMaps(client.CoreV1().ConfigMaps(namespace)).List(func(r *release.Release) bool {
+			return r.Info != nil && r.Info.Status == release.StatusDeployed && r.Chart != nil && r.Chart.Metadata != nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range releases {
+			charts = append(charts, report.Chart{
+				Namespace:  namespace,
+				Release:    r.Name,
+				Name:       r.Chart.Metadata.Name,
+				Version:    r.Chart.Metadata.Version,
+				AppVersion: r.Chart.Metadata.AppVersion,
+			})
+		}
+	}
+	sort.Slice(charts, func(i, j int) bool {
+		if charts[i].Namespace != charts[j].Namespace {
+			return charts[i].Namespace < charts[j].Namespace
+		}
+		return charts[i].Release < charts[j].Release
+	})
+	return charts, nil
+}
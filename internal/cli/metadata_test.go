@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"path/filepath"
+	"testing"
+)
+
+func newMetadataNode(name string, instanceType string, cpu string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				instanceTypeLabel:    instanceType,
+				"kubernetes.io/arch": "amd64",
+			},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+}
+
+func newMetadataRelease(name string, namespace string, status release.Status) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   1,
+		Info:      &release.Info{Status: status},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "atomix", Version: "1.2.0", AppVersion: "1.2.0"},
+		},
+	}
+}
+
+func TestGetBenchmarkMetadata(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newMetadataNode("foo", "m5.large", "2"),
+		newMetadataNode("bar", "m5.xlarge", "4"),
+		newMetadataNode("baz", "m5.large", "2"))
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.26.1"}
+	releases := driver.NewConfigMaps(client.CoreV1().ConfigMaps("bench"))
+	assert.NoError(t, releases.Create("sh.helm.release.v1.my-map.v1", newMetadataRelease("my-map", "bench", release.StatusDeployed)))
+	assert.NoError(t, releases.Create("sh.helm.release.v1.old-map.v1", newMetadataRelease("old-map", "bench", release.StatusUninstalled)))
+
+	flags := pflag.NewFlagSet("bench", pflag.ContinueOnError)
+	flags.IntP("workers", "w", 1, "")
+	flags.StringArray("set-secret", []string{}, "")
+	flags.StringSlice("secret", []string{}, "")
+	flags.String("git-commit", "", "")
+	flags.String("id", "", "")
+	_ = flags.MarkHidden("git-commit")
+	_ = flags.MarkHidden("id")
+	assert.NoError(t, flags.Parse([]string{"-w", "2", "--set-secret", "redis.auth.password=hunter2", "--secret", "token=abc", "--id", "happy-panda"}))
+	assert.NoError(t, flags.Set("git-commit", "0123abc"))
+
+	metadata := getBenchmarkMetadata(context.Background(), client, []string{"./cmd/benchmarks"}, flags, []string{"bench", "bench"})
+	assert.Equal(t, "0123abc", metadata.GitCommit)
+	assert.Equal(t, "v1.26.1", metadata.KubernetesVersion)
+	assert.Equal(t, []report.NodeGroup{
+		{InstanceType: "m5.large", Arch: "amd64", CPU: "2", Memory: "8Gi", Count: 2},
+		{InstanceType: "m5.xlarge", Arch: "amd64", CPU: "4", Memory: "8Gi", Count: 1},
+	}, metadata.Nodes)
+	assert.Equal(t, []report.Chart{
+		{Namespace: "bench", Release: "my-map", Name: "atomix", Version: "1.2.0", AppVersion: "1.2.0"},
+	}, metadata.Charts)
+	assert.Equal(t, []string{
+		"./cmd/benchmarks",
+		"--secret=token=<redacted>",
+		"--set-secret=redis.auth.password=<redacted>",
+		"--workers=2",
+	}, metadata.Args)
+}
+
+func TestGetGitCommit(t *testing.T) {
+	assert.Empty(t, getGitCommit(filepath.Join(t.TempDir(), "missing")))
+	assert.Empty(t, getGitCommit(t.TempDir()))
+}
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/control"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+	"time"
+)
+
+// addBuildFlags adds the flags passed through to go build to the given command
+func addBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("tags", []string{}, "a comma-separated list of build tags to pass to go build")
+	cmd.Flags().String("ldflags", "", "linker flags to pass to go build")
+	cmd.Flags().Bool("race", false, "build with the race detector enabled")
+	cmd.Flags().Bool("no-build-cache", false, "always rebuild the binary rather than reusing a cached binary built from the same sources")
+	cmd.Flags().Bool("build-in-cluster", false, "copy the module source to the job pod and build the binary inside the cluster")
+}
+
+// getBuildOptions returns the build options set by the flags added with addBuildFlags
+func getBuildOptions(cmd *cobra.Command) build.Options {
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	ldflags, _ := cmd.Flags().GetString("ldflags")
+	race, _ := cmd.Flags().GetBool("race")
+	noCache, _ := cmd.Flags().GetBool("no-build-cache")
+	return build.Options{
+		Tags:    tags,
+		LDFlags: ldflags,
+		Race:    race,
+		NoCache: noCache,
+	}
+}
+
+// newJobSource returns a job source for building the given source inside the cluster
+func newJobSource(source build.Source, options build.Options) *job.Source {
+	return &job.Source{
+		Dir:   source.Dir,
+		Main:  source.Main,
+		Flags: options.Flags(),
+	}
+}
+
+// addNoCopyFlags adds the flags for running a prebuilt --image without copying files to the job pods to the given
+// command
+func addNoCopyFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-copy", false, "run the --image as is, without copying a binary or any files to the job pods")
+	cmd.Flags().String("entrypoint", "", "the path of the binary to run in the --image with --no-copy (defaults to the image's entrypoint)")
+}
+
+// noCopy is the configuration of job pods run without copying files set by the flags added with addNoCopyFlags
+type noCopy struct {
+	enabled bool
+	command []string
+}
+
+// getNoCopy returns the configuration of job pods run without copying files set by the flags added with
+// addNoCopyFlags
+// Nothing is copied to the pods, so the image must include the binary, and no package, context, or values files
+// may be set.
+func getNoCopy(cmd *cobra.Command, image string, pkgPaths []string) (noCopy, error) {
+	enabled, _ := cmd.Flags().GetBool("no-copy")
+	entrypoint, _ := cmd.Flags().GetString("entrypoint")
+	if !enabled {
+		if entrypoint != "" {
+			return noCopy{}, errors.New("--entrypoint requires --no-copy")
+		}
+		return noCopy{}, nil
+	}
+	if image == "" || len(pkgPaths) > 0 {
+		return noCopy{}, errors.New("--no-copy requires an --image to run rather than a package")
+	}
+	if contextPath, _ := cmd.Flags().GetString("context"); contextPath != "" {
+		return noCopy{}, errors.New("--no-copy cannot be used with --context")
+	}
+	for _, name := range []string{"values", "values-a", "values-b"} {
+		if files, err := cmd.Flags().GetStringArray(name); err == nil && len(files) > 0 {
+			return noCopy{}, fmt.Errorf("--no-copy cannot be used with --%s", name)
+		}
+	}
+	config := noCopy{enabled: true}
+	if entrypoint != "" {
+		config.command = []string{entrypoint}
+	}
+	return config, nil
+}
+
+// addSchedulingFlags adds the flags controlling where the given pods are scheduled to the given command
+func addSchedulingFlags(cmd *cobra.Command, pods string) {
+	cmd.Flags().StringToString("node-selector", map[string]string{}, fmt.Sprintf("node labels to which to constrain the %s", pods))
+	cmd.Flags().StringArray("toleration", []string{}, fmt.Sprintf("a taint for the %s to tolerate in the format {key}[={value}][:{effect}]", pods))
+	cmd.Flags().String("affinity", "", fmt.Sprintf("a YAML file containing the affinity rules for the %s", pods))
+	cmd.Flags().String("priority-class", "", fmt.Sprintf("the name of the PriorityClass to assign the %s", pods))
+}
+
+// scheduling is the pod scheduling configuration set by the flags added with addSchedulingFlags
+type scheduling struct {
+	nodeSelector      map[string]string
+	tolerations       []corev1.Toleration
+	affinity          *corev1.Affinity
+	priorityClassName string
+}
+
+// getScheduling returns the pod scheduling configuration set by the flags added with addSchedulingFlags
+func getScheduling(cmd *cobra.Command) (scheduling, error) {
+	nodeSelector, _ := cmd.Flags().GetStringToString("node-selector")
+	tolerationFlags, _ := cmd.Flags().GetStringArray("toleration")
+	affinityFile, _ := cmd.Flags().GetString("affinity")
+	priorityClassName, _ := cmd.Flags().GetString("priority-class")
+
+	tolerations, err := parseTolerations(tolerationFlags)
+	if err != nil {
+		return scheduling{}, err
+	}
+	affinity, err := parseAffinity(affinityFile)
+	if err != nil {
+		return scheduling{}, err
+	}
+	return scheduling{
+		nodeSelector:      nodeSelector,
+		tolerations:       tolerations,
+		affinity:          affinity,
+		priorityClassName: priorityClassName,
+	}, nil
+}
+
+// addNamespaceFlags adds the flags controlling the metadata of the namespace created for the command to the given
+// command
+func addNamespaceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringToString("namespace-label", map[string]string{}, "labels to apply to the namespace created with --create-namespace")
+	cmd.Flags().StringToString("namespace-annotation", map[string]string{}, "annotations to apply to the namespace created with --create-namespace")
+}
+
+// namespaceMetadata is the metadata of created namespaces set by the flags added with addNamespaceFlags
+type namespaceMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+// getNamespaceMetadata returns the namespace metadata set by the flags added with addNamespaceFlags
+// The metadata can only be set when the command creates the namespace.
+func getNamespaceMetadata(cmd *cobra.Command, createNamespace bool) (namespaceMetadata, error) {
+	labels, _ := cmd.Flags().GetStringToString("namespace-label")
+	annotations, _ := cmd.Flags().GetStringToString("namespace-annotation")
+	if !createNamespace && (len(labels) > 0 || len(annotations) > 0) {
+		return namespaceMetadata{}, errors.New("--namespace-label and --namespace-annotation require --create-namespace")
+	}
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return namespaceMetadata{}, fmt.Errorf("invalid namespace label %s: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return namespaceMetadata{}, fmt.Errorf("invalid value for namespace label %s: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for key := range annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return namespaceMetadata{}, fmt.Errorf("invalid namespace annotation %s: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return namespaceMetadata{
+		labels:      labels,
+		annotations: annotations,
+	}, nil
+}
+
+// addReadinessFlags adds the flags controlling how the job pods are polled during the handshake with the
+// helmit-runner to the given command
+func addReadinessFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("poll-interval", control.DefaultPollInterval, "the interval at which to poll the control endpoint of job pods")
+	cmd.Flags().Duration("ready-timeout", job.DefaultReadyTimeout, "the time to wait for the control endpoint of job pods to become reachable")
+}
+
+// readiness is the job pod polling configuration set by the flags added with addReadinessFlags
+type readiness struct {
+	pollInterval time.Duration
+	readyTimeout time.Duration
+}
+
+// getReadiness returns the job pod polling configuration set by the flags added with addReadinessFlags
+func getReadiness(cmd *cobra.Command) (readiness, error) {
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+	readyTimeout, _ := cmd.Flags().GetDuration("ready-timeout")
+	if pollInterval <= 0 {
+		return readiness{}, errors.New("--poll-interval must be positive")
+	}
+	if readyTimeout < pollInterval {
+		return readiness{}, errors.New("--ready-timeout must be at least --poll-interval")
+	}
+	return readiness{
+		pollInterval: pollInterval,
+		readyTimeout: readyTimeout,
+	}, nil
+}
+
+// addTeardownFlags adds the flags controlling how the deletion of namespaces created for jobs is awaited to the
+// given command
+func addTeardownFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("force-teardown", false, "remove the finalizers of resources blocking the deletion of namespaces after --teardown-grace-period")
+	cmd.Flags().Duration("teardown-grace-period", job.DefaultTeardownGracePeriod, "the time to wait for namespaces to be deleted before reporting the resources blocking their deletion")
+}
+
+// teardown is the namespace teardown configuration set by the flags added with addTeardownFlags
+type teardown struct {
+	force       bool
+	gracePeriod time.Duration
+}
+
+// getTeardown returns the namespace teardown configuration set by the flags added with addTeardownFlags
+func getTeardown(cmd *cobra.Command) (teardown, error) {
+	force, _ := cmd.Flags().GetBool("force-teardown")
+	gracePeriod, _ := cmd.Flags().GetDuration("teardown-grace-period")
+	if gracePeriod < 0 {
+		return teardown{}, errors.New("--teardown-grace-period cannot be negative")
+	}
+	return teardown{
+		force:       force,
+		gracePeriod: gracePeriod,
+	}, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGetNoCopy(t *testing.T) {
+	newCommand := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringP("context", "c", "", "")
+		cmd.Flags().StringArrayP("values", "f", []string{}, "")
+		addNoCopyFlags(cmd)
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	config, err := getNoCopy(newCommand(), "", []string{"./cmd/tests"})
+	assert.NoError(t, err)
+	assert.False(t, config.enabled)
+
+	config, err = getNoCopy(newCommand("--no-copy"), "atomix/tests:latest", nil)
+	assert.NoError(t, err)
+	assert.True(t, config.enabled)
+	assert.Nil(t, config.command)
+
+	config, err = getNoCopy(newCommand("--no-copy", "--entrypoint", "/usr/local/bin/tests"), "atomix/tests:latest", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/usr/local/bin/tests"}, config.command)
+
+	_, err = getNoCopy(newCommand("--entrypoint", "/usr/local/bin/tests"), "atomix/tests:latest", nil)
+	assert.EqualError(t, err, "--entrypoint requires --no-copy")
+	_, err = getNoCopy(newCommand("--no-copy"), "", []string{"./cmd/tests"})
+	assert.Error(t, err)
+	_, err = getNoCopy(newCommand("--no-copy", "-c", "./charts"), "atomix/tests:latest", nil)
+	assert.EqualError(t, err, "--no-copy cannot be used with --context")
+	_, err = getNoCopy(newCommand("--no-copy", "-f", "store=values.yaml"), "atomix/tests:latest", nil)
+	assert.EqualError(t, err, "--no-copy cannot be used with --values")
+}
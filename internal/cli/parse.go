@@ -6,8 +6,15 @@ package cli
 
 import (
 	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/logging"
+	"helm.sh/helm/v3/pkg/strvals"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"os"
 	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strconv"
 	"strings"
 )
 
@@ -48,6 +55,10 @@ func parseOverrides(values []string) (map[string][]string, error) {
 			return nil, errors.New("values must be in the format {release}.{path}={value}")
 		}
 		release, value := set[:index], set[index+1:]
+		// Values are parsed by the suite with Helm's strvals parser, so they're validated before deploying anything
+		if _, err := strvals.Parse(value); err != nil {
+			return nil, fmt.Errorf("invalid value %q: %w", set, err)
+		}
 		override, ok := overrides[release]
 		if !ok {
 			override = make([]string, 0)
@@ -57,6 +68,37 @@ func parseOverrides(values []string) (map[string][]string, error) {
 	return overrides, nil
 }
 
+// setSecretPrefix is the prefix of the names of the secrets holding values set with --set-secret
+const setSecretPrefix = "set-secret-"
+
+// parseSecretOverrides parses values in the format {release}.{path}={value} that are to be kept secret, returning
+// per-release references to the secrets holding the values and the secrets to pass to the job
+// Only the paths and the names of the secrets are stored in the job configuration, and the values are stored in
+// the job's Secret.
+func parseSecretOverrides(values []string) (map[string][]string, map[string]string, error) {
+	overrides := make(map[string][]string)
+	secrets := make(map[string]string)
+	for i, set := range values {
+		index := strings.Index(set, ".")
+		if index == -1 {
+			return nil, nil, errors.New("secret values must be in the format {release}.{path}={value}")
+		}
+		release, value := set[:index], set[index+1:]
+		index = strings.Index(value, "=")
+		if index == -1 {
+			return nil, nil, errors.New("secret values must be in the format {release}.{path}={value}")
+		}
+		path, secret := value[:index], value[index+1:]
+		name := fmt.Sprintf("%s%d", setSecretPrefix, i)
+		if _, err := strvals.Parse(path + "=" + name); err != nil {
+			return nil, nil, fmt.Errorf("invalid secret value path %q: %w", release+"."+path, err)
+		}
+		overrides[release] = append(overrides[release], path+"="+name)
+		secrets[name] = secret
+	}
+	return overrides, secrets, nil
+}
+
 func parseSecrets(secrets []string) (map[string]string, error) {
 	if len(secrets) == 0 {
 		return map[string]string{}, nil
@@ -73,3 +115,113 @@ func parseSecrets(secrets []string) (map[string]string, error) {
 	}
 	return values, nil
 }
+
+func parseRules(file string) ([]rbacv1.PolicyRule, error) {
+	if file == "" {
+		return nil, nil
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []rbacv1.PolicyRule
+	if err := yaml.Unmarshal(bytes, &rules); err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("RBAC rules file must contain at least one policy rule")
+	}
+	return rules, nil
+}
+
+// parseTolerations parses tolerations in the format {key}[={value}][:{effect}]
+func parseTolerations(values []string) ([]corev1.Toleration, error) {
+	var tolerations []corev1.Toleration
+	for _, value := range values {
+		var toleration corev1.Toleration
+		if index := strings.LastIndex(value, ":"); index != -1 {
+			value, toleration.Effect = value[:index], corev1.TaintEffect(value[index+1:])
+			switch toleration.Effect {
+			case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+			default:
+				return nil, fmt.Errorf("invalid toleration effect %s", toleration.Effect)
+			}
+		}
+		if index := strings.Index(value, "="); index != -1 {
+			toleration.Key, toleration.Value = value[:index], value[index+1:]
+			toleration.Operator = corev1.TolerationOpEqual
+		} else {
+			toleration.Key = value
+			toleration.Operator = corev1.TolerationOpExists
+		}
+		if toleration.Key == "" {
+			return nil, errors.New("tolerations must be in the format {key}[={value}][:{effect}]")
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
+
+func parseAffinity(file string) (*corev1.Affinity, error) {
+	if file == "" {
+		return nil, nil
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	affinity := &corev1.Affinity{}
+	if err := yaml.Unmarshal(bytes, affinity); err != nil {
+		return nil, err
+	}
+	return affinity, nil
+}
+
+// parseSidecars parses a manifest of sidecar containers and the volumes they mount in the format of a pod spec
+func parseSidecars(file string) ([]corev1.Container, []corev1.Volume, error) {
+	if file == "" {
+		return nil, nil, nil
+	}
+
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifest corev1.PodSpec
+	if err := yaml.UnmarshalStrict(bytes, &manifest); err != nil {
+		return nil, nil, err
+	}
+	if len(manifest.Containers) == 0 {
+		return nil, nil, errors.New("sidecar manifest must contain at least one container")
+	}
+	return manifest.Containers, manifest.Volumes, nil
+}
+
+func parseLogFile(file string) (logging.Sink, error) {
+	if file == "" {
+		return logging.NewTeeSink(), nil
+	}
+	return logging.NewFileSink(file)
+}
+
+// parsePercent parses a non-negative percentage, e.g. 20%, returning it as a fraction
+// Values without a percent sign are parsed as fractions.
+func parsePercent(value string) (float64, error) {
+	percent := strings.HasSuffix(value, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a percentage", value)
+	}
+	if f < 0 {
+		return 0, fmt.Errorf("%q must not be negative", value)
+	}
+	if percent {
+		f /= 100
+	}
+	return f, nil
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePercent(t *testing.T) {
+	value, err := parsePercent("20%")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.2, value)
+
+	value, err = parsePercent("0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, value)
+
+	value, err = parsePercent("0%")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), value)
+
+	_, err = parsePercent("twenty%")
+	assert.Error(t, err)
+	_, err = parsePercent("-5%")
+	assert.Error(t, err)
+}
+
+func TestParseSecretOverrides(t *testing.T) {
+	overrides, secrets, err := parseSecretOverrides([]string{"db.auth.password=p@ss=word", "db.token=abc", "cache.key="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"db":    {"auth.password=set-secret-0", "token=set-secret-1"},
+		"cache": {"key=set-secret-2"},
+	}, overrides)
+	assert.Equal(t, map[string]string{
+		"set-secret-0": "p@ss=word",
+		"set-secret-1": "abc",
+		"set-secret-2": "",
+	}, secrets)
+
+	_, _, err = parseSecretOverrides([]string{"password=secret"})
+	assert.Error(t, err)
+	_, _, err = parseSecretOverrides([]string{"db.password"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/match"
+	"io"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// printSuites prints the given suites along with the methods that match the given filters
+// Tags are declared by the suites at runtime, so the tag selector is printed but not applied to the methods.
+func printSuites(out io.Writer, suites []build.Suite, tests []string, methods []string, tags []string) error {
+	testMatcher, err := match.New(tests...)
+	if err != nil {
+		return err
+	}
+	methodMatcher, err := match.New(methods...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Suites:")
+	for _, suite := range suites {
+		if !testMatcher.Match(suite.Name) {
+			continue
+		}
+		fmt.Fprintf(out, "  %s (%s)\n", suite.Name, suite.Package)
+		for _, method := range suite.Methods {
+			if methodMatcher.Match(method) && testMatcher.Match(suite.Name, method) {
+				fmt.Fprintf(out, "    %s\n", method)
+			}
+		}
+	}
+	if len(tags) > 0 {
+		fmt.Fprintf(out, "Tags: %s (applied when the tests are run)\n", strings.Join(tags, ","))
+	}
+	return nil
+}
+
+// printValues prints the Helm values files and overrides for each release
+func printValues(out io.Writer, valueFiles map[string][]string, values map[string][]string, secretValues map[string][]string) {
+	releases := make(map[string]bool)
+	for release := range valueFiles {
+		releases[release] = true
+	}
+	for release := range values {
+		releases[release] = true
+	}
+	for release := range secretValues {
+		releases[release] = true
+	}
+	if len(releases) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(releases))
+	for release := range releases {
+		names = append(names, release)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "Helm values:")
+	for _, release := range names {
+		fmt.Fprintf(out, "  %s:\n", release)
+		for _, file := range valueFiles[release] {
+			fmt.Fprintf(out, "    -f %s\n", file)
+		}
+		for _, value := range values[release] {
+			fmt.Fprintf(out, "    --set %s\n", value)
+		}
+		// Only the name of the secret holding each secret value is printed
+		for _, value := range secretValues[release] {
+			path, name, _ := strings.Cut(value, "=")
+			fmt.Fprintf(out, "    --set-secret %s=<secret %s>\n", path, name)
+		}
+	}
+}
+
+// printJob prints the resources that would be created for the given job
+func printJob[T any](out io.Writer, j *job.Job[T]) error {
+	objects, err := j.Plan()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Resources:")
+	for _, object := range objects {
+		bytes, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "---")
+		fmt.Fprint(out, string(bytes))
+	}
+	return nil
+}
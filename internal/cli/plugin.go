@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// GetPluginCommand returns the root command of the kubectl-helmit plugin
+// The plugin runs the helmit commands, but commands run in the namespace kubectl would use when no namespace is
+// given rather than the default namespace.
+func GetPluginCommand() *cobra.Command {
+	cmd := GetRootCommand()
+	cmd.Use = "kubectl-helmit <command> [args]"
+	preRun := cmd.PersistentPreRunE
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := preRun(cmd, args); err != nil {
+			return err
+		}
+		return applyKubectlNamespace(cmd.Flags())
+	}
+	return cmd
+}
+
+// applyKubectlNamespace sets the namespace flag to the namespace kubectl would use if it was not set on the
+// command line
+// The flag is not marked as changed, so a namespace from the project file still takes precedence. Commands that
+// create their namespace generate its name as usual.
+func applyKubectlNamespace(flags *pflag.FlagSet) error {
+	flag := flags.Lookup("namespace")
+	if flag == nil || flag.Changed {
+		return nil
+	}
+	if createNamespace, _ := flags.GetBool("create-namespace"); createNamespace {
+		return nil
+	}
+	namespace, err := k8s.GetNamespace()
+	if err != nil {
+		return err
+	}
+	return flag.Value.Set(namespace)
+}
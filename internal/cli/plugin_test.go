@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyKubectlNamespace(t *testing.T) {
+	t.Setenv(k8s.PluginNamespaceEnv, "atomix")
+	newFlags := func(args ...string) *pflag.FlagSet {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.StringP("namespace", "n", "", "")
+		flags.Bool("create-namespace", false, "")
+		assert.NoError(t, flags.Parse(args))
+		return flags
+	}
+
+	flags := newFlags()
+	assert.NoError(t, applyKubectlNamespace(flags))
+	namespace, _ := flags.GetString("namespace")
+	assert.Equal(t, "atomix", namespace)
+	assert.False(t, flags.Changed("namespace"))
+
+	flags = newFlags("-n", "onos")
+	assert.NoError(t, applyKubectlNamespace(flags))
+	namespace, _ = flags.GetString("namespace")
+	assert.Equal(t, "onos", namespace)
+
+	flags = newFlags("--create-namespace")
+	assert.NoError(t, applyKubectlNamespace(flags))
+	namespace, _ = flags.GetString("namespace")
+	assert.Equal(t, "", namespace)
+
+	assert.NoError(t, applyKubectlNamespace(pflag.NewFlagSet("test", pflag.ContinueOnError)))
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	cpuProfile = "cpu"
+	// defaultCPUProfileDuration is the duration for which CPU profiles are captured if not specified
+	defaultCPUProfileDuration = 30 * time.Second
+)
+
+// profileKinds are the kinds of profiles that can be captured from workers, and whether they're captured over
+// a duration
+var profileKinds = map[string]bool{
+	cpuProfile:  true,
+	"heap":      false,
+	"allocs":    false,
+	"goroutine": false,
+}
+
+// profileSpec is a kind of profile to capture from each worker
+type profileSpec struct {
+	kind     string
+	duration time.Duration
+}
+
+// getPath returns the path of the profile served by workers
+func (s profileSpec) getPath() string {
+	if s.kind == cpuProfile {
+		return fmt.Sprintf("%sprofile?seconds=%d", benchmark.ProfilePath, int(s.duration.Seconds()))
+	}
+	return benchmark.ProfilePath + s.kind
+}
+
+// parseProfiles parses the --profile values, e.g. cpu=30s and heap
+func parseProfiles(values []string) ([]profileSpec, error) {
+	var specs []profileSpec
+	seen := make(map[string]bool)
+	for _, value := range values {
+		kind, durationValue, hasDuration := strings.Cut(value, "=")
+		timed, ok := profileKinds[kind]
+		if !ok {
+			return nil, fmt.Errorf("--profile %s is not a known profile (cpu, heap, allocs, or goroutine)", kind)
+		}
+		if seen[kind] {
+			return nil, fmt.Errorf("--profile %s is set more than once", kind)
+		}
+		seen[kind] = true
+		spec := profileSpec{kind: kind}
+		if hasDuration {
+			if !timed {
+				return nil, fmt.Errorf("--profile %s does not take a duration", kind)
+			}
+			duration, err := time.ParseDuration(durationValue)
+			if err != nil {
+				return nil, fmt.Errorf("--profile %s has an invalid duration: %w", kind, err)
+			}
+			if duration < time.Second {
+				return nil, fmt.Errorf("--profile %s duration must be at least 1s", kind)
+			}
+			spec.duration = duration
+		} else if timed {
+			spec.duration = defaultCPUProfileDuration
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// newWorkerProfiler returns a workerProfiler capturing the given profiles from each worker to the given directory,
+// or nil if no profiles are to be captured
+func newWorkerProfiler(specs []profileSpec, dir string) *workerProfiler {
+	if len(specs) == 0 {
+		return nil
+	}
+	return &workerProfiler{
+		specs: specs,
+		dir:   dir,
+	}
+}
+
+// workerProfiler captures runtime profiles from the workers of a running benchmark
+// Calls to a nil workerProfiler are ignored, so profiles are only captured when --profile is set.
+type workerProfiler struct {
+	specs []profileSpec
+	dir   string
+	// start is the time at which the workers of the running benchmark are profiled
+	start time.Time
+}
+
+// schedule returns a copy of the profiler that profiles the workers of a benchmark started at the given time with
+// the given warm-up period and duration
+// Profiles are captured in the middle of the measured window, so the longest profile is centered in it, or once
+// the warm-up period has elapsed if the benchmark has no duration.
+func (p *workerProfiler) schedule(started time.Time, warmup time.Duration, duration time.Duration) *workerProfiler {
+	if p == nil {
+		return nil
+	}
+	var longest time.Duration
+	for _, spec := range p.specs {
+		if spec.duration > longest {
+			longest = spec.duration
+		}
+	}
+	start := started.Add(warmup)
+	if duration > longest {
+		start = start.Add((duration - longest) / 2)
+	}
+	return &workerProfiler{
+		specs: p.specs,
+		dir:   p.dir,
+		start: start,
+	}
+}
+
+// profile captures the profiles of the given worker once the scheduled time is reached, logging where each
+// profile is saved, and returning a channel that's closed once profiling stops
+// Profiling stops without saving the remaining profiles if the context is done first.
+func (p *workerProfiler) profile(ctx context.Context, job job.Job[benchmark.Config], ui benchmarkUI, worker int) <-chan struct{} {
+	doneCh := make(chan struct{})
+	if p == nil {
+		close(doneCh)
+		return doneCh
+	}
+	go func() {
+		defer close(doneCh)
+		select {
+		case <-time.After(time.Until(p.start)):
+		case <-ctx.Done():
+			return
+		}
+		if err := os.MkdirAll(p.dir, 0755); err != nil {
+			ui.Log(job.ID, fmt.Sprintf("Failed to profile worker %d: %s", worker, err))
+			return
+		}
+		for _, spec := range p.specs {
+			path := filepath.Join(p.dir, fmt.Sprintf("%s.%s.pprof", job.ID, spec.kind))
+			if err := fetchProfile(ctx, job, spec, path); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				ui.Log(job.ID, fmt.Sprintf("Failed to capture %s profile of worker %d: %s", spec.kind, worker, err))
+				continue
+			}
+			ui.Log(job.ID, fmt.Sprintf("Saved %s profile of worker %d to %s", spec.kind, worker, path))
+		}
+	}()
+	return doneCh
+}
+
+// fetchProfile fetches the given profile from the worker through a forwarded port and writes it to the given path
+func fetchProfile(ctx context.Context, job job.Job[benchmark.Config], spec profileSpec, path string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	port, err := job.Forward(ctx, benchmark.ProfilePort)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://localhost:%d%s", port, spec.getPath()), nil)
+	if err != nil {
+		return err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, response.Body); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
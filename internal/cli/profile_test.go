@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseProfiles(t *testing.T) {
+	specs, err := parseProfiles([]string{"cpu=10s", "heap"})
+	assert.NoError(t, err)
+	assert.Equal(t, []profileSpec{{kind: "cpu", duration: 10 * time.Second}, {kind: "heap"}}, specs)
+	assert.Equal(t, "/debug/pprof/profile?seconds=10", specs[0].getPath())
+	assert.Equal(t, "/debug/pprof/heap", specs[1].getPath())
+
+	specs, err = parseProfiles([]string{"cpu"})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultCPUProfileDuration, specs[0].duration)
+
+	_, err = parseProfiles([]string{"threads"})
+	assert.Error(t, err)
+	_, err = parseProfiles([]string{"heap=10s"})
+	assert.Error(t, err)
+	_, err = parseProfiles([]string{"cpu=10ms"})
+	assert.Error(t, err)
+	_, err = parseProfiles([]string{"cpu=10s", "cpu=20s"})
+	assert.Error(t, err)
+}
+
+func TestWorkerProfilerSchedule(t *testing.T) {
+	assert.Nil(t, newWorkerProfiler(nil, "profiles"))
+	var profiler *workerProfiler
+	assert.Nil(t, profiler.schedule(time.Now(), time.Minute, time.Hour))
+
+	started := time.Now()
+	profiler = newWorkerProfiler([]profileSpec{{kind: "cpu", duration: 30 * time.Second}, {kind: "heap"}}, "profiles")
+	assert.Equal(t, started.Add(time.Minute+(10*time.Minute-30*time.Second)/2), profiler.schedule(started, time.Minute, 10*time.Minute).start)
+	assert.Equal(t, started.Add(time.Minute), profiler.schedule(started, time.Minute, 0).start)
+	assert.Equal(t, started.Add(time.Minute), profiler.schedule(started, time.Minute, 10*time.Second).start)
+}
@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"helm.sh/helm/v3/pkg/strvals"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// projectFile is the name of the project file from which command defaults are loaded
+const projectFile = "helmit.yaml"
+
+// project is the set of command defaults declared in a project file
+type project struct {
+	// Namespace is the default namespace in which to run jobs
+	Namespace string `json:"namespace,omitempty"`
+	// Timeout is the default timeout for all commands
+	Timeout string `json:"timeout,omitempty"`
+	// Values are chart value overrides keyed by release name and value path
+	Values map[string]map[string]any `json:"values,omitempty"`
+	// ValueFiles are values files keyed by release name, relative to the project file
+	ValueFiles map[string][]string `json:"valueFiles,omitempty"`
+	// Test are the defaults for the test command
+	Test projectTest `json:"test,omitempty"`
+	// Bench are the defaults for the bench command
+	Bench projectBench `json:"bench,omitempty"`
+	// Run are the defaults for the run command
+	Run projectRun `json:"run,omitempty"`
+	// Hooks are the provisioning hooks run around the test, bench, and run commands
+	Hooks projectHooks `json:"hooks,omitempty"`
+}
+
+type projectTest struct {
+	Suites  []string `json:"suites,omitempty"`
+	Tests   []string `json:"tests,omitempty"`
+	Methods []string `json:"methods,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Timeout string   `json:"timeout,omitempty"`
+}
+
+type projectBench struct {
+	Suite     string `json:"suite,omitempty"`
+	Benchmark string `json:"benchmark,omitempty"`
+	Workers   int    `json:"workers,omitempty"`
+	Timeout   string `json:"timeout,omitempty"`
+}
+
+type projectRun struct {
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type projectHooks struct {
+	BeforeCluster []string `json:"beforeCluster,omitempty"`
+	AfterCluster  []string `json:"afterCluster,omitempty"`
+	// Plugins are the paths of Go plugins exporting hooks, relative to the project file
+	Plugins []string `json:"plugins,omitempty"`
+	Timeout string   `json:"timeout,omitempty"`
+}
+
+// applyProjectFile loads the project file from the command's context directory, if present, and applies it
+// to the command's flags
+// Flags set on the command line take precedence over the project file. Chart values and values files from
+// the project file are applied before those set on the command line, so the command line values win.
+func applyProjectFile(cmd *cobra.Command) error {
+	dir, _ := cmd.Flags().GetString("context")
+	if dir == "" {
+		dir = "."
+	}
+	path := filepath.Join(dir, projectFile)
+	project, err := loadProject(path)
+	if err != nil || project == nil {
+		return err
+	}
+	return project.apply(cmd.Name(), filepath.Dir(path), cmd.Flags())
+}
+
+// loadProject loads the project file at the given path, returning nil if the file does not exist
+func loadProject(path string) (*project, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	project := &project{}
+	if err := yaml.UnmarshalStrict(bytes, project); err != nil {
+		return nil, fmt.Errorf("invalid project file %s: %w", path, err)
+	}
+	return project, nil
+}
+
+// apply applies the project defaults for the named command to the given flags
+func (p *project) apply(command string, dir string, flags *pflag.FlagSet) error {
+	defaults := make(map[string][]string)
+	setDefault := func(name string, values ...string) {
+		if len(values) > 0 && values[0] != "" {
+			defaults[name] = values
+		}
+	}
+
+	setDefault("namespace", p.Namespace)
+	setDefault("timeout", p.Timeout)
+	switch command {
+	case "test":
+		setDefault("suite", p.Test.Suites...)
+		setDefault("test", p.Test.Tests...)
+		setDefault("method", p.Test.Methods...)
+		setDefault("test-tags", p.Test.Tags...)
+		setDefault("timeout", p.Test.Timeout)
+	case "bench":
+		setDefault("suite", p.Bench.Suite)
+		setDefault("benchmark", p.Bench.Benchmark)
+		if p.Bench.Workers > 0 {
+			setDefault("workers", strconv.Itoa(p.Bench.Workers))
+		}
+		setDefault("timeout", p.Bench.Timeout)
+	case "run":
+		setDefault("timeout", p.Run.Timeout)
+	}
+
+	setDefault("before-cluster", p.Hooks.BeforeCluster...)
+	setDefault("after-cluster", p.Hooks.AfterCluster...)
+	setDefault("hook-timeout", p.Hooks.Timeout)
+	for _, plugin := range p.Hooks.Plugins {
+		if !filepath.IsAbs(plugin) {
+			plugin = filepath.Join(dir, plugin)
+		}
+		defaults["hook-plugin"] = append(defaults["hook-plugin"], plugin)
+	}
+
+	for _, release := range sortedKeys(p.Values) {
+		values := p.Values[release]
+		for _, path := range sortedKeys(values) {
+			sets, err := getValueOverrides(release+"."+path, values[path])
+			if err != nil {
+				return fmt.Errorf("invalid project file value for %s.%s: %w", release, path, err)
+			}
+			defaults["set"] = append(defaults["set"], sets...)
+		}
+	}
+	for _, release := range sortedKeys(p.ValueFiles) {
+		for _, file := range p.ValueFiles[release] {
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(dir, file)
+			}
+			defaults["values"] = append(defaults["values"], fmt.Sprintf("%s=%s", release, file))
+		}
+	}
+
+	for name, values := range defaults {
+		if err := applyDefault(flags, name, values); err != nil {
+			return fmt.Errorf("invalid project file value for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// getValueOverrides returns the --set overrides setting the given value at the given path
+// Maps and lists are set element by element, and the characters special to Helm's strvals parser are escaped, so
+// the value is parsed exactly as it's written in the project file.
+func getValueOverrides(path string, value any) ([]string, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		var sets []string
+		for _, key := range sortedKeys(v) {
+			elemSets, err := getValueOverrides(path+"."+valueKeyEscaper.Replace(key), v[key])
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, elemSets...)
+		}
+		return sets, nil
+	case []any:
+		if len(v) == 0 {
+			return nil, errors.New("empty lists cannot be set with --set; use a values file")
+		}
+		var sets []string
+		for i, elem := range v {
+			elemSets, err := getValueOverrides(fmt.Sprintf("%s[%d]", path, i), elem)
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, elemSets...)
+		}
+		return sets, nil
+	case string:
+		escaped := valueEscaper.Replace(v)
+		if strings.HasPrefix(escaped, "{") {
+			escaped = "\\" + escaped
+		}
+		// Strings that strvals parses as other types, e.g. "true", cannot be set as strings with --set
+		if parsed, err := strvals.Parse("value=" + escaped); err != nil || parsed["value"] != v {
+			return nil, fmt.Errorf("string %q cannot be set with --set; use a values file", v)
+		}
+		return []string{path + "=" + escaped}, nil
+	case float64:
+		return []string{path + "=" + strconv.FormatFloat(v, 'f', -1, 64)}, nil
+	case bool:
+		return []string{path + "=" + strconv.FormatBool(v)}, nil
+	case nil:
+		return []string{path + "=null"}, nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", value)
+}
+
+// valueKeyEscaper escapes the characters special to Helm's strvals parser in map keys
+var valueKeyEscaper = strings.NewReplacer(`\`, `\\`, `.`, `\.`, `[`, `\[`, `=`, `\=`, `,`, `\,`)
+
+// valueEscaper escapes the characters special to Helm's strvals parser in values
+var valueEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`)
+
+// applyDefault applies the given default values to the named flag
+// Values lists set on the command line are appended to the defaults, and other flags set on the command line
+// are left unchanged.
+func applyDefault(flags *pflag.FlagSet, name string, values []string) error {
+	flag := flags.Lookup(name)
+	if flag == nil {
+		return nil
+	}
+	if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+		if flag.Changed {
+			if name != "set" && name != "values" {
+				return nil
+			}
+			values = append(values, sliceValue.GetSlice()...)
+		}
+		return sliceValue.Replace(values)
+	}
+	if flag.Changed {
+		return nil
+	}
+	return flag.Value.Set(values[0])
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
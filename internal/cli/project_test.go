@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/strvals"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testProject = `
+namespace: atomix
+timeout: 20m
+values:
+  atomix-raft:
+    replicas: 3
+valueFiles:
+  atomix-raft:
+  - raft.yaml
+test:
+  suites:
+  - atomix
+  timeout: 30m
+bench:
+  suite: atomix
+  workers: 3
+hooks:
+  beforeCluster:
+  - kind create cluster
+  plugins:
+  - hooks.so
+`
+
+func TestProjectFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, projectFile), []byte(testProject), 0644))
+
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"-c", dir, "--timeout", "5m", "--set", "atomix-raft.replicas=5"}))
+	assert.NoError(t, applyProjectFile(cmd))
+
+	namespace, _ := cmd.Flags().GetString("namespace")
+	assert.Equal(t, "atomix", namespace)
+	suites, _ := cmd.Flags().GetStringSlice("suite")
+	assert.Equal(t, []string{"atomix"}, suites)
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	assert.Equal(t, 5*time.Minute, timeout)
+	sets, _ := cmd.Flags().GetStringArray("set")
+	assert.Equal(t, []string{"atomix-raft.replicas=3", "atomix-raft.replicas=5"}, sets)
+	files, _ := cmd.Flags().GetStringArray("values")
+	assert.Equal(t, []string{"atomix-raft=" + filepath.Join(dir, "raft.yaml")}, files)
+	beforeCluster, _ := cmd.Flags().GetStringArray("before-cluster")
+	assert.Equal(t, []string{"kind create cluster"}, beforeCluster)
+	plugins, _ := cmd.Flags().GetStringArray("hook-plugin")
+	assert.Equal(t, []string{filepath.Join(dir, "hooks.so")}, plugins)
+
+	cmd = getBenchCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"-c", dir, "--workers", "5"}))
+	assert.NoError(t, applyProjectFile(cmd))
+
+	suite, _ := cmd.Flags().GetString("suite")
+	assert.Equal(t, "atomix", suite)
+	workers, _ := cmd.Flags().GetInt("workers")
+	assert.Equal(t, 5, workers)
+	timeout, _ = cmd.Flags().GetDuration("timeout")
+	assert.Equal(t, 20*time.Minute, timeout)
+}
+
+func TestProjectFileNotFound(t *testing.T) {
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"-c", t.TempDir()}))
+	assert.NoError(t, applyProjectFile(cmd))
+	suites, _ := cmd.Flags().GetStringSlice("suite")
+	assert.Equal(t, []string{"TestSuite$"}, suites)
+}
+
+func TestProjectFileValues(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, projectFile), []byte(`
+values:
+  store:
+    args: [--verbose, --port=8080]
+    image: {repository: store, tag: v1.0}
+    labels: {app.kubernetes.io/name: store}
+    peers: "a:5679,b:5679"
+    servers: [{port: 8080}, {port: 8081}]
+    template: "{{ .Release.Name }}"
+    weight: 1.5
+    debug: false
+    annotations: null
+`), 0644))
+
+	cmd := getTestCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"-c", dir}))
+	assert.NoError(t, applyProjectFile(cmd))
+	sets, _ := cmd.Flags().GetStringArray("set")
+	assert.Equal(t, []string{
+		"store.annotations=null",
+		"store.args[0]=--verbose",
+		"store.args[1]=--port=8080",
+		"store.debug=false",
+		"store.image.repository=store",
+		"store.image.tag=v1.0",
+		`store.labels.app\.kubernetes\.io/name=store`,
+		`store.peers=a:5679\,b:5679`,
+		"store.servers[0].port=8080",
+		"store.servers[1].port=8081",
+		`store.template=\{{ .Release.Name }}`,
+		"store.weight=1.5",
+	}, sets)
+
+	// The overrides are parsed back into the values in the project file
+	overrides, err := parseOverrides(sets)
+	assert.NoError(t, err)
+	values := make(map[string]any)
+	for _, value := range overrides["store"] {
+		assert.NoError(t, strvals.ParseInto(value, values))
+	}
+	assert.Equal(t, []any{"--verbose", "--port=8080"}, values["args"])
+	assert.Equal(t, map[string]any{"repository": "store", "tag": "v1.0"}, values["image"])
+	assert.Equal(t, map[string]any{"app.kubernetes.io/name": "store"}, values["labels"])
+	assert.Equal(t, "a:5679,b:5679", values["peers"])
+	assert.Equal(t, []any{map[string]any{"port": int64(8080)}, map[string]any{"port": int64(8081)}}, values["servers"])
+	assert.Equal(t, "{{ .Release.Name }}", values["template"])
+	assert.Equal(t, false, values["debug"])
+
+	for _, value := range []string{`"true"`, `"8080"`, `[]`} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, projectFile), []byte("values:\n  store:\n    foo: "+value+"\n"), 0644))
+		cmd := getTestCommand()
+		assert.NoError(t, cmd.ParseFlags([]string{"-c", dir}))
+		assert.Error(t, applyProjectFile(cmd), value)
+	}
+}
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// stepEvent is a line of step output
+	stepEvent = "step"
+	// startEvent is the start of a benchmark UI
+	startEvent = "start"
+	// updateEvent is a worker report displayed by a benchmark UI
+	updateEvent = "update"
+	// logEvent is a line of worker output displayed by a benchmark UI
+	logEvent = "log"
+	// closeEvent is the closing of a benchmark UI
+	closeEvent = "close"
+)
+
+// consoleEvent is an event in a recorded console event stream
+type consoleEvent struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Job is the ID of the benchmark shown by a UI or of the job that logged a line
+	Job  string `json:"job,omitempty"`
+	Text string `json:"text,omitempty"`
+	// Workers is the number of workers shown by a UI when it starts or is updated
+	Workers     int               `json:"workers,omitempty"`
+	Parallelism int               `json:"parallelism,omitempty"`
+	Rate        float64           `json:"rate,omitempty"`
+	Report      *benchmark.Report `json:"report,omitempty"`
+	Worker      int               `json:"worker,omitempty"`
+	Stalls      int               `json:"stalls,omitempty"`
+	Restarts    int               `json:"restarts,omitempty"`
+}
+
+// newConsoleRecorder creates a consoleRecorder writing to the given file
+// Once created, steps are recorded until the recorder is closed.
+func newConsoleRecorder(path string) (*consoleRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &consoleRecorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}
+	logging.SetRecorder(&stepRecorder{recorder: recorder})
+	return recorder, nil
+}
+
+// consoleRecorder records the console event stream of a benchmark run to a file for replay
+// A nil recorder records nothing.
+type consoleRecorder struct {
+	file    *os.File
+	encoder *json.Encoder
+	err     error
+	mu      sync.Mutex
+}
+
+func (r *consoleRecorder) record(event consoleEvent) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	event.Time = time.Now()
+	r.err = r.encoder.Encode(event)
+}
+
+// wrap returns a benchmarkUI that records the events displayed by the given UI
+func (r *consoleRecorder) wrap(ui benchmarkUI, benchID string, workers int, config benchmark.Config) benchmarkUI {
+	if r == nil {
+		return ui
+	}
+	r.record(consoleEvent{
+		Type:        startEvent,
+		Job:         benchID,
+		Workers:     workers,
+		Parallelism: config.Parallelism,
+		Rate:        config.Rate,
+	})
+	return &consoleRecordingUI{benchmarkUI: ui, recorder: r}
+}
+
+// Close stops recording and closes the file, returning the first error encountered while recording
+func (r *consoleRecorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	logging.SetRecorder(nil)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// stepRecorder records each line of step output as a step event
+type stepRecorder struct {
+	recorder *consoleRecorder
+	buf      []byte
+	mu       sync.Mutex
+}
+
+func (w *stepRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.recorder.record(consoleEvent{Type: stepEvent, Text: string(w.buf[:i+1])})
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// consoleRecordingUI is a benchmarkUI that records the updates and logs it displays
+type consoleRecordingUI struct {
+	benchmarkUI
+	recorder *consoleRecorder
+}
+
+func (ui *consoleRecordingUI) Update(reports []*workerReport, report workerReport) {
+	ui.recorder.record(consoleEvent{
+		Type:     updateEvent,
+		Workers:  len(reports),
+		Report:   &report.Report,
+		Worker:   report.worker,
+		Stalls:   report.stalls,
+		Restarts: report.restarts,
+	})
+	ui.benchmarkUI.Update(reports, report)
+}
+
+func (ui *consoleRecordingUI) Log(job string, line string) {
+	ui.recorder.record(consoleEvent{Type: logEvent, Job: job, Text: line})
+	ui.benchmarkUI.Log(job, line)
+}
+
+func (ui *consoleRecordingUI) Close() error {
+	ui.recorder.record(consoleEvent{Type: closeEvent})
+	return ui.benchmarkUI.Close()
+}
+
+// errReplayStopped is returned when the user stops a replay from the interactive UI
+var errReplayStopped = errors.New("replay stopped")
+
+// replayConsole re-renders the console event stream read from the given reader in benchmark UIs of the given type
+// Events are replayed with their recorded timing, sped up by the given factor, or without delay if the speed is
+// zero.
+func replayConsole(in io.Reader, uiType string, speed float64) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var ui benchmarkUI
+	var reports []*workerReport
+	defer func() {
+		if ui != nil {
+			_ = ui.Close()
+		}
+	}()
+
+	var last time.Time
+	for line := 1; scanner.Scan(); line++ {
+		var event consoleEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("invalid event on line %d: %w", line, err)
+		}
+
+		if speed > 0 && !last.IsZero() && event.Time.After(last) {
+			var stopped <-chan struct{}
+			if ui != nil {
+				stopped = ui.Stopped()
+			}
+			timer := time.NewTimer(time.Duration(float64(event.Time.Sub(last)) / speed))
+			select {
+			case <-timer.C:
+			case <-stopped:
+				timer.Stop()
+				return errReplayStopped
+			}
+		}
+		last = event.Time
+
+		switch event.Type {
+		case stepEvent:
+			logging.Print(event.Text)
+		case startEvent:
+			if ui != nil {
+				_ = ui.Close()
+			}
+			var err error
+			ui, err = newBenchmarkUI(uiType, event.Job, event.Workers, benchmark.Config{
+				Parallelism: event.Parallelism,
+				Rate:        event.Rate,
+			})
+			if err != nil {
+				return err
+			}
+			reports = make([]*workerReport, event.Workers)
+		case updateEvent:
+			if ui == nil || event.Report == nil {
+				continue
+			}
+			for len(reports) < event.Workers || len(reports) <= event.Worker {
+				reports = append(reports, nil)
+			}
+			report := workerReport{
+				Report:   *event.Report,
+				worker:   event.Worker,
+				stalls:   event.Stalls,
+				restarts: event.Restarts,
+			}
+			reports[event.Worker] = &report
+			ui.Update(reports, report)
+		case logEvent:
+			if ui != nil {
+				ui.Log(event.Job, event.Text)
+			}
+		case closeEvent:
+			if ui != nil {
+				err := ui.Close()
+				ui = nil
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.jsonl")
+	recorder, err := newConsoleRecorder(path)
+	assert.NoError(t, err)
+
+	logging.NewStep("bench-1", "Starting benchmark").Start()
+	ui := recorder.wrap(&recordingUI{benchmarkUI: newPlainBenchmarkUI("bench-1")}, "bench-1", 2, benchmark.Config{Parallelism: 4})
+	report := workerReport{
+		Report: benchmark.Report{Iterations: 10, Duration: time.Second},
+		worker: 1,
+		stalls: 2,
+	}
+	ui.Update([]*workerReport{nil, &report}, report)
+	ui.Log("bench-1-worker-1", "hello")
+	assert.NoError(t, ui.Close())
+	assert.NoError(t, recorder.Close())
+
+	// Steps logged after the recorder is closed are not recorded
+	logging.NewStep("bench-1", "Done").Start()
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var events []consoleEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event consoleEvent
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	assert.Len(t, events, 5)
+	assert.Equal(t, stepEvent, events[0].Type)
+	assert.Contains(t, events[0].Text, "Starting benchmark")
+	assert.Equal(t, startEvent, events[1].Type)
+	assert.Equal(t, "bench-1", events[1].Job)
+	assert.Equal(t, 2, events[1].Workers)
+	assert.Equal(t, 4, events[1].Parallelism)
+	assert.Equal(t, updateEvent, events[2].Type)
+	assert.Equal(t, 1, events[2].Worker)
+	assert.Equal(t, 2, events[2].Stalls)
+	assert.Equal(t, 10, events[2].Report.Iterations)
+	assert.Equal(t, logEvent, events[3].Type)
+	assert.Equal(t, "hello", events[3].Text)
+	assert.Equal(t, closeEvent, events[4].Type)
+
+	_, err = file.Seek(0, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, replayConsole(file, plainUI, 0))
+}
+
+func TestReplayConsoleInvalidEvent(t *testing.T) {
+	err := replayConsole(strings.NewReader("{\"type\":\"step\"}\nnot json\n"), plainUI, 0)
+	assert.ErrorContains(t, err, "line 2")
+}
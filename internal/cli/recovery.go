@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"sync"
+	"time"
+)
+
+// reconfigureInterval is the interval at which the configuration of a recovered worker is retried until the
+// worker serves its gRPC services
+const reconfigureInterval = time.Second
+
+// withDurableWorkers returns workerJobs creating the workers of the given workerJobs as durable jobs if enabled,
+// so workers whose pods are restarted, e.g. when their nodes are drained, can be recovered
+func withDurableWorkers(getWorkerJob workerJobs, durable bool) workerJobs {
+	if !durable {
+		return getWorkerJob
+	}
+	return func(worker int) job.Job[benchmark.Config] {
+		j := getWorkerJob(worker)
+		j.Durable = true
+		return j
+	}
+}
+
+// workerRecovery recovers the durable workers of a running benchmark whose pods are restarted, reapplying the
+// configuration pushed to the workers since the benchmark was started
+type workerRecovery struct {
+	config     benchmark.WorkerConfig
+	configured bool
+	mu         sync.Mutex
+}
+
+// configure records the given configuration pushed to the workers
+// Zero values leave the corresponding parameters unchanged and arguments are added or replaced, as when the
+// configuration is applied by the workers.
+func (r *workerRecovery) configure(config benchmark.WorkerConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if config.Parallelism > 0 {
+		r.config.Parallelism = config.Parallelism
+	}
+	if config.Rate > 0 {
+		r.config.Rate = config.Rate
+	}
+	for name, value := range config.Args {
+		if r.config.Args == nil {
+			r.config.Args = make(map[string]string)
+		}
+		r.config.Args[name] = value
+	}
+	r.configured = true
+}
+
+// getConfig returns the configuration pushed to the workers, and whether any configuration was pushed
+func (r *workerRecovery) getConfig() (benchmark.WorkerConfig, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config, r.configured
+}
+
+// restarted returns whether the pod of the given durable worker was restarted while the benchmark was running
+// Workers whose restart cannot be determined are assumed to have exited, so they're torn down.
+func (r *workerRecovery) restarted(ctx context.Context, job job.Job[benchmark.Config]) bool {
+	if !job.Durable || ctx.Err() != nil {
+		return false
+	}
+	restarted, err := job.Restarted(ctx)
+	return err == nil && restarted
+}
+
+// recover waits for the restarted pod of the given worker to start running and starts the worker again, reapplying
+// the configuration pushed to the workers
+func (r *workerRecovery) recover(ctx context.Context, job *job.Job[benchmark.Config], worker int, timeout time.Duration) error {
+	step := logging.NewStep(job.ID, "Recovering worker %d", worker)
+	step.Start()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := job.Recover(ctx, step); err != nil {
+		step.Fail(err)
+		return err
+	}
+	if config, ok := r.getConfig(); ok {
+		if err := reconfigureWorker(ctx, *job, config); err != nil {
+			step.Fail(err)
+			return err
+		}
+	}
+	step.Complete()
+	return nil
+}
+
+// reconfigureWorker pushes the given configuration to a recovered worker, retrying until the worker serves its
+// gRPC services or the context is done
+func reconfigureWorker(ctx context.Context, job job.Job[benchmark.Config], config benchmark.WorkerConfig) error {
+	for {
+		err := pushWorkerConfig(ctx, job, config)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-time.After(reconfigureInterval):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// pushWorkerConfig pushes the given configuration to the given worker's job
+func pushWorkerConfig(ctx context.Context, job job.Job[benchmark.Config], config benchmark.WorkerConfig) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	conn, err := dialWorker(ctx, job)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return benchmark.ConfigureWorker(ctx, conn, config)
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithDurableWorkers(t *testing.T) {
+	j := job.Job[benchmark.Config]{ID: "happy-panda"}
+	assert.False(t, withDurableWorkers(newWorkerJobs(j), false)(0).Durable)
+	assert.True(t, withDurableWorkers(newWorkerJobs(j), true)(1).Durable)
+}
+
+func TestWorkerRecovery(t *testing.T) {
+	recovery := &workerRecovery{}
+	_, ok := recovery.getConfig()
+	assert.False(t, ok)
+
+	recovery.configure(benchmark.WorkerConfig{Parallelism: 4, Args: map[string]string{"size": "10"}})
+	recovery.configure(benchmark.WorkerConfig{Rate: 100, Args: map[string]string{"keys": "1000"}})
+	recovery.configure(benchmark.WorkerConfig{Args: map[string]string{"size": "20"}})
+	config, ok := recovery.getConfig()
+	assert.True(t, ok)
+	assert.Equal(t, 4, config.Parallelism)
+	assert.Equal(t, 100.0, config.Rate)
+	assert.Equal(t, map[string]string{"size": "20", "keys": "1000"}, config.Args)
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const replayExamples = `
+  # Record the console of a benchmark run.
+  helmit bench ./cmd/benchmarks --suite my-benchmarks --duration 10m --console-record bench.jsonl
+
+  # Replay the recorded run in the interactive UI at four times the original speed.
+  helmit replay bench.jsonl --ui interactive --speed 4
+`
+
+func getReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "replay <file>",
+		Short:   "Replay the console of a benchmark run recorded with --console-record",
+		Example: replayExamples,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runReplayCommand,
+	}
+	cmd.Flags().String("ui", plainUI, "the benchmark progress display (plain or interactive)")
+	cmd.Flags().Float64("speed", 1, "the factor by which to speed up the replay (0 replays the events without delay)")
+	return cmd
+}
+
+func runReplayCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	uiType, _ := cmd.Flags().GetString("ui")
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	if uiType != plainUI && uiType != interactiveUI {
+		return fmt.Errorf("unknown UI %q", uiType)
+	}
+	if speed < 0 {
+		return errors.New("--speed must not be negative")
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := replayConsole(file, uiType, speed); err != nil && err != errReplayStopped {
+		return err
+	}
+	return nil
+}
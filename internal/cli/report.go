@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// addReportFlags adds the flags controlling the rendered report of the command's results to the given command
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().String("report-format", "", "the format in which to render a report of the results (md, html, or json)")
+	cmd.Flags().String("report-file", "", "a file to which to write the rendered report (defaults to stdout)")
+}
+
+// reportOptions are the report options set by the flags added with addReportFlags
+type reportOptions struct {
+	renderer report.Renderer
+	file     string
+}
+
+// getReportOptions returns the report options set by the flags added with addReportFlags, or nil if no report
+// was requested
+// If only a report file is set, the format is determined by the file's extension.
+func getReportOptions(cmd *cobra.Command) (*reportOptions, error) {
+	format, _ := cmd.Flags().GetString("report-format")
+	file, _ := cmd.Flags().GetString("report-file")
+	if format == "" && file != "" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".md", ".markdown":
+			format = string(report.MarkdownFormat)
+		case ".html", ".htm":
+			format = string(report.HTMLFormat)
+		case ".json":
+			format = string(report.JSONFormat)
+		default:
+			return nil, fmt.Errorf("cannot determine the report format of %s; set --report-format", file)
+		}
+	}
+	if format == "" {
+		return nil, nil
+	}
+	renderer, err := report.NewRenderer(report.Format(format))
+	if err != nil {
+		return nil, err
+	}
+	return &reportOptions{
+		renderer: renderer,
+		file:     file,
+	}, nil
+}
+
+// write renders a report to the report file, or to the given writer if no file was set
+// Writing to nil options is a no-op, so the options can be used whether or not a report was requested.
+func (o *reportOptions) write(out io.Writer, render func(renderer report.Renderer, out io.Writer) error) error {
+	if o == nil {
+		return nil
+	}
+	if o.file == "" {
+		fmt.Fprintln(out)
+		return render(o.renderer, out)
+	}
+	file, err := os.Create(o.file)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := render(o.renderer, file); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Report written to %s\n", o.file)
+	return nil
+}
+
+// getReport returns a report of the parsed test results
+func (s *testSummary) getReport(testID string, duration time.Duration) report.TestReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	testReport := report.TestReport{
+		ID:       testID,
+		Start:    time.Now().Add(-duration),
+		Duration: duration,
+	}
+	for _, result := range s.getLeafResults() {
+		testResult := report.TestResult{
+			Name:     result.name,
+			Status:   result.status,
+			Duration: result.duration,
+		}
+		if result.status == testStatusFail {
+			testResult.Failure = getFailureDigest(result.output)
+		}
+		testReport.Results = append(testReport.Results, testResult)
+	}
+	if s.timings != nil {
+		testReport.Timings = newReportTimings(s.timings.Get())
+	}
+	return testReport
+}
+
+// writeTestReport renders a report of the summarized test results
+// Failures to write the report are printed rather than returned so the exit code reflects the test results.
+func writeTestReport(out io.Writer, opts *reportOptions, summary *testSummary, testID string, duration time.Duration) {
+	err := opts.write(out, func(renderer report.Renderer, out io.Writer) error {
+		return renderer.RenderTests(out, summary.getReport(testID, duration))
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write test report: %s\n", err)
+	}
+}
+
+// uploadTestResults uploads the summarized test results, the collected artifacts, and the log file
+func uploadTestResults(uploads *resultsUpload, summary *testSummary, testID string, duration time.Duration, artifactsDir string, logFile string) {
+	files := map[string]string{
+		"artifacts": artifactsDir,
+	}
+	if logFile != "" {
+		files[filepath.Base(logFile)] = logFile
+	}
+	uploads.upload(testID, func(renderer report.Renderer, out io.Writer) error {
+		return renderer.RenderTests(out, summary.getReport(testID, duration))
+	}, files)
+}
+
+// newBenchmarkRun returns the report of a single benchmark run from the final worker reports
+func newBenchmarkRun(name string, reports []*workerReport, history *benchmarkHistory, err error) report.BenchmarkRun {
+	run := report.BenchmarkRun{
+		Name: name,
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	for _, workerReport := range reports {
+		if workerReport != nil {
+			run.Workers = append(run.Workers, report.BenchmarkWorker{
+				Report:   workerReport.Report,
+				Worker:   workerReport.worker,
+				Stalls:   workerReport.stalls,
+				Restarts: workerReport.restarts,
+			})
+		}
+	}
+	run.Total, _ = sumReports(reports)
+	if history != nil {
+		run.History = history.samples
+	}
+	return run
+}
+
+// newBenchmarkHistory returns a benchmarkHistory recording samples at the given interval
+func newBenchmarkHistory(interval time.Duration) *benchmarkHistory {
+	return &benchmarkHistory{
+		interval: interval,
+		start:    time.Now(),
+	}
+}
+
+// benchmarkHistory records the total throughput and latency across workers over the course of a benchmark run
+type benchmarkHistory struct {
+	interval time.Duration
+	start    time.Time
+	samples  []report.BenchmarkSample
+}
+
+// record records a sample of the given worker reports
+// Workers report at the same interval, so samples within an interval replace each other as worker reports
+// arrive, leaving one sample per interval.
+func (h *benchmarkHistory) record(reports []*workerReport) {
+	sample := report.BenchmarkSample{
+		Elapsed: time.Since(h.start),
+	}
+	for _, workerReport := range reports {
+		if workerReport != nil {
+			sample.Throughput += getThroughput(workerReport.Report)
+		}
+	}
+	total, _ := sumReports(reports)
+	sample.P99Latency = total.P99Latency
+	if n := len(h.samples); n > 0 && h.interval > 0 && h.samples[n-1].Elapsed/h.interval == sample.Elapsed/h.interval {
+		h.samples[n-1] = sample
+	} else {
+		h.samples = append(h.samples, sample)
+	}
+}
+
+// historyUI records the reports displayed by a benchmark UI in a benchmarkHistory
+type historyUI struct {
+	benchmarkUI
+	history *benchmarkHistory
+}
+
+func (ui *historyUI) Update(reports []*workerReport, report workerReport) {
+	ui.history.record(reports)
+	ui.benchmarkUI.Update(reports, report)
+}
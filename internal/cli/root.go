@@ -5,6 +5,11 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/console"
+	"github.com/onosproject/helmit/internal/images"
+	"github.com/onosproject/helmit/internal/k8s"
 	"github.com/onosproject/helmit/internal/logging"
 	"math/rand"
 	"time"
@@ -13,10 +18,9 @@ import (
 )
 
 const (
-	defaultRunnerImage = "onosproject/helmit-runner"
-	binFile            = "/var/helmit/bin/run"
-	contextDir         = "/var/helmit/context"
-	valuesDir          = "/var/helmit/values"
+	binFile    = "/var/helmit/bin/run"
+	contextDir = "/var/helmit/context"
+	valuesDir  = "/var/helmit/values"
 )
 
 func init() {
@@ -30,13 +34,70 @@ func GetRootCommand() *cobra.Command {
 		Short:        "Setup test clusters and run integration tests on Kubernetes",
 		SilenceUsage: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			verbose, _ := cmd.Flags().GetBool("verbose")
-			logging.SetVerbose(verbose)
-			return nil
+			verbosity, _ := cmd.Flags().GetCount("verbose")
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			console.Init(noColor)
+			if quiet {
+				logging.SetLevel(logging.QuietLevel)
+			} else {
+				logging.SetLevel(logging.InfoLevel + logging.Level(verbosity))
+			}
+			kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+			kubeContext, _ := cmd.Flags().GetString("kube-context")
+			qps, _ := cmd.Flags().GetFloat32("kube-qps")
+			burst, _ := cmd.Flags().GetInt("kube-burst")
+			if qps < 0 || burst < 0 {
+				return errors.New("--kube-qps and --kube-burst must not be negative")
+			}
+			runnerImage, _ := cmd.Flags().GetString("runner-image")
+			builderImage, _ := cmd.Flags().GetString("builder-image")
+			images.SetRunner(runnerImage)
+			images.SetBuilder(builderImage)
+			if err := k8s.SetConfig(kubeconfig, kubeContext); err != nil {
+				return err
+			}
+			return k8s.SetRateLimits(qps, burst)
 		},
 	}
 	cmd.AddCommand(getTestCommand())
 	cmd.AddCommand(getBenchCommand())
-	cmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
+	cmd.AddCommand(getRunCommand())
+	cmd.AddCommand(getCleanupCommand())
+	cmd.AddCommand(getListCommand())
+	cmd.AddCommand(getStatusCommand())
+	cmd.AddCommand(getLogsCommand())
+	cmd.AddCommand(getAttachCommand())
+	cmd.AddCommand(getReplayCommand())
+	cmd.AddCommand(getImagesCommand())
+	cmd.PersistentFlags().CountP("verbose", "v", "enable verbose output (-v streams worker logs, -vv includes Kubernetes API operations)")
+	cmd.PersistentFlags().BoolP("quiet", "q", false, "output only final results and errors")
+	cmd.PersistentFlags().Bool("no-color", false, "disable colored output (colors are also disabled when the output is not a terminal or NO_COLOR is set)")
+	cmd.PersistentFlags().String("kubeconfig", "", "the path to the kubeconfig file to use in place of the in-cluster or default configuration")
+	cmd.PersistentFlags().String("kube-context", "", "the name of the kubeconfig context to use")
+	cmd.PersistentFlags().Float32("kube-qps", 0, "the maximum rate of requests per second to the Kubernetes API server from helmit and its jobs (defaults to the client default)")
+	cmd.PersistentFlags().Int("kube-burst", 0, "the maximum burst of requests to the Kubernetes API server from helmit and its jobs (defaults to the client default)")
+	cmd.PersistentFlags().String("runner-image", "", "the runner image repository, or an image reference with a tag or digest, to use in place of the published image matching the helmit version")
+	cmd.PersistentFlags().String("builder-image", "", "the builder image repository, or an image reference with a tag or digest, to use in place of the published image matching the helmit version")
+	cmd.PersistentFlags().BoolP("yes", "y", false, "do not ask for confirmation before modifying the target cluster")
 	return cmd
 }
+
+// ExitError is returned by commands that completed but must exit with a non-zero code, e.g. because tests failed
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.Code)
+}
+
+// exit returns the error with which a command completing with the given exit code returns
+// The process exits with the code once the command returns, after its deferred cleanup has run.
+func exit(cmd *cobra.Command, code int) error {
+	if code == 0 {
+		return nil
+	}
+	cmd.SilenceErrors = true
+	return &ExitError{Code: code}
+}
@@ -0,0 +1,412 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	petname "github.com/dustinkirkland/golang-petname"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/images"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/run"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const runExamples = `
+  # Run a job packaged in a Docker image.
+  helmit run --image atomix/kubernetes-setup:latest
+
+  # Run the binary built into an image without copying anything to the job pod.
+  helmit run --image atomix/kubernetes-setup:latest --no-copy --entrypoint /usr/local/bin/setup
+
+  # Run a job by referencing a command package and providing a context.
+  # The specified context will be loaded into the job pod as the current working directory.
+  helmit run ./cmd/job --context ./charts
+
+  # Run a job in a specific namespace.
+  helmit run ./cmd/job -n setup
+
+  # Override Helm chart values with flags.
+  # Value overrids must be namespaced with the name of the release to which to apply the value.
+  helmit run ./cmd/job -c ./charts --set atomix-controller.image=atomix/atomix-controller:latest
+
+  # Override Helm chart values with values files.
+  # Values files must be key/value pairs where the key is the Helm release name and the value the path to the file.
+  helmit run ./cmd/job -c ./charts -f atomix-controller=./atomix-controller.yaml
+`
+
+func getRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "run",
+		Short:   "Run a job on Kubernetes",
+		Example: runExamples,
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    runRunCommand,
+	}
+	cmd.Flags().StringP("namespace", "n", "", "the namespace in which to run the job")
+	cmd.Flags().Bool("create-namespace", false, "whether to create the namespace when running the job")
+	cmd.Flags().String("service-account", "", "the name of the service account to use to run the job pod")
+	cmd.Flags().String("rbac-rules", "", "a YAML file containing RBAC policy rules to grant the job pod in place of cluster-admin")
+	cmd.Flags().Bool("namespaced-rbac", false, "whether to grant the RBAC rules with a namespaced Role rather than a ClusterRole")
+	cmd.Flags().StringP("context", "c", "", "the job context")
+	cmd.Flags().StringP("image", "i", "", "the job image to run")
+	cmd.Flags().String("image-pull-policy", string(corev1.PullIfNotPresent), "the Docker image pull policy")
+	cmd.Flags().String("arch", "", "the CPU architecture for which to build the job (defaults to the architecture of the cluster's nodes)")
+	cmd.Flags().StringToStringP("label", "l", map[string]string{}, "labels to apply to the job pod")
+	cmd.Flags().StringToStringP("annotation", "a", map[string]string{}, "annotations to apply to the job pod")
+	cmd.Flags().String("sidecar-manifest", "", "a YAML file containing sidecar containers and volumes to add to the job pod")
+	cmd.Flags().StringArrayP("values", "f", []string{}, "release values paths")
+	cmd.Flags().StringArray("set", []string{}, "chart value overrides")
+	cmd.Flags().StringArray("set-secret", []string{}, "release value overrides passed to the pod in a Kubernetes Secret rather than in the job configuration, in the format {release}.{path}={value}")
+	cmd.Flags().Duration("timeout", 10*time.Minute, "job timeout")
+	cmd.Flags().StringSlice("secret", []string{}, "secrets to pass to the kubernetes pod")
+	cmd.Flags().StringSlice("secret-from", []string{}, "existing Kubernetes secrets in the format [{namespace}/]{name} whose keys to pass to the kubernetes pod")
+	cmd.Flags().StringToString("arg", map[string]string{}, "a mapping of named job arguments")
+	cmd.Flags().String("log-file", "", "a file to which to write the raw output of the job pod")
+	addBuildFlags(cmd)
+	addNoCopyFlags(cmd)
+	addSchedulingFlags(cmd, "job pod")
+	addNamespaceFlags(cmd)
+	addReadinessFlags(cmd)
+	addTeardownFlags(cmd)
+	addHookFlags(cmd)
+	addChartDependencyFlags(cmd)
+	addWorkflowFlags(cmd)
+	return cmd
+}
+
+func runRunCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	if err := applyProjectFile(cmd); err != nil {
+		return err
+	}
+
+	namespace, _ := cmd.Flags().GetString("namespace")
+	createNamespace, _ := cmd.Flags().GetBool("create-namespace")
+	serviceAccount, _ := cmd.Flags().GetString("service-account")
+	rbacRules, _ := cmd.Flags().GetString("rbac-rules")
+	namespacedRBAC, _ := cmd.Flags().GetBool("namespaced-rbac")
+	contextPath, _ := cmd.Flags().GetString("context")
+	image, _ := cmd.Flags().GetString("image")
+	labels, _ := cmd.Flags().GetStringToString("label")
+	annotations, _ := cmd.Flags().GetStringToString("annotation")
+	sidecarManifest, _ := cmd.Flags().GetString("sidecar-manifest")
+	files, _ := cmd.Flags().GetStringArray("values")
+	sets, _ := cmd.Flags().GetStringArray("set")
+	setSecrets, _ := cmd.Flags().GetStringArray("set-secret")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	imagePullPolicy, _ := cmd.Flags().GetString("image-pull-policy")
+	pullPolicy := corev1.PullPolicy(imagePullPolicy)
+	arch, _ := cmd.Flags().GetString("arch")
+	secretsArray, _ := cmd.Flags().GetStringSlice("secret")
+	secretsFrom, _ := cmd.Flags().GetStringSlice("secret-from")
+	jobArgs, _ := cmd.Flags().GetStringToString("arg")
+	logFile, _ := cmd.Flags().GetString("log-file")
+	buildInCluster, _ := cmd.Flags().GetBool("build-in-cluster")
+
+	// Either a command package or image must be specified
+	if len(args) == 0 && image == "" {
+		return errors.New("must specify either a command package or --image to run")
+	}
+
+	// Generate a unique job ID
+	jobID := petname.Generate(2, "-")
+
+	// If the create-namespace is enabled, generate a default namespace if not specified.
+	if namespace == "" {
+		if createNamespace {
+			namespace = jobID
+		} else {
+			namespace = "default"
+		}
+	}
+
+	valueFiles, err := parseFiles(files)
+	if err != nil {
+		return err
+	}
+
+	values, err := parseOverrides(sets)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := parseSecrets(secretsArray)
+	if err != nil {
+		return err
+	}
+
+	secretValues, valueSecrets, err := parseSecretOverrides(setSecrets)
+	if err != nil {
+		return err
+	}
+	for name, value := range valueSecrets {
+		secrets[name] = value
+	}
+
+	rules, err := parseRules(rbacRules)
+	if err != nil {
+		return err
+	}
+
+	scheduling, err := getScheduling(cmd)
+	if err != nil {
+		return err
+	}
+
+	namespaceMeta, err := getNamespaceMetadata(cmd, createNamespace)
+	if err != nil {
+		return err
+	}
+
+	readiness, err := getReadiness(cmd)
+	if err != nil {
+		return err
+	}
+
+	teardown, err := getTeardown(cmd)
+	if err != nil {
+		return err
+	}
+
+	hooks, err := getHooks(cmd, nil)
+	if err != nil {
+		return err
+	}
+
+	noCopy, err := getNoCopy(cmd, image, args)
+	if err != nil {
+		return err
+	}
+
+	workflow, err := getWorkflowEngine(cmd, noCopy)
+	if err != nil {
+		return err
+	}
+
+	chartDeps, err := getChartDependencies(cmd)
+	if err != nil {
+		return err
+	}
+
+	sidecars, sidecarVolumes, err := parseSidecars(sidecarManifest)
+	if err != nil {
+		return err
+	}
+
+	logs, err := parseLogFile(logFile)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	// Hooks provisioning the cluster are run before the cluster is used and once the job is torn down
+	if workflow == "" {
+		defer hooks.afterCluster(true)
+		if err := hooks.beforeCluster(jobID, namespace); err != nil {
+			return err
+		}
+		if err := confirmCluster(cmd); err != nil {
+			return err
+		}
+	}
+
+	var executable string
+	var source *job.Source
+	if len(args) > 0 {
+		step := logging.NewStep(jobID, "Preparing artifacts")
+		step.Start()
+		arch = getArch(step, arch, workflow == "")
+		options := getBuildOptions(cmd)
+		if buildInCluster {
+			if image == "" {
+				image = images.Builder(arch)
+			}
+			main, err := build.GetSource(args[0])
+			if err != nil {
+				step.Fail(err)
+				return err
+			}
+			source = newJobSource(main, options)
+		} else {
+			if image == "" {
+				image = images.Runner(arch)
+			}
+			executable = filepath.Join(os.TempDir(), "helmit", jobID)
+			defer os.RemoveAll(executable)
+			if err := build.Binary(step, executable, args[0], arch, options); err != nil {
+				step.Fail(err)
+				return err
+			}
+		}
+		step.Complete()
+	}
+
+	// Missing chart dependencies are built into a copy of the context, which is removed once it's been copied
+	vendoredContext, err := chartDeps.vendor(jobID, namespace, contextPath)
+	if err != nil {
+		return err
+	}
+	defer removeVendoredContext(vendoredContext, contextPath)
+
+	config := run.Config{
+		Namespace:    namespace,
+		Values:       values,
+		SecretValues: secretValues,
+		Args:         jobArgs,
+		Timeout:      timeout,
+	}
+
+	if contextPath != "" {
+		config.Context = filepath.Join(job.HomeDir, job.ContextDir)
+	}
+
+	if len(valueFiles) > 0 {
+		config.ValueFiles = make(map[string][]string)
+		for release, releaseFiles := range valueFiles {
+			var absFiles []string
+			for _, releaseFile := range releaseFiles {
+				absFiles = append(absFiles, filepath.Join(job.HomeDir, filepath.Base(releaseFile)))
+			}
+			config.ValueFiles[release] = absFiles
+		}
+	}
+
+	job := job.Job[run.Config]{
+		ID:                   jobID,
+		Namespace:            namespace,
+		CreateNamespace:      createNamespace,
+		DeleteNamespace:      createNamespace,
+		NamespaceLabels:      namespaceMeta.labels,
+		NamespaceAnnotations: namespaceMeta.annotations,
+		ServiceAccount:       serviceAccount,
+		Rules:                rules,
+		NamespacedRBAC:       namespacedRBAC,
+		Image:                image,
+		ImagePullPolicy:      pullPolicy,
+		NodeSelector:         scheduling.nodeSelector,
+		Tolerations:          scheduling.tolerations,
+		Affinity:             scheduling.affinity,
+		PriorityClassName:    scheduling.priorityClassName,
+		Sidecars:             sidecars,
+		SidecarVolumes:       sidecarVolumes,
+		Labels:               labels,
+		Annotations:          annotations,
+		Command:              noCopy.command,
+		NoCopy:               noCopy.enabled,
+		Executable:           executable,
+		Source:               source,
+		Context:              vendoredContext,
+		ValueFiles:           valueFiles,
+		Secrets:              secrets,
+		SecretsFrom:          secretsFrom,
+		PollInterval:         readiness.pollInterval,
+		ReadyTimeout:         readiness.readyTimeout,
+		Config:               config,
+	}
+
+	if workflow != "" {
+		return printWorkflow(cmd.OutOrStdout(), &job, workflow)
+	}
+
+	interrupt := newInterruptHandler(cmd.ErrOrStderr())
+	defer interrupt.stop()
+	ctx := interrupt.ctx
+
+	step := logging.NewStep(jobID, "Setting up job")
+	step.Start()
+	if err := createJob(ctx, job, step, interrupt, timeout); err != nil {
+		step.Fail(err)
+		if interrupt.interrupted() {
+			interrupt.writeSummary(cmd.OutOrStdout(), jobID)
+		}
+		return err
+	}
+	step.Complete()
+	removeVendoredContext(vendoredContext, contextPath)
+
+	step = logging.NewStep(jobID, "Running job")
+	step.Start()
+
+	doneCh := make(chan error, 1)
+	go func() {
+		// Open a log stream for the job
+		stream, err := job.GetLogs(ctx)
+		if err != nil {
+			doneCh <- err
+			return
+		}
+		defer stream.Close()
+
+		sink := logging.NewTeeSink(logging.NewConsoleSink(cmd.OutOrStdout(), logging.InfoLevel), logs)
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			_ = sink.Write(jobID, scanner.Text())
+		}
+		doneCh <- nil
+	}()
+
+	// Interrupting the job also ends the log stream, so check for the interrupt once either is done
+	select {
+	case <-ctx.Done():
+	case err = <-doneCh:
+	}
+
+	if interrupt.interrupted() {
+		step.Fail(errors.New("job canceled"))
+
+		step = logging.NewStep(jobID, "Cancelling job")
+		step.Start()
+		if err := deleteJob(job, step, interrupt, timeout); err != nil {
+			step.Fail(err)
+			return err
+		}
+		step.Complete()
+		interrupt.writeSummary(cmd.OutOrStdout(), jobID)
+		return errInterrupted
+	}
+
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+
+	// Get the exit code for the job.
+	_, code, err := job.GetStatus(ctx)
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+	if code == 0 {
+		step.Complete()
+	} else {
+		step.Fail(errors.New("job failed"))
+	}
+
+	step = logging.NewStep(jobID, "Cleaning up job")
+	step.Start()
+	if err := job.Delete(ctx, step); err != nil {
+		step.Fail(err)
+		return err
+	}
+	step.Complete()
+
+	if job.DeleteNamespace {
+		_ = awaitTeardown(cmd.OutOrStdout(), jobID, namespace, teardown)
+	}
+	if err := hooks.afterCluster(code != 0); err != nil && code == 0 {
+		code = 1
+	}
+
+	return exit(cmd, code)
+}
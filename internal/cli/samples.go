@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	csvSamplesFormat  = ".csv"
+	jsonSamplesFormat = ".jsonl"
+	// maxReportSize is the maximum size of a worker report line, which includes the worker's samples
+	maxReportSize = 16 * 1024 * 1024
+)
+
+var samplesHeader = []string{"run", "worker", "start", "latency_ns", "error", "weight"}
+
+// newSamplesWriter creates the given samples file, in CSV or JSON Lines format depending on its extension
+func newSamplesWriter(path string) (*samplesWriter, error) {
+	format := filepath.Ext(path)
+	if format != csvSamplesFormat && format != jsonSamplesFormat {
+		return nil, fmt.Errorf("unsupported samples file format %q: the samples file must be a %s or %s file", format, csvSamplesFormat, jsonSamplesFormat)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &samplesWriter{
+		file:   file,
+		format: format,
+	}
+	if format == csvSamplesFormat {
+		w.csv = csv.NewWriter(file)
+		if err := w.csv.Write(samplesHeader); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// samplesWriter writes the iterations sampled by benchmark workers to a file for offline analysis
+type samplesWriter struct {
+	file   *os.File
+	format string
+	csv    *csv.Writer
+	mu     sync.Mutex
+}
+
+// sampleRecord is a sample written to a JSON Lines samples file
+type sampleRecord struct {
+	Run     string        `json:"run,omitempty"`
+	Worker  int           `json:"worker"`
+	Start   time.Time     `json:"start"`
+	Latency time.Duration `json:"latency"`
+	Error   bool          `json:"error,omitempty"`
+	Weight  float64       `json:"weight"`
+}
+
+// write writes the given samples reported by a worker during the given run
+// The weight is the number of iterations represented by each sample.
+func (w *samplesWriter) write(run string, worker int, samples []benchmark.Sample, weight float64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.csv != nil {
+		for _, sample := range samples {
+			err := w.csv.Write([]string{
+				run,
+				strconv.Itoa(worker),
+				sample.Start.Format(time.RFC3339Nano),
+				strconv.FormatInt(sample.Latency.Nanoseconds(), 10),
+				strconv.FormatBool(sample.Error),
+				strconv.FormatFloat(weight, 'f', -1, 64),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+
+	encoder := json.NewEncoder(w.file)
+	for _, sample := range samples {
+		err := encoder.Encode(sampleRecord{
+			Run:     run,
+			Worker:  worker,
+			Start:   sample.Start,
+			Latency: sample.Latency,
+			Error:   sample.Error,
+			Weight:  weight,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the samples file
+func (w *samplesWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	return w.file.Close()
+}
+
+// samplesUI is a benchmarkUI that writes the samples in worker reports to the samples file
+// Samples are removed from the reports before they are displayed or recorded, since they are only needed
+// in the samples file.
+type samplesUI struct {
+	benchmarkUI
+	writer *samplesWriter
+	job    string
+	run    string
+}
+
+func (ui *samplesUI) Update(reports []*workerReport, report workerReport) {
+	if len(report.Samples) > 0 {
+		if err := ui.writer.write(ui.run, report.worker, report.Samples, report.SampleWeight); err != nil {
+			ui.Log(ui.job, fmt.Sprintf("Failed to write samples: %s", err))
+		}
+		report.Samples = nil
+		if latest := reports[report.worker]; latest != nil {
+			latest.Samples = nil
+		}
+	}
+	ui.benchmarkUI.Update(reports, report)
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplesWriter(t *testing.T) {
+	dir := t.TempDir()
+	_, err := newSamplesWriter(filepath.Join(dir, "samples.parquet"))
+	assert.Error(t, err)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []benchmark.Sample{
+		{Start: start, Latency: time.Millisecond},
+		{Start: start.Add(time.Second), Latency: 2 * time.Millisecond, Error: true},
+	}
+
+	path := filepath.Join(dir, "samples.csv")
+	writer, err := newSamplesWriter(path)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.write("replicas=1", 2, samples, 1.5))
+	assert.NoError(t, writer.Close())
+	bytes, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"run,worker,start,latency_ns,error,weight",
+		"replicas=1,2,2023-01-01T00:00:00Z,1000000,false,1.5",
+		"replicas=1,2,2023-01-01T00:00:01Z,2000000,true,1.5",
+	}, strings.Split(strings.TrimSpace(string(bytes)), "\n"))
+
+	path = filepath.Join(dir, "samples.jsonl")
+	writer, err = newSamplesWriter(path)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.write("", 0, samples, 1))
+	assert.NoError(t, writer.Close())
+	bytes, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"worker":0,"start":"2023-01-01T00:00:00Z","latency":1000000,"weight":1}`,
+		`{"worker":0,"start":"2023-01-01T00:00:01Z","latency":2000000,"error":true,"weight":1}`,
+	}, strings.Split(strings.TrimSpace(string(bytes)), "\n"))
+}
+
+func TestSamplesUI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	writer, err := newSamplesWriter(path)
+	assert.NoError(t, err)
+
+	ui := &samplesUI{benchmarkUI: &recordingUI{}, writer: writer, job: "bench"}
+	report := workerReport{
+		Report: benchmark.Report{
+			Iterations:   2,
+			Samples:      []benchmark.Sample{{Latency: time.Millisecond}, {Latency: time.Second}},
+			SampleWeight: 1,
+		},
+		worker: 0,
+	}
+	reports := []*workerReport{&report}
+	ui.Update(reports, report)
+	assert.NoError(t, writer.Close())
+
+	// Samples are written to the file but not passed on to the wrapped UI
+	assert.Nil(t, reports[0].Samples)
+	assert.Len(t, ui.benchmarkUI.(*recordingUI).reports, 1)
+	assert.Nil(t, ui.benchmarkUI.(*recordingUI).reports[0].Samples)
+	bytes, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(bytes)), "\n"), 3)
+}
+
+type recordingUI struct {
+	benchmarkUI
+	reports []workerReport
+}
+
+func (ui *recordingUI) Update(_ []*workerReport, report workerReport) {
+	ui.reports = append(ui.reports, report)
+}
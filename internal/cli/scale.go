@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+func newAdaptiveScaler(targetP99 time.Duration, maxWorkers int) *adaptiveScaler {
+	return &adaptiveScaler{
+		targetP99:  targetP99,
+		maxWorkers: maxWorkers,
+		reported:   make(map[int]bool),
+	}
+}
+
+// adaptiveScaler adds benchmark workers one at a time until the observed 99th percentile latency exceeds
+// the target, tracking the maximum throughput sustained within the target
+type adaptiveScaler struct {
+	targetP99      time.Duration
+	maxWorkers     int
+	reported       map[int]bool
+	exceeded       bool
+	exceededP99    time.Duration
+	exceededCount  int
+	bestThroughput float64
+	bestWorkers    int
+	bestP99        time.Duration
+}
+
+// scaleAction is an action to be taken by the benchmark in response to a report
+type scaleAction int
+
+const (
+	// scaleNone indicates the worker set should not change
+	scaleNone scaleAction = iota
+	// scaleUp indicates a worker should be added
+	scaleUp
+	// scaleStop indicates the benchmark should be stopped
+	scaleStop
+)
+
+// observe records the given report and returns the action to take
+// A scaling decision is made only once every running worker has reported since the last change to the worker set.
+func (s *adaptiveScaler) observe(reports []*workerReport, report workerReport) scaleAction {
+	if s.exceeded {
+		return scaleNone
+	}
+	s.reported[report.worker] = true
+	if len(s.reported) < len(reports) {
+		return scaleNone
+	}
+
+	var throughput float64
+	var p99 time.Duration
+	for _, report := range reports {
+		if report == nil {
+			return scaleNone
+		}
+		throughput += getThroughput(report.Report)
+		if report.P99Latency > p99 {
+			p99 = report.P99Latency
+		}
+	}
+
+	if p99 > s.targetP99 {
+		s.exceeded = true
+		s.exceededP99 = p99
+		s.exceededCount = len(reports)
+		return scaleStop
+	}
+
+	if throughput > s.bestThroughput {
+		s.bestThroughput = throughput
+		s.bestWorkers = len(reports)
+		s.bestP99 = p99
+	}
+	if len(reports) >= s.maxWorkers {
+		return scaleNone
+	}
+	s.reported = make(map[int]bool)
+	return scaleUp
+}
+
+// writeResult writes the maximum sustainable throughput found by the scaler to the given writer
+// Nothing is written by a nil scaler, i.e. when the benchmark is not run in adaptive mode.
+func (s *adaptiveScaler) writeResult(out io.Writer) {
+	if s == nil {
+		return
+	}
+	if s.bestWorkers == 0 {
+		if s.exceeded {
+			fmt.Fprintf(out, "99%% latency %s exceeded the target %s with %d workers; no sustainable throughput found\n",
+				s.exceededP99, s.targetP99, s.exceededCount)
+		} else {
+			fmt.Fprintln(out, "The benchmark ended before a sustainable throughput was measured")
+		}
+		return
+	}
+	fmt.Fprintf(out, "Maximum sustainable throughput: %f/sec with %d workers (99%% latency %s, target %s)\n",
+		s.bestThroughput, s.bestWorkers, s.bestP99, s.targetP99)
+	if s.exceeded {
+		fmt.Fprintf(out, "99%% latency %s exceeded the target with %d workers\n", s.exceededP99, s.exceededCount)
+	} else if s.bestWorkers >= s.maxWorkers {
+		fmt.Fprintf(out, "The target was not exceeded with the maximum of %d workers\n", s.maxWorkers)
+	} else {
+		fmt.Fprintln(out, "The benchmark ended before the target was exceeded")
+	}
+}
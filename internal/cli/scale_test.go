@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveScaler(t *testing.T) {
+	// observation is a report from a worker along with the latest reports of all running workers, identified by
+	// their 99th percentile latency; a negative latency marks a worker that has not reported yet
+	type observation struct {
+		p99s   []time.Duration
+		worker int
+		want   scaleAction
+	}
+
+	tests := []struct {
+		name         string
+		maxWorkers   int
+		observations []observation
+		wantWorkers  int
+		wantExceeded bool
+	}{
+		{
+			name:       "scales up once every worker has reported",
+			maxWorkers: 3,
+			observations: []observation{
+				{p99s: []time.Duration{10 * time.Millisecond}, worker: 0, want: scaleUp},
+				{p99s: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, worker: 0, want: scaleNone},
+				{p99s: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, worker: 1, want: scaleUp},
+			},
+			wantWorkers: 2,
+		},
+		{
+			name:       "waits for workers that have not reported",
+			maxWorkers: 3,
+			observations: []observation{
+				{p99s: []time.Duration{10 * time.Millisecond, -1}, worker: 0, want: scaleNone},
+				{p99s: []time.Duration{10 * time.Millisecond, -1}, worker: 1, want: scaleNone},
+			},
+		},
+		{
+			name:       "stops when the target is exceeded",
+			maxWorkers: 3,
+			observations: []observation{
+				{p99s: []time.Duration{50 * time.Millisecond}, worker: 0, want: scaleUp},
+				{p99s: []time.Duration{50 * time.Millisecond, 150 * time.Millisecond}, worker: 0, want: scaleNone},
+				{p99s: []time.Duration{50 * time.Millisecond, 150 * time.Millisecond}, worker: 1, want: scaleStop},
+				{p99s: []time.Duration{50 * time.Millisecond, 150 * time.Millisecond}, worker: 0, want: scaleNone},
+			},
+			wantWorkers:  1,
+			wantExceeded: true,
+		},
+		{
+			name:       "stops without a sustainable throughput",
+			maxWorkers: 3,
+			observations: []observation{
+				{p99s: []time.Duration{time.Second}, worker: 0, want: scaleStop},
+			},
+			wantExceeded: true,
+		},
+		{
+			name:       "does not scale beyond the maximum workers",
+			maxWorkers: 2,
+			observations: []observation{
+				{p99s: []time.Duration{10 * time.Millisecond}, worker: 0, want: scaleUp},
+				{p99s: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}, worker: 0, want: scaleNone},
+				{p99s: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}, worker: 1, want: scaleNone},
+				{p99s: []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}, worker: 0, want: scaleNone},
+			},
+			wantWorkers: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scaler := newAdaptiveScaler(100*time.Millisecond, test.maxWorkers)
+			for i, observation := range test.observations {
+				reports := make([]*workerReport, len(observation.p99s))
+				for worker, p99 := range observation.p99s {
+					if p99 >= 0 {
+						reports[worker] = &workerReport{
+							Report: benchmark.Report{
+								Iterations: 100,
+								Duration:   time.Second,
+								P99Latency: p99,
+							},
+							worker: worker,
+						}
+					}
+				}
+				report := workerReport{worker: observation.worker}
+				if reports[observation.worker] != nil {
+					report = *reports[observation.worker]
+				}
+				assert.Equal(t, observation.want, scaler.observe(reports, report), "observation %d", i)
+			}
+			assert.Equal(t, test.wantWorkers, scaler.bestWorkers)
+			assert.Equal(t, test.wantExceeded, scaler.exceeded)
+		})
+	}
+}
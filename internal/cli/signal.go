@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// interruptExitCode is the exit code of a command forced to exit by a second interrupt
+const interruptExitCode = 130
+
+// errInterrupted is returned by commands interrupted before they produced any results
+var errInterrupted = errors.New("interrupted")
+
+// newInterruptHandler returns an interruptHandler listening for interrupt and termination signals
+// Notices of interrupts are written to the given writer.
+func newInterruptHandler(out io.Writer) *interruptHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &interruptHandler{
+		ctx:      ctx,
+		cancel:   cancel,
+		out:      out,
+		start:    time.Now(),
+		signalCh: make(chan os.Signal, 1),
+	}
+	signal.Notify(handler.signalCh, os.Interrupt, syscall.SIGTERM)
+	go handler.listen()
+	return handler
+}
+
+// newContextInterruptHandler returns an interruptHandler that interrupts the command once the given context is
+// done, for commands run through pkg/launcher rather than from the command line
+func newContextInterruptHandler(parent context.Context) *interruptHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &interruptHandler{
+		ctx:    ctx,
+		cancel: cancel,
+		out:    io.Discard,
+		start:  time.Now(),
+	}
+	go func() {
+		select {
+		case <-parent.Done():
+			handler.interrupt()
+		case <-ctx.Done():
+		}
+	}()
+	return handler
+}
+
+// interruptHandler cancels a command's context when the command is interrupted, giving the command a chance
+// to stop its workers and delete the resources it created before exiting
+// Cleanup runs with contexts that are not canceled by the interrupt; a second interrupt exits immediately.
+type interruptHandler struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	out      io.Writer
+	start    time.Time
+	signalCh chan os.Signal
+	mu       sync.Mutex
+	canceled bool
+	cleanups []string
+}
+
+func (h *interruptHandler) listen() {
+	for range h.signalCh {
+		if !h.interrupt() {
+			fmt.Fprintln(h.out, "Interrupted again, exiting without cleaning up")
+			os.Exit(interruptExitCode)
+		}
+		fmt.Fprintln(h.out, "Interrupted, cleaning up (interrupt again to exit immediately)")
+	}
+}
+
+// interrupt cancels the command's context, returning false if the command was already interrupted
+func (h *interruptHandler) interrupt() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.canceled {
+		return false
+	}
+	h.canceled = true
+	h.cancel()
+	return true
+}
+
+// interrupted returns whether the command has been interrupted
+func (h *interruptHandler) interrupted() bool {
+	if h == nil {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.canceled
+}
+
+// record records a resource cleaned up or retained by the command for the cancellation summary
+func (h *interruptHandler) record(format string, args ...any) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cleanups = append(h.cleanups, fmt.Sprintf(format, args...))
+}
+
+// writeSummary writes a summary of the cleanup of an interrupted command to the given writer
+func (h *interruptHandler) writeSummary(out io.Writer, id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(out, "Canceled %s after %s\n", id, time.Since(h.start).Round(time.Second))
+	for _, cleanup := range h.cleanups {
+		fmt.Fprintf(out, "  %s\n", cleanup)
+	}
+}
+
+// stop stops listening for signals and releases the command's context
+func (h *interruptHandler) stop() {
+	if h.signalCh != nil {
+		signal.Stop(h.signalCh)
+	}
+	h.cancel()
+}
+
+// newCleanupContext returns a context for cleaning up resources that outlives an interrupt
+func newCleanupContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// createJob creates the given job, deleting any resources it created if creation is interrupted
+func createJob[T any](ctx context.Context, job job.Job[T], log logging.Logger, interrupt *interruptHandler, timeout time.Duration) error {
+	if err := job.Create(ctx, log); err != nil {
+		if interrupt.interrupted() {
+			_ = deleteJob(job, log, interrupt, timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteJob deletes the given job, recording the deleted resources for the cancellation summary
+func deleteJob[T any](job job.Job[T], log logging.Logger, interrupt *interruptHandler, timeout time.Duration) error {
+	ctx, cancel := newCleanupContext(timeout)
+	defer cancel()
+	if err := job.Delete(ctx, log); err != nil {
+		return err
+	}
+	interrupt.record("Deleted job %s", job.ID)
+	if job.DeleteNamespace {
+		interrupt.record("Deleted namespace %s", job.Namespace)
+	}
+	return nil
+}
+
+// keepNamespace records a namespace retained because teardown is disabled for the cancellation summary
+func keepNamespace[T any](job job.Job[T], interrupt *interruptHandler) {
+	if job.CreateNamespace && !job.DeleteNamespace {
+		interrupt.record("Kept namespace %s (--no-teardown)", job.Namespace)
+	}
+}
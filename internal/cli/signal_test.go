@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInterruptHandler(t *testing.T) {
+	var out bytes.Buffer
+	interrupt := newInterruptHandler(&out)
+	defer interrupt.stop()
+
+	assert.False(t, interrupt.interrupted())
+	assert.NoError(t, interrupt.ctx.Err())
+	assert.True(t, interrupt.interrupt())
+	assert.True(t, interrupt.interrupted())
+	assert.Error(t, interrupt.ctx.Err())
+	assert.False(t, interrupt.interrupt())
+
+	interrupt.record("Stopped worker %s", "happy-panda-worker-0")
+	keepNamespace(job.Job[benchmark.Config]{Namespace: "happy-panda", CreateNamespace: true}, interrupt)
+	keepNamespace(job.Job[benchmark.Config]{Namespace: "default"}, interrupt)
+	interrupt.writeSummary(&out, "happy-panda")
+	assert.Contains(t, out.String(), "Canceled happy-panda after")
+	assert.Contains(t, out.String(), "  Stopped worker happy-panda-worker-0\n  Kept namespace happy-panda (--no-teardown)\n")
+	assert.NotContains(t, out.String(), "default")
+
+	var handler *interruptHandler
+	assert.False(t, handler.interrupted())
+	handler.record("Deleted job %s", "happy-panda")
+}
+
+func TestContextInterruptHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := newContextInterruptHandler(ctx)
+	defer interrupt.stop()
+
+	assert.False(t, interrupt.interrupted())
+	cancel()
+	<-interrupt.ctx.Done()
+	assert.True(t, interrupt.interrupted())
+}
@@ -0,0 +1,247 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	meanMetric       = "mean"
+	p50Metric        = "p50"
+	p75Metric        = "p75"
+	p95Metric        = "p95"
+	p99Metric        = "p99"
+	errorRateMetric  = "errorRate"
+	throughputMetric = "throughput"
+)
+
+// sloOperators are the comparison operators supported in SLO expressions, longest first so "<=" is not
+// parsed as "<"
+var sloOperators = []string{"<=", ">=", "<", ">"}
+
+// sloObjective is an objective the aggregated metrics of a benchmark must meet, e.g. p99<100ms
+type sloObjective struct {
+	metric    string
+	operator  string
+	threshold float64
+	expr      string
+}
+
+// parseSLO parses a comma-separated list of SLO expressions in the format {metric}{operator}{threshold}
+// Latency metrics (mean, p50, p75, p95, p99) take a duration, errorRate a fraction or percentage, and throughput
+// a number of iterations per second.
+func parseSLO(value string) ([]sloObjective, error) {
+	var objectives []sloObjective
+	for _, expr := range strings.Split(value, ",") {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+		objective, err := parseSLOObjective(expr)
+		if err != nil {
+			return nil, err
+		}
+		objectives = append(objectives, objective)
+	}
+	if len(objectives) == 0 {
+		return nil, fmt.Errorf("no SLO expressions in %q", value)
+	}
+	return objectives, nil
+}
+
+func parseSLOObjective(expr string) (sloObjective, error) {
+	for _, operator := range sloOperators {
+		i := strings.Index(expr, operator)
+		if i < 0 {
+			continue
+		}
+		objective := sloObjective{
+			metric:   strings.TrimSpace(expr[:i]),
+			operator: operator,
+			expr:     expr,
+		}
+		threshold := strings.TrimSpace(expr[i+len(operator):])
+		switch objective.metric {
+		case meanMetric, p50Metric, p75Metric, p95Metric, p99Metric:
+			latency, err := time.ParseDuration(threshold)
+			if err != nil {
+				return sloObjective{}, fmt.Errorf("invalid SLO expression %q: %q is not a duration", expr, threshold)
+			}
+			objective.threshold = float64(latency)
+		case errorRateMetric:
+			errorRate, err := parsePercent(threshold)
+			if err != nil {
+				return sloObjective{}, fmt.Errorf("invalid SLO expression %q: %w", expr, err)
+			}
+			objective.threshold = errorRate
+		case throughputMetric:
+			throughput, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "/sec"), 64)
+			if err != nil {
+				return sloObjective{}, fmt.Errorf("invalid SLO expression %q: %q is not a number", expr, threshold)
+			}
+			objective.threshold = throughput
+		default:
+			return sloObjective{}, fmt.Errorf("invalid SLO expression %q: unknown metric %q", expr, objective.metric)
+		}
+		return objective, nil
+	}
+	return sloObjective{}, fmt.Errorf("invalid SLO expression %q: expected {metric}{<,<=,>,>=}{threshold}", expr)
+}
+
+// met returns whether the given value of the objective's metric meets the objective
+func (o sloObjective) met(value float64) bool {
+	switch o.operator {
+	case "<":
+		return value < o.threshold
+	case "<=":
+		return value <= o.threshold
+	case ">":
+		return value > o.threshold
+	default:
+		return value >= o.threshold
+	}
+}
+
+// format formats the given value of the objective's metric for display
+func (o sloObjective) format(value float64) string {
+	switch o.metric {
+	case errorRateMetric:
+		return fmt.Sprintf("%.2f%%", value*100)
+	case throughputMetric:
+		return fmt.Sprintf("%f/sec", value)
+	default:
+		return time.Duration(value).String()
+	}
+}
+
+// newSLOMonitor returns an sloMonitor evaluating the given objectives over the given window, or nil if there
+// are no objectives
+// If the window is shorter than the report interval, each interval's reports are evaluated.
+func newSLOMonitor(objectives []sloObjective, window time.Duration, interval time.Duration) *sloMonitor {
+	if len(objectives) == 0 {
+		return nil
+	}
+	if window < interval {
+		window = interval
+	}
+	return &sloMonitor{
+		objectives: objectives,
+		window:     window,
+		interval:   interval,
+	}
+}
+
+// sloMonitor evaluates the aggregated metrics of the worker reports received within a sliding window against
+// a set of objectives
+type sloMonitor struct {
+	objectives []sloObjective
+	window     time.Duration
+	interval   time.Duration
+	start      time.Time
+	reports    []windowReport
+}
+
+// windowReport is a worker report received within the evaluation window
+type windowReport struct {
+	workerReport
+	received time.Time
+}
+
+// sloBreach is an error describing the objectives breached by a benchmark
+type sloBreach struct {
+	window   time.Duration
+	breaches []string
+}
+
+func (b *sloBreach) Error() string {
+	return fmt.Sprintf("SLO breached over the last %s: %s", b.window, strings.Join(b.breaches, ", "))
+}
+
+// observe records the given report received at the given time, returning an sloBreach if the metrics aggregated
+// over the window breach any of the objectives
+// Objectives are not evaluated until reports covering a full window have been received.
+func (m *sloMonitor) observe(report workerReport, now time.Time) *sloBreach {
+	if m == nil {
+		return nil
+	}
+	if m.start.IsZero() {
+		// Each report covers the preceding report interval
+		m.start = now.Add(-m.interval)
+	}
+	m.reports = append(m.reports, windowReport{workerReport: report, received: now})
+	for len(m.reports) > 0 && now.Sub(m.reports[0].received) >= m.window {
+		m.reports = m.reports[1:]
+	}
+	if now.Sub(m.start) < m.window {
+		return nil
+	}
+
+	metrics, ok := m.aggregate()
+	if !ok {
+		return nil
+	}
+	var breaches []string
+	for _, objective := range m.objectives {
+		value := metrics[objective.metric]
+		if !objective.met(value) {
+			breaches = append(breaches, fmt.Sprintf("%s %s (objective %s)", objective.metric, objective.format(value), objective.expr))
+		}
+	}
+	if len(breaches) == 0 {
+		return nil
+	}
+	return &sloBreach{
+		window:   m.window,
+		breaches: breaches,
+	}
+}
+
+// aggregate returns the metrics aggregated over the reports in the window
+// Throughput is summed across workers, and latencies are averaged across reports weighted by their successful
+// iterations. If no iterations completed or failed in the window, no metrics are returned.
+func (m *sloMonitor) aggregate() (map[string]float64, bool) {
+	var iterations, failures int
+	var mean, p50, p75, p95, p99 float64
+	workerIterations := make(map[int]int)
+	workerDurations := make(map[int]time.Duration)
+	for _, report := range m.reports {
+		iterations += report.Iterations
+		failures += report.Errors
+		workerIterations[report.worker] += report.Iterations
+		workerDurations[report.worker] += report.Duration
+		weight := float64(report.Iterations)
+		mean += float64(report.MeanLatency) * weight
+		p50 += float64(report.P50Latency) * weight
+		p75 += float64(report.P75Latency) * weight
+		p95 += float64(report.P95Latency) * weight
+		p99 += float64(report.P99Latency) * weight
+	}
+	if iterations+failures == 0 {
+		return nil, false
+	}
+
+	var throughput float64
+	for worker, duration := range workerDurations {
+		if duration > 0 {
+			throughput += float64(workerIterations[worker]) / duration.Seconds()
+		}
+	}
+	metrics := map[string]float64{
+		errorRateMetric:  getErrorRate(iterations, failures),
+		throughputMetric: throughput,
+	}
+	if iterations > 0 {
+		metrics[meanMetric] = mean / float64(iterations)
+		metrics[p50Metric] = p50 / float64(iterations)
+		metrics[p75Metric] = p75 / float64(iterations)
+		metrics[p95Metric] = p95 / float64(iterations)
+		metrics[p99Metric] = p99 / float64(iterations)
+	}
+	return metrics, true
+}
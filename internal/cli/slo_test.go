@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseSLO(t *testing.T) {
+	objectives, err := parseSLO("p99<100ms, errorRate<=1%,throughput>=500/sec")
+	assert.NoError(t, err)
+	assert.Len(t, objectives, 3)
+	assert.Equal(t, p99Metric, objectives[0].metric)
+	assert.Equal(t, "<", objectives[0].operator)
+	assert.Equal(t, float64(100*time.Millisecond), objectives[0].threshold)
+	assert.Equal(t, errorRateMetric, objectives[1].metric)
+	assert.Equal(t, "<=", objectives[1].operator)
+	assert.Equal(t, 0.01, objectives[1].threshold)
+	assert.Equal(t, throughputMetric, objectives[2].metric)
+	assert.Equal(t, ">=", objectives[2].operator)
+	assert.Equal(t, 500.0, objectives[2].threshold)
+
+	_, err = parseSLO("")
+	assert.Error(t, err)
+	_, err = parseSLO("p99=100ms")
+	assert.Error(t, err)
+	_, err = parseSLO("p42<100ms")
+	assert.Error(t, err)
+	_, err = parseSLO("p99<fast")
+	assert.Error(t, err)
+	_, err = parseSLO("errorRate<lots")
+	assert.Error(t, err)
+}
+
+func TestSLOMonitor(t *testing.T) {
+	objectives, err := parseSLO("p99<100ms,errorRate<1%")
+	assert.NoError(t, err)
+	assert.Nil(t, newSLOMonitor(nil, time.Minute, time.Second))
+	var nilMonitor *sloMonitor
+	assert.Nil(t, nilMonitor.observe(workerReport{}, time.Now()))
+
+	monitor := newSLOMonitor(objectives, 3*time.Second, time.Second)
+	newReport := func(worker int, iterations int, errors int, p99 time.Duration) workerReport {
+		return workerReport{
+			Report: benchmark.Report{
+				Iterations: iterations,
+				Errors:     errors,
+				Duration:   time.Second,
+				P99Latency: p99,
+			},
+			worker: worker,
+		}
+	}
+
+	// Objectives are not evaluated until a full window has been reported
+	start := time.Now()
+	assert.Nil(t, monitor.observe(newReport(0, 100, 0, 500*time.Millisecond), start))
+	assert.Nil(t, monitor.observe(newReport(1, 100, 0, 500*time.Millisecond), start))
+	assert.Nil(t, monitor.observe(newReport(0, 100, 0, 50*time.Millisecond), start.Add(time.Second)))
+	assert.Nil(t, monitor.observe(newReport(1, 100, 0, 50*time.Millisecond), start.Add(time.Second)))
+
+	// The window is breached once the average latency across its reports exceeds the objective
+	breach := monitor.observe(newReport(0, 100, 0, 50*time.Millisecond), start.Add(2*time.Second))
+	assert.NotNil(t, breach)
+	assert.Equal(t, "SLO breached over the last 3s: p99 230ms (objective p99<100ms)", breach.Error())
+
+	// Reports older than the window are dropped
+	assert.Nil(t, monitor.observe(newReport(1, 100, 0, 50*time.Millisecond), start.Add(3*time.Second)))
+
+	// Error rates are aggregated across workers
+	breach = monitor.observe(newReport(0, 96, 4, 50*time.Millisecond), start.Add(4*time.Second))
+	assert.NotNil(t, breach)
+	assert.Contains(t, breach.Error(), "errorRate 1.33% (objective errorRate<1%)")
+
+	// A window shorter than the report interval evaluates each interval
+	monitor = newSLOMonitor(objectives, 0, time.Second)
+	assert.NotNil(t, monitor.observe(newReport(0, 10, 0, time.Second), start))
+}
@@ -0,0 +1,445 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// snapshotSuffix is appended to the benchmark ID to name the ConfigMap storing the benchmark's progress
+	snapshotSuffix = "-snapshot"
+	snapshotKey    = "snapshot"
+	// snapshotInterval is the minimum interval at which the benchmark's progress is stored
+	snapshotInterval = 10 * time.Second
+	// leaseDuration is the time for which the session coordinating a benchmark holds its lease after last storing
+	// the benchmark's progress, before another session may take the benchmark over
+	leaseDuration = 3 * snapshotInterval
+)
+
+// benchmarkSnapshot is the progress of a running benchmark, persisted so a benchmark whose session was lost
+// can be resumed with --resume
+type benchmarkSnapshot struct {
+	Namespace       string    `json:"namespace"`
+	DeleteNamespace bool      `json:"deleteNamespace,omitempty"`
+	Started         time.Time `json:"started"`
+	Updated         time.Time `json:"updated"`
+	// Holder identifies the session coordinating the benchmark, whose lease is renewed each time it stores the
+	// progress
+	Holder  string           `json:"holder,omitempty"`
+	Workers []workerProgress `json:"workers"`
+}
+
+// getLeaseRemaining returns how long the lease of the session coordinating the benchmark remains held, or zero
+// if the lease has expired
+func (s *benchmarkSnapshot) getLeaseRemaining() time.Duration {
+	if s.Holder == "" {
+		return 0
+	}
+	if remaining := leaseDuration - time.Since(s.Updated); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// workerProgress is the number of iterations completed by a benchmark worker
+type workerProgress struct {
+	Iterations int `json:"iterations"`
+	Errors     int `json:"errors,omitempty"`
+	// Job and Namespace address the worker's job, so a session resuming the benchmark reconnects to the same worker
+	Job       string `json:"job,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Durable indicates the worker's job is durable, so it's deleted and recovered as such once resumed
+	Durable bool `json:"durable,omitempty"`
+}
+
+// leaseLostError is returned when storing the progress of a benchmark that has been taken over by another session
+type leaseLostError struct {
+	holder string
+}
+
+func (e *leaseLostError) Error() string {
+	return fmt.Sprintf("benchmark was taken over by %s", e.holder)
+}
+
+// getHolder returns the identity with which this session holds the lease on the benchmarks it coordinates
+func getHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+// newSnapshotStore returns a store for the progress of the given benchmark
+func newSnapshotStore(namespace string, benchID string) (*snapshotStore, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotStore{
+		client:    client,
+		namespace: namespace,
+		benchID:   benchID,
+	}, nil
+}
+
+// findSnapshotStore finds the namespace in which the progress of the given benchmark is stored, returning nil
+// if no progress was found for the benchmark
+func findSnapshotStore(ctx context.Context, benchID string) (*snapshotStore, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	configMaps, err := client.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: job.JobLabel + "=" + benchID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		store := &snapshotStore{
+			client:    client,
+			namespace: configMap.Namespace,
+			benchID:   benchID,
+		}
+		if configMap.Name == store.getName() {
+			return store, nil
+		}
+	}
+	return nil, nil
+}
+
+// snapshotStore stores the progress of a benchmark in a ConfigMap in the benchmark's namespace
+// The ConfigMap is labeled with the benchmark ID, so it's removed by the cleanup command.
+type snapshotStore struct {
+	client    kubernetes.Interface
+	namespace string
+	benchID   string
+}
+
+func (s *snapshotStore) getName() string {
+	return s.benchID + snapshotSuffix
+}
+
+// put stores the given snapshot, returning a *leaseLostError if another session holds the lease on the stored
+// progress
+func (s *snapshotStore) put(ctx context.Context, snapshot benchmarkSnapshot) error {
+	bytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.getName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      s.getName(),
+					Namespace: s.namespace,
+					Labels:    job.NewLabels(s.benchID),
+				},
+				Data: map[string]string{
+					snapshotKey: string(bytes),
+				},
+			}
+			_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		// The progress may only be taken over from another session once its lease has expired. The update is
+		// conditioned on the version read, so a session taking the benchmark over in between is detected on the
+		// next attempt.
+		current := &benchmarkSnapshot{}
+		if err := json.Unmarshal([]byte(configMap.Data[snapshotKey]), current); err == nil &&
+			current.Holder != snapshot.Holder && current.getLeaseRemaining() > 0 {
+			return &leaseLostError{holder: current.Holder}
+		}
+		configMap.Data = map[string]string{
+			snapshotKey: string(bytes),
+		}
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// get returns the stored benchmark progress, or nil if no progress has been stored
+func (s *snapshotStore) get(ctx context.Context) (*benchmarkSnapshot, error) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.getName(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snapshot := &benchmarkSnapshot{}
+	if err := json.Unmarshal([]byte(configMap.Data[snapshotKey]), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// delete deletes the stored benchmark progress
+func (s *snapshotStore) delete(ctx context.Context) error {
+	err := s.client.CoreV1().ConfigMaps(s.namespace).Delete(ctx, s.getName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// newBenchmarkProgress returns the progress of a benchmark stored in the given store, resuming from the given
+// snapshot if it's not nil
+// The progress is stored under a lease held by this session. If another session takes the benchmark over, the
+// progress is no longer stored and the channel returned by lost is closed. Failures to store the progress are
+// passed to the given log function.
+func newBenchmarkProgress(store *snapshotStore, snapshot *benchmarkSnapshot, workers int, deleteNamespace bool, log func(message string)) *benchmarkProgress {
+	progress := &benchmarkProgress{
+		store:  store,
+		log:    log,
+		lostCh: make(chan struct{}),
+	}
+	if snapshot != nil {
+		progress.snapshot = *snapshot
+		progress.resumed = snapshot.Updated
+	} else {
+		progress.snapshot = benchmarkSnapshot{
+			Namespace:       store.namespace,
+			DeleteNamespace: deleteNamespace,
+			Started:         time.Now(),
+			Workers:         make([]workerProgress, workers),
+		}
+	}
+	progress.snapshot.Holder = getHolder()
+	return progress
+}
+
+// benchmarkProgress tracks the iterations completed by the workers of a running benchmark, periodically storing
+// a snapshot of the progress
+// Calls to a nil benchmarkProgress are ignored, so progress is only tracked for benchmarks that can be resumed.
+type benchmarkProgress struct {
+	store    *snapshotStore
+	snapshot benchmarkSnapshot
+	log      func(message string)
+	lostCh   chan struct{}
+	// holder is the holder of the lease once the benchmark has been taken over by another session
+	holder string
+	// resumed is the time the progress of a resumed benchmark was last stored by the session that started it
+	resumed time.Time
+	saved   time.Time
+	deleted bool
+	mu      sync.Mutex
+}
+
+// isRunning returns whether the given worker was started by the session that started a resumed benchmark
+func (p *benchmarkProgress) isRunning(worker int) bool {
+	return p != nil && !p.resumed.IsZero() && worker < len(p.snapshot.Workers)
+}
+
+// start records the address of the job of the given worker and whether the job is durable
+func (p *benchmarkProgress) start(worker int, job job.Job[benchmark.Config]) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.snapshot.Workers) <= worker {
+		p.snapshot.Workers = append(p.snapshot.Workers, workerProgress{})
+	}
+	p.snapshot.Workers[worker].Job = getWorkerID(job.ID, worker)
+	p.snapshot.Workers[worker].Namespace = job.Namespace
+	p.snapshot.Workers[worker].Durable = job.Durable
+}
+
+// getWorkerJob returns the given job of a worker started by the session that started a resumed benchmark,
+// addressed to the worker's job recorded by that session
+// Benchmarks stored by versions of helmit that did not record the workers' jobs are addressed by convention.
+func (p *benchmarkProgress) getWorkerJob(worker int, job job.Job[benchmark.Config]) job.Job[benchmark.Config] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	job.ID = getWorkerID(job.ID, worker)
+	if address := p.snapshot.Workers[worker]; address.Job != "" {
+		job.ID = address.Job
+		job.Namespace = address.Namespace
+		job.Durable = address.Durable
+	}
+	return job
+}
+
+// getResumed returns the time the progress of a resumed benchmark was last stored by the session that started it
+// Reports written by the benchmark's workers after that time have not been recorded.
+func (p *benchmarkProgress) getResumed() time.Time {
+	return p.resumed
+}
+
+// getRemaining returns how much of the given duration remains since the benchmark was started
+func (p *benchmarkProgress) getRemaining(duration time.Duration) time.Duration {
+	if p == nil {
+		return duration
+	}
+	return duration - time.Since(p.snapshot.Started)
+}
+
+// getTotals returns the total number of iterations and errors completed by all workers
+func (p *benchmarkProgress) getTotals() (int, int) {
+	if p == nil {
+		return 0, 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var iterations, errors int
+	for _, worker := range p.snapshot.Workers {
+		iterations += worker.Iterations
+		errors += worker.Errors
+	}
+	return iterations, errors
+}
+
+// record records the iterations in the given worker report, storing a snapshot if the snapshot interval
+// has elapsed since the last snapshot was stored
+func (p *benchmarkProgress) record(report workerReport) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.snapshot.Workers) <= report.worker {
+		p.snapshot.Workers = append(p.snapshot.Workers, workerProgress{})
+	}
+	p.snapshot.Workers[report.worker].Iterations += report.Iterations
+	p.snapshot.Workers[report.worker].Errors += report.Errors
+	if time.Since(p.saved) >= snapshotInterval {
+		p.put()
+	}
+}
+
+// renew stores the progress at the snapshot interval until the context is done, renewing the lease even while
+// no reports are received
+func (p *benchmarkProgress) renew(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			if time.Since(p.saved) >= snapshotInterval {
+				p.put()
+			}
+			p.mu.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// save stores a snapshot of the progress
+// Failures to store the snapshot are logged rather than failing the benchmark.
+func (p *benchmarkProgress) save() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.put()
+}
+
+func (p *benchmarkProgress) put() {
+	if p.deleted {
+		return
+	}
+	p.snapshot.Updated = time.Now()
+	p.saved = p.snapshot.Updated
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := p.store.put(ctx, p.snapshot); err != nil {
+		var lostErr *leaseLostError
+		if errors.As(err, &lostErr) {
+			p.deleted = true
+			p.holder = lostErr.holder
+			close(p.lostCh)
+			return
+		}
+		if p.log != nil {
+			p.log(fmt.Sprintf("Failed to store benchmark progress: %s", err))
+		}
+	}
+}
+
+// lost returns a channel that is closed once the benchmark has been taken over by another session
+func (p *benchmarkProgress) lost() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	return p.lostCh
+}
+
+// leaseErr returns a *leaseLostError if the benchmark has been taken over by another session
+func (p *benchmarkProgress) leaseErr() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.holder == "" {
+		return nil
+	}
+	return &leaseLostError{holder: p.holder}
+}
+
+// delete deletes the stored progress once the benchmark has completed
+func (p *benchmarkProgress) delete() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.deleted {
+		return nil
+	}
+	p.deleted = true
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return p.store.delete(ctx)
+}
+
+// findSnapshot returns the stored progress of the given benchmark and the store in which it was found
+func findSnapshot(benchID string, timeout time.Duration) (*snapshotStore, *benchmarkSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	store, err := findSnapshotStore(ctx, benchID)
+	if err != nil {
+		return nil, nil, err
+	} else if store == nil {
+		return nil, nil, fmt.Errorf("no running benchmark found for %s", benchID)
+	}
+	snapshot, err := store.get(ctx)
+	if err != nil {
+		return nil, nil, err
+	} else if snapshot == nil {
+		return nil, nil, fmt.Errorf("no running benchmark found for %s", benchID)
+	}
+	if remaining := snapshot.getLeaseRemaining(); remaining > 0 {
+		return nil, nil, fmt.Errorf("benchmark %s is still coordinated by %s; it can be resumed once its lease expires in %s",
+			benchID, snapshot.Holder, remaining.Round(time.Second))
+	}
+	return store, snapshot, nil
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func TestBenchmarkProgress(t *testing.T) {
+	store := &snapshotStore{
+		client:    fake.NewSimpleClientset(),
+		namespace: "test",
+		benchID:   "happy-panda",
+	}
+	ctx := context.Background()
+
+	progress := newBenchmarkProgress(store, nil, 2, true, nil)
+	assert.False(t, progress.isRunning(0))
+	progress.start(1, job.Job[benchmark.Config]{ID: "happy-panda", Namespace: "test"})
+	progress.save()
+	progress.record(workerReport{Report: benchmark.Report{Iterations: 10, Errors: 1}, worker: 0})
+	progress.record(workerReport{Report: benchmark.Report{Iterations: 20}, worker: 1})
+	progress.record(workerReport{Report: benchmark.Report{Iterations: 5}, worker: 0})
+	iterations, errors := progress.getTotals()
+	assert.Equal(t, 35, iterations)
+	assert.Equal(t, 1, errors)
+
+	// Reports are only stored once the snapshot interval has elapsed
+	snapshot, err := store.get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", snapshot.Namespace)
+	assert.True(t, snapshot.DeleteNamespace)
+	assert.Len(t, snapshot.Workers, 2)
+	assert.Equal(t, 0, snapshot.Workers[0].Iterations)
+	assert.Equal(t, "happy-panda-worker-1", snapshot.Workers[1].Job)
+	assert.Equal(t, getHolder(), snapshot.Holder)
+	assert.Greater(t, snapshot.getLeaseRemaining(), time.Duration(0))
+
+	progress.save()
+	snapshot, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, snapshot.Workers[0].Iterations)
+	assert.Equal(t, 1, snapshot.Workers[0].Errors)
+	assert.Equal(t, 20, snapshot.Workers[1].Iterations)
+
+	resumed := newBenchmarkProgress(store, snapshot, 1, false, nil)
+	assert.True(t, resumed.isRunning(0))
+	assert.True(t, resumed.isRunning(1))
+	assert.False(t, resumed.isRunning(2))
+	worker := job.Job[benchmark.Config]{ID: "happy-panda", Namespace: "other"}
+	assert.Equal(t, "happy-panda-worker-0", resumed.getWorkerJob(0, worker).ID)
+	assert.Equal(t, "other", resumed.getWorkerJob(0, worker).Namespace)
+	assert.Equal(t, "happy-panda-worker-1", resumed.getWorkerJob(1, worker).ID)
+	assert.Equal(t, "test", resumed.getWorkerJob(1, worker).Namespace)
+	assert.Equal(t, snapshot.Updated, resumed.getResumed())
+	iterations, errors = resumed.getTotals()
+	assert.Equal(t, 35, iterations)
+	assert.Equal(t, 1, errors)
+	assert.Less(t, resumed.getRemaining(time.Hour), time.Hour)
+
+	resumed.delete()
+	snapshot, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+
+	var untracked *benchmarkProgress
+	untracked.record(workerReport{Report: benchmark.Report{Iterations: 10}})
+	untracked.save()
+	assert.False(t, untracked.isRunning(0))
+	assert.Equal(t, time.Minute, untracked.getRemaining(time.Minute))
+}
+
+func TestBenchmarkLease(t *testing.T) {
+	store := &snapshotStore{
+		client:    fake.NewSimpleClientset(),
+		namespace: "test",
+		benchID:   "happy-panda",
+	}
+	ctx := context.Background()
+
+	progress := newBenchmarkProgress(store, nil, 1, false, nil)
+	progress.save()
+	assert.NoError(t, progress.leaseErr())
+
+	// Another session takes the benchmark over once the lease has expired
+	snapshot, err := store.get(ctx)
+	assert.NoError(t, err)
+	snapshot.Updated = time.Now().Add(-leaseDuration)
+	assert.Equal(t, time.Duration(0), snapshot.getLeaseRemaining())
+	assert.NoError(t, store.put(ctx, *snapshot))
+	snapshot.Holder = "other-host/1"
+	snapshot.Updated = time.Now()
+	assert.NoError(t, store.put(ctx, *snapshot))
+
+	// The lease cannot be taken back while the other session holds it
+	snapshot.Holder = getHolder()
+	var lostErr *leaseLostError
+	assert.ErrorAs(t, store.put(ctx, *snapshot), &lostErr)
+
+	progress.save()
+	select {
+	case <-progress.lost():
+	default:
+		t.Fatal("expected the lease to be lost")
+	}
+	assert.ErrorAs(t, progress.leaseErr(), &lostErr)
+	assert.Equal(t, "other-host/1", lostErr.holder)
+
+	// The session that lost the lease no longer stores or deletes the progress
+	assert.NoError(t, progress.delete())
+	snapshot, err = store.get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "other-host/1", snapshot.Holder)
+
+	assert.Equal(t, time.Duration(0), (&benchmarkSnapshot{Updated: time.Now()}).getLeaseRemaining())
+}
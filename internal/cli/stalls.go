@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"time"
+)
+
+// errWorkerStalled is returned by streamBenchmarkWorker when a stalled worker is deleted to be restarted
+var errWorkerStalled = errors.New("worker stalled")
+
+// workerStall is a worker that completed no iterations for the configured number of report intervals
+type workerStall struct {
+	worker int
+	// since is the time at which the worker last made progress
+	since time.Time
+	// restarted indicates whether the worker is being restarted
+	restarted bool
+}
+
+// newStallDetector returns a stallDetector flagging workers that complete no iterations for the given number of
+// report intervals, or nil if stall detection is disabled
+func newStallDetector(intervals int, restart bool) *stallDetector {
+	if intervals <= 0 {
+		return nil
+	}
+	return &stallDetector{
+		intervals: intervals,
+		restart:   restart,
+		ch:        make(chan workerStall),
+	}
+}
+
+// stallDetector polls the heartbeats of running workers for stalls
+type stallDetector struct {
+	intervals int
+	restart   bool
+	ch        chan workerStall
+}
+
+// stalls returns a channel on which stalled workers are reported
+func (d *stallDetector) stalls() <-chan workerStall {
+	if d == nil {
+		return nil
+	}
+	return d.ch
+}
+
+// monitor polls the heartbeats of the given worker at the report interval until the context is done, returning
+// a channel that's closed once monitoring stops
+// If the worker stalls and restarts are enabled, the restart function is called and monitoring stops.
+func (d *stallDetector) monitor(ctx context.Context, job job.Job[benchmark.Config], worker int, restart func()) <-chan struct{} {
+	doneCh := make(chan struct{})
+	if d == nil {
+		close(doneCh)
+		return doneCh
+	}
+	go func() {
+		defer close(doneCh)
+		interval := job.Config.ReportInterval
+		tracker := &stallTracker{timeout: time.Duration(d.intervals) * interval}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// The port forward to the worker is closed with the connection
+		var conn *grpc.ClientConn
+		var cancelConn context.CancelFunc
+		closeConn := func() {
+			if conn != nil {
+				_ = conn.Close()
+				cancelConn()
+				conn = nil
+			}
+		}
+		defer closeConn()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			// The connection is reestablished if the port forward is lost
+			if conn == nil {
+				connCtx, cancel := context.WithCancel(ctx)
+				c, err := dialWorker(connCtx, job)
+				if err != nil {
+					cancel()
+					continue
+				}
+				conn, cancelConn = c, cancel
+			}
+			heartbeat, err := benchmark.GetWorkerHeartbeat(ctx, conn)
+			if err != nil {
+				// Workers built with a version of helmit that does not report heartbeats are not monitored
+				if status.Code(err) == codes.Unimplemented {
+					return
+				}
+				closeConn()
+				continue
+			}
+
+			if !tracker.observe(heartbeat, time.Now()) {
+				continue
+			}
+			stall := workerStall{
+				worker:    worker,
+				since:     tracker.since(),
+				restarted: d.restart,
+			}
+			select {
+			case d.ch <- stall:
+			case <-ctx.Done():
+				return
+			}
+			if d.restart {
+				restart()
+				return
+			}
+		}
+	}()
+	return doneCh
+}
+
+// stallTracker tracks the progress of a worker through its heartbeats
+// Progress is timed by the local clock, since the clocks of the worker and the executor may be skewed.
+type stallTracker struct {
+	timeout       time.Duration
+	iterations    uint64
+	lastIteration time.Time
+	lastProgress  time.Time
+	stalled       bool
+}
+
+// observe records the given heartbeat received at the given time, returning true when the worker has made no
+// progress for the timeout
+// The timeout starts with the first heartbeat, so workers are not flagged while they're being set up, and a
+// stalled worker is flagged again only after it has resumed making progress.
+func (t *stallTracker) observe(heartbeat benchmark.Heartbeat, now time.Time) bool {
+	if t.lastProgress.IsZero() || heartbeat.Iterations != t.iterations {
+		t.iterations = heartbeat.Iterations
+		t.lastIteration = heartbeat.LastIteration
+		t.lastProgress = now
+		t.stalled = false
+		return false
+	}
+	if t.stalled || now.Sub(t.lastProgress) < t.timeout {
+		return false
+	}
+	t.stalled = true
+	return true
+}
+
+// since returns the time since which the worker has made no progress
+func (t *stallTracker) since() time.Time {
+	if !t.lastIteration.IsZero() {
+		return t.lastIteration
+	}
+	return t.lastProgress
+}
+
+// stallCounts counts the stalls and restarts of each worker
+type stallCounts map[int]*workerStalls
+
+// workerStalls is the number of times a worker stalled and was restarted
+type workerStalls struct {
+	stalls   int
+	restarts int
+}
+
+// record records the given stall
+func (c stallCounts) record(stall workerStall) {
+	counts, ok := c[stall.worker]
+	if !ok {
+		counts = &workerStalls{}
+		c[stall.worker] = counts
+	}
+	counts.stalls++
+	if stall.restarted {
+		counts.restarts++
+	}
+}
+
+// apply sets the stall counts of the given report's worker on the report
+func (c stallCounts) apply(report *workerReport) {
+	if counts, ok := c[report.worker]; ok {
+		report.stalls = counts.stalls
+		report.restarts = counts.restarts
+	}
+}
+
+// formatStall formats a stalled worker for display
+func formatStall(stall workerStall) string {
+	message := fmt.Sprintf("Worker %d stalled: no iterations completed since %s", stall.worker, stall.since.Format(time.RFC3339))
+	if stall.restarted {
+		message += ", restarting worker"
+	}
+	return message
+}
+
+// writeStalls writes the number of times each of the given workers stalled to the given writer
+func writeStalls(out io.Writer, reports []*workerReport) {
+	for _, report := range reports {
+		if report != nil && report.stalls > 0 {
+			fmt.Fprintf(out, "Worker %d stalled %d time(s) and was restarted %d time(s)\n", report.worker, report.stalls, report.restarts)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStallTracker(t *testing.T) {
+	tracker := &stallTracker{timeout: 3 * time.Second}
+	start := time.Now()
+	lastIteration := start.Add(-time.Second)
+
+	// The timeout starts with the first heartbeat, even if the worker has not completed any iterations
+	assert.False(t, tracker.observe(benchmark.Heartbeat{}, start))
+	assert.False(t, tracker.observe(benchmark.Heartbeat{}, start.Add(2*time.Second)))
+	assert.False(t, tracker.observe(benchmark.Heartbeat{Iterations: 10, LastIteration: lastIteration}, start.Add(2*time.Second)))
+	assert.False(t, tracker.observe(benchmark.Heartbeat{Iterations: 10, LastIteration: lastIteration}, start.Add(4*time.Second)))
+	assert.True(t, tracker.observe(benchmark.Heartbeat{Iterations: 10, LastIteration: lastIteration}, start.Add(5*time.Second)))
+	assert.Equal(t, lastIteration, tracker.since())
+
+	// A stalled worker is flagged once until it makes progress again
+	assert.False(t, tracker.observe(benchmark.Heartbeat{Iterations: 10, LastIteration: lastIteration}, start.Add(10*time.Second)))
+	assert.False(t, tracker.observe(benchmark.Heartbeat{Iterations: 11, LastIteration: start}, start.Add(11*time.Second)))
+	assert.True(t, tracker.observe(benchmark.Heartbeat{Iterations: 11, LastIteration: start}, start.Add(14*time.Second)))
+}
+
+func TestStallCounts(t *testing.T) {
+	counts := make(stallCounts)
+	counts.record(workerStall{worker: 1})
+	counts.record(workerStall{worker: 1, restarted: true})
+
+	reports := []*workerReport{{worker: 0}, {worker: 1}}
+	for _, report := range reports {
+		counts.apply(report)
+	}
+	assert.Equal(t, 0, reports[0].stalls)
+	assert.Equal(t, 2, reports[1].stalls)
+	assert.Equal(t, 1, reports[1].restarts)
+
+	var buf bytes.Buffer
+	writeStalls(&buf, reports)
+	assert.Equal(t, "Worker 1 stalled 2 time(s) and was restarted 1 time(s)\n", buf.String())
+
+	assert.Nil(t, newStallDetector(0, false))
+	var detector *stallDetector
+	assert.Nil(t, detector.stalls())
+	_, ok := <-detector.monitor(context.Background(), job.Job[benchmark.Config]{}, 0, nil)
+	assert.False(t, ok)
+}
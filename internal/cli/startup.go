@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// workerStartup configures how benchmark workers are brought up
+type workerStartup struct {
+	// batch is the maximum number of workers started at once, or 0 to start all workers at once
+	batch int
+	// timeout is the time allowed for each batch of workers to start, or 0 for no limit
+	timeout time.Duration
+}
+
+// newWorkerStarter returns a workerStarter bringing up the given number of workers in batches, logging progress
+// with the given function, or nil if workers are not started in batches
+func newWorkerStarter(startup workerStartup, workers int, log func(string)) *workerStarter {
+	if startup.batch <= 0 {
+		return nil
+	}
+	return &workerStarter{
+		workerStartup: startup,
+		workers:       workers,
+		log:           log,
+		batchDone:     make(chan struct{}),
+	}
+}
+
+// workerStarter limits the number of workers being started at once
+// Up to batch workers are started together, and the next batch is started once every worker in the current
+// batch has either started or failed to start.
+type workerStarter struct {
+	workerStartup
+	workers   int
+	log       func(string)
+	active    int
+	pending   int
+	batchDone chan struct{}
+	started   int
+	failed    int
+	mu        sync.Mutex
+}
+
+// acquire waits for a worker to be admitted to a batch, returning a context bounded by the batch timeout
+// in which to start the worker
+// The returned function must be called with the result of starting the worker.
+func (s *workerStarter) acquire(ctx context.Context) (context.Context, func(error), error) {
+	if s == nil {
+		return ctx, func(error) {}, nil
+	}
+	for {
+		s.mu.Lock()
+		if s.active < s.batch {
+			s.active++
+			s.pending++
+			s.mu.Unlock()
+			break
+		}
+		batchDone := s.batchDone
+		s.mu.Unlock()
+		select {
+		case <-batchDone:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	cancel := func() {}
+	if s.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+	}
+	var once sync.Once
+	return ctx, func(err error) {
+		once.Do(func() {
+			cancel()
+			s.release(err)
+		})
+	}, nil
+}
+
+// release records the result of starting a worker, starting the next batch once the current batch is done
+func (s *workerStarter) release(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.started++
+	} else {
+		s.failed++
+	}
+	s.pending--
+	if s.pending == 0 {
+		s.active = 0
+		close(s.batchDone)
+		s.batchDone = make(chan struct{})
+	}
+	// Only the progress of the initial startup is logged, not that of workers added or restarted later
+	if done := s.started + s.failed; done <= s.workers {
+		s.log(formatStartup(s.started, s.failed, s.workers))
+	}
+}
+
+// formatStartup formats the progress of starting workers for display
+func formatStartup(started, failed, workers int) string {
+	message := fmt.Sprintf("Started %d/%d workers", started, workers)
+	if failed > 0 {
+		message += fmt.Sprintf(" (%d failed to start)", failed)
+	}
+	return message
+}
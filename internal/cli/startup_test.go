@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWorkerStarter(t *testing.T) {
+	assert.Nil(t, newWorkerStarter(workerStartup{}, 3, nil))
+	var nilStarter *workerStarter
+	ctx, started, err := nilStarter.acquire(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, ctx)
+	started(nil)
+
+	var messages []string
+	starter := newWorkerStarter(workerStartup{batch: 2, timeout: time.Minute}, 3, func(message string) {
+		messages = append(messages, message)
+	})
+
+	ctx1, started1, err := starter.acquire(context.Background())
+	assert.NoError(t, err)
+	_, ok := ctx1.Deadline()
+	assert.True(t, ok)
+	_, started2, err := starter.acquire(context.Background())
+	assert.NoError(t, err)
+
+	// The third worker waits for the first batch to complete
+	acquired := make(chan func(error))
+	go func() {
+		_, started3, err := starter.acquire(context.Background())
+		assert.NoError(t, err)
+		acquired <- started3
+	}()
+	started1(nil)
+	assert.Error(t, ctx1.Err())
+	select {
+	case <-acquired:
+		t.Fatal("worker started before its batch")
+	case <-time.After(10 * time.Millisecond):
+	}
+	started2(errors.New("timeout"))
+	started3 := <-acquired
+	started3(nil)
+	started3(nil)
+	assert.Equal(t, []string{
+		"Started 1/3 workers",
+		"Started 1/3 workers (1 failed to start)",
+		"Started 2/3 workers (1 failed to start)",
+	}, messages)
+
+	// Waiting workers are canceled with the context
+	starter = newWorkerStarter(workerStartup{batch: 1}, 2, func(string) {})
+	_, _, err = starter.acquire(context.Background())
+	assert.NoError(t, err)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = starter.acquire(canceled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/helmit/internal/console"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+const statusExamples = `
+  # Show the status of the jobs started by a test or benchmark run.
+  helmit status happy-panda
+
+  # Show the progress of a running benchmark until it completes.
+  helmit status happy-panda --watch
+`
+
+var workerIDRegex = regexp.MustCompile(`-worker-([0-9]+)$`)
+
+func getStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status <id>",
+		Short:   "Show the status of a test or benchmark running in the cluster",
+		Example: statusExamples,
+		Args:    cobra.ExactArgs(1),
+		RunE:    runStatusCommand,
+	}
+	cmd.Flags().BoolP("watch", "w", false, "refresh the status until all the jobs have completed")
+	cmd.Flags().Duration("interval", 2*time.Second, "the interval at which to refresh the status when watching")
+	return cmd
+}
+
+func runStatusCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	monitor, err := job.NewMonitor()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if !watch {
+		_, err := writeStatus(ctx, cmd.OutOrStdout(), monitor, args[0])
+		return err
+	}
+
+	writer := console.NewReporter(cmd.OutOrStdout(), args[0])
+	for {
+		// Render the status to a buffer first so the display is not cleared while the logs are being read
+		var buf bytes.Buffer
+		done, err := writeStatus(ctx, &buf, monitor, args[0])
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		_, _ = writer.Write(buf.Bytes())
+		_ = writer.Flush()
+		if done {
+			return nil
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// writeStatus writes the status of the jobs for the given ID and the latest reports of any benchmark workers,
+// returning whether all the jobs have completed
+func writeStatus(ctx context.Context, out io.Writer, monitor *job.Monitor, id string) (bool, error) {
+	statuses, err := monitor.Find(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if len(statuses) == 0 {
+		return false, fmt.Errorf("no jobs found for %s", id)
+	}
+	writeJobStatuses(out, statuses)
+
+	var reports []*workerReport
+	for _, status := range statuses {
+		match := workerIDRegex.FindStringSubmatch(status.ID)
+		if match == nil {
+			continue
+		}
+		worker, _ := strconv.Atoi(match[1])
+		report, err := getLatestReport(ctx, monitor, status)
+		if err != nil {
+			return false, err
+		}
+		for len(reports) <= worker {
+			reports = append(reports, nil)
+		}
+		if report != nil {
+			reports[worker] = &workerReport{
+				Report: *report,
+				worker: worker,
+			}
+		}
+	}
+	if len(reports) > 0 {
+		fmt.Fprintln(out)
+		writeReports(out, reports)
+	}
+
+	for _, status := range statuses {
+		if !status.Terminated {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// writeJobStatuses writes a table of the given job statuses
+func writeJobStatuses(out io.Writer, statuses []job.Status) {
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "JOB\tNAMESPACE\tSTATUS\tAGE")
+	for _, status := range statuses {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", status.ID, status.Namespace, status, time.Since(status.Created).Round(time.Second))
+	}
+	writer.Flush()
+}
+
+// getLatestReport returns the most recent report in the logs of the given benchmark worker
+func getLatestReport(ctx context.Context, monitor *job.Monitor, status job.Status) (*benchmark.Report, error) {
+	stream, err := monitor.GetLogs(ctx, status, false)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return parseLatestReport(stream), nil
+}
+
+// parseLatestReport returns the last benchmark report in the given worker output, or nil if no report was found
+func parseLatestReport(reader io.Reader) *benchmark.Report {
+	var latest *benchmark.Report
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var report benchmark.Report
+		if err := json.Unmarshal(scanner.Bytes(), &report); err == nil {
+			latest = &report
+		}
+	}
+	return latest
+}
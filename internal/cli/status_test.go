@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseLatestReport(t *testing.T) {
+	output := `Setting up worker
+{"iterations":10,"errors":1}
+connection reset
+{"iterations":20,"errors":0}
+`
+	report := parseLatestReport(strings.NewReader(output))
+	assert.NotNil(t, report)
+	assert.Equal(t, 20, report.Iterations)
+	assert.Nil(t, parseLatestReport(strings.NewReader("no reports\n")))
+}
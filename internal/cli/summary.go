@@ -0,0 +1,359 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/internal/logging"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	maxSlowestTests = 10
+	// maxListedIterations is the number of iterations above which only failed iterations are listed
+	maxListedIterations = 20
+	maxDigestLength     = 200
+	testStatusPass      = "PASS"
+	testStatusFail      = "FAIL"
+	testStatusSkip      = "SKIP"
+	testifyErrorLine    = "Error:"
+)
+
+var (
+	testResultRegex = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([0-9.]+)s\)`)
+	testEventRegex  = regexp.MustCompile(`^=== (RUN|NAME|CONT|PAUSE)\s+(\S+)`)
+	// testIterationRegex matches the marker written by suites at the start of each iteration of repeated tests
+	testIterationRegex = regexp.MustCompile(`^=== ITERATION (\d+)`)
+	// helmTimingRegex matches the marker written by the Helm API once each release operation completes
+	helmTimingRegex = regexp.MustCompile(`^=== HELM (\S+) (\S+) \(([0-9.]+)s\)`)
+)
+
+// testResult is the result of a single test parsed from go test output
+type testResult struct {
+	name      string
+	status    string
+	duration  time.Duration
+	output    []string
+	iteration int
+}
+
+// helmTiming is the time spent in a Helm operation on a release parsed from the test output
+type helmTiming struct {
+	suite     string
+	operation string
+	release   string
+	duration  time.Duration
+}
+
+// testSummary is a logging.Sink that parses verbose go test output to summarize the test results
+type testSummary struct {
+	results []*testResult
+	helm    []helmTiming
+	output  map[string][]string
+	current string
+	// suite is the suite of the last test started, to which Helm operations are attributed
+	suite     string
+	iteration int
+	// timings are the timings of the steps of the run, which are added to the report if set
+	timings *logging.Timings
+	mu      sync.Mutex
+}
+
+func newTestSummary() *testSummary {
+	return &testSummary{
+		output:    make(map[string][]string),
+		iteration: 1,
+	}
+}
+
+// Write parses a line of go test output
+func (s *testSummary) Write(_ string, line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if match := testIterationRegex.FindStringSubmatch(line); match != nil {
+		s.iteration, _ = strconv.Atoi(match[1])
+		return nil
+	}
+	if match := helmTimingRegex.FindStringSubmatch(line); match != nil {
+		seconds, _ := time.ParseDuration(match[3] + "s")
+		s.helm = append(s.helm, helmTiming{
+			suite:     s.suite,
+			operation: match[1],
+			release:   match[2],
+			duration:  seconds,
+		})
+		return nil
+	}
+	if match := testEventRegex.FindStringSubmatch(line); match != nil {
+		s.current = match[2]
+		s.suite = getTestSuite(match[2])
+		return nil
+	}
+	if match := testResultRegex.FindStringSubmatch(line); match != nil {
+		seconds, _ := time.ParseDuration(match[3] + "s")
+		name := match[2]
+		result := &testResult{
+			name:      name,
+			status:    match[1],
+			duration:  seconds,
+			iteration: s.iteration,
+		}
+		// Only the output of failed tests is summarized, so it's not retained for soak runs of passing tests
+		if result.status == testStatusFail {
+			result.output = s.output[name]
+		}
+		s.results = append(s.results, result)
+		delete(s.output, name)
+		s.current = ""
+		return nil
+	}
+	if s.current != "" && strings.TrimSpace(line) != "" {
+		s.output[s.current] = append(s.output[s.current], line)
+	}
+	return nil
+}
+
+func (s *testSummary) Close() error {
+	return nil
+}
+
+// getLeafResults returns the results of tests without subtests, since a parent test's result is determined
+// by its subtests
+func (s *testSummary) getLeafResults() []*testResult {
+	parents := make(map[string]bool)
+	for _, result := range s.results {
+		for i := strings.LastIndex(result.name, "/"); i != -1; i = strings.LastIndex(result.name[:i], "/") {
+			parents[result.name[:i]] = true
+		}
+	}
+	var leaves []*testResult
+	for _, result := range s.results {
+		if !parents[result.name] {
+			leaves = append(leaves, result)
+		}
+	}
+	return leaves
+}
+
+// write writes the summary of the test results to the given writer
+func (s *testSummary) write(out io.Writer, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := s.getLeafResults()
+	if len(results) == 0 {
+		return
+	}
+
+	passed, failed, skipped := countResults(results)
+	var failures []*testResult
+	failureIterations := make(map[string][]int)
+	for _, result := range results {
+		if result.status == testStatusFail {
+			// Repeated failures of a test are listed once with the iterations in which the test failed
+			if _, ok := failureIterations[result.name]; !ok {
+				failures = append(failures, result)
+			}
+			failureIterations[result.name] = append(failureIterations[result.name], result.iteration)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d tests, %d passed, %d failed, %d skipped in %s\n",
+		len(results), passed, failed, skipped, duration.Round(time.Millisecond))
+
+	iterations := s.iteration
+	if iterations > 1 {
+		writeIterations(out, results, iterations)
+	}
+
+	if packages, packageResults := groupResultsByPackage(results); len(packages) > 1 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Packages:")
+		writer := new(tabwriter.Writer)
+		writer.Init(out, 0, 0, 3, ' ', 0)
+		for _, pkg := range packages {
+			passed, failed, skipped := countResults(packageResults[pkg])
+			fmt.Fprintf(writer, "  %s\t%d tests, %d passed, %d failed, %d skipped\n",
+				pkg, len(packageResults[pkg]), passed, failed, skipped)
+		}
+		writer.Flush()
+	}
+
+	slowest := make([]*testResult, len(results))
+	copy(slowest, results)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].duration > slowest[j].duration
+	})
+	if len(slowest) > maxSlowestTests {
+		slowest = slowest[:maxSlowestTests]
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Slowest tests:")
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	for _, result := range slowest {
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", result.name, result.duration, result.status)
+	}
+	writer.Flush()
+
+	if len(failures) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Failures:")
+		for _, result := range failures {
+			if iterations > 1 {
+				fmt.Fprintf(out, "  %s: %s (iterations %s)\n", result.name, getFailureDigest(result.output), formatIterations(failureIterations[result.name]))
+			} else {
+				fmt.Fprintf(out, "  %s: %s\n", result.name, getFailureDigest(result.output))
+			}
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// writeIterations writes the results of each iteration of repeated tests and the failure rate of flaky tests,
+// i.e. tests that both passed and failed across iterations
+// For long soak runs, only the iterations in which tests failed are listed.
+func writeIterations(out io.Writer, results []*testResult, iterations int) {
+	iterationResults := make([][]*testResult, iterations)
+	var names []string
+	testResults := make(map[string][]*testResult)
+	for _, result := range results {
+		if result.iteration >= 1 && result.iteration <= iterations {
+			iterationResults[result.iteration-1] = append(iterationResults[result.iteration-1], result)
+		}
+		if _, ok := testResults[result.name]; !ok {
+			names = append(names, result.name)
+		}
+		testResults[result.name] = append(testResults[result.name], result)
+	}
+
+	var failedIterations int
+	for _, results := range iterationResults {
+		if _, failed, _ := countResults(results); failed > 0 {
+			failedIterations++
+		}
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%d iterations, %d passed, %d failed (%.2f%% failed)\n", iterations,
+		iterations-failedIterations, failedIterations, float64(failedIterations)/float64(iterations)*100)
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	for i, results := range iterationResults {
+		passed, failed, skipped := countResults(results)
+		if iterations > maxListedIterations && failed == 0 {
+			continue
+		}
+		fmt.Fprintf(writer, "  Iteration %d\t%d tests, %d passed, %d failed, %d skipped\n", i+1, len(results), passed, failed, skipped)
+	}
+	writer.Flush()
+
+	var flaky []string
+	for _, name := range names {
+		if passed, failed, _ := countResults(testResults[name]); passed > 0 && failed > 0 {
+			flaky = append(flaky, name)
+		}
+	}
+	if len(flaky) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flaky tests:")
+	writer = new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	for _, name := range flaky {
+		runs := testResults[name]
+		_, failed, _ := countResults(runs)
+		fmt.Fprintf(writer, "  %s\t%d/%d failed (%.2f%%)\n", name, failed, len(runs), float64(failed)/float64(len(runs))*100)
+	}
+	writer.Flush()
+}
+
+// formatIterations formats a list of iteration numbers for display
+func formatIterations(iterations []int) string {
+	values := make([]string, len(iterations))
+	for i, iteration := range iterations {
+		values[i] = strconv.Itoa(iteration)
+	}
+	return strings.Join(values, ", ")
+}
+
+// countResults returns the number of passed, failed, and skipped tests in the given results
+func countResults(results []*testResult) (passed, failed, skipped int) {
+	for _, result := range results {
+		switch result.status {
+		case testStatusPass:
+			passed++
+		case testStatusFail:
+			failed++
+		case testStatusSkip:
+			skipped++
+		}
+	}
+	return passed, failed, skipped
+}
+
+// groupResultsByPackage groups the given results by the package of their suite, returning the packages in the
+// order in which they were run
+// Suites are qualified with the names of their packages only when tests from multiple packages are run together.
+func groupResultsByPackage(results []*testResult) ([]string, map[string][]*testResult) {
+	var packages []string
+	packageResults := make(map[string][]*testResult)
+	for _, result := range results {
+		pkg := getTestPackage(result.name)
+		if _, ok := packageResults[pkg]; !ok {
+			packages = append(packages, pkg)
+		}
+		packageResults[pkg] = append(packageResults[pkg], result)
+	}
+	return packages, packageResults
+}
+
+// getTestPackage returns the package qualifying the suite of the named test, if any
+func getTestPackage(name string) string {
+	suite := strings.SplitN(name, "/", 2)[0]
+	if i := strings.LastIndex(suite, "."); i != -1 {
+		return suite[:i]
+	}
+	return ""
+}
+
+// getFailureDigest returns a one line digest of the failure message in the given test output
+// Testify assertion failures are reduced to their error message, and other failures to the first line of output.
+func getFailureDigest(output []string) string {
+	var digest string
+	for i, line := range output {
+		line = strings.TrimSpace(line)
+		if index := strings.Index(line, testifyErrorLine); index != -1 {
+			digest = strings.TrimSpace(line[index+len(testifyErrorLine):])
+			// Include the continuation lines of multi-line testify messages, e.g. expected and actual values
+			for _, next := range output[i+1:] {
+				next = strings.TrimSpace(next)
+				if next == "" || strings.HasSuffix(strings.TrimSpace(strings.SplitN(next, "\t", 2)[0]), ":") {
+					break
+				}
+				digest += " " + next
+			}
+			break
+		}
+		if digest == "" {
+			digest = line
+		}
+	}
+	if digest == "" {
+		return "no output"
+	}
+	if len(digest) > maxDigestLength {
+		digest = digest[:maxDigestLength] + "..."
+	}
+	return digest
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testOutput = `=== RUN   TestSuite
+=== RUN   TestSuite/TestMap
+=== RUN   TestSuite/TestMap/Put
+=== RUN   TestSuite/TestMap/Get
+    map_test.go:42:
+        	Error Trace:	/src/test/map_test.go:42
+        	Error:      	Not equal:
+        	            	expected: "bar"
+        	            	actual  : "baz"
+        	Test:       	TestSuite/TestMap/Get
+=== RUN   TestSuite/TestCounter
+    counter_test.go:12: failed to connect to counter
+    counter_test.go:13: retrying
+=== RUN   TestSuite/TestLock
+    lock_test.go:10: lock not supported
+--- FAIL: TestSuite (3.50s)
+    --- FAIL: TestSuite/TestMap (2.25s)
+        --- PASS: TestSuite/TestMap/Put (2.00s)
+        --- FAIL: TestSuite/TestMap/Get (0.25s)
+    --- FAIL: TestSuite/TestCounter (1.25s)
+    --- SKIP: TestSuite/TestLock (0.00s)
+FAIL
+`
+
+func TestSummary(t *testing.T) {
+	summary := newTestSummary()
+	for _, line := range strings.Split(testOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+
+	results := summary.getLeafResults()
+	assert.Len(t, results, 4)
+
+	var out bytes.Buffer
+	summary.write(&out, 4*time.Second)
+	output := out.String()
+	assert.Contains(t, output, "4 tests, 1 passed, 2 failed, 1 skipped in 4s")
+	assert.Less(t, strings.Index(output, "TestSuite/TestMap/Put"), strings.Index(output, "TestSuite/TestCounter"))
+	assert.NotContains(t, output, "TestSuite/TestMap ")
+	assert.Contains(t, output, `TestSuite/TestMap/Get: Not equal: expected: "bar" actual  : "baz"`)
+	assert.Contains(t, output, "TestSuite/TestCounter: counter_test.go:12: failed to connect to counter")
+}
+
+func TestFailureDigest(t *testing.T) {
+	assert.Equal(t, "no output", getFailureDigest(nil))
+	digest := getFailureDigest([]string{strings.Repeat("a", 300)})
+	assert.Equal(t, strings.Repeat("a", maxDigestLength)+"...", digest)
+}
+
+const multiPackageTestOutput = `=== RUN   tests.TestSuite
+=== RUN   tests.TestSuite/TestMap
+=== RUN   more-tests.TestSuite
+=== RUN   more-tests.TestSuite/TestLock
+=== RUN   more-tests.TestSuite/TestCounter
+--- PASS: tests.TestSuite (1.00s)
+    --- PASS: tests.TestSuite/TestMap (1.00s)
+--- FAIL: more-tests.TestSuite (2.00s)
+    --- PASS: more-tests.TestSuite/TestLock (1.00s)
+    --- FAIL: more-tests.TestSuite/TestCounter (1.00s)
+FAIL
+`
+
+func TestPackageSummary(t *testing.T) {
+	summary := newTestSummary()
+	for _, line := range strings.Split(multiPackageTestOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+
+	var out bytes.Buffer
+	summary.write(&out, 3*time.Second)
+	output := out.String()
+	assert.Contains(t, output, "3 tests, 2 passed, 1 failed, 0 skipped in 3s")
+	assert.Contains(t, output, "Packages:\n  tests        1 tests, 1 passed, 0 failed, 0 skipped\n  more-tests   2 tests, 1 passed, 1 failed, 0 skipped\n")
+
+	out.Reset()
+	summary = newTestSummary()
+	for _, line := range strings.Split(testOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+	summary.write(&out, 4*time.Second)
+	assert.NotContains(t, out.String(), "Packages:")
+}
+
+const repeatedTestOutput = `=== RUN   TestSuite
+=== ITERATION 1
+=== RUN   TestSuite/TestMap
+=== RUN   TestSuite/TestCounter
+--- PASS: TestSuite (2.00s)
+    --- PASS: TestSuite/TestMap (1.00s)
+    --- PASS: TestSuite/TestCounter (1.00s)
+=== RUN   TestSuite
+=== ITERATION 2
+=== RUN   TestSuite/TestMap
+=== RUN   TestSuite/TestCounter
+    counter_test.go:12: failed to connect to counter
+--- FAIL: TestSuite (2.00s)
+    --- PASS: TestSuite/TestMap (1.00s)
+    --- FAIL: TestSuite/TestCounter (1.00s)
+=== RUN   TestSuite
+=== ITERATION 3
+=== RUN   TestSuite/TestMap
+=== RUN   TestSuite/TestCounter
+    counter_test.go:12: failed to connect to counter
+--- FAIL: TestSuite (2.00s)
+    --- PASS: TestSuite/TestMap (1.00s)
+    --- FAIL: TestSuite/TestCounter (1.00s)
+=== RUN   TestSuite
+=== ITERATION 4
+=== RUN   TestSuite/TestMap
+=== RUN   TestSuite/TestCounter
+--- PASS: TestSuite (2.00s)
+    --- PASS: TestSuite/TestMap (1.00s)
+    --- PASS: TestSuite/TestCounter (1.00s)
+FAIL
+`
+
+func TestIterationSummary(t *testing.T) {
+	summary := newTestSummary()
+	for _, line := range strings.Split(repeatedTestOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+
+	results := summary.getLeafResults()
+	assert.Len(t, results, 8)
+	assert.Equal(t, 4, results[7].iteration)
+	assert.Nil(t, results[0].output)
+
+	var out bytes.Buffer
+	summary.write(&out, 8*time.Second)
+	output := out.String()
+	assert.Contains(t, output, "8 tests, 6 passed, 2 failed, 0 skipped in 8s")
+	assert.Contains(t, output, "4 iterations, 2 passed, 2 failed (50.00% failed)")
+	assert.Contains(t, output, "  Iteration 2   2 tests, 1 passed, 1 failed, 0 skipped\n")
+	assert.Contains(t, output, "Flaky tests:\n  TestSuite/TestCounter   2/4 failed (50.00%)\n")
+	assert.Contains(t, output, "TestSuite/TestCounter: counter_test.go:12: failed to connect to counter (iterations 2, 3)")
+	assert.Equal(t, 1, strings.Count(output, "TestSuite/TestCounter: "))
+
+	// Results of tests run once are not summarized by iteration
+	out.Reset()
+	summary = newTestSummary()
+	for _, line := range strings.Split(testOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+	summary.write(&out, 4*time.Second)
+	assert.NotContains(t, out.String(), "iterations")
+}
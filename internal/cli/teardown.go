@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"io"
+	"time"
+)
+
+// awaitTeardown waits for the given namespace to be deleted, reporting the content remaining in the namespace
+// and the resources whose finalizers block its deletion while it's terminating
+// Failures are reported but should not fail the run, since a namespace that's stuck terminating does not affect
+// the results, and the teardown can be resumed with helmit cleanup.
+func awaitTeardown(out io.Writer, jobID string, namespace string, options teardown) error {
+	step := logging.NewStep(jobID, "Deleting namespace %s", namespace)
+	step.Start()
+	namespaceTeardown, err := job.NewNamespaceTeardown(namespace, options.force, options.gracePeriod)
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+
+	// Forced teardowns are given another grace period for the namespace to be deleted once finalizers are removed
+	ctx, cancel := context.WithTimeout(context.Background(), options.gracePeriod+job.DefaultTeardownGracePeriod)
+	defer cancel()
+	err = namespaceTeardown.Await(ctx, func(status job.TeardownStatus) {
+		writeTeardownStatus(out, status)
+	}, logging.NewLogger(out))
+	if err != nil {
+		step.Fail(err)
+		var stuck *job.NamespaceStuckError
+		if errors.As(err, &stuck) && len(stuck.Blocking) > 0 {
+			fmt.Fprintf(out, "Run helmit cleanup --force-teardown to remove the finalizers blocking the deletion of namespace %s\n", namespace)
+		}
+		return err
+	}
+	step.Complete()
+	return nil
+}
+
+// writeTeardownStatus prints the content remaining in a terminating namespace
+func writeTeardownStatus(out io.Writer, status job.TeardownStatus) {
+	if len(status.Remaining) == 0 && len(status.Blocking) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "  Namespace %s terminating for %s:\n", status.Namespace, status.Elapsed.Round(time.Second))
+	for _, remaining := range status.Remaining {
+		fmt.Fprintf(out, "    %s\n", remaining)
+	}
+	for _, resource := range status.Blocking {
+		fmt.Fprintf(out, "    %s\n", resource)
+	}
+}
@@ -6,22 +6,23 @@ package cli
 
 import (
 	"bufio"
-	"context"
 	"errors"
 	"fmt"
 	petname "github.com/dustinkirkland/golang-petname"
 	"github.com/fatih/color"
 	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/local"
 	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/match"
 	"math/rand"
 	"os"
-	"os/signal"
 	"path/filepath"
-	"syscall"
+	"strings"
 	"time"
 
 	"github.com/onosproject/helmit/internal/job"
 
+	"github.com/onosproject/helmit/internal/images"
 	"github.com/onosproject/helmit/pkg/test"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -45,10 +46,20 @@ const testExamples = `
   # Run tests packaged in a Docker image.
   helmit test --image atomix/kubernetes-tests:latest
 
+  # Run the tests built into an image without copying anything to the test pod.
+  helmit test --image atomix/kubernetes-tests:latest --no-copy --entrypoint /usr/local/bin/tests
+
   # Run tests by referencing a command package and providing a context.
   # The specified context will be loaded into the test pod as the current working directory.
   helmit test ./cmd/tests --context ./charts
 
+  # Run the suites from multiple packages together.
+  # Suites are qualified with the names of their packages and the results are summarized by package.
+  helmit test ./cmd/tests ./cmd/more-tests --context ./charts
+
+  # Run the standard go tests in all packages of the current module in a test pod.
+  helmit test --gotest ./...
+
   # Run tests in a specific namespace.
   helmit test ./cmd/tests -n integration-tests
 
@@ -58,6 +69,15 @@ const testExamples = `
   # Run a single test by name.
   helmit test ./cmd/tests -c ./charts --suite atomix --test TestMap
 
+  # Run the smoke tests, skipping those tagged slow.
+  helmit test ./cmd/tests -c ./charts --test-tags smoke,!slow
+
+  # Stop running tests as soon as one fails.
+  helmit test ./cmd/tests -c ./charts --fail-fast
+
+  # Run a test repeatedly until it fails to hunt for flakes.
+  helmit test ./cmd/tests -c ./charts --suite atomix --test TestMap --until-failure --iterations 100
+
   # Override Helm chart values with flags.
   # Value overrids must be namespaced with the name of the release to which to apply the value.
   helmit test ./cmd/tests -c ./charts --set atomix-controller.image=atomix/atomix-controller:latest --set atomix-raft.replicas=3 --suite atomix
@@ -79,20 +99,49 @@ func getTestCommand() *cobra.Command {
 	cmd.Flags().StringP("namespace", "n", "", "the namespace in which to run the tests")
 	cmd.Flags().Bool("create-namespace", false, "whether to create the namespace when running the test")
 	cmd.Flags().String("service-account", "", "the name of the service account to use to run test pods")
+	cmd.Flags().String("rbac-rules", "", "a YAML file containing RBAC policy rules to grant the test pods in place of cluster-admin")
+	cmd.Flags().Bool("namespaced-rbac", false, "whether to grant the RBAC rules with a namespaced Role rather than a ClusterRole")
 	cmd.Flags().StringP("context", "c", "", "the test context")
 	cmd.Flags().StringP("image", "i", "", "the test image to run")
 	cmd.Flags().String("image-pull-policy", string(corev1.PullIfNotPresent), "the Docker image pull policy")
+	cmd.Flags().String("arch", "", "the CPU architecture for which to build the tests (defaults to the architecture of the cluster's nodes)")
 	cmd.Flags().StringToStringP("label", "l", map[string]string{}, "labels to apply to the test pod")
 	cmd.Flags().StringToStringP("annotation", "a", map[string]string{}, "annotations to apply to the test pod")
+	cmd.Flags().String("sidecar-manifest", "", "a YAML file containing sidecar containers and volumes to add to the test pod")
 	cmd.Flags().StringArrayP("values", "f", []string{}, "release values paths")
 	cmd.Flags().StringArray("set", []string{}, "chart value overrides")
+	cmd.Flags().StringArray("set-secret", []string{}, "release value overrides passed to the pod in a Kubernetes Secret rather than in the job configuration, in the format {release}.{path}={value}")
 	cmd.Flags().StringSliceP("suite", "s", []string{"TestSuite$"}, "regular expressions to filter the names of test suite(s)")
 	cmd.Flags().StringSliceP("test", "t", []string{".*/^Test"}, "regular expressions to filter the names of tests")
 	cmd.Flags().StringSliceP("method", "m", []string{"^Test"}, "regular expressions to filter the names of test suite methods")
+	cmd.Flags().StringSlice("test-tags", []string{}, "tags to select tests by, e.g. smoke,!slow to run tests tagged smoke that are not tagged slow")
 	cmd.Flags().Duration("timeout", 10*time.Minute, "test timeout")
+	cmd.Flags().Int("iterations", 1, "the number of times to run the tests")
+	cmd.Flags().Bool("until-failure", false, "run the tests repeatedly until a test fails, up to --iterations times if set")
+	cmd.Flags().Bool("fail-fast", false, "skip the remaining tests and suites and tear down as soon as a test fails")
 	cmd.Flags().Bool("no-teardown", false, "do not tear down clusters following tests")
+	cmd.Flags().Bool("fail-on-leak", false, "fail if resources labeled with the job or belonging to uninstalled releases are left behind after teardown")
 	cmd.Flags().StringSlice("secret", []string{}, "secrets to pass to the kubernetes pod")
+	cmd.Flags().StringSlice("secret-from", []string{}, "existing Kubernetes secrets in the format [{namespace}/]{name} whose keys to pass to the kubernetes pod")
 	cmd.Flags().StringToString("arg", map[string]string{}, "a mapping of named test arguments")
+	cmd.Flags().String("artifacts-dir", "", "a local directory to which to collect test artifacts")
+	cmd.Flags().Bool("collect-fixtures", false, "copy the temporary files written by failed tests to the artifacts directory")
+	cmd.Flags().String("log-file", "", "a file to which to write the raw output of test pods")
+	cmd.Flags().Bool("local", false, "run the tests in a local process against the current Kubernetes configuration rather than in a test pod")
+	cmd.Flags().Bool("dry-run", false, "build the tests and print the suites, values, and resources that would be created without running the tests")
+	cmd.Flags().Bool("gotest", false, "run the standard go tests in the given packages with go test in a test pod rather than helmit suites")
+	addBuildFlags(cmd)
+	addNoCopyFlags(cmd)
+	addReportFlags(cmd)
+	addSchedulingFlags(cmd, "test pod")
+	addNamespaceFlags(cmd)
+	addReadinessFlags(cmd)
+	addTeardownFlags(cmd)
+	addHookFlags(cmd)
+	addEphemeralClusterFlags(cmd)
+	addUploadFlags(cmd)
+	addChartDependencyFlags(cmd)
+	addWorkflowFlags(cmd)
 	return cmd
 }
 
@@ -100,31 +149,96 @@ func runTestCommand(cmd *cobra.Command, args []string) error {
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 
-	verbose, _ := cmd.Flags().GetBool("verbose")
+	if err := applyProjectFile(cmd); err != nil {
+		return err
+	}
+
+	verbose := logging.GetVerbose()
 	namespace, _ := cmd.Flags().GetString("namespace")
 	createNamespace, _ := cmd.Flags().GetBool("create-namespace")
 	serviceAccount, _ := cmd.Flags().GetString("service-account")
+	rbacRules, _ := cmd.Flags().GetString("rbac-rules")
+	namespacedRBAC, _ := cmd.Flags().GetBool("namespaced-rbac")
 	contextPath, _ := cmd.Flags().GetString("context")
 	image, _ := cmd.Flags().GetString("image")
 	labels, _ := cmd.Flags().GetStringToString("label")
 	annotations, _ := cmd.Flags().GetStringToString("annotation")
+	sidecarManifest, _ := cmd.Flags().GetString("sidecar-manifest")
 	files, _ := cmd.Flags().GetStringArray("values")
 	sets, _ := cmd.Flags().GetStringArray("set")
+	setSecrets, _ := cmd.Flags().GetStringArray("set-secret")
 	suites, _ := cmd.Flags().GetStringSlice("suite")
 	tests, _ := cmd.Flags().GetStringSlice("test")
 	methods, _ := cmd.Flags().GetStringSlice("method")
+	tags, _ := cmd.Flags().GetStringSlice("test-tags")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	untilFailure, _ := cmd.Flags().GetBool("until-failure")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
 	imagePullPolicy, _ := cmd.Flags().GetString("image-pull-policy")
 	pullPolicy := corev1.PullPolicy(imagePullPolicy)
+	arch, _ := cmd.Flags().GetString("arch")
 	noTeardown, _ := cmd.Flags().GetBool("no-teardown")
+	failOnLeak, _ := cmd.Flags().GetBool("fail-on-leak")
 	secretsArray, _ := cmd.Flags().GetStringSlice("secret")
+	secretsFrom, _ := cmd.Flags().GetStringSlice("secret-from")
 	testArgs, _ := cmd.Flags().GetStringToString("arg")
+	artifactsDir, _ := cmd.Flags().GetString("artifacts-dir")
+	collectFixtures, _ := cmd.Flags().GetBool("collect-fixtures")
+	logFile, _ := cmd.Flags().GetString("log-file")
+	local, _ := cmd.Flags().GetBool("local")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	buildInCluster, _ := cmd.Flags().GetBool("build-in-cluster")
+	goTest, _ := cmd.Flags().GetBool("gotest")
 
 	// Either a command package or image must be specified
 	pkgPaths := args
 	if len(pkgPaths) == 0 && image == "" {
 		return errors.New("must specify either a test package or --image to run")
 	}
+	if goTest {
+		if err := validateGoTestFlags(cmd, pkgPaths); err != nil {
+			return err
+		}
+	}
+	if local && len(pkgPaths) == 0 {
+		return errors.New("must specify a test package to run with --local")
+	}
+	if local && dryRun {
+		return errors.New("--dry-run cannot be used with --local")
+	}
+	if local && buildInCluster {
+		return errors.New("--build-in-cluster cannot be used with --local")
+	}
+	if collectFixtures && artifactsDir == "" {
+		return errors.New("--collect-fixtures requires --artifacts-dir")
+	}
+	if iterations < 1 {
+		return errors.New("--iterations must be positive")
+	}
+	// Tests run until failure are only limited by --iterations if it's set explicitly
+	if untilFailure && !cmd.Flags().Changed("iterations") {
+		iterations = 0
+	}
+
+	reportOpts, err := getReportOptions(cmd)
+	if err != nil {
+		return err
+	}
+	uploads, err := getResultsUpload(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Validate the test filters before building or deploying anything
+	for _, patterns := range [][]string{suites, tests, methods} {
+		if err := match.Validate(patterns...); err != nil {
+			return err
+		}
+	}
+	if err := match.ValidateTags(tags...); err != nil {
+		return err
+	}
 
 	// Generate a unique test ID
 	testID := petname.Generate(2, "-")
@@ -153,38 +267,212 @@ func runTestCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	secretValues, valueSecrets, err := parseSecretOverrides(setSecrets)
+	if err != nil {
+		return err
+	}
+	for name, value := range valueSecrets {
+		secrets[name] = value
+	}
+
+	rules, err := parseRules(rbacRules)
+	if err != nil {
+		return err
+	}
+
+	scheduling, err := getScheduling(cmd)
+	if err != nil {
+		return err
+	}
+
+	namespaceMeta, err := getNamespaceMetadata(cmd, createNamespace)
+	if err != nil {
+		return err
+	}
+
+	readiness, err := getReadiness(cmd)
+	if err != nil {
+		return err
+	}
+
+	teardown, err := getTeardown(cmd)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := getEphemeralCluster(cmd, artifactsDir, noTeardown)
+	if err != nil {
+		return err
+	}
+
+	hooks, err := getHooks(cmd, cluster)
+	if err != nil {
+		return err
+	}
+
+	noCopy, err := getNoCopy(cmd, image, pkgPaths)
+	if err != nil {
+		return err
+	}
+
+	workflow, err := getWorkflowEngine(cmd, noCopy)
+	if err != nil {
+		return err
+	}
+	if noCopy.enabled && artifactsDir != "" {
+		return errors.New("--no-copy cannot be used with --artifacts-dir")
+	}
+
+	chartDeps, err := getChartDependencies(cmd)
+	if err != nil {
+		return err
+	}
+
+	sidecars, sidecarVolumes, err := parseSidecars(sidecarManifest)
+	if err != nil {
+		return err
+	}
+
+	logs, err := parseLogFile(logFile)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	// The time spent in each step is recorded for the timing breakdown of the summary and the report
+	timings := logging.NewTimings()
+	logging.SetTimings(timings)
+	defer logging.SetTimings(nil)
+
+	// Hooks provisioning the cluster are run before the cluster is used and once the tests are torn down
+	if !dryRun && workflow == "" {
+		defer hooks.afterCluster(true)
+		if err := hooks.beforeCluster(testID, namespace); err != nil {
+			return err
+		}
+		if err := confirmCluster(cmd); err != nil {
+			return err
+		}
+	}
+
 	var executable string
-	if len(pkgPaths) > 0 {
+	var source *job.Source
+	var env map[string]string
+	var testSuites []build.Suite
+	if goTest {
 		step := logging.NewStep(testID, "Preparing artifacts")
 		step.Start()
+		moduleDir, dir, err := getGoTestModule()
+		if err != nil {
+			step.Fail(err)
+			return err
+		}
+		if image == "" {
+			arch = getArch(step, arch, !dryRun && workflow == "")
+			image = images.Builder(arch)
+		}
+		var run string
+		if cmd.Flags().Changed("test") {
+			run = tests[0]
+		}
+		args := getGoTestArgs(pkgPaths, getBuildOptions(cmd), run, iterations, failFast, timeout)
 		executable = filepath.Join(os.TempDir(), "helmit", testID)
 		defer os.RemoveAll(executable)
-		if image == "" {
-			image = defaultRunnerImage
+		if err := os.MkdirAll(filepath.Dir(executable), 0755); err != nil {
+			step.Fail(err)
+			return err
 		}
-		if err := build.Tests(step, suites...).Build(executable, pkgPaths...); err != nil {
+		if err := os.WriteFile(executable, []byte(getGoTestScript(dir, namespace, args)), 0755); err != nil {
 			step.Fail(err)
 			return err
 		}
+		source = &job.Source{Dir: moduleDir}
+		env = getGoTestEnv(namespace)
 		step.Complete()
+	} else if len(pkgPaths) > 0 {
+		step := logging.NewStep(testID, "Preparing artifacts")
+		step.Start()
+		options := getBuildOptions(cmd)
+		builder := build.Tests(step, suites...).Options(options)
+		if local {
+			builder = builder.Local()
+		} else {
+			arch = getArch(step, arch, !dryRun && workflow == "")
+			if image == "" {
+				if buildInCluster {
+					image = images.Builder(arch)
+				} else {
+					image = images.Runner(arch)
+				}
+			}
+			builder = builder.Arch(arch)
+		}
+		if buildInCluster {
+			generated, err := builder.Generate(pkgPaths...)
+			if err != nil {
+				step.Fail(err)
+				return err
+			}
+			defer generated.Remove()
+			source = newJobSource(generated, options)
+		} else {
+			executable = filepath.Join(os.TempDir(), "helmit", testID)
+			defer os.RemoveAll(executable)
+			if err := builder.Build(executable, pkgPaths...); err != nil {
+				step.Fail(err)
+				return err
+			}
+		}
+		if dryRun {
+			testSuites, err = builder.Suites(pkgPaths...)
+			if err != nil {
+				step.Fail(err)
+				return err
+			}
+		}
+		step.Complete()
+	}
+
+	// Missing chart dependencies are built into a copy of the context, which is removed once it's been copied
+	vendoredContext := contextPath
+	if !dryRun && workflow == "" {
+		vendoredContext, err = chartDeps.vendor(testID, namespace, contextPath)
+		if err != nil {
+			return err
+		}
+		defer removeVendoredContext(vendoredContext, contextPath)
 	}
 
 	config := test.Config{
-		Namespace:  namespace,
-		Suites:     suites,
-		Tests:      tests,
-		Methods:    methods,
-		Values:     values,
-		Verbose:    verbose,
-		Args:       testArgs,
-		Timeout:    timeout,
-		NoTeardown: noTeardown,
+		Namespace:       namespace,
+		Suites:          suites,
+		Tests:           tests,
+		Methods:         methods,
+		Tags:            tags,
+		Values:          values,
+		SecretValues:    secretValues,
+		Verbose:         verbose,
+		Args:            testArgs,
+		Timeout:         timeout,
+		NoTeardown:      noTeardown,
+		CollectFixtures: collectFixtures,
+		Iterations:      iterations,
+		UntilFailure:    untilFailure,
+		FailFast:        failFast,
+	}
+
+	if local {
+		return runLocalTests(cmd, testID, executable, vendoredContext, artifactsDir, valueFiles, secrets, secretsFrom, createNamespace, namespaceMeta, logs, reportOpts, uploads, failOnLeak, hooks, timings, config)
 	}
 
 	if contextPath != "" {
 		config.Context = filepath.Join(job.HomeDir, job.ContextDir)
 	}
 
+	if artifactsDir != "" {
+		config.ArtifactsDir = filepath.Join(job.HomeDir, job.ArtifactsDir)
+	}
+
 	if len(valueFiles) > 0 {
 		config.ValueFiles = make(map[string][]string)
 		for release, releaseFiles := range valueFiles {
@@ -197,42 +485,138 @@ func runTestCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	job := job.Job[test.Config]{
-		ID:              testID,
-		Namespace:       namespace,
-		CreateNamespace: createNamespace,
-		DeleteNamespace: createNamespace && !noTeardown,
-		ServiceAccount:  serviceAccount,
-		Image:           image,
-		ImagePullPolicy: pullPolicy,
-		Labels:          labels,
-		Annotations:     annotations,
-		Executable:      executable,
-		Context:         contextPath,
-		ValueFiles:      valueFiles,
-		Secrets:         secrets,
-		Config:          config,
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	step := logging.NewStep(testID, "Setting up tests")
+		ID:                   testID,
+		Namespace:            namespace,
+		CreateNamespace:      createNamespace,
+		DeleteNamespace:      createNamespace && !noTeardown,
+		NamespaceLabels:      namespaceMeta.labels,
+		NamespaceAnnotations: namespaceMeta.annotations,
+		ServiceAccount:       serviceAccount,
+		Rules:                rules,
+		NamespacedRBAC:       namespacedRBAC,
+		Image:                image,
+		ImagePullPolicy:      pullPolicy,
+		NodeSelector:         scheduling.nodeSelector,
+		Tolerations:          scheduling.tolerations,
+		Affinity:             scheduling.affinity,
+		PriorityClassName:    scheduling.priorityClassName,
+		Sidecars:             sidecars,
+		SidecarVolumes:       sidecarVolumes,
+		Command:              noCopy.command,
+		NoCopy:               noCopy.enabled,
+		Labels:               labels,
+		Annotations:          annotations,
+		Env:                  env,
+		Executable:           executable,
+		Source:               source,
+		Context:              vendoredContext,
+		ValueFiles:           valueFiles,
+		Secrets:              secrets,
+		SecretsFrom:          secretsFrom,
+		Hold:                 artifactsDir != "",
+		PollInterval:         readiness.pollInterval,
+		ReadyTimeout:         readiness.readyTimeout,
+		Config:               config,
+	}
+
+	if workflow != "" {
+		return printWorkflow(cmd.OutOrStdout(), &job, workflow)
+	}
+
+	if dryRun {
+		out := cmd.OutOrStdout()
+		if goTest {
+			fmt.Fprintf(out, "Tests: go tests in %s\n", strings.Join(pkgPaths, ", "))
+		} else if len(pkgPaths) > 0 {
+			if err := printSuites(out, testSuites, tests, methods, tags); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintf(out, "Suites: packaged in image %s\n", image)
+		}
+		printValues(out, valueFiles, values, secretValues)
+		return printJob(out, &job)
+	}
+
+	interrupt := newInterruptHandler(cmd.ErrOrStderr())
+	defer interrupt.stop()
+
+	if err := setUpTestJob(job, interrupt, timeout); err != nil {
+		if interrupt.interrupted() {
+			interrupt.writeSummary(cmd.OutOrStdout(), testID)
+		}
+		return err
+	}
+	removeVendoredContext(vendoredContext, contextPath)
+
+	start := time.Now()
+	summary := newTestSummary()
+	summary.timings = timings
+
+	sink := logging.NewTeeSink(logging.NewConsoleSink(cmd.OutOrStdout(), logging.InfoLevel), summary)
+	if goTest {
+		sink = newGoTestSink(sink)
+	}
+	code, err := runTestJob(job, logging.NewTeeSink(sink, logs), summary, interrupt, artifactsDir, timeout)
+	if err != nil {
+		if interrupt.interrupted() {
+			interrupt.writeSummary(cmd.OutOrStdout(), testID)
+		}
+		return err
+	}
+
+	if job.DeleteNamespace {
+		_ = awaitTeardown(cmd.OutOrStdout(), testID, namespace, teardown)
+	}
+
+	if !noTeardown {
+		if err := checkLeaks(cmd.OutOrStdout(), testID, namespace, failOnLeak); err != nil && code == 0 {
+			code = 1
+		}
+	}
+	if err := hooks.afterCluster(code != 0); err != nil && code == 0 {
+		code = 1
+	}
+
+	summary.write(cmd.OutOrStdout(), time.Since(start))
+	writeTimings(cmd.OutOrStdout(), timings.Get())
+	writeTestReport(cmd.OutOrStdout(), reportOpts, summary, testID, time.Since(start))
+	uploadTestResults(uploads, summary, testID, time.Since(start), artifactsDir, logFile)
+	if code == 0 {
+		successColor.Fprintf(cmd.OutOrStdout(), "%s Tests passed!\n", successIcon)
+	} else {
+		failureColor.Fprintf(cmd.OutOrStdout(), "%s Tests failed!\n", failureIcon)
+	}
+	return exit(cmd, code)
+}
+
+// setUpTestJob creates the given test job
+func setUpTestJob(job job.Job[test.Config], interrupt *interruptHandler, timeout time.Duration) error {
+	step := logging.NewStep(job.ID, "Setting up tests")
 	step.Start()
-	if err := job.Create(ctx, step); err != nil {
+	if err := createJob(interrupt.ctx, job, step, interrupt, timeout); err != nil {
 		step.Fail(err)
 		return err
 	}
 	step.Complete()
+	return nil
+}
 
-	step = logging.NewStep(testID, "Running tests")
+// runTestJob writes the output of the given test job to the given sink until the tests complete, collecting
+// their artifacts to the given directory if the job holds them, and returns the exit code of the tests once the
+// job is deleted
+// If summary is not nil, the time spent in each suite is recorded as part of running the tests. If the tests are
+// interrupted, the job is deleted and errInterrupted is returned.
+func runTestJob(job job.Job[test.Config], sink logging.Sink, summary *testSummary, interrupt *interruptHandler, artifactsDir string, timeout time.Duration) (int, error) {
+	ctx := interrupt.ctx
+	step := logging.NewStep(job.ID, "Running tests")
 	step.Start()
 
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
 	doneCh := make(chan struct{})
 
+	logsCh := make(chan struct{})
 	go func() {
-		defer close(doneCh)
+		defer close(logsCh)
 
 		// Open a log stream for the job
 		stream, err := job.GetLogs(ctx)
@@ -244,43 +628,173 @@ func runTestCommand(cmd *cobra.Command, args []string) error {
 
 		scanner := bufio.NewScanner(stream)
 		for scanner.Scan() {
-			fmt.Fprintf(cmd.OutOrStdout(), "    %s\n", scanner.Text())
+			_ = sink.Write(job.ID, scanner.Text())
 		}
 	}()
 
+	go func() {
+		defer close(doneCh)
+		if job.Hold {
+			// Wait for the tests to complete and collect artifacts before releasing the job
+			if _, err := job.AwaitExit(ctx); err == nil {
+				artifactsStep := logging.NewStep(job.ID, "Collecting artifacts")
+				artifactsStep.Start()
+				if err := job.CopyFrom(ctx, job.Config.ArtifactsDir, artifactsDir); err != nil {
+					artifactsStep.Fail(err)
+				} else {
+					artifactsStep.Complete()
+				}
+			}
+			if err := job.Release(ctx); err != nil {
+				step.Fail(err)
+				return
+			}
+		}
+		<-logsCh
+	}()
+
+	// Interrupting the tests also ends the log stream, so check for the interrupt once either is done
 	select {
-	case <-signalCh:
+	case <-ctx.Done():
+	case <-doneCh:
+	}
+
+	if interrupt.interrupted() {
 		step.Fail(errors.New("tests canceled"))
 
-		step = logging.NewStep(testID, "Cancelling test job")
+		step = logging.NewStep(job.ID, "Cancelling test job")
 		step.Start()
-		if err := job.Delete(ctx, step); err != nil {
+		if err := deleteJob(job, step, interrupt, timeout); err != nil {
 			step.Fail(err)
-			return err
+			return 0, err
 		}
+		keepNamespace(job, interrupt)
 		step.Complete()
-	case <-doneCh:
-		// Get the exit code for the job.
-		_, code, err := job.GetStatus(ctx)
+		return 0, errInterrupted
+	}
+
+	// Get the exit code for the job.
+	_, code, err := job.GetStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if summary != nil {
+		summary.recordTimings(step)
+	}
+	step.Complete()
+
+	step = logging.NewStep(job.ID, "Cleaning up tests")
+	step.Start()
+	if err := job.Delete(ctx, step); err != nil {
+		step.Fail(err)
+		return 0, err
+	}
+	step.Complete()
+	return code, nil
+}
+
+// runLocalTests runs the tests in a local process against the current Kubernetes configuration
+func runLocalTests(cmd *cobra.Command, testID, executable, contextPath, artifactsDir string, valueFiles map[string][]string,
+	secrets map[string]string, secretsFrom []string, createNamespace bool, namespaceMeta namespaceMetadata, logs logging.Sink, reportOpts *reportOptions, uploads *resultsUpload, failOnLeak bool, hooks *provisioningHooks, timings *logging.Timings, config test.Config) error {
+	if contextPath != "" {
+		path, err := filepath.Abs(contextPath)
 		if err != nil {
 			return err
 		}
-		step.Complete()
+		config.Context = path
+	}
 
-		step = logging.NewStep(testID, "Cleaning up tests")
-		step.Start()
-		if err := job.Delete(ctx, step); err != nil {
-			step.Fail(err)
+	if artifactsDir != "" {
+		path, err := filepath.Abs(artifactsDir)
+		if err != nil {
 			return err
 		}
-		step.Complete()
+		config.ArtifactsDir = path
+	}
 
-		if code == 0 {
-			successColor.Fprintf(cmd.OutOrStdout(), "%s Tests passed!\n", successIcon)
-		} else {
-			failureColor.Fprintf(cmd.OutOrStdout(), "%s Tests failed!\n", failureIcon)
+	if len(valueFiles) > 0 {
+		config.ValueFiles = make(map[string][]string)
+		for release, releaseFiles := range valueFiles {
+			var absFiles []string
+			for _, releaseFile := range releaseFiles {
+				path, err := filepath.Abs(releaseFile)
+				if err != nil {
+					return err
+				}
+				absFiles = append(absFiles, path)
+			}
+			config.ValueFiles[release] = absFiles
 		}
-		os.Exit(code)
 	}
-	return nil
+
+	process := local.Process[test.Config]{
+		ID:                   testID,
+		Namespace:            config.Namespace,
+		CreateNamespace:      createNamespace,
+		DeleteNamespace:      createNamespace && !config.NoTeardown,
+		NamespaceLabels:      namespaceMeta.labels,
+		NamespaceAnnotations: namespaceMeta.annotations,
+		Executable:           executable,
+		Context:              config.Context,
+		Secrets:              secrets,
+		SecretsFrom:          secretsFrom,
+		Config:               config,
+	}
+
+	interrupt := newInterruptHandler(cmd.ErrOrStderr())
+	defer interrupt.stop()
+	ctx := interrupt.ctx
+
+	step := logging.NewStep(testID, "Running tests")
+	step.Start()
+	start := time.Now()
+	summary := newTestSummary()
+	summary.timings = timings
+	sink := logging.NewTeeSink(logging.NewConsoleSink(cmd.OutOrStdout(), logging.InfoLevel), logs, summary)
+	out := logging.NewSinkWriter(testID, sink)
+	code, err := process.Run(ctx, out, step)
+	_ = out.Close()
+	if err != nil {
+		step.Fail(err)
+		return err
+	}
+	if interrupt.interrupted() {
+		step.Fail(errors.New("tests canceled"))
+		// The process deletes the namespace it created when it exits
+		if process.DeleteNamespace {
+			interrupt.record("Deleted namespace %s", process.Namespace)
+		} else if process.CreateNamespace {
+			interrupt.record("Kept namespace %s (--no-teardown)", process.Namespace)
+		}
+		interrupt.writeSummary(cmd.OutOrStdout(), testID)
+		return errInterrupted
+	}
+	summary.recordTimings(step)
+	step.Complete()
+
+	// The process deleted the namespace it created when it exited
+	if process.DeleteNamespace {
+		teardown, _ := getTeardown(cmd)
+		_ = awaitTeardown(cmd.OutOrStdout(), testID, config.Namespace, teardown)
+	}
+
+	if !config.NoTeardown {
+		if err := checkLeaks(cmd.OutOrStdout(), testID, config.Namespace, failOnLeak); err != nil && code == 0 {
+			code = 1
+		}
+	}
+	if err := hooks.afterCluster(code != 0); err != nil && code == 0 {
+		code = 1
+	}
+
+	summary.write(cmd.OutOrStdout(), time.Since(start))
+	writeTimings(cmd.OutOrStdout(), timings.Get())
+	writeTestReport(cmd.OutOrStdout(), reportOpts, summary, testID, time.Since(start))
+	uploadTestResults(uploads, summary, testID, time.Since(start), artifactsDir, "")
+	if code == 0 {
+		successColor.Fprintf(cmd.OutOrStdout(), "%s Tests passed!\n", successIcon)
+	} else {
+		failureColor.Fprintf(cmd.OutOrStdout(), "%s Tests failed!\n", failureIcon)
+	}
+	return exit(cmd, code)
 }
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/report"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// getTestSuite returns the suite of the named test, i.e. the top-level test
+func getTestSuite(name string) string {
+	return strings.SplitN(name, "/", 2)[0]
+}
+
+// recordTimings records the time spent in each suite as sub-steps of the given step
+func (s *testSummary) recordTimings(step logging.TimingRecorder) {
+	for _, timing := range s.getTimings() {
+		step.Record(timing)
+	}
+}
+
+// getTimings returns the time spent in each suite, broken down into the Helm operations run by the suite and the
+// time spent in each of its tests
+// The time spent in a test across repeated iterations is added up, and the Helm operations on the same release
+// are added up by operation.
+func (s *testSummary) getTimings() []logging.Timing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var suites []string
+	suiteTimings := make(map[string]*logging.Timing)
+	getSuite := func(name string) *logging.Timing {
+		timing, ok := suiteTimings[name]
+		if !ok {
+			timing = &logging.Timing{Name: name}
+			suiteTimings[name] = timing
+			suites = append(suites, name)
+		}
+		return timing
+	}
+	addStep := func(timing *logging.Timing, name string, duration time.Duration) {
+		for i := range timing.Steps {
+			if timing.Steps[i].Name == name {
+				timing.Steps[i].Duration += duration
+				return
+			}
+		}
+		timing.Steps = append(timing.Steps, logging.Timing{Name: name, Duration: duration})
+	}
+
+	for _, result := range s.results {
+		if getTestSuite(result.name) == result.name {
+			getSuite(result.name).Duration += result.duration
+		}
+	}
+	for _, helm := range s.helm {
+		if helm.suite == "" {
+			continue
+		}
+		addStep(getSuite(helm.suite), fmt.Sprintf("helm %s %s", helm.operation, helm.release), helm.duration)
+	}
+	for _, result := range s.getLeafResults() {
+		if suite := getTestSuite(result.name); suite != result.name {
+			addStep(getSuite(suite), strings.TrimPrefix(result.name, suite+"/"), result.duration)
+		}
+	}
+
+	timings := make([]logging.Timing, 0, len(suites))
+	for _, suite := range suites {
+		timings = append(timings, *suiteTimings[suite])
+	}
+	return timings
+}
+
+// writeTimings writes a breakdown of the time spent in each of the given steps and their sub-steps
+// Each step's share of the total time spent in the top-level steps is listed, so the steps dominating the run
+// stand out.
+func writeTimings(out io.Writer, timings []logging.Timing) {
+	if len(timings) == 0 {
+		return
+	}
+	var total time.Duration
+	for _, timing := range timings {
+		total += timing.Duration
+	}
+	fmt.Fprintln(out, "Timing:")
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	writeTimingSteps(writer, timings, total, 1)
+	writer.Flush()
+	fmt.Fprintln(out)
+}
+
+func writeTimingSteps(writer io.Writer, timings []logging.Timing, total time.Duration, depth int) {
+	for _, timing := range timings {
+		percent := 0.0
+		if total > 0 {
+			percent = float64(timing.Duration) / float64(total) * 100
+		}
+		fmt.Fprintf(writer, "%s%s\t%s\t%.1f%%\n", strings.Repeat("  ", depth), timing.Name, formatTiming(timing.Duration), percent)
+		writeTimingSteps(writer, timing.Steps, total, depth+1)
+	}
+}
+
+// formatTiming formats the given duration with a precision suited to its magnitude
+func formatTiming(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// newReportTimings returns the report of the given step timings
+func newReportTimings(timings []logging.Timing) []report.Timing {
+	var reportTimings []report.Timing
+	for _, timing := range timings {
+		reportTimings = append(reportTimings, report.Timing{
+			Name:     timing.Name,
+			Duration: timing.Duration,
+			Steps:    newReportTimings(timing.Steps),
+		})
+	}
+	return reportTimings
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const timingOutput = `=== RUN   TestChartSuite
+=== HELM install controller (12.50s)
+=== HELM install raft (30.00s)
+=== RUN   TestChartSuite/TestPut
+--- PASS: TestChartSuite/TestPut (1.50s)
+=== RUN   TestChartSuite/TestGet
+--- PASS: TestChartSuite/TestGet (0.50s)
+=== HELM uninstall raft (2.00s)
+--- PASS: TestChartSuite (47.00s)
+=== RUN   TestOtherSuite
+--- PASS: TestOtherSuite (0.25s)
+PASS
+`
+
+func TestTimings(t *testing.T) {
+	summary := newTestSummary()
+	for _, line := range strings.Split(timingOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+
+	timings := summary.getTimings()
+	assert.Len(t, timings, 2)
+	assert.Equal(t, "TestChartSuite", timings[0].Name)
+	assert.Equal(t, 47*time.Second, timings[0].Duration)
+	assert.Equal(t, []logging.Timing{
+		{Name: "helm install controller", Duration: 12500 * time.Millisecond},
+		{Name: "helm install raft", Duration: 30 * time.Second},
+		{Name: "helm uninstall raft", Duration: 2 * time.Second},
+		{Name: "TestPut", Duration: 1500 * time.Millisecond},
+		{Name: "TestGet", Duration: 500 * time.Millisecond},
+	}, timings[0].Steps)
+	assert.Equal(t, "TestOtherSuite", timings[1].Name)
+	assert.Empty(t, timings[1].Steps)
+
+	reportTimings := newReportTimings(timings)
+	assert.Len(t, reportTimings, 2)
+	assert.Len(t, reportTimings[0].Steps, 5)
+	assert.Equal(t, "helm install raft", reportTimings[0].Steps[1].Name)
+}
+
+func TestRepeatedTimings(t *testing.T) {
+	summary := newTestSummary()
+	for _, line := range strings.Split("=== ITERATION 1\n"+timingOutput+"=== ITERATION 2\n"+timingOutput, "\n") {
+		assert.NoError(t, summary.Write("test", line))
+	}
+	timings := summary.getTimings()
+	assert.Len(t, timings, 2)
+	assert.Equal(t, 94*time.Second, timings[0].Duration)
+	assert.Len(t, timings[0].Steps, 5)
+	assert.Equal(t, 60*time.Second, timings[0].Steps[1].Duration)
+}
+
+func TestWriteTimings(t *testing.T) {
+	var out bytes.Buffer
+	writeTimings(&out, nil)
+	assert.Empty(t, out.String())
+
+	writeTimings(&out, []logging.Timing{
+		{
+			Name:     "Setting up tests",
+			Duration: 30 * time.Second,
+			Steps: []logging.Timing{
+				{Name: "Pulling images and starting containers", Duration: 25 * time.Second},
+			},
+		},
+		{Name: "Running tests", Duration: 90 * time.Second},
+	})
+	output := out.String()
+	assert.Contains(t, output, "Timing:")
+	assert.Regexp(t, `Setting up tests\s+30s\s+25\.0%`, output)
+	assert.Regexp(t, `    Pulling images and starting containers\s+25s\s+20\.8%`, output)
+	assert.Regexp(t, `Running tests\s+1m30s\s+75\.0%`, output)
+}
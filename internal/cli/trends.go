@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/spf13/cobra"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const trendsExamples = `
+  # Summarize the trends of the benchmark results uploaded to a directory with --upload-url.
+  helmit bench trends --dir ./results
+
+  # Compare the latest run of each benchmark with the previous 5 runs and fail if any regressed.
+  helmit bench trends --dir ./results --baseline 5 --fail-on-regression
+
+  # Render the trends as Markdown, e.g. for a CI job summary.
+  helmit bench trends --dir ./results -o md >> $GITHUB_STEP_SUMMARY
+`
+
+// markdownOutput is the output format of commands rendering GitHub flavored Markdown
+const markdownOutput = "md"
+
+func getBenchTrendsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "trends",
+		Short:   "Summarize the trends of historical benchmark results and flag regressions",
+		Example: trendsExamples,
+		Args:    cobra.NoArgs,
+		RunE:    runBenchTrendsCommand,
+	}
+	cmd.Flags().String("dir", "results", "the directory containing the JSON benchmark reports of past runs, e.g. uploaded with --upload-url")
+	cmd.Flags().Int("baseline", 10, "the number of runs preceding the latest run of each benchmark to compare it with")
+	cmd.Flags().StringP("output", "o", textOutput, "the output format (text or md)")
+	cmd.Flags().Bool("fail-on-regression", false, "fail if the latest run of any benchmark regressed significantly")
+	return cmd
+}
+
+func runBenchTrendsCommand(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	dir, _ := cmd.Flags().GetString("dir")
+	baseline, _ := cmd.Flags().GetInt("baseline")
+	output, _ := cmd.Flags().GetString("output")
+	failOnRegression, _ := cmd.Flags().GetBool("fail-on-regression")
+	if baseline < 1 {
+		return errors.New("--baseline must be positive")
+	}
+	if output != textOutput && output != markdownOutput {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	reports, err := loadBenchmarkReports(dir, cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("no benchmark reports found in %s", dir)
+	}
+	trends := getBenchmarkTrends(reports, baseline)
+	if output == markdownOutput {
+		writeTrendsMarkdown(cmd.OutOrStdout(), trends)
+	} else {
+		writeTrends(cmd.OutOrStdout(), trends)
+	}
+
+	if failOnRegression {
+		var regressions int
+		for _, trend := range trends {
+			if trend.regressed() {
+				regressions++
+			}
+		}
+		if regressions > 0 {
+			return fmt.Errorf("%d benchmark(s) regressed", regressions)
+		}
+	}
+	return nil
+}
+
+// loadBenchmarkReports loads the JSON benchmark reports in the given directory and its subdirectories
+// Other JSON files, e.g. test reports, are skipped, and files that cannot be parsed are reported and skipped so
+// one corrupt upload does not hide the history.
+func loadBenchmarkReports(dir string, errOut io.Writer) ([]report.BenchmarkReport, error) {
+	var reports []report.BenchmarkReport
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		bytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var benchReport report.BenchmarkReport
+		if err := json.Unmarshal(bytes, &benchReport); err != nil {
+			fmt.Fprintf(errOut, "Skipping %s: %s\n", path, err)
+			return nil
+		}
+		if benchReport.Suite != "" && len(benchReport.Runs) > 0 {
+			reports = append(reports, benchReport)
+		}
+		return nil
+	})
+	return reports, err
+}
+
+// trendMetric is a metric whose samples are compared between runs
+type trendMetric struct {
+	// latest is the value of the metric in the latest run
+	latest float64
+	// baseline is the median value of the metric in the baseline runs
+	baseline float64
+	// history is the value of the metric in each run, oldest first
+	history []float64
+	// p is the p-value of the difference between the latest run and the baseline, or -1 if not tested
+	p float64
+	// err is the reason the difference could not be tested
+	err error
+	// higherIsBetter indicates whether an increase in the metric is an improvement
+	higherIsBetter bool
+}
+
+// change returns the relative change from the baseline to the latest run
+func (m trendMetric) change() string {
+	if len(m.history) < 2 {
+		return "-"
+	}
+	return formatChange(m.baseline, m.latest)
+}
+
+// result returns whether the latest run improved or regressed significantly
+func (m trendMetric) result() string {
+	if m.err != nil {
+		return m.err.Error()
+	}
+	if m.p < 0 || m.p >= significanceLevel || m.latest == m.baseline {
+		return "no change"
+	}
+	if (m.latest > m.baseline) == m.higherIsBetter {
+		return "improved"
+	}
+	return "regressed"
+}
+
+// benchmarkTrend is the trend of a benchmark over its historical runs
+type benchmarkTrend struct {
+	name       string
+	runs       int
+	latest     string
+	throughput trendMetric
+	p99Latency trendMetric
+}
+
+// regressed returns whether the throughput or latency of the latest run regressed significantly
+func (t benchmarkTrend) regressed() bool {
+	return t.throughput.result() == "regressed" || t.p99Latency.result() == "regressed"
+}
+
+// result summarizes the results of the latest run's metrics
+func (t benchmarkTrend) result() string {
+	throughput, latency := t.throughput.result(), t.p99Latency.result()
+	switch {
+	case throughput == "regressed" || latency == "regressed":
+		return "regressed"
+	case throughput == "improved" || latency == "improved":
+		return "improved"
+	case t.throughput.err != nil:
+		return throughput
+	}
+	return "no change"
+}
+
+// trendRun is a successful run of a benchmark with the samples of its metrics
+type trendRun struct {
+	id         string
+	start      time.Time
+	throughput []float64
+	p99Latency []float64
+}
+
+// getBenchmarkTrends returns the trend of each benchmark in the given reports, sorted by name
+// Runs are identified by the suite, the benchmark, and the run's matrix parameters, and are ordered by their
+// start time. The latest run of each benchmark is compared with up to baseline preceding runs.
+func getBenchmarkTrends(reports []report.BenchmarkReport, baseline int) []benchmarkTrend {
+	series := make(map[string][]trendRun)
+	for _, benchReport := range reports {
+		for _, run := range benchReport.Runs {
+			if run.Error != "" {
+				continue
+			}
+			name := fmt.Sprintf("%s/%s", benchReport.Suite, benchReport.Benchmark)
+			if run.Name != "" {
+				name = fmt.Sprintf("%s [%s]", name, run.Name)
+			}
+			series[name] = append(series[name], newTrendRun(benchReport, run))
+		}
+	}
+
+	trends := make([]benchmarkTrend, 0, len(series))
+	for _, name := range sortedKeys(series) {
+		runs := series[name]
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[i].start.Before(runs[j].start)
+		})
+		if len(runs) > baseline+1 {
+			runs = runs[len(runs)-baseline-1:]
+		}
+		latest, previous := runs[len(runs)-1], runs[:len(runs)-1]
+		var throughput, p99Latency [][]float64
+		for _, run := range previous {
+			throughput = append(throughput, run.throughput)
+			p99Latency = append(p99Latency, run.p99Latency)
+		}
+		trends = append(trends, benchmarkTrend{
+			name:       name,
+			runs:       len(runs),
+			latest:     latest.id,
+			throughput: newTrendMetric(latest.throughput, throughput, true),
+			p99Latency: newTrendMetric(latest.p99Latency, p99Latency, false),
+		})
+	}
+	return trends
+}
+
+// newTrendRun returns the samples of the given run
+// The samples of a run are its interval samples, so the variance within runs is accounted for when runs are
+// compared. Runs recorded without a history are represented by their totals.
+func newTrendRun(benchReport report.BenchmarkReport, run report.BenchmarkRun) trendRun {
+	trendRun := trendRun{
+		id:    benchReport.ID,
+		start: benchReport.Start,
+	}
+	for _, sample := range run.History {
+		if sample.Throughput > 0 {
+			trendRun.throughput = append(trendRun.throughput, sample.Throughput)
+			trendRun.p99Latency = append(trendRun.p99Latency, float64(sample.P99Latency))
+		}
+	}
+	if len(trendRun.throughput) == 0 {
+		trendRun.throughput = []float64{report.Throughput(run.Total)}
+		trendRun.p99Latency = []float64{float64(run.Total.P99Latency)}
+	}
+	return trendRun
+}
+
+// newTrendMetric returns the trend of a metric given the samples of the latest run and of each baseline run
+func newTrendMetric(latest []float64, baseline [][]float64, higherIsBetter bool) trendMetric {
+	metric := trendMetric{
+		latest:         median(latest),
+		p:              -1,
+		higherIsBetter: higherIsBetter,
+	}
+	var pooled []float64
+	for _, samples := range baseline {
+		metric.history = append(metric.history, median(samples))
+		pooled = append(pooled, samples...)
+	}
+	metric.history = append(metric.history, metric.latest)
+	if len(baseline) == 0 {
+		metric.err = errors.New("no baseline")
+		return metric
+	}
+	metric.baseline = median(pooled)
+	metric.p, metric.err = mannWhitneyUTest(latest, pooled)
+	return metric
+}
+
+// median returns the median of the given samples
+func median(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// mannWhitneyUTest returns the two-tailed p-value of the Mann-Whitney U test for a difference between the
+// distributions of two samples
+// The test makes no assumption about the distribution of the samples, e.g. latencies, which are rarely normal.
+// The p-value is computed with the normal approximation, corrected for ties and continuity.
+func mannWhitneyUTest(a, b []float64) (float64, error) {
+	if len(a) < 3 || len(b) < 3 {
+		return 0, errors.New("too few samples")
+	}
+	type rankedSample struct {
+		value float64
+		a     bool
+	}
+	samples := make([]rankedSample, 0, len(a)+len(b))
+	for _, value := range a {
+		samples = append(samples, rankedSample{value: value, a: true})
+	}
+	for _, value := range b {
+		samples = append(samples, rankedSample{value: value})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].value < samples[j].value
+	})
+
+	// Tied samples are assigned the mean of the ranks they span
+	var rankSumA, ties float64
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if samples[k].a {
+				rankSumA += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+	u := rankSumA - n1*(n1+1)/2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 0, errors.New("no variance")
+	}
+	z := (math.Abs(u-n1*n2/2) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2), nil
+}
+
+// formatTrendLatency formats a latency in nanoseconds
+func formatTrendLatency(value float64) string {
+	return time.Duration(value).Round(time.Microsecond).String()
+}
+
+// writeTrends writes a table of the trends of each benchmark
+func writeTrends(out io.Writer, trends []benchmarkTrend) {
+	writer := new(tabwriter.Writer)
+	writer.Init(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(writer, "BENCHMARK\tRUNS\tLATEST\tTHROUGHPUT\tCHANGE\tTREND\t99% LATENCY\tCHANGE\tTREND\tRESULT")
+	for _, trend := range trends {
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%.2f/sec\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			trend.name, trend.runs, trend.latest,
+			trend.throughput.latest, trend.throughput.change(), sparkline(trend.throughput.history),
+			formatTrendLatency(trend.p99Latency.latest), trend.p99Latency.change(), sparkline(trend.p99Latency.history),
+			trend.result())
+	}
+	writer.Flush()
+}
+
+// writeTrendsMarkdown writes the trends of each benchmark as a Markdown table
+func writeTrendsMarkdown(out io.Writer, trends []benchmarkTrend) {
+	fmt.Fprintln(out, "# Benchmark Trends")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "| Benchmark | Runs | Latest | Throughput | Change | Trend | 99% Latency | Change | Trend | Result |")
+	fmt.Fprintln(out, "|-----------|------|--------|------------|--------|-------|-------------|--------|-------|--------|")
+	for _, trend := range trends {
+		result := trend.result()
+		switch result {
+		case "regressed":
+			result = "❌ regressed"
+		case "improved":
+			result = "✅ improved"
+		}
+		fmt.Fprintf(out, "| `%s` | %d | %s | %.2f/sec | %s | %s | %s | %s | %s | %s |\n",
+			trend.name, trend.runs, trend.latest,
+			trend.throughput.latest, trend.throughput.change(), sparkline(trend.throughput.history),
+			formatTrendLatency(trend.p99Latency.latest), trend.p99Latency.change(), sparkline(trend.p99Latency.history),
+			result)
+	}
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMannWhitneyUTest(t *testing.T) {
+	p, err := mannWhitneyUTest([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.0122, p, 0.001)
+
+	p, err = mannWhitneyUTest([]float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10})
+	assert.NoError(t, err)
+	assert.Greater(t, p, significanceLevel)
+
+	_, err = mannWhitneyUTest([]float64{1, 1, 1}, []float64{1, 1, 1})
+	assert.Error(t, err)
+	_, err = mannWhitneyUTest([]float64{1, 2}, []float64{1, 2, 3})
+	assert.Error(t, err)
+}
+
+// newTrendReport returns a benchmark report of a run with the given throughput and latency samples
+func newTrendReport(id string, start time.Time, throughput []float64, latency time.Duration) report.BenchmarkReport {
+	run := report.BenchmarkRun{
+		Total: benchmark.Report{Iterations: 1000, Duration: time.Second, P99Latency: latency},
+	}
+	for i, value := range throughput {
+		run.History = append(run.History, report.BenchmarkSample{
+			Elapsed:    time.Duration(i) * time.Second,
+			Throughput: value,
+			P99Latency: latency + time.Duration(i)*time.Microsecond,
+		})
+	}
+	return report.BenchmarkReport{
+		ID:        id,
+		Suite:     "atomix",
+		Benchmark: "BenchmarkMap",
+		Start:     start,
+		Runs:      []report.BenchmarkRun{run},
+	}
+}
+
+func TestBenchmarkTrends(t *testing.T) {
+	start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	var reports []report.BenchmarkReport
+	for i := 0; i < 4; i++ {
+		reports = append(reports, newTrendReport(fmt.Sprintf("run-%d", i), start.Add(time.Duration(i)*24*time.Hour),
+			[]float64{1000, 1010, 990, 1005, 995}, time.Millisecond))
+	}
+	// The latest run is loaded first, but compared with the runs that started before it
+	reports = append([]report.BenchmarkReport{newTrendReport("latest", start.Add(5*24*time.Hour),
+		[]float64{800, 810, 790, 805, 795}, 2*time.Millisecond)}, reports...)
+
+	trends := getBenchmarkTrends(reports, 2)
+	if assert.Len(t, trends, 1) {
+		trend := trends[0]
+		assert.Equal(t, "atomix/BenchmarkMap", trend.name)
+		assert.Equal(t, 3, trend.runs)
+		assert.Equal(t, "latest", trend.latest)
+		assert.Equal(t, "-20.00%", trend.throughput.change())
+		assert.Equal(t, "regressed", trend.throughput.result())
+		assert.Equal(t, "regressed", trend.p99Latency.result())
+		assert.True(t, trend.regressed())
+		assert.Len(t, trend.throughput.history, 3)
+	}
+
+	// Runs without a baseline cannot be compared
+	trends = getBenchmarkTrends(reports[:1], 2)
+	if assert.Len(t, trends, 1) {
+		assert.Equal(t, "no baseline", trends[0].result())
+		assert.False(t, trends[0].regressed())
+	}
+
+	var buf bytes.Buffer
+	writeTrendsMarkdown(&buf, getBenchmarkTrends(reports, 2))
+	assert.Contains(t, buf.String(), "| `atomix/BenchmarkMap` | 3 | latest | 800.00/sec | -20.00% |")
+	assert.Contains(t, buf.String(), "❌ regressed")
+}
+
+func TestLoadBenchmarkReports(t *testing.T) {
+	dir := t.TempDir()
+	renderer, err := report.NewRenderer(report.JSONFormat)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, renderer.RenderBenchmark(&buf, newTrendReport("happy-panda", time.Now(), []float64{1000}, time.Millisecond)))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "happy-panda"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "happy-panda", "report.json"), buf.Bytes(), 0644))
+
+	buf.Reset()
+	assert.NoError(t, renderer.RenderTests(&buf, report.TestReport{ID: "sad-panda"}))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tests.json"), buf.Bytes(), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0644))
+
+	var errOut bytes.Buffer
+	reports, err := loadBenchmarkReports(dir, &errOut)
+	assert.NoError(t, err)
+	if assert.Len(t, reports, 1) {
+		assert.Equal(t, "happy-panda", reports[0].ID)
+	}
+	assert.Contains(t, errOut.String(), "corrupt.json")
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/onosproject/helmit/internal/upload"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+const (
+	// resultsFile is the name of the serialized report uploaded for each run
+	resultsFile = "report.json"
+	// uploadTimeout is the time allowed to upload the results of a run
+	uploadTimeout = 10 * time.Minute
+)
+
+// addUploadFlags adds the flags for uploading the command's results to the given command
+func addUploadFlags(cmd *cobra.Command) {
+	cmd.Flags().String("upload-url", "", "a URL to which to upload the results and artifacts, e.g. s3://bucket/path, gs://bucket/path, https://host/path, or a local directory")
+}
+
+// resultsUpload uploads the results of a run set by the flags added with addUploadFlags
+type resultsUpload struct {
+	uploader upload.Uploader
+	url      string
+}
+
+// getResultsUpload returns the results upload set by the flags added with addUploadFlags, or nil if the results
+// are not uploaded
+func getResultsUpload(cmd *cobra.Command) (*resultsUpload, error) {
+	url, _ := cmd.Flags().GetString("upload-url")
+	if url == "" {
+		return nil, nil
+	}
+	uploader, err := upload.New(url)
+	if err != nil {
+		return nil, err
+	}
+	return &resultsUpload{
+		uploader: uploader,
+		url:      url,
+	}, nil
+}
+
+// upload uploads the serialized report and the given local files and directories of the run with the given ID
+// Results are uploaded under a directory named for the run, with the files keyed by their name in the directory.
+// Files that do not exist are skipped. Failures to upload are printed rather than returned so the exit code
+// reflects the results, and uploading with nil options is a no-op.
+func (u *resultsUpload) upload(id string, render func(renderer report.Renderer, out io.Writer) error, files map[string]string) {
+	if u == nil {
+		return
+	}
+	step := logging.NewStep(id, "Uploading results to %s", u.url)
+	step.Start()
+	if err := u.uploadResults(id, render, files); err != nil {
+		step.Fail(err)
+		return
+	}
+	step.Complete()
+}
+
+func (u *resultsUpload) uploadResults(id string, render func(renderer report.Renderer, out io.Writer) error, files map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	renderer, err := report.NewRenderer(report.JSONFormat)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := render(renderer, &buf); err != nil {
+		return err
+	}
+	if err := u.uploader.Upload(ctx, path.Join(id, resultsFile), &buf); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(files) {
+		file := files[name]
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			err = upload.Dir(ctx, u.uploader, path.Join(id, name), file)
+		} else {
+			err = upload.File(ctx, u.uploader, path.Join(id, name), file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
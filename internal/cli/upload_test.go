@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"github.com/onosproject/helmit/internal/report"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultsUpload(t *testing.T) {
+	uploads, err := getResultsUpload(getBenchCommand())
+	assert.NoError(t, err)
+	assert.Nil(t, uploads)
+
+	src := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "happy-panda.cpu.pprof"), []byte("cpu"), 0644))
+	dst := t.TempDir()
+	cmd := getBenchCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--upload-url", "file://" + dst}))
+	uploads, err = getResultsUpload(cmd)
+	assert.NoError(t, err)
+
+	render := func(renderer report.Renderer, out io.Writer) error {
+		return renderer.RenderBenchmark(out, report.BenchmarkReport{ID: "happy-panda", Suite: "atomix"})
+	}
+	assert.NoError(t, uploads.uploadResults("happy-panda", render, map[string]string{
+		"profiles":    src,
+		"samples.csv": filepath.Join(src, "samples.csv"),
+	}))
+
+	bytes, err := os.ReadFile(filepath.Join(dst, "happy-panda", "report.json"))
+	assert.NoError(t, err)
+	var benchReport report.BenchmarkReport
+	assert.NoError(t, json.Unmarshal(bytes, &benchReport))
+	assert.Equal(t, "atomix", benchReport.Suite)
+	_, err = os.Stat(filepath.Join(dst, "happy-panda", "profiles", "happy-panda.cpu.pprof"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dst, "happy-panda", "samples.csv"))
+	assert.True(t, os.IsNotExist(err))
+
+	cmd = getBenchCommand()
+	assert.NoError(t, cmd.ParseFlags([]string{"--upload-url", "ftp://example.com/results"}))
+	_, err = getResultsUpload(cmd)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/onosproject/helmit/internal/logging"
+	"sync"
+)
+
+// workerColors are the colors with which worker tags are displayed, cycled by worker number
+// Colors are disabled automatically when the output is not a terminal.
+var workerColors = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgMagenta),
+	color.New(color.FgYellow),
+	color.New(color.FgGreen),
+	color.New(color.FgBlue),
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiYellow),
+}
+
+// tagWorkerLine prefixes a line of worker output with the worker's tag
+func tagWorkerLine(worker int, line string) string {
+	tag := workerColors[worker%len(workerColors)].Sprintf("[worker-%d]", worker)
+	return fmt.Sprintf("%s %s", tag, line)
+}
+
+// newBufferedLogUI returns a benchmark UI that buffers the output of each job, writing it to the given sink
+// grouped by job once the UI is closed
+func newBufferedLogUI(ui benchmarkUI, sink logging.Sink) benchmarkUI {
+	return &bufferedLogUI{
+		benchmarkUI: ui,
+		sink:        sink,
+		lines:       make(map[string][]string),
+	}
+}
+
+// bufferedLogUI buffers job output so the logs of concurrent workers are not interleaved
+type bufferedLogUI struct {
+	benchmarkUI
+	sink  logging.Sink
+	jobs  []string
+	lines map[string][]string
+	mu    sync.Mutex
+}
+
+func (ui *bufferedLogUI) Log(job string, line string) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	if _, ok := ui.lines[job]; !ok {
+		ui.jobs = append(ui.jobs, job)
+	}
+	ui.lines[job] = append(ui.lines[job], line)
+}
+
+func (ui *bufferedLogUI) Close() error {
+	err := ui.benchmarkUI.Close()
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	for _, job := range ui.jobs {
+		for _, line := range ui.lines[job] {
+			_ = ui.sink.Write(job, line)
+		}
+	}
+	ui.jobs = nil
+	ui.lines = make(map[string][]string)
+	return err
+}
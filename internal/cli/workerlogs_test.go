@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTagWorkerLine(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+	assert.Equal(t, "[worker-3] connected", tagWorkerLine(3, "connected"))
+	assert.Equal(t, "[worker-12] connected", tagWorkerLine(12, "connected"))
+}
+
+func TestBufferedLogUI(t *testing.T) {
+	sink := &testSink{}
+	ui := newBufferedLogUI(newPlainBenchmarkUI("bench-1"), sink)
+	ui.Log("bench-worker-1", "a")
+	ui.Log("bench-worker-0", "b")
+	ui.Log("bench-worker-1", "c")
+	ui.Log("bench-worker-0", "d")
+	assert.Empty(t, sink.lines)
+
+	assert.NoError(t, ui.Close())
+	assert.Equal(t, []string{
+		"bench-worker-1 a",
+		"bench-worker-1 c",
+		"bench-worker-0 b",
+		"bench-worker-0 d",
+	}, sink.lines)
+}
+
+type testSink struct {
+	lines []string
+}
+
+func (s *testSink) Write(job string, line string) error {
+	s.lines = append(s.lines, fmt.Sprintf("%s %s", job, line))
+	return nil
+}
+
+func (s *testSink) Close() error {
+	return nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/spf13/cobra"
+	"io"
+	"sigs.k8s.io/yaml"
+)
+
+// addWorkflowFlags adds the flags for emitting a workflow in place of running the job to the given command
+func addWorkflowFlags(cmd *cobra.Command) {
+	cmd.Flags().String("emit-workflow", "", "print a workflow manifest running the job with the given pipeline engine (argo or tekton) instead of running it; requires --no-copy")
+}
+
+// getWorkflowEngine returns the engine for which to emit a workflow set by the flags added with addWorkflowFlags,
+// or an empty engine if the job is to be run
+// Nothing can be copied to the pods of a workflow, and the CLI isn't around to collect its results, so the job
+// must be run with --no-copy, and flags acting on the run from the CLI cannot be set.
+func getWorkflowEngine(cmd *cobra.Command, noCopy noCopy) (job.WorkflowEngine, error) {
+	value, _ := cmd.Flags().GetString("emit-workflow")
+	engine := job.WorkflowEngine(value)
+	switch engine {
+	case "":
+		return "", nil
+	case job.ArgoEngine, job.TektonEngine:
+	default:
+		return "", fmt.Errorf("--emit-workflow must be one of %s or %s", job.ArgoEngine, job.TektonEngine)
+	}
+	if !noCopy.enabled {
+		return "", errors.New("--emit-workflow requires --no-copy, since nothing can be copied to the pods of a workflow")
+	}
+	for _, name := range []string{"local", "dry-run", "ephemeral-cluster", "upload-url", "report-format"} {
+		if cmd.Flags().Changed(name) {
+			return "", fmt.Errorf("--emit-workflow cannot be used with --%s", name)
+		}
+	}
+	return engine, nil
+}
+
+// printWorkflow prints the resources that run the given job as a workflow of the given engine
+func printWorkflow[T any](out io.Writer, j *job.Job[T], engine job.WorkflowEngine) error {
+	objects, err := j.Workflow(engine)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		bytes, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "---")
+		fmt.Fprint(out, string(bytes))
+	}
+	return nil
+}
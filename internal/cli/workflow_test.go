@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestGetWorkflowEngine(t *testing.T) {
+	newCommand := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("dry-run", false, "")
+		addWorkflowFlags(cmd)
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	engine, err := getWorkflowEngine(newCommand(), noCopy{})
+	assert.NoError(t, err)
+	assert.Empty(t, engine)
+
+	engine, err = getWorkflowEngine(newCommand("--emit-workflow", "argo"), noCopy{enabled: true})
+	assert.NoError(t, err)
+	assert.Equal(t, job.ArgoEngine, engine)
+
+	engine, err = getWorkflowEngine(newCommand("--emit-workflow", "tekton"), noCopy{enabled: true})
+	assert.NoError(t, err)
+	assert.Equal(t, job.TektonEngine, engine)
+
+	_, err = getWorkflowEngine(newCommand("--emit-workflow", "jenkins"), noCopy{enabled: true})
+	assert.EqualError(t, err, "--emit-workflow must be one of argo or tekton")
+	_, err = getWorkflowEngine(newCommand("--emit-workflow", "argo"), noCopy{})
+	assert.Error(t, err)
+	_, err = getWorkflowEngine(newCommand("--emit-workflow", "argo", "--dry-run"), noCopy{enabled: true})
+	assert.EqualError(t, err, "--emit-workflow cannot be used with --dry-run")
+}
+
+func TestPrintWorkflow(t *testing.T) {
+	j := &job.Job[any]{
+		ID:        "test",
+		Namespace: "default",
+		Image:     "atomix/tests:latest",
+		NoCopy:    true,
+	}
+	out := &bytes.Buffer{}
+	assert.NoError(t, printWorkflow(out, j, job.ArgoEngine))
+	assert.Equal(t, 4, strings.Count(out.String(), "---\n"))
+	assert.Contains(t, out.String(), "kind: Workflow\n")
+}
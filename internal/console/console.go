@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/fatih/color"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ansiRegex matches the ANSI escape sequences that color and position console output
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Init prepares the console for output
+// Colors are disabled if noColor is set or the console cannot render ANSI escape sequences, e.g. a legacy Windows
+// console. Colors are also disabled when stdout is not a terminal or NO_COLOR is set in the environment.
+func Init(noColor bool) {
+	if noColor || (IsTerminal(os.Stdout) && !enableVirtualTerminal(os.Stdout)) {
+		color.NoColor = true
+	}
+}
+
+// IsTerminal returns whether the given writer is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Reporter is a writer that displays the latest frame written to it each time it's flushed
+type Reporter interface {
+	io.Writer
+	// Flush displays the output written since the last flush as the latest frame
+	Flush() error
+}
+
+// NewReporter returns a Reporter displaying frames for the named task on the given writer
+// On a terminal that can render ANSI escape sequences, each frame replaces the previous one in place. Otherwise,
+// frames are logged sequentially, each headed by a timestamp and the name of the task, so logs captured from
+// pipes and CI consoles remain readable.
+func NewReporter(out io.Writer, name string) Reporter {
+	if f, ok := out.(*os.File); ok && IsTerminal(f) && enableVirtualTerminal(f) {
+		return newLiveReporter(out, func() (int, int) {
+			width, height, err := term.GetSize(int(f.Fd()))
+			if err != nil {
+				return 0, 0
+			}
+			return width, height
+		})
+	}
+	return newPlainReporter(out, name)
+}
+
+func newLiveReporter(out io.Writer, size func() (int, int)) *liveReporter {
+	return &liveReporter{
+		out:  out,
+		size: size,
+	}
+}
+
+// liveReporter rewrites each frame in place on a terminal
+// Lines are truncated to the width of the terminal and frames to its height, so the number of lines to rewrite is
+// known exactly rather than guessed from how the terminal wraps long lines.
+type liveReporter struct {
+	out   io.Writer
+	size  func() (int, int)
+	buf   bytes.Buffer
+	lines int
+	mu    sync.Mutex
+}
+
+func (r *liveReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Write(p)
+}
+
+func (r *liveReporter) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf.Len() == 0 {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(r.buf.String(), "\n"), "\n")
+	r.buf.Reset()
+	width, height := r.size()
+	if height > 1 && len(lines) > height-1 {
+		lines = lines[:height-1]
+	}
+
+	var frame bytes.Buffer
+	if r.lines > 0 {
+		// Move to the start of the previous frame and clear it
+		fmt.Fprintf(&frame, "\x1b[%dA\r\x1b[J", r.lines)
+	}
+	for _, line := range lines {
+		// Writing to the last column wraps the line on some terminals, e.g. the Windows console
+		if width > 1 {
+			line = truncate(line, width-1)
+		}
+		frame.WriteString(line)
+		frame.WriteString("\n")
+	}
+	r.lines = len(lines)
+	_, err := r.out.Write(frame.Bytes())
+	return err
+}
+
+func newPlainReporter(out io.Writer, name string) *plainReporter {
+	return &plainReporter{
+		out:  out,
+		name: name,
+	}
+}
+
+// plainReporter logs each frame sequentially, headed by a timestamp and the name of the task
+// Frames that are unchanged from the previous frame are not logged again.
+type plainReporter struct {
+	out  io.Writer
+	name string
+	buf  bytes.Buffer
+	last string
+	mu   sync.Mutex
+}
+
+func (r *plainReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Write(p)
+}
+
+func (r *plainReporter) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf.Len() == 0 {
+		return nil
+	}
+	frame := r.buf.String()
+	r.buf.Reset()
+	if frame == r.last {
+		return nil
+	}
+	r.last = frame
+	if !strings.HasSuffix(frame, "\n") {
+		frame += "\n"
+	}
+	_, err := fmt.Fprintf(r.out, "%s %s\n%s", time.Now().Format(time.RFC3339), r.name, frame)
+	return err
+}
+
+// truncate truncates the given line to the given number of visible characters, preserving escape sequences
+func truncate(line string, width int) string {
+	if len([]rune(ansiRegex.ReplaceAllString(line, ""))) <= width {
+		return line
+	}
+	var b strings.Builder
+	visible := 0
+	for len(line) > 0 && visible < width {
+		if loc := ansiRegex.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			b.WriteString(line[:loc[1]])
+			line = line[loc[1]:]
+			continue
+		}
+		r := []rune(line)[0]
+		b.WriteRune(r)
+		line = line[len(string(r)):]
+		visible++
+	}
+	// Keep any escape sequences that follow, e.g. resetting the color
+	for _, sequence := range ansiRegex.FindAllString(line, -1) {
+		b.WriteString(sequence)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", truncate("abc", 5))
+	assert.Equal(t, "abc", truncate("abcdef", 3))
+	assert.Equal(t, "\x1b[32mab\x1b[0m", truncate("\x1b[32mabcdef\x1b[0m", 2))
+	assert.Equal(t, "\x1b[32mabcdef\x1b[0m", truncate("\x1b[32mabcdef\x1b[0m", 6))
+	assert.Equal(t, "äö", truncate("äöü", 2))
+}
+
+func TestLiveReporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	reporter := newLiveReporter(out, func() (int, int) {
+		return 5, 3
+	})
+
+	fmt.Fprintln(reporter, "first line")
+	fmt.Fprintln(reporter, "second line")
+	fmt.Fprintln(reporter, "third line")
+	assert.NoError(t, reporter.Flush())
+	assert.Equal(t, "firs\nseco\n", out.String())
+
+	out.Reset()
+	fmt.Fprintln(reporter, "one")
+	assert.NoError(t, reporter.Flush())
+	assert.Equal(t, "\x1b[2A\r\x1b[Jone\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, reporter.Flush())
+	assert.Empty(t, out.String())
+}
+
+func TestPlainReporter(t *testing.T) {
+	out := &bytes.Buffer{}
+	reporter := newPlainReporter(out, "test-1")
+
+	fmt.Fprint(reporter, "running")
+	assert.NoError(t, reporter.Flush())
+	lines := strings.Split(out.String(), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[0], " test-1"))
+	assert.Equal(t, "running", lines[1])
+
+	out.Reset()
+	fmt.Fprint(reporter, "running")
+	assert.NoError(t, reporter.Flush())
+	assert.Empty(t, out.String())
+
+	fmt.Fprint(reporter, "passed")
+	assert.NoError(t, reporter.Flush())
+	assert.True(t, strings.HasSuffix(out.String(), " test-1\npassed\n"))
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package console
+
+import "os"
+
+// enableVirtualTerminal returns whether the given terminal supports ANSI escape sequences, which all terminals
+// outside of Windows do
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
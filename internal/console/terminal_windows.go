@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package console
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+// enableVirtualTerminal enables the processing of ANSI escape sequences by the given Windows console, returning
+// whether the console supports them
+// Consoles predating Windows 10 do not support escape sequences, and print them as is.
+func enableVirtualTerminal(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package control
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Port is the port on which job pods serve the control endpoint
+	// The endpoint is only served on the loopback interface, where it's reached through port forwards, so other
+	// clients in the cluster cannot hand executables to or release job pods.
+	Port = 5001
+	// ReadyPort is the port on which job pods serve readiness probes on all interfaces
+	ReadyPort = 5003
+	// PollIntervalEnv is an environment variable setting the interval at which processes in the job pod poll
+	// the control endpoint
+	PollIntervalEnv = "HELMIT_POLL_INTERVAL"
+	// DefaultPollInterval is the default interval at which the control endpoint is polled
+	DefaultPollInterval = time.Second
+)
+
+const (
+	// ReadyPath is the path at which the control endpoint reports whether the job pod has received its executable
+	ReadyPath      = "/ready"
+	executablePath = "/executable"
+	exitPath       = "/exit"
+	shutdownPath   = "/shutdown"
+)
+
+// GetPollInterval returns the poll interval set by the PollIntervalEnv environment variable
+func GetPollInterval() time.Duration {
+	if value := os.Getenv(PollIntervalEnv); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return DefaultPollInterval
+}
+
+// NewServer returns a new control server
+func NewServer() *Server {
+	return &Server{
+		readyCh:    make(chan struct{}),
+		shutdownCh: make(chan struct{}),
+	}
+}
+
+// Listen returns a server serving the control endpoint on the loopback interface and readiness probes on all
+// interfaces of the job pod
+func Listen() (*Server, error) {
+	lis, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", Port))
+	if err != nil {
+		return nil, err
+	}
+	readyLis, err := net.Listen("tcp", fmt.Sprintf(":%d", ReadyPort))
+	if err != nil {
+		lis.Close()
+		return nil, err
+	}
+	server := NewServer()
+	server.Serve(lis)
+	server.ServeReady(readyLis)
+	return server, nil
+}
+
+// Server serves the control endpoint through which the job manager hands the executable to the job pod,
+// reads the executable's exit code, and releases held jobs
+type Server struct {
+	server      *http.Server
+	readyServer *http.Server
+	executable  string
+	exitCode    *int
+	readyCh     chan struct{}
+	shutdownCh  chan struct{}
+	mu          sync.RWMutex
+}
+
+// Serve serves the control endpoint on the given listener in the background
+func (s *Server) Serve(lis net.Listener) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ReadyPath, s.handleReady)
+	mux.HandleFunc(executablePath, s.handleExecutable)
+	mux.HandleFunc(exitPath, s.handleExit)
+	mux.HandleFunc(shutdownPath, s.handleShutdown)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = s.server.Serve(lis)
+	}()
+}
+
+// ServeReady serves readiness probes on the given listener in the background
+func (s *Server) ServeReady(lis net.Listener) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ReadyPath, s.handleReady)
+	s.readyServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = s.readyServer.Serve(lis)
+	}()
+}
+
+// Stop stops serving the control endpoint
+func (s *Server) Stop() {
+	if s.server != nil {
+		_ = s.server.Close()
+	}
+	if s.readyServer != nil {
+		_ = s.readyServer.Close()
+	}
+}
+
+// AwaitExecutable waits for the job manager to hand the path of the executable to the server
+func (s *Server) AwaitExecutable(ctx context.Context) (string, error) {
+	select {
+	case <-s.readyCh:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return s.executable, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Exit records the exit code of the executable
+func (s *Server) Exit(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exitCode = &code
+}
+
+// AwaitShutdown waits for the job manager to release the job
+func (s *Server) AwaitShutdown(ctx context.Context) error {
+	select {
+	case <-s.shutdownCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handleReady reports whether the executable has been handed to the server, for use by readiness probes
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-s.readyCh:
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func (s *Server) handleExecutable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	executable := strings.TrimSpace(string(data))
+	if executable == "" {
+		http.Error(w, "no executable specified", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.readyCh:
+		// The executable may be sent again if the response to a previous request was lost
+		if executable != s.executable {
+			http.Error(w, fmt.Sprintf("executable %s already started", s.executable), http.StatusConflict)
+			return
+		}
+	default:
+		s.executable = executable
+		close(s.readyCh)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleExit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.exitCode == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = io.WriteString(w, strconv.Itoa(*s.exitCode))
+}
+
+func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		select {
+		case <-s.shutdownCh:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	case http.MethodPost:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-s.shutdownCh:
+		default:
+			close(s.shutdownCh)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// ErrNotReady is returned by requests for state the control endpoint does not have yet
+var ErrNotReady = errors.New("not ready")
+
+// NewClient returns a new client for the control endpoint at the given address
+func NewClient(address string) *Client {
+	return &Client{
+		url:    fmt.Sprintf("http://%s", address),
+		client: &http.Client{},
+	}
+}
+
+// Client is a client for the control endpoint of a job pod
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// SetExecutable hands the path of the executable to run to the job pod
+func (c *Client) SetExecutable(ctx context.Context, executable string) error {
+	_, err := c.do(ctx, http.MethodPut, executablePath, []byte(executable))
+	return err
+}
+
+// GetExit returns the exit code of the executable in the job pod, or ErrNotReady if it has not exited
+func (c *Client) GetExit(ctx context.Context) (int, error) {
+	data, err := c.do(ctx, http.MethodGet, exitPath, nil)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// Shutdown releases the job pod
+func (c *Client) Shutdown(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, shutdownPath, nil)
+	return err
+}
+
+// IsShutdown returns whether the job pod has been released
+func (c *Client) IsShutdown(ctx context.Context) (bool, error) {
+	if _, err := c.do(ctx, http.MethodGet, shutdownPath, nil); err != nil {
+		if errors.Is(err, ErrNotReady) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return data, nil
+	case http.StatusServiceUnavailable:
+		return nil, ErrNotReady
+	default:
+		return nil, fmt.Errorf("%s %s failed: %s: %s", method, path, response.Status, strings.TrimSpace(string(data)))
+	}
+}
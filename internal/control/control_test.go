@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package control
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestControl(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	server := NewServer()
+	server.Serve(lis)
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	address := lis.Addr().String()
+	client := NewClient(address)
+
+	response, err := http.Get("http://" + address + ReadyPath)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	_, err = client.GetExit(ctx)
+	assert.ErrorIs(t, err, ErrNotReady)
+
+	assert.NoError(t, client.SetExecutable(ctx, "/home/helmit/tests"))
+	executable, err := server.AwaitExecutable(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/helmit/tests", executable)
+
+	response, err = http.Get("http://" + address + ReadyPath)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// Resending the same executable succeeds, but the executable cannot be changed once started
+	assert.NoError(t, client.SetExecutable(ctx, "/home/helmit/tests"))
+	assert.Error(t, client.SetExecutable(ctx, "/home/helmit/benchmarks"))
+
+	server.Exit(3)
+	code, err := client.GetExit(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, code)
+
+	shutdown, err := client.IsShutdown(ctx)
+	assert.NoError(t, err)
+	assert.False(t, shutdown)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, server.AwaitShutdown(shutdownCtx), context.DeadlineExceeded)
+	shutdownCancel()
+
+	assert.NoError(t, client.Shutdown(ctx))
+	assert.NoError(t, client.Shutdown(ctx))
+	assert.NoError(t, server.AwaitShutdown(ctx))
+	shutdown, err = client.IsShutdown(ctx)
+	assert.NoError(t, err)
+	assert.True(t, shutdown)
+}
+
+func TestServeReady(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	readyLis, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err)
+	server := NewServer()
+	server.Serve(lis)
+	server.ServeReady(readyLis)
+	defer server.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	address := readyLis.Addr().String()
+
+	response, err := http.Get("http://" + address + ReadyPath)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+
+	// Only readiness is served on the readiness listener
+	assert.Error(t, NewClient(address).SetExecutable(ctx, "/home/helmit/tests"))
+	assert.NoError(t, NewClient(lis.Addr().String()).SetExecutable(ctx, "/home/helmit/tests"))
+
+	response, err = http.Get("http://" + address + ReadyPath)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestGetPollInterval(t *testing.T) {
+	t.Setenv(PollIntervalEnv, "")
+	assert.Equal(t, DefaultPollInterval, GetPollInterval())
+	t.Setenv(PollIntervalEnv, "250ms")
+	assert.Equal(t, 250*time.Millisecond, GetPollInterval())
+	t.Setenv(PollIntervalEnv, "invalid")
+	assert.Equal(t, DefaultPollInterval, GetPollInterval())
+}
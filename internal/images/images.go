@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package images selects the runner and builder images run by helmit jobs.
+package images
+
+import (
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultRunner is the image used to run jobs built from Go packages
+	DefaultRunner = "onosproject/helmit-runner"
+	// DefaultBuilder is the image used to build and run jobs inside the cluster
+	DefaultBuilder = "onosproject/helmit-builder"
+	// latestVersion is the version of the images run by development builds of helmit
+	latestVersion = "latest"
+	modulePath    = "github.com/onosproject/helmit"
+)
+
+// Archs are the CPU architectures for which the runner and builder images are published
+var Archs = []string{"amd64", "arm64"}
+
+// releaseVersionRegex matches helmit release versions, for which images are published
+var releaseVersionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+var (
+	imagesMu     sync.RWMutex
+	runnerImage  string
+	builderImage string
+)
+
+// SetRunner overrides the image used to run jobs built from Go packages
+// The image may be a repository, e.g. to use a mirror of the published images, to which the tag for the version
+// and architecture is appended, or a reference with a tag or digest, which is used as is for all architectures.
+func SetRunner(image string) {
+	imagesMu.Lock()
+	defer imagesMu.Unlock()
+	runnerImage = image
+}
+
+// SetBuilder overrides the image used to build and run jobs inside the cluster
+// The image may be a repository or a reference with a tag or digest, as with SetRunner.
+func SetBuilder(image string) {
+	imagesMu.Lock()
+	defer imagesMu.Unlock()
+	builderImage = image
+}
+
+// Builder returns the image used to build and run jobs inside the cluster for the given CPU architecture
+func Builder(arch string) string {
+	imagesMu.RLock()
+	defer imagesMu.RUnlock()
+	return getImage(builderImage, DefaultBuilder, Version(), arch)
+}
+
+// Runner returns the image used to run jobs built from Go packages for the given CPU architecture
+func Runner(arch string) string {
+	imagesMu.RLock()
+	defer imagesMu.RUnlock()
+	return getImage(runnerImage, DefaultRunner, Version(), arch)
+}
+
+// Version returns the version of the runner and builder images matching this version of helmit
+// Images are published for each release, so release builds of helmit, or programs depending on a release of
+// helmit, run the images of that release. Development builds run the latest images.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return latestVersion
+	}
+	version := info.Main.Version
+	if info.Main.Path != modulePath {
+		version = ""
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath && dep.Replace == nil {
+				version = dep.Version
+			}
+		}
+	}
+	return getImageVersion(version)
+}
+
+// getImageVersion returns the version of the images for the given version of helmit
+// Pseudo-versions and development builds run the latest images, since no images are published for them.
+func getImageVersion(version string) string {
+	if releaseVersionRegex.MatchString(version) {
+		return version
+	}
+	return latestVersion
+}
+
+// getImage returns the image for the given architecture, applying the given override
+func getImage(override string, repository string, version string, arch string) string {
+	if override != "" {
+		if HasTagOrDigest(override) {
+			return override
+		}
+		repository = override
+	}
+	return fmt.Sprintf("%s:%s-%s", repository, version, arch)
+}
+
+// HasTagOrDigest returns whether the given image reference includes a tag or digest
+// The registry host may include a port, so only a colon in the last path component denotes a tag.
+func HasTagOrDigest(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	return strings.Contains(image[strings.LastIndex(image, "/")+1:], ":")
+}
+
+// GetRepository returns the repository of the given image reference, without its tag or digest
+func GetRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package images
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImages(t *testing.T) {
+	assert.Equal(t, "v0.3.1", getImageVersion("v0.3.1"))
+	assert.Equal(t, "latest", getImageVersion("v0.3.2-0.20230405123456-0123456789ab"))
+	assert.Equal(t, "latest", getImageVersion("(devel)"))
+	assert.Equal(t, "latest", getImageVersion(""))
+
+	assert.Equal(t, "onosproject/helmit-runner:v0.3.1-arm64", getImage("", DefaultRunner, "v0.3.1", "arm64"))
+	assert.Equal(t, "localhost:5000/helmit-runner:v0.3.1-amd64", getImage("localhost:5000/helmit-runner", DefaultRunner, "v0.3.1", "amd64"))
+	assert.Equal(t, "localhost:5000/helmit-runner:dev", getImage("localhost:5000/helmit-runner:dev", DefaultRunner, "v0.3.1", "amd64"))
+	assert.Equal(t, "helmit-runner@sha256:abc", getImage("helmit-runner@sha256:abc", DefaultRunner, "v0.3.1", "amd64"))
+
+	assert.Equal(t, "localhost:5000/helmit-runner", GetRepository("localhost:5000/helmit-runner:v0.3.1-amd64"))
+	assert.Equal(t, "localhost:5000/helmit-runner", GetRepository("localhost:5000/helmit-runner"))
+	assert.Equal(t, "helmit-runner", GetRepository("helmit-runner:latest@sha256:abc"))
+
+	SetRunner("registry.example.com/helmit-runner")
+	defer SetRunner("")
+	assert.Equal(t, "registry.example.com/helmit-runner:latest-amd64", Runner("amd64"))
+	assert.Equal(t, "onosproject/helmit-builder:latest-amd64", Builder("amd64"))
+}
@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"time"
+)
+
+// Resource is a Kubernetes resource created by helmit for a job
+type Resource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Job       string
+	Created   time.Time
+}
+
+// String returns the kind and namespaced name of the resource
+func (r Resource) String() string {
+	if r.Namespace == "" {
+		return r.Kind + " " + r.Name
+	}
+	return r.Kind + " " + r.Namespace + "/" + r.Name
+}
+
+// NewCleaner returns a new Cleaner for resources created by helmit
+func NewCleaner() (*Cleaner, error) {
+	config, err := k8s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Cleaner{
+		client: client,
+	}, nil
+}
+
+// Cleaner finds and deletes resources left behind by helmit jobs
+type Cleaner struct {
+	client kubernetes.Interface
+}
+
+// Find lists the resources created by helmit jobs more than olderThan ago
+// Namespaced resources are returned first and namespaces last, in the order in which they should be deleted.
+func (c *Cleaner) Find(ctx context.Context, olderThan time.Duration) ([]Resource, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: ManagedByLabel + "=" + ManagedByValue + "," + JobLabel,
+	}
+	var resources []Resource
+	add := func(kind string, meta metav1.ObjectMeta) {
+		if time.Since(meta.CreationTimestamp.Time) < olderThan {
+			return
+		}
+		resources = append(resources, Resource{
+			Kind:      kind,
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			Job:       meta.Labels[JobLabel],
+			Created:   meta.CreationTimestamp.Time,
+		})
+	}
+
+	jobs, err := c.client.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs.Items {
+		add("Job", job.ObjectMeta)
+	}
+
+	statefulSets, err := c.client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range statefulSets.Items {
+		add("StatefulSet", statefulSet.ObjectMeta)
+	}
+
+	services, err := c.client.CoreV1().Services(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services.Items {
+		add("Service", service.ObjectMeta)
+	}
+
+	configMaps, err := c.client.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		add("ConfigMap", configMap.ObjectMeta)
+	}
+
+	secrets, err := c.client.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets.Items {
+		add("Secret", secret.ObjectMeta)
+	}
+
+	serviceAccounts, err := c.client.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, serviceAccount := range serviceAccounts.Items {
+		add("ServiceAccount", serviceAccount.ObjectMeta)
+	}
+
+	roleBindings, err := c.client.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, roleBinding := range roleBindings.Items {
+		add("RoleBinding", roleBinding.ObjectMeta)
+	}
+
+	roles, err := c.client.RbacV1().Roles(metav1.NamespaceAll).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles.Items {
+		add("Role", role.ObjectMeta)
+	}
+
+	clusterRoleBindings, err := c.client.RbacV1().ClusterRoleBindings().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterRoleBinding := range clusterRoleBindings.Items {
+		add("ClusterRoleBinding", clusterRoleBinding.ObjectMeta)
+	}
+
+	clusterRoles, err := c.client.RbacV1().ClusterRoles().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterRole := range clusterRoles.Items {
+		add("ClusterRole", clusterRole.ObjectMeta)
+	}
+
+	namespaces, err := c.client.CoreV1().Namespaces().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range namespaces.Items {
+		add("Namespace", namespace.ObjectMeta)
+	}
+	return resources, nil
+}
+
+// Delete deletes the given resource
+func (c *Cleaner) Delete(ctx context.Context, resource Resource, log logging.Logger) error {
+	log.Logf("Deleting %s", resource)
+	var err error
+	switch resource.Kind {
+	case "Job":
+		err = c.client.BatchV1().Jobs(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "StatefulSet":
+		err = c.client.AppsV1().StatefulSets(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "Service":
+		err = c.client.CoreV1().Services(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "ConfigMap":
+		err = c.client.CoreV1().ConfigMaps(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "Secret":
+		err = c.client.CoreV1().Secrets(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "ServiceAccount":
+		err = c.client.CoreV1().ServiceAccounts(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "RoleBinding":
+		err = c.client.RbacV1().RoleBindings(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "Role":
+		err = c.client.RbacV1().Roles(resource.Namespace).Delete(ctx, resource.Name, getDeleteOptions())
+	case "ClusterRoleBinding":
+		err = c.client.RbacV1().ClusterRoleBindings().Delete(ctx, resource.Name, getDeleteOptions())
+	case "ClusterRole":
+		err = c.client.RbacV1().ClusterRoles().Delete(ctx, resource.Name, getDeleteOptions())
+	case "Namespace":
+		err = c.client.CoreV1().Namespaces().Delete(ctx, resource.Name, getDeleteOptions())
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// FindStaleSubjects returns the subjects of the shared cluster-admin ClusterRoleBinding whose ServiceAccounts
+// no longer exist or are among the given resources to be deleted
+func (c *Cleaner) FindStaleSubjects(ctx context.Context, resources []Resource) ([]rbacv1.Subject, error) {
+	roleBinding, err := c.client.RbacV1().ClusterRoleBindings().Get(ctx, defaultRoleBindingName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	deleted := make(map[string]bool)
+	for _, resource := range resources {
+		switch resource.Kind {
+		case "ServiceAccount":
+			deleted[resource.Namespace+"/"+resource.Name] = true
+		case "Namespace":
+			deleted[resource.Name+"/"] = true
+		}
+	}
+
+	var subjects []rbacv1.Subject
+	for _, subject := range roleBinding.Subjects {
+		if subject.Kind != "ServiceAccount" {
+			continue
+		}
+		if deleted[subject.Namespace+"/"+subject.Name] || deleted[subject.Namespace+"/"] {
+			subjects = append(subjects, subject)
+			continue
+		}
+		_, err := c.client.CoreV1().ServiceAccounts(subject.Namespace).Get(ctx, subject.Name, metav1.GetOptions{})
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, err
+			}
+			subjects = append(subjects, subject)
+		}
+	}
+	return subjects, nil
+}
+
+// RemoveSubjects removes the given subjects from the shared cluster-admin ClusterRoleBinding, deleting the
+// ClusterRoleBinding if no subjects remain
+func (c *Cleaner) RemoveSubjects(ctx context.Context, subjects []rbacv1.Subject, log logging.Logger) error {
+	if len(subjects) == 0 {
+		return nil
+	}
+
+	removed := make(map[rbacv1.Subject]bool)
+	for _, subject := range subjects {
+		removed[subject] = true
+	}
+
+	return retry.RetryOnConflict(conflictBackoff, func() error {
+		roleBinding, err := c.client.RbacV1().ClusterRoleBindings().Get(ctx, defaultRoleBindingName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		var remaining []rbacv1.Subject
+		for _, subject := range roleBinding.Subjects {
+			if !removed[subject] {
+				remaining = append(remaining, subject)
+			}
+		}
+
+		if len(remaining) == 0 {
+			log.Logf("Deleting ClusterRoleBinding %s", roleBinding.Name)
+			err = c.client.RbacV1().ClusterRoleBindings().Delete(ctx, roleBinding.Name, getDeleteOptions())
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+			return nil
+		}
+
+		roleBinding.Subjects = remaining
+		log.Logf("Updating ClusterRoleBinding %s", roleBinding.Name)
+		_, err = c.client.RbacV1().ClusterRoleBindings().Update(ctx, roleBinding, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCleanup(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-3 * time.Hour))
+	recent := metav1.NewTime(time.Now())
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "old-job",
+				Labels:            NewLabels("old-job"),
+				CreationTimestamp: old,
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "old-job",
+				Namespace:         "old-job",
+				Labels:            NewLabels("old-job"),
+				CreationTimestamp: old,
+			},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "old-job",
+				Namespace:         "old-job",
+				Labels:            NewLabels("old-job"),
+				CreationTimestamp: old,
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "new-job",
+				Namespace:         "default",
+				Labels:            NewLabels("new-job"),
+				CreationTimestamp: recent,
+			},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "new-job",
+				Namespace:         "default",
+				Labels:            NewLabels("new-job"),
+				CreationTimestamp: recent,
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-job",
+				Namespace: "default",
+				Labels: map[string]string{
+					JobLabel: "other-job",
+				},
+				CreationTimestamp: old,
+			},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: defaultRoleBindingName,
+			},
+			Subjects: []rbacv1.Subject{
+				{Kind: "ServiceAccount", Namespace: "old-job", Name: "old-job"},
+				{Kind: "ServiceAccount", Namespace: "default", Name: "new-job"},
+				{Kind: "ServiceAccount", Namespace: "default", Name: "missing-job"},
+			},
+		},
+	)
+	cleaner := &Cleaner{client: client}
+	ctx := context.Background()
+
+	resources, err := cleaner.Find(ctx, 2*time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+	assert.Equal(t, "Job old-job/old-job", resources[0].String())
+	assert.Equal(t, "ServiceAccount old-job/old-job", resources[1].String())
+	assert.Equal(t, "Namespace old-job", resources[2].String())
+	assert.Equal(t, "old-job", resources[2].Job)
+
+	all, err := cleaner.Find(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	subjects, err := cleaner.FindStaleSubjects(ctx, resources)
+	assert.NoError(t, err)
+	assert.Len(t, subjects, 2)
+	assert.Equal(t, "old-job", subjects[0].Name)
+	assert.Equal(t, "missing-job", subjects[1].Name)
+
+	log := logging.NewLogger(os.Stdout)
+	for _, resource := range resources {
+		assert.NoError(t, cleaner.Delete(ctx, resource, log))
+	}
+	assert.NoError(t, cleaner.RemoveSubjects(ctx, subjects, log))
+
+	resources, err = cleaner.Find(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	roleBinding, err := client.RbacV1().ClusterRoleBindings().Get(ctx, defaultRoleBindingName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, roleBinding.Subjects, 1)
+	assert.Equal(t, "new-job", roleBinding.Subjects[0].Name)
+}
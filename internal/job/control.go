@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/control"
+	"time"
+)
+
+// DefaultReadyTimeout is the default time to wait for a job's control endpoint to become reachable
+const DefaultReadyTimeout = time.Minute
+
+// ServeControl serves the control endpoint in a job running a custom Command, which must await its executable
+// from the endpoint rather than from the helmit-runner
+func ServeControl() (*control.Server, error) {
+	return control.Listen()
+}
+
+// AwaitExit waits for the executable in a held job to exit and returns its exit code
+func (j *Job[T]) AwaitExit(ctx context.Context) (int, error) {
+	if err := j.init(); err != nil {
+		return 0, err
+	}
+	var code int
+	err := j.pollControl(ctx, func(ctx context.Context, client *control.Client) error {
+		var err error
+		code, err = client.GetExit(ctx)
+		return err
+	})
+	return code, err
+}
+
+// Release signals a held job to shut down
+func (j *Job[T]) Release(ctx context.Context) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+	return j.pollControl(ctx, func(ctx context.Context, client *control.Client) error {
+		return client.Shutdown(ctx)
+	})
+}
+
+// setExecutable hands the path of the executable to run to the job pod
+// Runner images predating the control endpoint await the executable in a file instead, so they never become
+// reachable; the error returned in that case says so, since such images are typically custom images set with --image.
+func (j *Job[T]) setExecutable(ctx context.Context, executable string) error {
+	err := j.pollControl(ctx, func(ctx context.Context, client *control.Client) error {
+		return client.SetExecutable(ctx, executable)
+	})
+	var unreachable *unreachableError
+	if errors.As(err, &unreachable) {
+		return fmt.Errorf("%w; if the job runs a custom image, it must be based on the helmit-runner image of this "+
+			"version of helmit, as older runner images do not serve the control endpoint", err)
+	}
+	return err
+}
+
+// unreachableError is returned when a job's control endpoint cannot be reached within the ready timeout
+type unreachableError struct {
+	id  string
+	err error
+}
+
+func (e *unreachableError) Error() string {
+	return fmt.Sprintf("control endpoint of %s not reachable: %s", e.id, e.err)
+}
+
+func (e *unreachableError) Unwrap() error {
+	return e.err
+}
+
+// pollControl calls the given function with a client for the job's control endpoint at the poll interval until it
+// succeeds. The function may return control.ErrNotReady to be called again. Other errors are retried through a new
+// port forward, and returned once the endpoint has been unreachable for the ready timeout.
+func (j *Job[T]) pollControl(ctx context.Context, f func(context.Context, *control.Client) error) error {
+	reached := time.Now()
+	for {
+		err := j.callControl(ctx, func(ctx context.Context, client *control.Client) error {
+			for {
+				err := f(ctx, client)
+				if !errors.Is(err, control.ErrNotReady) {
+					return err
+				}
+				reached = time.Now()
+				select {
+				case <-time.After(j.getPollInterval()):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		})
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Since(reached) > j.getReadyTimeout() {
+			return &unreachableError{id: j.ID, err: err}
+		}
+		select {
+		case <-time.After(j.getPollInterval()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// callControl calls the given function with a client for the job's control endpoint through a port forwarded
+// until the function returns
+func (j *Job[T]) callControl(ctx context.Context, f func(context.Context, *control.Client) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	port, err := j.Forward(ctx, control.Port)
+	if err != nil {
+		return err
+	}
+	return f(ctx, control.NewClient(fmt.Sprintf("localhost:%d", port)))
+}
+
+func (j *Job[T]) getPollInterval() time.Duration {
+	if j.PollInterval > 0 {
+		return j.PollInterval
+	}
+	return control.DefaultPollInterval
+}
+
+func (j *Job[T]) getReadyTimeout() time.Duration {
+	if j.ReadyTimeout > 0 {
+		return j.ReadyTimeout
+	}
+	return DefaultReadyTimeout
+}
@@ -7,12 +7,21 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/onosproject/helmit/internal/control"
+	"github.com/onosproject/helmit/internal/k8s"
 	"github.com/onosproject/helmit/internal/logging"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"math"
+	"time"
 )
 
 // Create creates the job resources
@@ -20,18 +29,39 @@ func (j *Job[T]) Create(ctx context.Context, log logging.Logger) error {
 	if err := j.init(); err != nil {
 		return err
 	}
+	if err := j.validateSidecars(); err != nil {
+		return err
+	}
+	if err := j.validateNoCopy(); err != nil {
+		return err
+	}
+	if err := j.loadSecretsFrom(ctx); err != nil {
+		return err
+	}
 
+	start := time.Now()
 	if j.CreateNamespace {
 		if err := j.createNamespace(ctx, log); err != nil {
 			return err
 		}
 	}
-	if err := j.createClusterRoleBinding(ctx, log); err != nil {
-		return err
+	if len(j.Rules) == 0 {
+		if err := j.createClusterRoleBinding(ctx, log); err != nil {
+			return err
+		}
+	} else if !j.NamespacedRBAC {
+		if err := j.createClusterRole(ctx, log); err != nil {
+			return err
+		}
 	}
 	if err := j.createJob(ctx, log); err != nil {
 		return err
 	}
+	if len(j.Rules) > 0 && j.NamespacedRBAC {
+		if err := j.createRole(ctx, log); err != nil {
+			return err
+		}
+	}
 	if err := j.createConfigMap(ctx, log); err != nil {
 		return err
 	}
@@ -41,18 +71,25 @@ func (j *Job[T]) Create(ctx context.Context, log logging.Logger) error {
 	if err := j.createSecrets(ctx, log); err != nil {
 		return err
 	}
+	logging.RecordTiming(log, "Creating resources", start)
 	if err := j.waitForRunning(ctx, log); err != nil {
 		return err
 	}
 	if err := j.copyExecutable(ctx, log); err != nil {
 		return err
 	}
+	if err := j.copySource(ctx, log); err != nil {
+		return err
+	}
 	if err := j.copyContext(ctx, log); err != nil {
 		return err
 	}
 	if err := j.copyValueFiles(ctx, log); err != nil {
 		return err
 	}
+	if err := j.buildSource(ctx, log); err != nil {
+		return err
+	}
 	if err := j.runExecutable(ctx, log); err != nil {
 		return err
 	}
@@ -60,14 +97,7 @@ func (j *Job[T]) Create(ctx context.Context, log logging.Logger) error {
 }
 
 func (j *Job[T]) createNamespace(ctx context.Context, log logging.Logger) error {
-	namespace := &corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: j.Namespace,
-			Annotations: map[string]string{
-				"job": j.ID,
-			},
-		},
-	}
+	namespace := j.newNamespace()
 	log.Logf("Creating Namespace %s", namespace.Name)
 	if _, err := j.client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
 		return err
@@ -75,8 +105,47 @@ func (j *Job[T]) createNamespace(ctx context.Context, log logging.Logger) error
 	return nil
 }
 
+func (j *Job[T]) newNamespace() *corev1.Namespace {
+	return NewNamespace(j.ID, j.Namespace, j.NamespaceLabels, j.NamespaceAnnotations)
+}
+
 // createJob creates the job to run tests
 func (j *Job[T]) createJob(ctx context.Context, log logging.Logger) error {
+	if j.Durable {
+		return j.createStatefulSet(ctx, log)
+	}
+	job := j.newJob()
+	log.Logf("Creating Job %s", job.Name)
+	_, err := j.client.BatchV1().Jobs(j.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// newJob returns the Job to run the executable
+func (j *Job[T]) newJob() *batchv1.Job {
+	template := j.newPodTemplate(corev1.RestartPolicyNever)
+	zero := int32(0)
+	one := int32(1)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        j.ID,
+			Namespace:   j.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: batchv1.JobSpec{
+			Parallelism:  &one,
+			Completions:  &one,
+			BackoffLimit: &zero,
+			Template:     template,
+		},
+	}
+}
+
+// newPodTemplate returns the template of the pod running the executable with the given restart policy
+func (j *Job[T]) newPodTemplate(restartPolicy corev1.RestartPolicy) corev1.PodTemplateSpec {
 	env := make([]corev1.EnvVar, 0, len(j.Env))
 	for key, value := range j.Env {
 		env = append(env, corev1.EnvVar{
@@ -84,6 +153,41 @@ func (j *Job[T]) createJob(ctx context.Context, log logging.Logger) error {
 			Value: value,
 		})
 	}
+	for key, value := range j.ResourceEnv {
+		env = append(env, corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				ResourceFieldRef: &corev1.ResourceFieldSelector{
+					ContainerName: "job",
+					Resource:      value,
+					Divisor:       resource.MustParse("1"),
+				},
+			},
+		})
+	}
+	if j.Hold {
+		env = append(env, corev1.EnvVar{
+			Name:  holdEnv,
+			Value: "true",
+		})
+	}
+	if j.PollInterval > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  control.PollIntervalEnv,
+			Value: j.PollInterval.String(),
+		})
+	}
+	// Suites running in the pod are subject to the same API rate limits as the CLI
+	for name, value := range k8s.GetRateLimitEnv() {
+		env = append(env, corev1.EnvVar{
+			Name:  name,
+			Value: value,
+		})
+	}
+	env = append(env, corev1.EnvVar{
+		Name:  IDEnv,
+		Value: j.ID,
+	})
 	env = append(env, corev1.EnvVar{
 		Name:  "SERVICE_NAMESPACE",
 		Value: j.Namespace,
@@ -148,202 +252,305 @@ func (j *Job[T]) createJob(ctx context.Context, log logging.Logger) error {
 	}
 
 	var containerPorts []corev1.ContainerPort
-	readinessProbe := &corev1.Probe{
-		ProbeHandler: corev1.ProbeHandler{
-			Exec: &corev1.ExecAction{
-				Command: []string{
-					"stat",
-					readyFile,
+
+	// Images run without copying files to the pod do not run the helmit-runner, which serves the control endpoint
+	var readinessProbe *corev1.Probe
+	if !j.NoCopy {
+		readinessProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: control.ReadyPath,
+					Port: intstr.FromInt(control.ReadyPort),
 				},
 			},
-		},
-		PeriodSeconds:    1,
-		FailureThreshold: 30,
+			PeriodSeconds: int32(math.Max(math.Ceil(j.getPollInterval().Seconds()), 1)),
+		}
 	}
 
-	serviceAccount := j.ServiceAccount
-	if serviceAccount == "" {
-		serviceAccount = j.ID
+	labels := make(map[string]string)
+	for key, value := range j.Labels {
+		labels[key] = value
 	}
-
-	labels := j.Labels
-	if labels == nil {
-		labels = make(map[string]string)
+	for key, value := range NewLabels(j.ID) {
+		labels[key] = value
 	}
-	labels["job"] = j.ID
 
 	annotations := j.Annotations
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
 
-	zero := int32(0)
-	one := int32(1)
-	job := &batchv1.Job{
+	containers := []corev1.Container{
+		{
+			Name:            "job",
+			Image:           j.Image,
+			ImagePullPolicy: j.ImagePullPolicy,
+			Command:         j.Command,
+			Args:            j.Args,
+			Env:             env,
+			Ports:           containerPorts,
+			VolumeMounts:    volumeMounts,
+			ReadinessProbe:  readinessProbe,
+		},
+	}
+	containers = append(containers, j.Sidecars...)
+	volumes = append(volumes, j.SidecarVolumes...)
+
+	return corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        j.ID,
-			Namespace:   j.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		Spec: batchv1.JobSpec{
-			Parallelism:  &one,
-			Completions:  &one,
-			BackoffLimit: &zero,
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
-					Annotations: annotations,
-				},
-				Spec: corev1.PodSpec{
-					ServiceAccountName: serviceAccount,
-					RestartPolicy:      corev1.RestartPolicyNever,
-					Containers: []corev1.Container{
-						{
-							Name:            "job",
-							Image:           j.Image,
-							ImagePullPolicy: j.ImagePullPolicy,
-							Args:            j.Args,
-							Env:             env,
-							Ports:           containerPorts,
-							VolumeMounts:    volumeMounts,
-							ReadinessProbe:  readinessProbe,
-						},
-					},
-					Volumes: volumes,
-				},
-			},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: j.getServiceAccountName(),
+			RestartPolicy:      restartPolicy,
+			NodeSelector:       j.NodeSelector,
+			Tolerations:        j.Tolerations,
+			Affinity:           j.Affinity,
+			PriorityClassName:  j.PriorityClassName,
+			Containers:         containers,
+			Volumes:            volumes,
 		},
 	}
+}
 
-	log.Logf("Creating Job %s", job.Name)
-	_, err := j.client.BatchV1().Jobs(j.Namespace).Create(ctx, job, metav1.CreateOptions{})
-	if err != nil {
-		return err
+// validateSidecars checks that the sidecar containers and volumes don't conflict with those of the job
+func (j *Job[T]) validateSidecars() error {
+	containers := map[string]bool{"job": true}
+	for _, sidecar := range j.Sidecars {
+		if sidecar.Name == "" || sidecar.Image == "" {
+			return errors.New("sidecar containers must specify a name and an image")
+		}
+		if containers[sidecar.Name] {
+			return fmt.Errorf("duplicate container name %s", sidecar.Name)
+		}
+		containers[sidecar.Name] = true
+	}
+	volumes := map[string]bool{"config": true, "secrets": true}
+	for _, volume := range j.SidecarVolumes {
+		if volumes[volume.Name] {
+			return fmt.Errorf("duplicate volume name %s", volume.Name)
+		}
+		volumes[volume.Name] = true
+	}
+	return nil
+}
+
+// validateNoCopy checks that nothing needs to be copied to the pod of a job run without copying files
+func (j *Job[T]) validateNoCopy() error {
+	if !j.NoCopy {
+		return nil
+	}
+	if j.Executable != "" || j.Source != nil || j.Context != "" || len(j.ValueFiles) > 0 {
+		return errors.New("an executable, source, context, or values files cannot be copied to the pod of a job run with no copy")
+	}
+	if j.Hold {
+		return errors.New("a job run with no copy cannot be held, since its pod has no control endpoint")
 	}
 	return nil
 }
 
 // createServiceAccount creates a ServiceAccount used by the test manager
 func (j *Job[T]) createServiceAccount(ctx context.Context, log logging.Logger) error {
-	jobObj, err := j.client.BatchV1().Jobs(j.Namespace).Get(ctx, j.ID, metav1.GetOptions{})
+	owners, err := j.getOwnerReferences(ctx)
 	if err != nil {
 		return err
 	}
+	serviceAccount := j.newServiceAccount(owners)
+	log.Logf("Creating ServiceAccount %s", serviceAccount.Name)
+	_, err = j.client.CoreV1().ServiceAccounts(j.Namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
 
-	serviceAccountName := j.ServiceAccount
-	if serviceAccountName == "" {
-		serviceAccountName = j.ID
+func (j *Job[T]) newServiceAccount(owners []metav1.OwnerReference) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            j.getServiceAccountName(),
+			Namespace:       j.Namespace,
+			Labels:          NewLabels(j.ID),
+			OwnerReferences: owners,
+		},
 	}
+}
+
+// createClusterRoleBinding creates the ClusterRoleBinding required by the test manager
+// The shared ClusterRoleBinding is updated by every job, so conflicting updates are retried with backoff.
+func (j *Job[T]) createClusterRoleBinding(ctx context.Context, log logging.Logger) error {
+	return retry.RetryOnConflict(conflictBackoff, func() error {
+		roleBinding, err := j.client.RbacV1().ClusterRoleBindings().Get(ctx, defaultRoleBindingName, metav1.GetOptions{})
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return err
+			}
+			roleBinding = j.newDefaultClusterRoleBinding()
+			log.Logf("Creating ClusterRoleBinding %s", roleBinding.Name)
+			_, err = j.client.RbacV1().ClusterRoleBindings().Create(ctx, roleBinding, metav1.CreateOptions{})
+			if err != nil && !k8serrors.IsAlreadyExists(err) {
+				return err
+			}
+			return nil
+		}
 
-	serviceAccount := &corev1.ServiceAccount{
+		roleBinding.Subjects = append(roleBinding.Subjects, j.newSubject())
+		log.Logf("Updating ClusterRoleBinding %s", roleBinding.Name)
+		_, err = j.client.RbacV1().ClusterRoleBindings().Update(ctx, roleBinding, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (j *Job[T]) newDefaultClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      serviceAccountName,
-			Namespace: j.Namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					Name:       jobObj.Name,
-					UID:        jobObj.UID,
-					Kind:       "Job",
-					APIVersion: "batch/v1",
-				},
+			Name: defaultRoleBindingName,
+			Labels: map[string]string{
+				ManagedByLabel: ManagedByValue,
 			},
 		},
+		Subjects: []rbacv1.Subject{
+			j.newSubject(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     defaultRoleName,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
 	}
-	log.Logf("Creating ServiceAccount %s", serviceAccount.Name)
-	_, err = j.client.CoreV1().ServiceAccounts(j.Namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
-	if err != nil && !k8serrors.IsAlreadyExists(err) {
+}
+
+// createClusterRole creates a ClusterRole and ClusterRoleBinding granting the job's ServiceAccount the configured rules
+func (j *Job[T]) createClusterRole(ctx context.Context, log logging.Logger) error {
+	role := j.newClusterRole()
+	log.Logf("Creating ClusterRole %s", role.Name)
+	if _, err := j.client.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	roleBinding := j.newClusterRoleBinding()
+	log.Logf("Creating ClusterRoleBinding %s", roleBinding.Name)
+	if _, err := j.client.RbacV1().ClusterRoleBindings().Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
 		return err
 	}
 	return nil
 }
 
-// createClusterRoleBinding creates the ClusterRoleBinding required by the test manager
-func (j *Job[T]) createClusterRoleBinding(ctx context.Context, log logging.Logger) error {
-	serviceAccountName := j.ServiceAccount
-	if serviceAccountName == "" {
-		serviceAccountName = j.ID
+func (j *Job[T]) newClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   j.ID,
+			Labels: NewLabels(j.ID),
+		},
+		Rules: j.Rules,
 	}
-	roleBinding, err := j.client.RbacV1().ClusterRoleBindings().Get(ctx, defaultRoleBindingName, metav1.GetOptions{})
+}
+
+func (j *Job[T]) newClusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   j.ID,
+			Labels: NewLabels(j.ID),
+		},
+		Subjects: []rbacv1.Subject{
+			j.newSubject(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     j.ID,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+}
+
+// createRole creates a namespaced Role and RoleBinding granting the job's ServiceAccount the configured rules
+func (j *Job[T]) createRole(ctx context.Context, log logging.Logger) error {
+	owners, err := j.getOwnerReferences(ctx)
 	if err != nil {
-		if !k8serrors.IsNotFound(err) {
-			return err
-		}
-		roleBinding = &rbacv1.ClusterRoleBinding{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: defaultRoleBindingName,
-			},
-			Subjects: []rbacv1.Subject{
-				{
-					Kind:      "ServiceAccount",
-					Name:      serviceAccountName,
-					Namespace: j.Namespace,
-				},
-			},
-			RoleRef: rbacv1.RoleRef{
-				Kind:     "ClusterRole",
-				Name:     defaultRoleName,
-				APIGroup: "rbac.authorization.k8s.io",
-			},
-		}
-		log.Logf("Creating ClusterRoleBinding %s", roleBinding.Name)
-		_, err = j.client.RbacV1().ClusterRoleBindings().Create(ctx, roleBinding, metav1.CreateOptions{})
-		if err != nil && !k8serrors.IsAlreadyExists(err) {
-			return err
-		}
-		return nil
+		return err
 	}
 
-	roleBinding.Subjects = append(roleBinding.Subjects, rbacv1.Subject{
-		Kind:      "ServiceAccount",
-		Name:      serviceAccountName,
-		Namespace: j.Namespace,
-	})
-	log.Logf("Updating ClusterRoleBinding %s", roleBinding.Name)
-	_, err = j.client.RbacV1().ClusterRoleBindings().Update(ctx, roleBinding, metav1.UpdateOptions{})
-	if err != nil && k8serrors.IsConflict(err) {
-		return j.createClusterRoleBinding(ctx, log)
+	role := j.newRole(owners)
+	log.Logf("Creating Role %s", role.Name)
+	if _, err := j.client.RbacV1().Roles(j.Namespace).Create(ctx, role, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	roleBinding := j.newRoleBinding(owners)
+	log.Logf("Creating RoleBinding %s", roleBinding.Name)
+	if _, err := j.client.RbacV1().RoleBindings(j.Namespace).Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+func (j *Job[T]) newRole(owners []metav1.OwnerReference) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            j.ID,
+			Namespace:       j.Namespace,
+			Labels:          NewLabels(j.ID),
+			OwnerReferences: owners,
+		},
+		Rules: j.Rules,
+	}
+}
+
+func (j *Job[T]) newRoleBinding(owners []metav1.OwnerReference) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            j.ID,
+			Namespace:       j.Namespace,
+			Labels:          NewLabels(j.ID),
+			OwnerReferences: owners,
+		},
+		Subjects: []rbacv1.Subject{
+			j.newSubject(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "Role",
+			Name:     j.ID,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
 	}
-	return err
 }
 
 func (j *Job[T]) createConfigMap(ctx context.Context, log logging.Logger) error {
-	configJSON, err := json.Marshal(j.Config)
+	owners, err := j.getOwnerReferences(ctx)
 	if err != nil {
 		return err
 	}
 
-	jobObj, err := j.client.BatchV1().Jobs(j.Namespace).Get(ctx, j.ID, metav1.GetOptions{})
+	cm, err := j.newConfigMap(owners)
 	if err != nil {
 		return err
 	}
+	log.Logf("Creating ConfigMap %s", cm.Name)
+	if _, err := j.client.CoreV1().ConfigMaps(j.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
 
-	cm := &corev1.ConfigMap{
+func (j *Job[T]) newConfigMap(owners []metav1.OwnerReference) (*corev1.ConfigMap, error) {
+	configJSON, err := json.Marshal(j.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      j.ID,
 			Namespace: j.Namespace,
+			Labels:    NewLabels(j.ID),
 			Annotations: map[string]string{
 				"job": j.ID,
 			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					Name:       jobObj.Name,
-					UID:        jobObj.UID,
-					Kind:       "Job",
-					APIVersion: "batch/v1",
-				},
-			},
+			OwnerReferences: owners,
 		},
 		Data: map[string]string{
 			configFile: string(configJSON),
 		},
-	}
-
-	log.Logf("Creating ConfigMap %s", cm.Name)
-	if _, err := j.client.CoreV1().ConfigMaps(j.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
-		return err
-	}
-	return nil
+	}, nil
 }
 
 // createSecrets copies over the CLI secrets into the pod
@@ -352,37 +559,65 @@ func (j *Job[T]) createSecrets(ctx context.Context, log logging.Logger) error {
 		return nil
 	}
 
-	jobObj, err := j.client.BatchV1().Jobs(j.Namespace).Get(ctx, j.ID, metav1.GetOptions{})
+	owners, err := j.getOwnerReferences(ctx)
 	if err != nil {
 		return err
 	}
-	secretData := make(map[string][]byte)
 
+	secret := j.newSecret(owners)
+	log.Logf("Creating Secret %s", secret.Name)
+	if _, err := j.client.CoreV1().Secrets(j.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (j *Job[T]) newSecret(owners []metav1.OwnerReference) *corev1.Secret {
+	secretData := make(map[string][]byte)
 	for k, v := range j.Secrets {
 		secretData[k] = []byte(v)
 	}
-
-	secret := &corev1.Secret{
+	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      j.ID,
-			Namespace: j.Namespace,
-			Labels: map[string]string{
-				"job": j.ID,
-			},
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					Name:       jobObj.Name,
-					UID:        jobObj.UID,
-					Kind:       "Job",
-					APIVersion: "batch/v1",
-				},
-			},
+			Name:            j.ID,
+			Namespace:       j.Namespace,
+			Labels:          NewLabels(j.ID),
+			OwnerReferences: owners,
 		},
 		Data: secretData,
 	}
-	log.Logf("Creating Secret %s", secret.Name)
-	if _, err := j.client.CoreV1().Secrets(j.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
-		return err
+}
+
+// getOwnerReferences returns references to the Job, or the StatefulSet of a durable job, that owns the job resources
+func (j *Job[T]) getOwnerReferences(ctx context.Context) ([]metav1.OwnerReference, error) {
+	if j.Durable {
+		return j.getStatefulSetOwnerReferences(ctx)
+	}
+	jobObj, err := j.client.BatchV1().Jobs(j.Namespace).Get(ctx, j.ID, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []metav1.OwnerReference{
+		{
+			Name:       jobObj.Name,
+			UID:        jobObj.UID,
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+	}, nil
+}
+
+func (j *Job[T]) getServiceAccountName() string {
+	if j.ServiceAccount != "" {
+		return j.ServiceAccount
+	}
+	return j.ID
+}
+
+func (j *Job[T]) newSubject() rbacv1.Subject {
+	return rbacv1.Subject{
+		Kind:      "ServiceAccount",
+		Name:      j.getServiceAccountName(),
+		Namespace: j.Namespace,
 	}
-	return nil
 }
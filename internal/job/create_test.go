@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"github.com/onosproject/helmit/internal/control"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestSidecars(t *testing.T) {
+	j := &Job[any]{
+		ID:        "test",
+		Namespace: "default",
+		Image:     "onosproject/helmit-runner:latest-amd64",
+		Sidecars: []corev1.Container{
+			{
+				Name:  "proxy",
+				Image: "envoyproxy/envoy:v1.25.0",
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      "proxy-config",
+						MountPath: "/etc/envoy",
+					},
+				},
+			},
+		},
+		SidecarVolumes: []corev1.Volume{
+			{
+				Name: "proxy-config",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		},
+	}
+	assert.NoError(t, j.validateSidecars())
+
+	pod := j.newJob().Spec.Template.Spec
+	assert.Len(t, pod.Containers, 2)
+	assert.Equal(t, "job", pod.Containers[0].Name)
+	assert.Equal(t, "proxy", pod.Containers[1].Name)
+	assert.Len(t, pod.Volumes, 2)
+	assert.Equal(t, "config", pod.Volumes[0].Name)
+	assert.Equal(t, "proxy-config", pod.Volumes[1].Name)
+
+	j.Sidecars = append(j.Sidecars, corev1.Container{Name: "job", Image: "busybox"})
+	assert.Error(t, j.validateSidecars())
+	j.Sidecars = j.Sidecars[:1]
+
+	j.SidecarVolumes = append(j.SidecarVolumes, corev1.Volume{Name: "config"})
+	assert.Error(t, j.validateSidecars())
+	j.SidecarVolumes = j.SidecarVolumes[:1]
+
+	j.Sidecars = append(j.Sidecars, corev1.Container{Name: "agent"})
+	assert.Error(t, j.validateSidecars())
+}
+
+func TestNamespace(t *testing.T) {
+	j := &Job[any]{
+		ID:        "test",
+		Namespace: "test",
+		NamespaceLabels: map[string]string{
+			"pod-security.kubernetes.io/enforce": "baseline",
+			ManagedByLabel:                       "other",
+		},
+		NamespaceAnnotations: map[string]string{
+			"scheduler.alpha.kubernetes.io/node-selector": "env=test",
+		},
+	}
+	namespace := j.newNamespace()
+	assert.Equal(t, "test", namespace.Name)
+	assert.Equal(t, "baseline", namespace.Labels["pod-security.kubernetes.io/enforce"])
+	assert.Equal(t, ManagedByValue, namespace.Labels[ManagedByLabel])
+	assert.Equal(t, "test", namespace.Labels[JobLabel])
+	assert.Equal(t, "env=test", namespace.Annotations["scheduler.alpha.kubernetes.io/node-selector"])
+	assert.Equal(t, "test", namespace.Annotations["job"])
+	assert.Len(t, j.NamespaceLabels, 2)
+}
+
+func TestReadinessProbe(t *testing.T) {
+	j := &Job[any]{
+		ID:        "test",
+		Namespace: "default",
+		Image:     "onosproject/helmit-runner:latest-amd64",
+	}
+	container := j.newJob().Spec.Template.Spec.Containers[0]
+	probe := container.ReadinessProbe
+	assert.Equal(t, control.ReadyPath, probe.HTTPGet.Path)
+	assert.Equal(t, control.ReadyPort, probe.HTTPGet.Port.IntValue())
+	assert.Equal(t, int32(1), probe.PeriodSeconds)
+	for _, env := range container.Env {
+		assert.NotEqual(t, control.PollIntervalEnv, env.Name)
+	}
+
+	j.PollInterval = 2500 * time.Millisecond
+	container = j.newJob().Spec.Template.Spec.Containers[0]
+	assert.Equal(t, int32(3), container.ReadinessProbe.PeriodSeconds)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: control.PollIntervalEnv, Value: "2.5s"})
+}
+
+func TestResourceEnv(t *testing.T) {
+	j := &Job[any]{
+		ID:          "test",
+		Namespace:   "default",
+		Image:       "onosproject/helmit-runner:latest-amd64",
+		ResourceEnv: map[string]string{"CPU_LIMIT": "limits.cpu"},
+	}
+	container := j.newJob().Spec.Template.Spec.Containers[0]
+	var found bool
+	for _, env := range container.Env {
+		if env.Name == "CPU_LIMIT" {
+			found = true
+			assert.Equal(t, "job", env.ValueFrom.ResourceFieldRef.ContainerName)
+			assert.Equal(t, "limits.cpu", env.ValueFrom.ResourceFieldRef.Resource)
+			assert.Equal(t, int64(1), env.ValueFrom.ResourceFieldRef.Divisor.Value())
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestNoCopy(t *testing.T) {
+	j := &Job[any]{
+		ID:        "test",
+		Namespace: "default",
+		Image:     "atomix/kubernetes-tests:latest",
+	}
+	assert.NotNil(t, j.newJob().Spec.Template.Spec.Containers[0].ReadinessProbe)
+	assert.NoError(t, j.validateNoCopy())
+
+	j.NoCopy = true
+	j.Command = []string{"/usr/local/bin/tests"}
+	container := j.newJob().Spec.Template.Spec.Containers[0]
+	assert.Nil(t, container.ReadinessProbe)
+	assert.Equal(t, []string{"/usr/local/bin/tests"}, container.Command)
+	assert.NoError(t, j.validateNoCopy())
+
+	j.Context = "/tmp/charts"
+	assert.Error(t, j.validateNoCopy())
+	j.Context = ""
+	j.Hold = true
+	assert.Error(t, j.validateNoCopy())
+}
+
+func TestRecordStartTimings(t *testing.T) {
+	scheduled := time.Now().Add(-10 * time.Second)
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(scheduled),
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "job",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{
+							StartedAt: metav1.NewTime(scheduled.Add(8 * time.Second)),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	step := logging.NewStep("test", "Setting up tests")
+	timings := logging.NewTimings()
+	logging.SetTimings(timings)
+	defer logging.SetTimings(nil)
+	step.Start()
+	recordStartTimings(step, pod, time.Now().Add(-12*time.Second))
+	step.Complete()
+
+	steps := timings.Get()[0].Steps
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "Scheduling pod", steps[0].Name)
+	assert.Equal(t, "Pulling images and starting containers", steps[1].Name)
+	assert.Equal(t, 8*time.Second, steps[1].Duration)
+	assert.InDelta(t, 4*time.Second, steps[0].Duration, float64(time.Second))
+
+	// Pods whose containers have not reported a start time are timed as a whole
+	pod.Status.ContainerStatuses = nil
+	step = logging.NewStep("test", "Setting up tests")
+	step.Start()
+	recordStartTimings(step, pod, time.Now().Add(-12*time.Second))
+	step.Complete()
+	steps = timings.Get()[1].Steps
+	assert.Len(t, steps, 1)
+	assert.Equal(t, "Starting pod", steps[0].Name)
+}
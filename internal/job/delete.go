@@ -25,6 +25,11 @@ func (j *Job[T]) Delete(ctx context.Context, log logging.Logger) error {
 	if err := j.deleteConfigMap(ctx, log); err != nil {
 		return err
 	}
+	if len(j.Rules) > 0 && !j.NamespacedRBAC {
+		if err := j.deleteClusterRole(ctx, log); err != nil {
+			return err
+		}
+	}
 	if j.DeleteNamespace {
 		if err := j.deleteNamespace(ctx, log); err != nil {
 			return err
@@ -33,6 +38,18 @@ func (j *Job[T]) Delete(ctx context.Context, log logging.Logger) error {
 	return nil
 }
 
+// AwaitDeleted waits until the pods of a deleted job are gone, so the job can be recreated
+func (j *Job[T]) AwaitDeleted(ctx context.Context) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+	informer, err := getPodInformer(ctx, j.client, j.Namespace)
+	if err != nil {
+		return err
+	}
+	return informer.awaitDeleted(ctx, j.Namespace, j.ID)
+}
+
 // deleteConfigMap deletes the job ConfigMap
 func (j *Job[T]) deleteConfigMap(ctx context.Context, log logging.Logger) error {
 	log.Logf("Deleting ConfigMap %s", j.ID)
@@ -46,6 +63,9 @@ func (j *Job[T]) deleteConfigMap(ctx context.Context, log logging.Logger) error
 
 // deleteJob deletes a job
 func (j *Job[T]) deleteJob(ctx context.Context, log logging.Logger) error {
+	if j.Durable {
+		return j.deleteStatefulSet(ctx, log)
+	}
 	log.Logf("Deleting Job %s", j.ID)
 	err := j.client.BatchV1().Jobs(j.Namespace).Delete(ctx, j.ID, getDeleteOptions())
 	stat, ok := status.FromError(err)
@@ -55,6 +75,23 @@ func (j *Job[T]) deleteJob(ctx context.Context, log logging.Logger) error {
 	return nil
 }
 
+// deleteClusterRole deletes the job ClusterRole and ClusterRoleBinding
+func (j *Job[T]) deleteClusterRole(ctx context.Context, log logging.Logger) error {
+	log.Logf("Deleting ClusterRoleBinding %s", j.ID)
+	err := j.client.RbacV1().ClusterRoleBindings().Delete(ctx, j.ID, getDeleteOptions())
+	stat, ok := status.FromError(err)
+	if err != nil && !k8serrors.IsNotFound(err) && ok && stat.Code() != codes.Unavailable {
+		return err
+	}
+	log.Logf("Deleting ClusterRole %s", j.ID)
+	err = j.client.RbacV1().ClusterRoles().Delete(ctx, j.ID, getDeleteOptions())
+	stat, ok = status.FromError(err)
+	if err != nil && !k8serrors.IsNotFound(err) && ok && stat.Code() != codes.Unavailable {
+		return err
+	}
+	return nil
+}
+
 // deleteNamespace deletes a job
 func (j *Job[T]) deleteNamespace(ctx context.Context, log logging.Logger) error {
 	log.Logf("Deleting Namespace %s", j.Namespace)
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// oomKilledReason is the reason for the termination of a container killed for exceeding its memory limit
+const oomKilledReason = "OOMKilled"
+
+// createStatefulSet creates the StatefulSet running the pod of a durable job, and the headless Service giving
+// the pod a stable network identity across restarts
+func (j *Job[T]) createStatefulSet(ctx context.Context, log logging.Logger) error {
+	statefulSet := j.newStatefulSet()
+	log.Logf("Creating StatefulSet %s", statefulSet.Name)
+	if _, err := j.client.AppsV1().StatefulSets(j.Namespace).Create(ctx, statefulSet, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	owners, err := j.getOwnerReferences(ctx)
+	if err != nil {
+		return err
+	}
+	service := j.newService(owners)
+	log.Logf("Creating Service %s", service.Name)
+	if _, err := j.client.CoreV1().Services(j.Namespace).Create(ctx, service, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// newStatefulSet returns the StatefulSet running the pod of a durable job
+// The pod is recreated with the same name if it's deleted, e.g. when its node is drained, and its job container
+// is restarted if it terminates.
+func (j *Job[T]) newStatefulSet() *appsv1.StatefulSet {
+	template := j.newPodTemplate(corev1.RestartPolicyAlways)
+	one := int32(1)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        j.ID,
+			Namespace:   j.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &one,
+			ServiceName: j.ID,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: NewLabels(j.ID),
+			},
+			Template: template,
+		},
+	}
+}
+
+// newService returns the headless Service of a durable job
+func (j *Job[T]) newService(owners []metav1.OwnerReference) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            j.ID,
+			Namespace:       j.Namespace,
+			Labels:          NewLabels(j.ID),
+			OwnerReferences: owners,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  NewLabels(j.ID),
+			// The pod is addressable while it's waiting for its executable, before its readiness probe passes
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// getStatefulSetOwnerReferences returns references to the StatefulSet that owns the resources of a durable job
+func (j *Job[T]) getStatefulSetOwnerReferences(ctx context.Context) ([]metav1.OwnerReference, error) {
+	statefulSet, err := j.client.AppsV1().StatefulSets(j.Namespace).Get(ctx, j.ID, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []metav1.OwnerReference{
+		{
+			Name:       statefulSet.Name,
+			UID:        statefulSet.UID,
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+	}, nil
+}
+
+// deleteStatefulSet deletes the StatefulSet of a durable job, whose Service is deleted with it
+func (j *Job[T]) deleteStatefulSet(ctx context.Context, log logging.Logger) error {
+	log.Logf("Deleting StatefulSet %s", j.ID)
+	err := j.client.AppsV1().StatefulSets(j.Namespace).Delete(ctx, j.ID, getDeleteOptions())
+	stat, ok := status.FromError(err)
+	if err != nil && !k8serrors.IsNotFound(err) && ok && stat.Code() != codes.Unavailable {
+		return err
+	}
+	return nil
+}
+
+// Restarted returns whether the pod of a durable job has been deleted, e.g. by a node drain, or its job container
+// was killed and restarted for exceeding its memory limit since the job was created or last recovered
+// Job containers restarted after their executable exited are not considered restarted, since the executable
+// would be expected to exit again.
+func (j *Job[T]) Restarted(ctx context.Context) (bool, error) {
+	if err := j.init(); err != nil {
+		return false, err
+	}
+	if err := j.ensurePod(ctx); err != nil {
+		return false, err
+	}
+	pod, err := j.getPod(ctx)
+	if err != nil {
+		return false, err
+	}
+	if pod == nil || pod.UID != j.pod.UID || pod.DeletionTimestamp != nil {
+		return true, nil
+	}
+	if !j.isRestarted(pod) {
+		return false, nil
+	}
+	terminated := getJobContainerStatus(pod).LastTerminationState.Terminated
+	return terminated != nil && terminated.Reason == oomKilledReason, nil
+}
+
+// Recover waits for the restarted pod of a durable job to start running, then copies the job's files to the pod
+// and starts its executable, since they're lost when the pod or its job container is restarted
+func (j *Job[T]) Recover(ctx context.Context, log logging.Logger) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+	if err := j.ensurePod(ctx); err != nil {
+		return err
+	}
+	log.Logf("Waiting for Job to restart...")
+	informer, err := getPodInformer(ctx, j.client, j.Namespace)
+	if err != nil {
+		return err
+	}
+	pod, err := informer.await(ctx, j.Namespace, j.ID, func(pod *corev1.Pod) bool {
+		return pod.DeletionTimestamp == nil && j.isRestarted(pod) && getJobContainerState(pod).Running != nil
+	})
+	if err != nil {
+		return err
+	}
+	j.pod = pod
+
+	if err := j.copyExecutable(ctx, log); err != nil {
+		return err
+	}
+	if err := j.copySource(ctx, log); err != nil {
+		return err
+	}
+	if err := j.copyContext(ctx, log); err != nil {
+		return err
+	}
+	if err := j.copyValueFiles(ctx, log); err != nil {
+		return err
+	}
+	if err := j.buildSource(ctx, log); err != nil {
+		return err
+	}
+	return j.runExecutable(ctx, log)
+}
+
+// isRestarted returns whether the given pod replaced the job's pod or restarted its job container, where a nil pod
+// indicates the job's pod was deleted
+func (j *Job[T]) isRestarted(pod *corev1.Pod) bool {
+	if pod == nil || pod.UID != j.pod.UID {
+		return true
+	}
+	return getJobContainerStatus(pod).RestartCount > getJobContainerStatus(j.pod).RestartCount
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func TestDurableJob(t *testing.T) {
+	j := &Job[any]{
+		ID:        "happy-panda-worker-0",
+		Namespace: "default",
+		Image:     "onosproject/helmit-runner:latest-amd64",
+		Durable:   true,
+	}
+
+	statefulSet := j.newStatefulSet()
+	assert.Equal(t, int32(1), *statefulSet.Spec.Replicas)
+	assert.Equal(t, j.ID, statefulSet.Spec.ServiceName)
+	assert.Equal(t, NewLabels(j.ID), statefulSet.Spec.Selector.MatchLabels)
+	assert.Equal(t, corev1.RestartPolicyAlways, statefulSet.Spec.Template.Spec.RestartPolicy)
+	assert.Equal(t, j.ID, statefulSet.Spec.Template.Labels[JobLabel])
+	assert.Equal(t, corev1.RestartPolicyNever, j.newJob().Spec.Template.Spec.RestartPolicy)
+
+	service := j.newService(nil)
+	assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+	assert.Equal(t, NewLabels(j.ID), service.Spec.Selector)
+
+	objects, err := j.Plan()
+	assert.NoError(t, err)
+	var kinds []string
+	for _, object := range objects {
+		kinds = append(kinds, object.GetObjectKind().GroupVersionKind().Kind)
+	}
+	assert.Contains(t, kinds, "StatefulSet")
+	assert.Contains(t, kinds, "Service")
+	assert.NotContains(t, kinds, "Job")
+}
+
+func TestDurableJobRestarts(t *testing.T) {
+	newPod := func(uid string, restarts int32, terminated *corev1.ContainerStateTerminated) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "happy-panda-worker-0-0",
+				UID:  types.UID(uid),
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:                 "job",
+						RestartCount:         restarts,
+						State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+						LastTerminationState: corev1.ContainerState{Terminated: terminated},
+					},
+				},
+			},
+		}
+	}
+
+	j := &Job[any]{ID: "happy-panda-worker-0", Durable: true}
+	j.pod = newPod("a", 0, nil)
+	assert.False(t, j.isRestarted(newPod("a", 0, nil)))
+	assert.Nil(t, j.getTerminated(newPod("a", 0, nil)))
+	assert.True(t, j.isRestarted(nil))
+	assert.True(t, j.isRestarted(newPod("b", 0, nil)))
+
+	// The termination of a durable job's container is found in the last state of the restarted container
+	completed := &corev1.ContainerStateTerminated{Reason: "Completed", Message: "done"}
+	assert.True(t, j.isRestarted(newPod("a", 1, completed)))
+	assert.Equal(t, completed, j.getTerminated(newPod("a", 1, completed)))
+	assert.Nil(t, j.getTerminated(newPod("b", 0, completed)))
+
+	j.Durable = false
+	assert.Nil(t, j.getTerminated(newPod("a", 1, completed)))
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"net/http"
+)
+
+// Forward forwards a local port to the given port on the job pod, returning the local port.
+// The port is forwarded until the given context is canceled.
+func (j *Job[T]) Forward(ctx context.Context, port int) (int, error) {
+	if err := j.init(); err != nil {
+		return 0, err
+	}
+	if err := j.ensurePod(ctx); err != nil {
+		return 0, err
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(j.config)
+	if err != nil {
+		return 0, err
+	}
+
+	req := j.client.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Name(j.pod.Name).
+		Namespace(j.pod.Namespace).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	readyCh := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", port)}, ctx.Done(), readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return 0, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyCh:
+		ports, err := forwarder.GetPorts()
+		if err != nil {
+			return 0, err
+		}
+		if len(ports) == 0 {
+			return 0, errors.New("failed to forward port")
+		}
+		return int(ports[0].Local), nil
+	case err := <-errCh:
+		if err == nil {
+			err = errors.New("port forwarding stopped")
+		}
+		return 0, err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sync"
+)
+
+// podInformers are the informers shared by all jobs in the process, keyed by namespace
+var (
+	podInformers   = make(map[string]*podInformer)
+	podInformersMu sync.Mutex
+)
+
+// getPodInformer returns the shared informer watching the pods of jobs in the given namespace, starting it
+// if it's not already running
+// Jobs wait for changes to their pods through a single watch per namespace rather than each polling the API server.
+func getPodInformer(ctx context.Context, client kubernetes.Interface, namespace string) (*podInformer, error) {
+	podInformersMu.Lock()
+	informer, ok := podInformers[namespace]
+	if !ok {
+		informer = newPodInformer(client, namespace)
+		podInformers[namespace] = informer
+	}
+	podInformersMu.Unlock()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.informer.HasSynced) {
+		return nil, fmt.Errorf("failed to sync pods in namespace %s: %w", namespace, ctx.Err())
+	}
+	return informer, nil
+}
+
+func newPodInformer(client kubernetes.Interface, namespace string) *podInformer {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = ManagedByLabel + "=" + ManagedByValue + "," + JobLabel
+		}))
+	pods := factory.Core().V1().Pods()
+	informer := &podInformer{
+		informer: pods.Informer(),
+		lister:   pods.Lister(),
+		watchers: make(map[chan struct{}]bool),
+	}
+	_, _ = informer.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) {
+			informer.notify()
+		},
+		UpdateFunc: func(interface{}, interface{}) {
+			informer.notify()
+		},
+		DeleteFunc: func(interface{}) {
+			informer.notify()
+		},
+	})
+	// The informer runs for the life of the process, since jobs may be awaited until the process exits
+	go informer.informer.Run(make(chan struct{}))
+	return informer
+}
+
+// podInformer caches the pods of jobs in a namespace and notifies watchers when they change
+type podInformer struct {
+	informer cache.SharedIndexInformer
+	lister   listersv1.PodLister
+	watchers map[chan struct{}]bool
+	mu       sync.Mutex
+}
+
+func (i *podInformer) notify() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for ch := range i.watchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// await waits until the given function returns true for the pod of the given job, returning the pod
+func (i *podInformer) await(ctx context.Context, namespace string, jobID string, f func(pod *corev1.Pod) bool) (*corev1.Pod, error) {
+	var match *corev1.Pod
+	err := i.wait(ctx, namespace, jobID, func(pods []*corev1.Pod) bool {
+		for _, pod := range pods {
+			if f(pod) {
+				match = pod.DeepCopy()
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// awaitDeleted waits until all the pods of the given job have been deleted
+func (i *podInformer) awaitDeleted(ctx context.Context, namespace string, jobID string) error {
+	return i.wait(ctx, namespace, jobID, func(pods []*corev1.Pod) bool {
+		return len(pods) == 0
+	})
+}
+
+// wait waits until the given function returns true for the pods of the given job
+func (i *podInformer) wait(ctx context.Context, namespace string, jobID string, f func(pods []*corev1.Pod) bool) error {
+	ch := make(chan struct{}, 1)
+	i.mu.Lock()
+	i.watchers[ch] = true
+	i.mu.Unlock()
+	defer func() {
+		i.mu.Lock()
+		delete(i.watchers, ch)
+		i.mu.Unlock()
+	}()
+
+	selector := labels.SelectorFromSet(labels.Set{JobLabel: jobID})
+	for {
+		pods, err := i.lister.Pods(namespace).List(selector)
+		if err != nil {
+			return err
+		}
+		if f(pods) {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
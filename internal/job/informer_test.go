@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func TestPodInformer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := fake.NewSimpleClientset()
+	informer, err := getPodInformer(ctx, client, "informer-test")
+	assert.NoError(t, err)
+
+	terminated := func(pod *corev1.Pod) bool {
+		return getJobContainerState(pod).Terminated != nil
+	}
+	podCh := make(chan *corev1.Pod)
+	go func() {
+		pod, err := informer.await(ctx, "informer-test", "test", terminated)
+		assert.NoError(t, err)
+		podCh <- pod
+	}()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-abcde",
+			Namespace: "informer-test",
+			Labels:    NewLabels("test"),
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "job",
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+	pod, err = client.CoreV1().Pods("informer-test").Create(ctx, pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	select {
+	case <-podCh:
+		t.Fatal("pod is not terminated")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 3,
+		},
+	}
+	_, err = client.CoreV1().Pods("informer-test").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	select {
+	case pod := <-podCh:
+		assert.Equal(t, int32(3), getJobContainerState(pod).Terminated.ExitCode)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the pod to terminate")
+	}
+
+	// Waiting is canceled with the context
+	awaitCtx, awaitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer awaitCancel()
+	_, err = informer.await(awaitCtx, "informer-test", "other", terminated)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Waiting for deletion completes once the job's pods are gone
+	deletedCh := make(chan error)
+	go func() {
+		deletedCh <- informer.awaitDeleted(ctx, "informer-test", "test")
+	}()
+	select {
+	case <-deletedCh:
+		t.Fatal("pod is not deleted")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.NoError(t, client.CoreV1().Pods("informer-test").Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+	select {
+	case err := <-deletedCh:
+		assert.NoError(t, err)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the pod to be deleted")
+	}
+}
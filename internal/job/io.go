@@ -6,29 +6,29 @@ package job
 
 import (
 	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
 	"github.com/onosproject/helmit/internal/logging"
 	"io"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/tools/remotecommand"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 )
 
-func (j *Job[T]) GetLogs(ctx context.Context) (io.ReadCloser, error) {
-	if err := j.init(); err != nil {
-		return nil, err
-	}
-
-	req := j.client.CoreV1().Pods(j.Namespace).GetLogs(j.pod.Name, &corev1.PodLogOptions{
-		Container: "job",
-		Follow:    true,
-	})
-	return req.Stream(ctx)
-}
+const (
+	maxCopyAttempts   = 3
+	progressIncrement = 10
+	// copyChunkSize is the size of the chunks in which files are copied, and from which a failed copy is resumed
+	copyChunkSize = 32 * 1024 * 1024
+)
 
 func (j *Job[T]) copyExecutable(ctx context.Context, log logging.Logger) error {
 	if j.Executable != "" {
@@ -37,8 +37,9 @@ func (j *Job[T]) copyExecutable(ctx context.Context, log logging.Logger) error {
 		} else if fileInfo.IsDir() {
 			return fmt.Errorf("%s is not a valid file", j.Executable)
 		}
+		defer logging.RecordTiming(log, "Copying executable", time.Now())
 		log.Logf("Copying %s to %s", j.Executable, j.pod.Name)
-		return j.copy(ctx, filepath.Base(j.Executable), j.Executable)
+		return j.copyFile(ctx, filepath.Join(HomeDir, filepath.Base(j.Executable)), j.Executable, log)
 	}
 	return nil
 }
@@ -50,13 +51,19 @@ func (j *Job[T]) copyContext(ctx context.Context, log logging.Logger) error {
 		} else if !fileInfo.IsDir() {
 			return fmt.Errorf("%s is not a valid directory", j.Context)
 		}
-		log.Logf("Copying %s to %s", j.Context, j.pod.Name)
-		return j.copy(ctx, filepath.Base(ContextDir), j.Context)
+		defer logging.RecordTiming(log, "Copying context", time.Now())
+		return j.retry(ctx, log, func() error {
+			log.Logf("Copying %s to %s", j.Context, j.pod.Name)
+			return j.copy(ctx, filepath.Base(ContextDir), j.Context, nil, log)
+		})
 	}
 	return nil
 }
 
 func (j *Job[T]) copyValueFiles(ctx context.Context, log logging.Logger) error {
+	if len(j.ValueFiles) > 0 {
+		defer logging.RecordTiming(log, "Copying values files", time.Now())
+	}
 	for _, files := range j.ValueFiles {
 		for _, file := range files {
 			if fileInfo, err := os.Stat(file); err != nil {
@@ -64,8 +71,11 @@ func (j *Job[T]) copyValueFiles(ctx context.Context, log logging.Logger) error {
 			} else if fileInfo.IsDir() {
 				return fmt.Errorf("%s is not a valid file", file)
 			}
-			log.Logf("Copying %s to %s", file, j.pod.Name)
-			if err := j.copy(ctx, filepath.Base(file), file); err != nil {
+			err := j.retry(ctx, log, func() error {
+				log.Logf("Copying %s to %s", file, j.pod.Name)
+				return j.copy(ctx, filepath.Base(file), file, nil, log)
+			})
+			if err != nil {
 				return err
 			}
 		}
@@ -74,106 +84,331 @@ func (j *Job[T]) copyValueFiles(ctx context.Context, log logging.Logger) error {
 }
 
 func (j *Job[T]) runExecutable(ctx context.Context, log logging.Logger) error {
+	if j.Source != nil && j.Source.Main != "" {
+		return j.setExecutable(ctx, j.getSourceBinary())
+	}
 	if j.Executable != "" {
-		return j.Echo(ctx, readyFile, []byte(filepath.Join(HomeDir, filepath.Base(j.Executable))))
+		return j.setExecutable(ctx, filepath.Join(HomeDir, filepath.Base(j.Executable)))
 	}
 	return nil
 }
 
-func (j *Job[T]) copy(ctx context.Context, dst, src string) error {
+// retry retries the given copy function until it succeeds or the maximum number of attempts is reached
+func (j *Job[T]) retry(ctx context.Context, log logging.Logger, f func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxCopyAttempts; attempt++ {
+		if err = f(); err == nil {
+			return nil
+		}
+		if attempt < maxCopyAttempts {
+			log.Logf("Copy attempt %d failed: %s", attempt, err)
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return err
+}
+
+// copy copies the given local file or directory to the job pod, omitting any files matched by exclude
+// The copy is streamed as a compressed tar and restarted from the beginning if it fails.
+func (j *Job[T]) copy(ctx context.Context, dst, src string, exclude excludeFunc, log logging.Logger) error {
 	if err := j.init(); err != nil {
 		return err
 	}
 
+	size, err := getSize(src, exclude)
+	if err != nil {
+		return err
+	}
+
+	progress := newCopyProgress(filepath.Base(src), size, log)
 	reader, writer := io.Pipe()
 
 	go func() {
-		defer writer.Close()
-		err := makeTar(src, dst, writer)
+		zipWriter, err := gzip.NewWriterLevel(progress.sent(writer), gzip.BestSpeed)
 		if err != nil {
-			fmt.Println(err)
+			writer.CloseWithError(err)
+			return
 		}
+		if err := makeTar(src, dst, progress.read(zipWriter), exclude); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(zipWriter.Close())
 	}()
+	defer reader.Close()
+
+	return j.exec(ctx, []string{"tar", "-xzf", "-"}, reader, os.Stdout, os.Stderr)
+}
 
-	cmd := []string{"tar", "-xf", "-"}
-	req := j.client.CoreV1().RESTClient().
-		Post().
-		Resource("pods").
-		Name(j.pod.Name).
-		Namespace(j.pod.Namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "job",
-			Command:   cmd,
-			Stdin:     true,
-			Stdout:    true,
-			Stderr:    true,
-			TTY:       false,
-		}, scheme.ParameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(j.config, "POST", req.URL())
+// copyFile copies the given local file to the given path in the job pod in compressed chunks, verifying the
+// checksum of each chunk once it's copied and of the whole file once all chunks are copied
+// A chunk that fails to copy is retried from the offset at which the last verified chunk ended, so a failure
+// late in the copy of a large executable does not restart the copy from the beginning.
+func (j *Job[T]) copyFile(ctx context.Context, dst, src string, log logging.Logger) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+
+	file, err := os.Open(src)
 	if err != nil {
 		return err
 	}
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:  reader,
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
-		Tty:    false,
-	})
+	defer file.Close()
+	fileInfo, err := file.Stat()
 	if err != nil {
 		return err
 	}
+	checksum, err := getChecksum(src)
+	if err != nil {
+		return err
+	}
+
+	progress := newCopyProgress(filepath.Base(src), fileInfo.Size(), log)
+	chunk := make([]byte, copyChunkSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(file, chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		err = j.retry(ctx, log, func() error {
+			return j.copyChunk(ctx, dst, offset, chunk[:n], progress)
+		})
+		if err != nil {
+			return err
+		}
+		offset += int64(n)
+		progress.verified(offset)
+		if n < len(chunk) {
+			break
+		}
+	}
+
+	if err := j.exec(ctx, []string{"chmod", fmt.Sprintf("%o", fileInfo.Mode().Perm()), dst}, nil, os.Stdout, os.Stderr); err != nil {
+		return err
+	}
+	log.Logf("Verifying checksum of %s", filepath.Base(src))
+	return j.verifyChecksum(ctx, dst, checksum)
+}
+
+// copyChunk writes the given chunk of a file at the given offset of the file in the job pod, discarding anything
+// written beyond the offset by a failed attempt, and verifies the checksum of the chunk
+func (j *Job[T]) copyChunk(ctx context.Context, dst string, offset int64, data []byte, progress *copyProgress) error {
+	reader, writer := io.Pipe()
+	go func() {
+		zipWriter, err := gzip.NewWriterLevel(progress.sent(writer), gzip.BestSpeed)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if _, err := zipWriter.Write(data); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(zipWriter.Close())
+	}()
+	defer reader.Close()
+
+	cmd := []string{"/bin/sh", "-c", fmt.Sprintf("truncate -s %d %s && gzip -dc >> %s", offset, dst, dst)}
+	if err := j.exec(ctx, cmd, reader, os.Stdout, os.Stderr); err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var stdout bytes.Buffer
+	cmd = []string{"/bin/sh", "-c", fmt.Sprintf("tail -c %d %s | sha256sum", len(data), dst)}
+	if err := j.exec(ctx, cmd, nil, &stdout, os.Stderr); err != nil {
+		return err
+	}
+	checksum := sha256.Sum256(data)
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 || fields[0] != hex.EncodeToString(checksum[:]) {
+		return fmt.Errorf("checksum mismatch for %s at offset %d", dst, offset)
+	}
 	return nil
 }
 
-// Echo echos bytes to a file in the job pod
-func (j *Job[T]) Echo(ctx context.Context, dst string, data []byte) error {
+// CopyFrom copies the given file or directory from the job pod to the local dst path
+func (j *Job[T]) CopyFrom(ctx context.Context, src, dst string) error {
 	if err := j.init(); err != nil {
 		return err
 	}
 
-	cmd := []string{"/bin/sh", "-c", fmt.Sprintf("echo \"%s\" > %s", string(data), dst)}
-	req := j.client.CoreV1().RESTClient().
-		Post().
-		Resource("pods").
-		Name(j.pod.Name).
-		Namespace(j.pod.Namespace).
-		SubResource("exec").
-		VersionedParams(&corev1.PodExecOptions{
-			Container: "job",
-			Command:   cmd,
-			Stdin:     false,
-			Stdout:    true,
-			Stderr:    true,
-			TTY:       false,
-		}, scheme.ParameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(j.config, "POST", req.URL())
+	reader, writer := io.Pipe()
+	go func() {
+		cmd := []string{"tar", "-czf", "-", "-C", path.Dir(src), path.Base(src)}
+		writer.CloseWithError(j.exec(ctx, cmd, nil, writer, os.Stderr))
+	}()
+	defer reader.Close()
+
+	zipReader, err := gzip.NewReader(reader)
 	if err != nil {
 		return err
 	}
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
-		Tty:    false,
-	})
-	if err != nil {
+	defer zipReader.Close()
+	return extractTar(zipReader, path.Base(src), dst)
+}
+
+// verifyChecksum verifies the SHA-256 checksum of the given file in the job pod
+func (j *Job[T]) verifyChecksum(ctx context.Context, file string, checksum string) error {
+	var stdout bytes.Buffer
+	if err := j.exec(ctx, []string{"sha256sum", file}, nil, &stdout, os.Stderr); err != nil {
 		return err
 	}
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 || fields[0] != checksum {
+		return fmt.Errorf("checksum mismatch for %s", file)
+	}
 	return nil
 }
 
-func makeTar(srcPath, destPath string, writer io.Writer) error {
-	// TODO: use compression here?
+// Echo echos bytes to a file in the job pod
+func (j *Job[T]) Echo(ctx context.Context, dst string, data []byte) error {
+	if err := j.init(); err != nil {
+		return err
+	}
+	cmd := []string{"/bin/sh", "-c", fmt.Sprintf("echo \"%s\" > %s", string(data), dst)}
+	return j.exec(ctx, cmd, nil, os.Stdout, os.Stderr)
+}
+
+// exec executes the given command in the job container
+func (j *Job[T]) exec(ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	return k8s.Exec(ctx, j.config, j.client, j.pod.Namespace, j.pod.Name, "job", cmd, stdin, stdout, stderr)
+}
+
+// getChecksum returns the hex encoded SHA-256 checksum of the given file
+func getChecksum(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// excludeFunc returns whether the given file should be excluded from a copy
+type excludeFunc func(info os.FileInfo) bool
+
+// getSize returns the total size of the given file or directory
+func getSize(src string, exclude excludeFunc) (int64, error) {
+	var size int64
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != src && exclude != nil && exclude(info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func newCopyProgress(name string, size int64, log logging.Logger) *copyProgress {
+	return &copyProgress{
+		name: name,
+		size: size,
+		log:  log,
+	}
+}
+
+// copyProgress logs the progress of a copy as the compressed stream is sent to the job pod
+// The progress is logged in increments of the source bytes that have been compressed and sent, along with the
+// number of compressed bytes sent, including those of failed attempts.
+type copyProgress struct {
+	name      string
+	size      int64
+	done      int64
+	sentBytes int64
+	percent   int64
+	log       logging.Logger
+	mu        sync.Mutex
+}
+
+// read returns a writer that counts the source bytes written to the given writer compressing the stream
+func (p *copyProgress) read(writer io.Writer) io.Writer {
+	return &progressWriter{
+		writer: writer,
+		count: func(n int) {
+			p.mu.Lock()
+			p.done += int64(n)
+			p.mu.Unlock()
+		},
+	}
+}
+
+// sent returns a writer that counts the compressed bytes sent through the given writer, logging the progress
+func (p *copyProgress) sent(writer io.Writer) io.Writer {
+	return &progressWriter{
+		writer: writer,
+		count: func(n int) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.sentBytes += int64(n)
+			p.update()
+		},
+	}
+}
+
+// verified records that the source bytes up to the given offset have been copied and verified
+func (p *copyProgress) verified(offset int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = offset
+	p.update()
+}
+
+func (p *copyProgress) update() {
+	if p.size == 0 {
+		return
+	}
+	percent := p.done * 100 / p.size
+	if percent > 100 {
+		percent = 100
+	}
+	if percent >= p.percent+progressIncrement {
+		p.percent = percent - percent%progressIncrement
+		p.log.Logf("Copying %s: %d%% (%d/%d bytes, %d compressed bytes sent)", p.name, p.percent, p.done, p.size, p.sentBytes)
+	}
+}
+
+// progressWriter is an io.Writer that counts the bytes written through it
+type progressWriter struct {
+	writer io.Writer
+	count  func(n int)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count(n)
+	return n, err
+}
+
+func makeTar(srcPath, destPath string, writer io.Writer, exclude excludeFunc) error {
 	tarWriter := tar.NewWriter(writer)
 	defer tarWriter.Close()
 	srcPath = path.Clean(srcPath)
 	destPath = path.Clean(destPath)
-	return recursiveTar(path.Dir(srcPath), path.Base(srcPath), path.Base(destPath), tarWriter)
+	return recursiveTar(path.Dir(srcPath), path.Base(srcPath), path.Base(destPath), tarWriter, exclude)
 }
 
-func recursiveTar(srcBase, srcFile, destFile string, tw *tar.Writer) error {
+func recursiveTar(srcBase, srcFile, destFile string, tw *tar.Writer, exclude excludeFunc) error {
 	filepath := path.Join(srcBase, srcFile)
 	stat, err := os.Lstat(filepath)
 	if err != nil {
@@ -193,7 +428,16 @@ func recursiveTar(srcBase, srcFile, destFile string, tw *tar.Writer) error {
 			}
 		}
 		for _, f := range files {
-			if err := recursiveTar(srcBase, path.Join(srcFile, f.Name()), path.Join(destFile, f.Name()), tw); err != nil {
+			if exclude != nil {
+				info, err := f.Info()
+				if err != nil {
+					return err
+				}
+				if exclude(info) {
+					continue
+				}
+			}
+			if err := recursiveTar(srcBase, path.Join(srcFile, f.Name()), path.Join(destFile, f.Name()), tw, exclude); err != nil {
 				return err
 			}
 		}
@@ -236,3 +480,47 @@ func recursiveTar(srcBase, srcFile, destFile string, tw *tar.Writer) error {
 	}
 	return nil
 }
+
+func extractTar(reader io.Reader, srcPrefix, destPath string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if name != srcPrefix && !strings.HasPrefix(name, srcPrefix+"/") {
+			continue
+		}
+		relPath := strings.TrimPrefix(strings.TrimPrefix(name, srcPrefix), "/")
+		if relPath == ".." || strings.HasPrefix(relPath, "../") {
+			return fmt.Errorf("invalid file path %s", hdr.Name)
+		}
+		destFile := filepath.Join(destPath, filepath.FromSlash(relPath))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(destFile, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(destFile), os.ModePerm); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(destFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tarReader); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
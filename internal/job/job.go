@@ -6,15 +6,19 @@ package job
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/onosproject/helmit/internal/k8s"
 	"github.com/onosproject/helmit/internal/logging"
 	"golang.org/x/net/context"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -22,11 +26,19 @@ const (
 	configPath  = "/etc/helmit/config"
 	secretsPath = "/etc/helmit/secrets"
 	configFile  = "config.json"
-	readyFile   = "/tmp/bin-ready"
+	// ConfigPathEnv is an environment variable overriding the path from which the job configuration is loaded
+	ConfigPathEnv = "HELMIT_CONFIG_PATH"
+	// SecretsPathEnv is an environment variable overriding the path from which the job secrets are loaded
+	SecretsPathEnv = "HELMIT_SECRETS_PATH"
+	// IDEnv is the environment variable to which the ID of the job is set
+	IDEnv   = "HELMIT_JOB_ID"
+	holdEnv = "HELMIT_HOLD"
 	// HomeDir is the home directory of the helmit-runner container
 	HomeDir = "/home/helmit"
 	// ContextDir is the directory to which job contexts will be copied if specified
 	ContextDir = "context"
+	// ArtifactsDir is the directory in which jobs store artifacts to be collected
+	ArtifactsDir = "artifacts"
 )
 
 const (
@@ -34,9 +46,59 @@ const (
 	defaultRoleName        = "cluster-admin"
 )
 
+const (
+	// JobLabel is the label identifying the job to which a resource belongs
+	JobLabel = "job"
+	// ManagedByLabel is the label identifying resources managed by helmit
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of the ManagedByLabel for resources managed by helmit
+	ManagedByValue = "helmit"
+)
+
+// conflictBackoff is the exponential backoff with which conflicting updates to shared resources are retried
+var conflictBackoff = wait.Backoff{
+	Steps:    10,
+	Duration: 10 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Cap:      5 * time.Second,
+}
+
+// NewLabels returns the labels applied to all resources created for the given job
+func NewLabels(id string) map[string]string {
+	return map[string]string{
+		JobLabel:       id,
+		ManagedByLabel: ManagedByValue,
+	}
+}
+
+// NewNamespace returns a Namespace with the given name created for the given job
+// The given labels and annotations are applied to the Namespace, but cannot override the labels identifying
+// the job that created it.
+func NewNamespace(id string, name string, labels map[string]string, annotations map[string]string) *corev1.Namespace {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      make(map[string]string),
+			Annotations: make(map[string]string),
+		},
+	}
+	for key, value := range labels {
+		namespace.Labels[key] = value
+	}
+	for key, value := range NewLabels(id) {
+		namespace.Labels[key] = value
+	}
+	for key, value := range annotations {
+		namespace.Annotations[key] = value
+	}
+	namespace.Annotations["job"] = id
+	return namespace
+}
+
 // LoadConfig loads the job configuration
 func LoadConfig(config any) error {
-	bytes, err := os.ReadFile(filepath.Join(configPath, configFile))
+	bytes, err := os.ReadFile(filepath.Join(getPath(ConfigPathEnv, configPath), configFile))
 	if err != nil {
 		return err
 	}
@@ -50,7 +112,8 @@ func LoadConfig(config any) error {
 // LoadSecrets loads the job secrets
 func LoadSecrets() (map[string]string, error) {
 	secrets := make(map[string]string)
-	files, err := os.ReadDir(secretsPath)
+	path := getPath(SecretsPathEnv, secretsPath)
+	files, err := os.ReadDir(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return secrets, nil
@@ -58,8 +121,8 @@ func LoadSecrets() (map[string]string, error) {
 		return nil, err
 	}
 	for _, file := range files {
-		if !file.IsDir() {
-			bytes, err := os.ReadFile(file.Name())
+		if !file.IsDir() && !strings.HasPrefix(file.Name(), ".") {
+			bytes, err := os.ReadFile(filepath.Join(path, file.Name()))
 			if err != nil {
 				return nil, err
 			}
@@ -69,27 +132,59 @@ func LoadSecrets() (map[string]string, error) {
 	return secrets, nil
 }
 
+// GetID returns the ID of the running job, or an empty string if not running in a job
+func GetID() string {
+	return os.Getenv(IDEnv)
+}
+
+// getPath returns the path set in the given environment variable, or the default path if not set
+func getPath(env string, defaultPath string) string {
+	if path := os.Getenv(env); path != "" {
+		return path
+	}
+	return defaultPath
+}
+
 // Job manages the lifecycle of a Kubernetes job
 type Job[T any] struct {
-	ID              string
-	Namespace       string
-	CreateNamespace bool
-	DeleteNamespace bool
-	ServiceAccount  string
-	Labels          map[string]string
-	Annotations     map[string]string
-	Image           string
-	ImagePullPolicy corev1.PullPolicy
-	Args            []string
-	Env             map[string]string
-	Secrets         map[string]string
-	Context         string
-	ValueFiles      map[string][]string
-	Executable      string
-	Config          T
-	config          *rest.Config
-	client          *kubernetes.Clientset
-	pod             *corev1.Pod
+	ID                   string
+	Namespace            string
+	CreateNamespace      bool
+	DeleteNamespace      bool
+	NamespaceLabels      map[string]string
+	NamespaceAnnotations map[string]string
+	ServiceAccount       string
+	Rules                []rbacv1.PolicyRule
+	NamespacedRBAC       bool
+	Labels               map[string]string
+	Annotations          map[string]string
+	Image                string
+	ImagePullPolicy      corev1.PullPolicy
+	NodeSelector         map[string]string
+	Tolerations          []corev1.Toleration
+	Affinity             *corev1.Affinity
+	PriorityClassName    string
+	Sidecars             []corev1.Container
+	SidecarVolumes       []corev1.Volume
+	Command              []string
+	Args                 []string
+	Env                  map[string]string
+	ResourceEnv          map[string]string
+	Secrets              map[string]string
+	SecretsFrom          []string
+	Context              string
+	ValueFiles           map[string][]string
+	Executable           string
+	Source               *Source
+	Hold                 bool
+	NoCopy               bool
+	Durable              bool
+	PollInterval         time.Duration
+	ReadyTimeout         time.Duration
+	Config               T
+	config               *rest.Config
+	client               *kubernetes.Clientset
+	pod                  *corev1.Pod
 }
 
 func (j *Job[T]) init() error {
@@ -111,47 +206,141 @@ func (j *Job[T]) init() error {
 	return nil
 }
 
-// GetStatus gets the status message and exit code of the given pod
+// GetStatus waits for the job container to terminate and returns its status message and exit code
 func (j *Job[T]) GetStatus(ctx context.Context) (string, int, error) {
-	for {
-		pod, err := j.getPod(ctx)
-		if err != nil {
+	if j.Durable {
+		if err := j.ensurePod(ctx); err != nil {
 			return "", 0, err
-		} else if pod != nil {
-			for _, containerStatus := range pod.Status.ContainerStatuses {
-				if containerStatus.Name == "job" && containerStatus.State.Terminated != nil {
-					return containerStatus.State.Terminated.Message, int(containerStatus.State.Terminated.ExitCode), nil
-				}
-			}
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
+	informer, err := getPodInformer(ctx, j.client, j.Namespace)
+	if err != nil {
+		return "", 0, err
+	}
+	pod, err := informer.await(ctx, j.Namespace, j.ID, func(pod *corev1.Pod) bool {
+		return j.getTerminated(pod) != nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	terminated := j.getTerminated(pod)
+	return terminated.Message, int(terminated.ExitCode), nil
+}
+
+// getTerminated returns the terminated state of the job container in the given pod, or nil if it's not terminated
+// The job container of a durable job is restarted once it terminates, so a termination since the job's pod was
+// looked up is found in the last state of the restarted container.
+func (j *Job[T]) getTerminated(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	status := getJobContainerStatus(pod)
+	if status.State.Terminated != nil {
+		return status.State.Terminated
+	}
+	if j.Durable && j.pod != nil && pod.UID == j.pod.UID && status.RestartCount > getJobContainerStatus(j.pod).RestartCount {
+		return status.LastTerminationState.Terminated
+	}
+	return nil
 }
 
+// getJobContainerState returns the state of the job container in the given pod
+func getJobContainerState(pod *corev1.Pod) corev1.ContainerState {
+	return getJobContainerStatus(pod).State
+}
+
+// getJobContainerStatus returns the status of the job container in the given pod
+func getJobContainerStatus(pod *corev1.Pod) corev1.ContainerStatus {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name == "job" {
+			return containerStatus
+		}
+	}
+	return corev1.ContainerStatus{}
+}
+
+// getPod returns the pod of the job, preferring a pod that's not being deleted, since the pod of a durable job
+// may be replaced while the old pod terminates
 func (j *Job[T]) getPod(ctx context.Context) (*corev1.Pod, error) {
 	pods, err := j.client.CoreV1().Pods(j.Namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: "job=" + j.ID,
 	})
 	if err != nil {
 		return nil, err
-	} else if len(pods.Items) > 0 {
-		for _, pod := range pods.Items {
-			return &pod, nil
+	}
+	var deleting *corev1.Pod
+	for i, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			return &pods.Items[i], nil
+		} else if deleting == nil {
+			deleting = &pods.Items[i]
 		}
 	}
-	return nil, nil
+	return deleting, nil
+}
+
+// ensurePod looks up the job pod if the job was not created by this process, e.g. when resuming a benchmark
+func (j *Job[T]) ensurePod(ctx context.Context) error {
+	if j.pod != nil {
+		return nil
+	}
+	pod, err := j.getPod(ctx)
+	if err != nil {
+		return err
+	} else if pod == nil {
+		return fmt.Errorf("no pod found for job %s", j.ID)
+	}
+	j.pod = pod
+	return nil
 }
 
 func (j *Job[T]) waitForRunning(ctx context.Context, log logging.Logger) error {
 	log.Logf("Waiting for Job to start running...")
-	for {
-		pod, err := j.getPod(ctx)
-		if err != nil {
-			return err
-		} else if pod != nil && len(pod.Status.ContainerStatuses) > 0 && pod.Status.ContainerStatuses[0].State.Running != nil {
-			j.pod = pod
-			return nil
+	start := time.Now()
+	informer, err := getPodInformer(ctx, j.client, j.Namespace)
+	if err != nil {
+		return err
+	}
+	// Jobs that complete before the pod is observed running are also done waiting
+	pod, err := informer.await(ctx, j.Namespace, j.ID, func(pod *corev1.Pod) bool {
+		state := getJobContainerState(pod)
+		return state.Running != nil || state.Terminated != nil
+	})
+	if err != nil {
+		return err
+	}
+	j.pod = pod
+	recordStartTimings(log, pod, start)
+	return nil
+}
+
+// recordStartTimings records the time the job's pod took to be scheduled and to start its containers, which
+// includes pulling their images
+// The time is split using the timestamps of the pod's status, which are only compared with each other, since the
+// cluster's clock may differ from the local clock.
+func recordStartTimings(log logging.Logger, pod *corev1.Pod, start time.Time) {
+	recorder, ok := log.(logging.TimingRecorder)
+	if !ok {
+		return
+	}
+	total := time.Since(start)
+	var scheduled, started time.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			scheduled = condition.LastTransitionTime.Time
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
+	state := getJobContainerState(pod)
+	if state.Running != nil {
+		started = state.Running.StartedAt.Time
+	} else if state.Terminated != nil {
+		started = state.Terminated.StartedAt.Time
+	}
+	if scheduled.IsZero() || started.Before(scheduled) {
+		recorder.Record(logging.Timing{Name: "Starting pod", Duration: total})
+		return
+	}
+	pulling := started.Sub(scheduled)
+	if pulling > total {
+		pulling = total
+	}
+	recorder.Record(logging.Timing{Name: "Scheduling pod", Duration: total - pulling})
+	recorder.Record(logging.Timing{Name: "Pulling images and starting containers", Duration: pulling})
 }
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	releaseNameAnnotation      = "meta.helm.sh/release-name"
+	releaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+	// releaseInstanceLabel is the label identifying the release to which resources created from templates in a
+	// chart, e.g. StatefulSet volume claims, belong
+	releaseInstanceLabel = "app.kubernetes.io/instance"
+	releaseOwnerLabel    = "owner"
+	releaseOwnerValue    = "helm"
+	releaseLabel         = "name"
+	releaseStatusLabel   = "status"
+	uninstalledStatus    = "uninstalled"
+)
+
+// Leak is a resource left behind after a job was torn down
+type Leak struct {
+	Resource
+	// Release is the uninstalled Helm release to which the resource belongs, if any
+	Release string
+}
+
+// String returns the kind and namespaced name of the resource and the release to which it belongs
+func (l Leak) String() string {
+	if l.Release == "" {
+		return l.Resource.String()
+	}
+	return l.Resource.String() + " (release " + l.Release + ")"
+}
+
+// leakKind is a kind of resource scanned for leaks
+type leakKind struct {
+	kind       string
+	namespaced bool
+	list       func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error)
+}
+
+var leakKinds = []leakKind{
+	{kind: "Deployment", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "StatefulSet", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "DaemonSet", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "Job", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "Service", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "ConfigMap", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "Secret", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "ServiceAccount", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "PersistentVolumeClaim", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "RoleBinding", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "Role", namespaced: true, list: func(ctx context.Context, client kubernetes.Interface, namespace string) ([]metav1.ObjectMeta, error) {
+		list, err := client.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "ClusterRoleBinding", list: func(ctx context.Context, client kubernetes.Interface, _ string) ([]metav1.ObjectMeta, error) {
+		list, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "ClusterRole", list: func(ctx context.Context, client kubernetes.Interface, _ string) ([]metav1.ObjectMeta, error) {
+		list, err := client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+	{kind: "Namespace", list: func(ctx context.Context, client kubernetes.Interface, _ string) ([]metav1.ObjectMeta, error) {
+		list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var metas []metav1.ObjectMeta
+		for _, item := range list.Items {
+			metas = append(metas, item.ObjectMeta)
+		}
+		return metas, nil
+	}},
+}
+
+// FindLeaks returns the resources left behind after the job with the given ID was torn down
+// Resources labeled with the job ID and resources belonging to Helm releases in the job's namespace that are no
+// longer installed are reported as leaks. Namespaced resources are only scanned if the namespace was not deleted.
+// Resources that are being deleted or that have an owner, and so are garbage collected with their owner, are ignored.
+func (c *Cleaner) FindLeaks(ctx context.Context, jobID string, namespace string) ([]Leak, error) {
+	scanNamespace := true
+	ns, err := c.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		scanNamespace = false
+	} else if ns.DeletionTimestamp != nil {
+		scanNamespace = false
+	}
+
+	releases := make(map[string]bool)
+	if scanNamespace {
+		releases, err = c.getInstalledReleases(ctx, namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var leaks []Leak
+	for _, kind := range leakKinds {
+		if kind.namespaced && !scanNamespace {
+			continue
+		}
+		listNamespace := metav1.NamespaceAll
+		if kind.namespaced {
+			listNamespace = namespace
+		}
+		metas, err := kind.list(ctx, c.client, listNamespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, meta := range metas {
+			if meta.DeletionTimestamp != nil || len(meta.OwnerReferences) > 0 {
+				continue
+			}
+			resource := Resource{
+				Kind:      kind.kind,
+				Namespace: meta.Namespace,
+				Name:      meta.Name,
+				Job:       meta.Labels[JobLabel],
+				Created:   meta.CreationTimestamp.Time,
+			}
+			if meta.Labels[ManagedByLabel] == ManagedByValue && meta.Labels[JobLabel] == jobID {
+				leaks = append(leaks, Leak{Resource: resource})
+			} else if release := getRelease(meta, namespace); release != "" && !releases[release] {
+				leaks = append(leaks, Leak{Resource: resource, Release: release})
+			}
+		}
+	}
+	return leaks, nil
+}
+
+// getRelease returns the Helm release in the given namespace to which a resource belongs
+func getRelease(meta metav1.ObjectMeta, namespace string) string {
+	if release, ok := meta.Annotations[releaseNameAnnotation]; ok {
+		if meta.Annotations[releaseNamespaceAnnotation] == namespace {
+			return release
+		}
+		return ""
+	}
+	if meta.Namespace == namespace {
+		return meta.Labels[releaseInstanceLabel]
+	}
+	return ""
+}
+
+// getInstalledReleases returns the names of the Helm releases installed in the given namespace
+// Releases are read from the records stored by Helm's Secret and ConfigMap storage drivers.
+func (c *Cleaner) getInstalledReleases(ctx context.Context, namespace string) (map[string]bool, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: releaseOwnerLabel + "=" + releaseOwnerValue,
+	}
+	releases := make(map[string]bool)
+	add := func(labels map[string]string) {
+		if name, ok := labels[releaseLabel]; ok && labels[releaseStatusLabel] != uninstalledStatus {
+			releases[name] = true
+		}
+	}
+	secrets, err := c.client.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets.Items {
+		add(secret.Labels)
+	}
+	configMaps, err := c.client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, configMap := range configMaps.Items {
+		add(configMap.Labels)
+	}
+	return releases, nil
+}
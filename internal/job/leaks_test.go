@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestFindLeaks(t *testing.T) {
+	releaseAnnotations := func(release string) map[string]string {
+		return map[string]string{
+			releaseNameAnnotation:      release,
+			releaseNamespaceAnnotation: "default",
+		}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "default",
+			},
+		},
+		// The installed release's storage record
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sh.helm.release.v1.installed.v1",
+				Namespace: "default",
+				Labels: map[string]string{
+					releaseOwnerLabel:  releaseOwnerValue,
+					releaseLabel:       "installed",
+					releaseStatusLabel: "deployed",
+				},
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "installed",
+				Namespace:   "default",
+				Annotations: releaseAnnotations("installed"),
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "uninstalled",
+				Namespace:   "default",
+				Annotations: releaseAnnotations("uninstalled"),
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "data-uninstalled-0",
+				Namespace: "default",
+				Labels: map[string]string{
+					releaseInstanceLabel: "uninstalled",
+				},
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "uninstalled",
+				Annotations: releaseAnnotations("uninstalled"),
+			},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					releaseNameAnnotation:      "uninstalled",
+					releaseNamespaceAnnotation: "other",
+				},
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+				Labels:    NewLabels("test"),
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-job",
+				Namespace: "default",
+				Labels:    NewLabels("other-job"),
+			},
+		},
+		&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "default",
+				Labels:    NewLabels("test"),
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "Job", Name: "test"},
+				},
+			},
+		},
+	)
+
+	cleaner := &Cleaner{client: client}
+	leaks, err := cleaner.FindLeaks(context.Background(), "test", "default")
+	assert.NoError(t, err)
+	var names []string
+	for _, leak := range leaks {
+		names = append(names, leak.String())
+	}
+	assert.Equal(t, []string{
+		"Deployment default/uninstalled (release uninstalled)",
+		"ConfigMap default/test",
+		"PersistentVolumeClaim default/data-uninstalled-0 (release uninstalled)",
+		"ClusterRole uninstalled (release uninstalled)",
+	}, names)
+
+	// Namespaced resources are not scanned once the namespace has been deleted
+	assert.NoError(t, client.CoreV1().Namespaces().Delete(context.Background(), "default", metav1.DeleteOptions{}))
+	leaks, err = cleaner.FindLeaks(context.Background(), "test", "default")
+	assert.NoError(t, err)
+	assert.Len(t, leaks, 1)
+	assert.Equal(t, "ClusterRole uninstalled (release uninstalled)", leaks[0].String())
+}
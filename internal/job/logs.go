@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	maxReconnectAttempts = 10
+	reconnectInterval    = time.Second
+)
+
+// errRestarted is returned when checking whether the job container has terminated once the pod of a durable job
+// has been replaced or its job container restarted, since the stream would otherwise follow the new container
+var errRestarted = errors.New("job container restarted")
+
+// GetLogs opens a stream of the job's logs
+// If the stream is dropped by the API server before the job container terminates, the stream is reopened
+// from the time of the last line read, so no output is lost or duplicated.
+func (j *Job[T]) GetLogs(ctx context.Context) (io.ReadCloser, error) {
+	return j.GetLogsSince(ctx, time.Time{})
+}
+
+// GetLogsSince opens a stream of the job's logs written after the given time
+// If the time is zero, the stream includes all the job's logs.
+func (j *Job[T]) GetLogsSince(ctx context.Context, since time.Time) (io.ReadCloser, error) {
+	if err := j.init(); err != nil {
+		return nil, err
+	}
+	if err := j.ensurePod(ctx); err != nil {
+		return nil, err
+	}
+	stream := &logStream{
+		ctx:  ctx,
+		open: j.openLogs,
+		done: j.isTerminated,
+		last: since,
+	}
+	if err := stream.connect(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (j *Job[T]) openLogs(ctx context.Context, since *metav1.Time) (io.ReadCloser, error) {
+	req := j.client.CoreV1().Pods(j.Namespace).GetLogs(j.pod.Name, &corev1.PodLogOptions{
+		Container:  "job",
+		Follow:     true,
+		Timestamps: true,
+		SinceTime:  since,
+	})
+	return req.Stream(ctx)
+}
+
+// isTerminated returns whether the job container has terminated
+func (j *Job[T]) isTerminated(ctx context.Context) (bool, error) {
+	pod, err := j.getPod(ctx)
+	if err != nil {
+		return false, err
+	} else if j.Durable && j.isRestarted(pod) {
+		return false, errRestarted
+	} else if pod == nil {
+		return false, fmt.Errorf("pod for job %s not found", j.ID)
+	}
+	return getJobContainerState(pod).Terminated != nil, nil
+}
+
+// logStream is a reader of timestamped container logs that reconnects when the underlying stream is dropped
+type logStream struct {
+	ctx    context.Context
+	open   func(ctx context.Context, since *metav1.Time) (io.ReadCloser, error)
+	done   func(ctx context.Context) (bool, error)
+	stream io.ReadCloser
+	reader *bufio.Reader
+	buf    []byte
+	// last is the timestamp of the last line read, and seen the number of lines read with that timestamp
+	last time.Time
+	seen int
+	// skip is the number of lines with the last timestamp to skip after reconnecting
+	skip int
+	// final indicates the stream was reopened after the container terminated
+	final bool
+}
+
+func (s *logStream) connect() error {
+	var since *metav1.Time
+	if !s.last.IsZero() {
+		since = &metav1.Time{Time: s.last}
+		s.skip = s.seen
+	}
+	stream, err := s.open(s.ctx, since)
+	if err != nil {
+		return err
+	}
+	s.stream = stream
+	s.reader = bufio.NewReader(stream)
+	return nil
+}
+
+// reconnect reopens the stream from the last line read, returning io.EOF once the container has terminated
+// and all its output has been read, or once the pod of a durable job has been restarted
+func (s *logStream) reconnect() error {
+	_ = s.stream.Close()
+	if s.final {
+		return io.EOF
+	}
+	var err error
+	for attempt := 1; attempt <= maxReconnectAttempts; attempt++ {
+		if s.ctx.Err() != nil {
+			return s.ctx.Err()
+		}
+		var done bool
+		if done, err = s.done(s.ctx); errors.Is(err, errRestarted) {
+			return io.EOF
+		} else if err == nil {
+			// Once the container has terminated, reopen the stream one last time to read any output
+			// written after the stream was dropped
+			s.final = done
+			if err = s.connect(); err == nil {
+				return nil
+			}
+		}
+		select {
+		case <-time.After(reconnectInterval):
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		}
+	}
+	return err
+}
+
+func (s *logStream) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		line, err := s.reader.ReadBytes('\n')
+		if err != nil && !s.final {
+			// Discard any partial line from a dropped stream, since it will be read again after reconnecting
+			line = nil
+		}
+		if len(line) > 0 {
+			s.buf = s.parseLine(line)
+		}
+		if err != nil && len(s.buf) == 0 {
+			if err := s.reconnect(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// parseLine strips the timestamp from the given line, returning nil if the line was already read
+func (s *logStream) parseLine(line []byte) []byte {
+	i := bytes.IndexByte(line, ' ')
+	if i < 0 {
+		return line
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, string(line[:i]))
+	if err != nil {
+		return line
+	}
+	line = line[i+1:]
+
+	switch {
+	case timestamp.Before(s.last):
+		return nil
+	case timestamp.Equal(s.last):
+		if s.skip > 0 {
+			s.skip--
+			return nil
+		}
+		s.seen++
+	default:
+		s.last = timestamp
+		s.seen = 1
+		s.skip = 0
+	}
+	return line
+}
+
+func (s *logStream) Close() error {
+	return s.stream.Close()
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strings"
+	"testing"
+)
+
+func TestLogStream(t *testing.T) {
+	const (
+		t1 = "2023-01-01T00:00:01.000000001Z"
+		t2 = "2023-01-01T00:00:02.000000002Z"
+		t3 = "2023-01-01T00:00:03.000000003Z"
+	)
+
+	// The first two streams are dropped while the container is running, and the last stream is
+	// opened after the container terminates
+	streams := []string{
+		t1 + " a\n" + t2 + " b\n" + t2 + " c\n" + t3 + " par",
+		t2 + " b\n" + t2 + " c\n" + t3 + " partial\n" + t3 + " d\n",
+		t3 + " partial\n" + t3 + " d\n" + t3 + " e\n" + "no timestamp\n",
+	}
+	var since []*metav1.Time
+	stream := &logStream{
+		ctx: context.Background(),
+		open: func(ctx context.Context, time *metav1.Time) (io.ReadCloser, error) {
+			since = append(since, time)
+			body := streams[0]
+			streams = streams[1:]
+			return io.NopCloser(strings.NewReader(body)), nil
+		},
+		done: func(ctx context.Context) (bool, error) {
+			return len(streams) == 1, nil
+		},
+	}
+	assert.NoError(t, stream.connect())
+
+	bytes, err := io.ReadAll(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\nc\npartial\nd\ne\nno timestamp\n", string(bytes))
+	assert.Len(t, since, 3)
+	assert.Nil(t, since[0])
+	assert.Equal(t, t2, since[1].UTC().Format("2006-01-02T15:04:05.999999999Z"))
+	assert.Equal(t, t3, since[2].UTC().Format("2006-01-02T15:04:05.999999999Z"))
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Status is the status of a job running in the cluster
+type Status struct {
+	ID        string
+	Namespace string
+	Pod       string
+	Phase     corev1.PodPhase
+	Created   time.Time
+	// Terminated indicates whether the job container has terminated, and ExitCode and Message its exit status
+	Terminated bool
+	ExitCode   int
+	Message    string
+}
+
+// String returns a short description of the job's state
+func (s Status) String() string {
+	if s.Terminated {
+		if s.ExitCode == 0 {
+			return "Succeeded"
+		}
+		return fmt.Sprintf("Failed (exit code %d)", s.ExitCode)
+	}
+	return string(s.Phase)
+}
+
+// NewMonitor returns a new Monitor for jobs running in the cluster
+func NewMonitor() (*Monitor, error) {
+	config, err := k8s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Monitor{
+		client: client,
+	}, nil
+}
+
+// Monitor observes jobs started by other helmit sessions
+type Monitor struct {
+	client kubernetes.Interface
+}
+
+// Find returns the status of the job with the given ID and of the jobs it started, e.g. benchmark workers
+// Jobs are returned in the order in which they were created.
+func (m *Monitor) Find(ctx context.Context, id string) ([]Status, error) {
+	pods, err := m.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: ManagedByLabel + "=" + ManagedByValue + "," + JobLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, pod := range pods.Items {
+		jobID := pod.Labels[JobLabel]
+		if jobID != id && !strings.HasPrefix(jobID, id+"-") {
+			continue
+		}
+		status := Status{
+			ID:        jobID,
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Phase:     pod.Status.Phase,
+			Created:   pod.CreationTimestamp.Time,
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.Name == "job" && containerStatus.State.Terminated != nil {
+				status.Terminated = true
+				status.ExitCode = int(containerStatus.State.Terminated.ExitCode)
+				status.Message = containerStatus.State.Terminated.Message
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Created.Equal(statuses[j].Created) {
+			return statuses[i].ID < statuses[j].ID
+		}
+		return statuses[i].Created.Before(statuses[j].Created)
+	})
+	return statuses, nil
+}
+
+// GetLogs opens a stream of the logs of the given job
+// If follow is true, the stream is read until the job container terminates, reconnecting if the stream is
+// dropped by the API server.
+func (m *Monitor) GetLogs(ctx context.Context, status Status, follow bool) (io.ReadCloser, error) {
+	if !follow {
+		return m.client.CoreV1().Pods(status.Namespace).GetLogs(status.Pod, &corev1.PodLogOptions{
+			Container: "job",
+		}).Stream(ctx)
+	}
+	stream := &logStream{
+		ctx: ctx,
+		open: func(ctx context.Context, since *metav1.Time) (io.ReadCloser, error) {
+			return m.client.CoreV1().Pods(status.Namespace).GetLogs(status.Pod, &corev1.PodLogOptions{
+				Container:  "job",
+				Follow:     true,
+				Timestamps: true,
+				SinceTime:  since,
+			}).Stream(ctx)
+		},
+		done: func(ctx context.Context) (bool, error) {
+			pod, err := m.client.CoreV1().Pods(status.Namespace).Get(ctx, status.Pod, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				if containerStatus.Name == "job" && containerStatus.State.Terminated != nil {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	}
+	if err := stream.connect(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
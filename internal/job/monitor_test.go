@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func newJobPod(id string, created time.Time, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              id + "-abcde",
+			Namespace:         "happy-panda",
+			Labels:            NewLabels(id),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "job",
+					State: state,
+				},
+			},
+		},
+	}
+}
+
+func TestMonitorFind(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	failed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Message: "failed"}}
+	monitor := &Monitor{
+		client: fake.NewSimpleClientset(
+			newJobPod("happy-panda-worker-1", now.Add(time.Second), failed),
+			newJobPod("happy-panda-worker-0", now.Add(time.Second), running),
+			newJobPod("happy-panda", now, running),
+			newJobPod("happy-pandas", now, running),
+		),
+	}
+
+	statuses, err := monitor.Find(context.Background(), "happy-panda")
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3)
+	assert.Equal(t, "happy-panda", statuses[0].ID)
+	assert.Equal(t, "happy-panda-worker-0", statuses[1].ID)
+	assert.Equal(t, "Running", statuses[1].String())
+	assert.Equal(t, "happy-panda-worker-1", statuses[2].ID)
+	assert.True(t, statuses[2].Terminated)
+	assert.Equal(t, "failed", statuses[2].Message)
+	assert.Equal(t, "Failed (exit code 2)", statuses[2].String())
+
+	statuses, err = monitor.Find(context.Background(), "sad-panda")
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+}
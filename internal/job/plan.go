@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const redactedSecret = "<redacted>"
+
+// Plan returns the resources that Create would create for the job without touching the cluster
+// Secret values are redacted in the returned resources.
+func (j *Job[T]) Plan() ([]runtime.Object, error) {
+	var objects []runtime.Object
+	if j.CreateNamespace {
+		namespace := j.newNamespace()
+		namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		objects = append(objects, namespace)
+	}
+
+	if len(j.Rules) == 0 {
+		roleBinding := j.newDefaultClusterRoleBinding()
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"))
+		objects = append(objects, roleBinding)
+	} else if !j.NamespacedRBAC {
+		role := j.newClusterRole()
+		role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"))
+		roleBinding := j.newClusterRoleBinding()
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"))
+		objects = append(objects, role, roleBinding)
+	}
+
+	if j.Durable {
+		service := j.newService(nil)
+		service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+		statefulSet := j.newStatefulSet()
+		statefulSet.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+		objects = append(objects, statefulSet, service)
+	} else {
+		job := j.newJob()
+		job.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+		objects = append(objects, job)
+	}
+
+	if len(j.Rules) > 0 && j.NamespacedRBAC {
+		role := j.newRole(nil)
+		role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("Role"))
+		roleBinding := j.newRoleBinding(nil)
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"))
+		objects = append(objects, role, roleBinding)
+	}
+
+	cm, err := j.newConfigMap(nil)
+	if err != nil {
+		return nil, err
+	}
+	cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	objects = append(objects, cm)
+
+	serviceAccount := j.newServiceAccount(nil)
+	serviceAccount.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ServiceAccount"))
+	objects = append(objects, serviceAccount)
+
+	if len(j.Secrets) > 0 {
+		secret := redactSecret(j.newSecret(nil))
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		objects = append(objects, secret)
+	}
+	return objects, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"fmt"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"strings"
+)
+
+// LoadSecretsFrom loads the keys of the referenced Kubernetes secrets, merged with the given secrets
+// Secrets are referenced in the format [{namespace}/]{name}, defaulting to the given namespace. Keys in the
+// given secrets take precedence over keys in the referenced secrets.
+func LoadSecretsFrom(ctx context.Context, client kubernetes.Interface, namespace string, refs []string, secrets map[string]string) (map[string]string, error) {
+	if len(refs) == 0 {
+		return secrets, nil
+	}
+	merged := make(map[string]string)
+	for _, ref := range refs {
+		secret, err := LoadSecret(ctx, client, namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range secret {
+			merged[key] = value
+		}
+	}
+	for key, value := range secrets {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// loadSecretsFrom merges the keys of the job's referenced secrets into the job's secrets
+func (j *Job[T]) loadSecretsFrom(ctx context.Context) error {
+	secrets, err := LoadSecretsFrom(ctx, j.client, j.Namespace, j.SecretsFrom, j.Secrets)
+	if err != nil {
+		return err
+	}
+	j.Secrets = secrets
+	return nil
+}
+
+// LoadSecret loads the keys of the referenced Kubernetes secret
+// The secret is referenced in the format [{namespace}/]{name}, defaulting to the given namespace.
+func LoadSecret(ctx context.Context, client kubernetes.Interface, namespace string, ref string) (map[string]string, error) {
+	secretNamespace, name := namespace, ref
+	if i := strings.Index(ref, "/"); i != -1 {
+		secretNamespace, name = ref[:i], ref[i+1:]
+	}
+	secret, err := client.CoreV1().Secrets(secretNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("secret %s not found in namespace %s", name, secretNamespace)
+		}
+		return nil, err
+	}
+	keys := make(map[string]string)
+	for key, value := range secret.Data {
+		keys[key] = string(value)
+	}
+	for key, value := range secret.StringData {
+		keys[key] = value
+	}
+	return keys, nil
+}
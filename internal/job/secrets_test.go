@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestLoadSecretsFrom(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db",
+				Namespace: "test",
+			},
+			Data: map[string][]byte{
+				"db_user":     []byte("admin"),
+				"db_password": []byte("secret"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registry",
+				Namespace: "shared",
+			},
+			Data: map[string][]byte{
+				"token": []byte("abc"),
+			},
+		},
+	)
+
+	ctx := context.Background()
+	secrets, err := LoadSecretsFrom(ctx, client, "test", []string{"db", "shared/registry"}, map[string]string{"db_user": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"db_user":     "test",
+		"db_password": "secret",
+		"token":       "abc",
+	}, secrets)
+
+	_, err = LoadSecretsFrom(ctx, client, "test", []string{"registry"}, nil)
+	assert.EqualError(t, err, "secret registry not found in namespace test")
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/logging"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SourceDir is the directory to which job sources are copied to be built inside the job pod
+const SourceDir = "src"
+
+// Source is a Go module from which to build the job binary inside the job pod
+// Building inside the pod requires an image with a Go toolchain.
+type Source struct {
+	// Dir is the local root directory of the module
+	Dir string
+	// Main is the path of the main package relative to Dir
+	// If Main is empty, the source is copied to the pod without being built, e.g. for an Executable to run.
+	Main string
+	// Flags are additional flags to pass to go build
+	Flags []string
+}
+
+// generatedDir is the hidden directory in which helmit generates mains, which must be copied with the source
+const generatedDir = ".helmit"
+
+// isExcludedSource returns whether the given file is excluded when copying a module's source
+// Hidden directories like .git are not needed to build the module and can be large.
+func isExcludedSource(info os.FileInfo) bool {
+	return info.IsDir() && strings.HasPrefix(info.Name(), ".") && info.Name() != generatedDir
+}
+
+func (j *Job[T]) copySource(ctx context.Context, log logging.Logger) error {
+	if j.Source == nil {
+		return nil
+	}
+	if fileInfo, err := os.Stat(j.Source.Dir); err != nil {
+		return err
+	} else if !fileInfo.IsDir() {
+		return fmt.Errorf("%s is not a valid directory", j.Source.Dir)
+	}
+	defer logging.RecordTiming(log, "Copying source", time.Now())
+	return j.retry(ctx, log, func() error {
+		log.Logf("Copying %s to %s", j.Source.Dir, j.pod.Name)
+		return j.copy(ctx, SourceDir, j.Source.Dir, isExcludedSource, log)
+	})
+}
+
+func (j *Job[T]) buildSource(ctx context.Context, log logging.Logger) error {
+	if j.Source == nil || j.Source.Main == "" {
+		return nil
+	}
+	log.Logf("Building %s in %s", j.Source.Main, j.pod.Name)
+	defer logging.RecordTiming(log, "Building source", time.Now())
+	cmd := []string{"go", "-C", path.Join(HomeDir, SourceDir), "build", "-mod=readonly", "-trimpath"}
+	cmd = append(cmd, j.Source.Flags...)
+	cmd = append(cmd, "-o", j.getSourceBinary(), "./"+filepath.ToSlash(filepath.Clean(j.Source.Main)))
+	var output bytes.Buffer
+	if err := j.exec(ctx, cmd, nil, &output, &output); err != nil {
+		return fmt.Errorf("failed to build %s: %w\n%s", j.Source.Main, err, output.String())
+	}
+	return nil
+}
+
+// getSourceBinary returns the path to the binary built from the job's source
+func (j *Job[T]) getSourceBinary() string {
+	return path.Join(HomeDir, j.ID)
+}
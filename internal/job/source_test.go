@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"archive/tar"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourceTar(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/tests\n",
+		"tests/suite.go":        "package tests\n",
+		".helmit/tests/main.go": "package main\n",
+		".git/HEAD":             "ref: refs/heads/master\n",
+		".github/ci.yaml":       "name: ci\n",
+		".golangci.yml":         "run:\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, makeTar(dir, SourceDir, &buf, isExcludedSource))
+
+	var names []string
+	reader := tar.NewReader(&buf)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			names = append(names, header.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"src/go.mod",
+		"src/tests/suite.go",
+		"src/.helmit/tests/main.go",
+		"src/.golangci.yml",
+	}, names)
+
+	size, err := getSize(dir, isExcludedSource)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(files["go.mod"])+len(files["tests/suite.go"])+len(files[".helmit/tests/main.go"])+len(files[".golangci.yml"])), size)
+}
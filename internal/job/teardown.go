@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTeardownGracePeriod is the default time a namespace may take to be deleted before the resources
+	// blocking its deletion are reported, and their finalizers removed if forced
+	DefaultTeardownGracePeriod = 2 * time.Minute
+	teardownPollInterval       = time.Second
+)
+
+// removeFinalizersPatch is the merge patch removing all the finalizers of a resource
+var removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+// BlockingResource is a resource whose finalizers block the deletion of its namespace
+type BlockingResource struct {
+	Resource
+	Finalizers []string
+	resource   schema.GroupVersionResource
+}
+
+// String returns the kind and namespaced name of the resource and its finalizers
+func (r BlockingResource) String() string {
+	return fmt.Sprintf("%s (finalizers: %s)", r.Resource, strings.Join(r.Finalizers, ", "))
+}
+
+// TeardownStatus is the progress of the deletion of a namespace
+type TeardownStatus struct {
+	Namespace string
+	// Elapsed is the time since the deletion of the namespace was requested
+	Elapsed time.Duration
+	// Remaining are the messages of the namespace conditions reporting the content remaining in the namespace
+	Remaining []string
+	// Blocking are the resources in the namespace with finalizers
+	Blocking []BlockingResource
+}
+
+// NamespaceStuckError is returned when a namespace is still being deleted after the teardown grace period, and
+// the finalizers blocking its deletion are not forcibly removed
+type NamespaceStuckError struct {
+	TeardownStatus
+}
+
+func (e *NamespaceStuckError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "namespace %s still terminating after %s", e.Namespace, e.Elapsed.Round(time.Second))
+	for _, remaining := range e.Remaining {
+		fmt.Fprintf(&b, "\n  %s", remaining)
+	}
+	for _, resource := range e.Blocking {
+		fmt.Fprintf(&b, "\n  %s", resource)
+	}
+	return b.String()
+}
+
+// NewNamespaceTeardown returns a new NamespaceTeardown for the given namespace
+// If force is set, the finalizers of the resources blocking the deletion of the namespace are removed once the
+// namespace has been terminating for the grace period.
+func NewNamespaceTeardown(namespace string, force bool, gracePeriod time.Duration) (*NamespaceTeardown, error) {
+	config, err := k8s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return newNamespaceTeardown(client, dynamicClient, namespace, force, gracePeriod), nil
+}
+
+func newNamespaceTeardown(client kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, force bool, gracePeriod time.Duration) *NamespaceTeardown {
+	return &NamespaceTeardown{
+		client:       client,
+		dynamic:      dynamicClient,
+		namespace:    namespace,
+		force:        force,
+		gracePeriod:  gracePeriod,
+		pollInterval: teardownPollInterval,
+	}
+}
+
+// NamespaceTeardown watches the deletion of a namespace
+// The grace period is measured from the namespace's deletion timestamp, so a teardown that's interrupted, e.g.
+// by helmit exiting, resumes where it left off when the namespace is deleted again, e.g. by helmit cleanup.
+type NamespaceTeardown struct {
+	client       kubernetes.Interface
+	dynamic      dynamic.Interface
+	namespace    string
+	force        bool
+	gracePeriod  time.Duration
+	pollInterval time.Duration
+}
+
+// Await waits for the namespace to be deleted, calling progress with the status of the deletion each time the
+// content remaining in the namespace changes
+// If the namespace is still being deleted after the grace period, the finalizers of the blocking resources are
+// removed if the teardown is forced, otherwise a *NamespaceStuckError is returned.
+func (t *NamespaceTeardown) Await(ctx context.Context, progress func(TeardownStatus), log logging.Logger) error {
+	var last string
+	for {
+		namespace, err := t.client.CoreV1().Namespaces().Get(ctx, t.namespace, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if namespace.DeletionTimestamp == nil {
+			return fmt.Errorf("namespace %s is not being deleted", t.namespace)
+		}
+
+		status, err := t.getStatus(ctx, namespace)
+		if err != nil {
+			return err
+		}
+		if key := fmt.Sprint(status.Remaining, status.Blocking); key != last {
+			progress(status)
+			last = key
+		}
+
+		if status.Elapsed >= t.gracePeriod && len(status.Blocking) > 0 {
+			if !t.force {
+				return &NamespaceStuckError{TeardownStatus: status}
+			}
+			if err := t.removeFinalizers(ctx, status.Blocking, log); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-time.After(t.pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// getStatus returns the status of the deletion of the given namespace
+func (t *NamespaceTeardown) getStatus(ctx context.Context, namespace *corev1.Namespace) (TeardownStatus, error) {
+	status := TeardownStatus{
+		Namespace: namespace.Name,
+		Elapsed:   time.Since(namespace.DeletionTimestamp.Time),
+	}
+	for _, condition := range namespace.Status.Conditions {
+		switch condition.Type {
+		case corev1.NamespaceContentRemaining, corev1.NamespaceFinalizersRemaining:
+			if condition.Status == corev1.ConditionTrue {
+				status.Remaining = append(status.Remaining, condition.Message)
+			}
+		}
+	}
+	blocking, err := t.getBlockingResources(ctx)
+	if err != nil {
+		return TeardownStatus{}, err
+	}
+	status.Blocking = blocking
+	return status, nil
+}
+
+// getBlockingResources returns the resources in the namespace that have finalizers
+// All the namespaced resources served by the API are scanned, including custom resources, which are often
+// blocked by the finalizers of operators that have already been deleted.
+func (t *NamespaceTeardown) getBlockingResources(ctx context.Context) ([]BlockingResource, error) {
+	_, lists, err := t.client.Discovery().ServerGroupsAndResources()
+	if err != nil && len(lists) == 0 {
+		return nil, err
+	}
+
+	scanned := make(map[schema.GroupResource]bool)
+	seen := make(map[types.UID]bool)
+	var blocking []BlockingResource
+	for _, list := range lists {
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, apiResource := range list.APIResources {
+			groupResource := groupVersion.WithResource(apiResource.Name).GroupResource()
+			if !apiResource.Namespaced || strings.Contains(apiResource.Name, "/") || scanned[groupResource] ||
+				!hasVerbs(apiResource.Verbs, "list", "patch") {
+				continue
+			}
+			scanned[groupResource] = true
+
+			resource := groupVersion.WithResource(apiResource.Name)
+			objects, err := t.dynamic.Resource(resource).Namespace(t.namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				if k8serrors.IsNotFound(err) || k8serrors.IsForbidden(err) || k8serrors.IsMethodNotSupported(err) {
+					continue
+				}
+				return nil, err
+			}
+			for _, object := range objects.Items {
+				if len(object.GetFinalizers()) == 0 || seen[object.GetUID()] {
+					continue
+				}
+				seen[object.GetUID()] = true
+				blocking = append(blocking, BlockingResource{
+					Resource: Resource{
+						Kind:      apiResource.Kind,
+						Namespace: object.GetNamespace(),
+						Name:      object.GetName(),
+						Job:       object.GetLabels()[JobLabel],
+						Created:   object.GetCreationTimestamp().Time,
+					},
+					Finalizers: object.GetFinalizers(),
+					resource:   resource,
+				})
+			}
+		}
+	}
+	sort.Slice(blocking, func(i, j int) bool {
+		return blocking[i].String() < blocking[j].String()
+	})
+	return blocking, nil
+}
+
+// removeFinalizers removes the finalizers of the given resources
+func (t *NamespaceTeardown) removeFinalizers(ctx context.Context, resources []BlockingResource, log logging.Logger) error {
+	for _, resource := range resources {
+		log.Logf("Removing finalizers %s from %s", strings.Join(resource.Finalizers, ", "), resource.Resource)
+		_, err := t.dynamic.Resource(resource.resource).Namespace(resource.Namespace).
+			Patch(ctx, resource.Name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasVerbs returns whether the given verbs include all the required verbs
+func hasVerbs(verbs metav1.Verbs, required ...string) bool {
+	for _, verb := range required {
+		found := false
+		for _, v := range verbs {
+			if v == verb {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
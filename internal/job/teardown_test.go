@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNamespaceTeardown(t *testing.T) {
+	deleted := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	client := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "happy-panda",
+			DeletionTimestamp: &deleted,
+		},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceTerminating,
+			Conditions: []corev1.NamespaceCondition{
+				{
+					Type:    corev1.NamespaceFinalizersRemaining,
+					Status:  corev1.ConditionTrue,
+					Message: "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances",
+				},
+			},
+		},
+	})
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "list", "patch"}},
+				{Name: "widgets/status", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "patch"}},
+			},
+		},
+	}
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	widget := &unstructured.Unstructured{}
+	widget.SetAPIVersion("example.com/v1")
+	widget.SetKind("Widget")
+	widget.SetNamespace("happy-panda")
+	widget.SetName("foo")
+	widget.SetFinalizers([]string{"example.com/cleanup"})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"}, widget)
+	log := logging.NewLogger(os.Stdout)
+	ctx := context.Background()
+
+	// The namespace is reported stuck after the grace period unless the teardown is forced
+	var statuses []TeardownStatus
+	teardown := newNamespaceTeardown(client, dynamicClient, "happy-panda", false, time.Minute)
+	err := teardown.Await(ctx, func(status TeardownStatus) {
+		statuses = append(statuses, status)
+	}, log)
+	var stuck *NamespaceStuckError
+	if assert.True(t, errors.As(err, &stuck)) {
+		assert.Equal(t, []string{"Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances"}, stuck.Remaining)
+		if assert.Len(t, stuck.Blocking, 1) {
+			assert.Equal(t, "Widget happy-panda/foo (finalizers: example.com/cleanup)", stuck.Blocking[0].String())
+		}
+	}
+	assert.Len(t, statuses, 1)
+
+	// Forced teardowns remove the finalizers of the blocking resources
+	teardown = newNamespaceTeardown(client, dynamicClient, "happy-panda", true, time.Minute)
+	teardown.pollInterval = 10 * time.Millisecond
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	err = teardown.Await(timeoutCtx, func(TeardownStatus) {}, log)
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	object, err := dynamicClient.Resource(widgets).Namespace("happy-panda").Get(ctx, "foo", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, object.GetFinalizers())
+
+	assert.NoError(t, client.CoreV1().Namespaces().Delete(ctx, "happy-panda", metav1.DeleteOptions{}))
+	assert.NoError(t, teardown.Await(ctx, func(TeardownStatus) {}, log))
+}
@@ -0,0 +1,345 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"errors"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"strings"
+)
+
+// WorkflowEngine is a pipeline engine that can run a job as a workflow
+type WorkflowEngine string
+
+const (
+	// ArgoEngine runs jobs as Argo Workflows
+	ArgoEngine WorkflowEngine = "argo"
+	// TektonEngine runs jobs as Tekton TaskRuns
+	TektonEngine WorkflowEngine = "tekton"
+)
+
+const (
+	// argoContainerName is the name Argo gives the main container of a workflow step
+	argoContainerName = "main"
+	// tektonContainerName is the name Tekton gives the container of the job step
+	tektonContainerName = "step-job"
+)
+
+// Workflow returns the resources that run the job as a workflow of the given engine in place of the Job that
+// Create would create
+// Nothing can be copied to the pods of a workflow, so the job must be run with no copy. The keys of the job's
+// referenced secrets are projected into the pod rather than loaded, so they must be in the job's namespace.
+// Secret values are redacted in the returned resources.
+func (j *Job[T]) Workflow(engine WorkflowEngine) ([]runtime.Object, error) {
+	if engine != ArgoEngine && engine != TektonEngine {
+		return nil, fmt.Errorf("unknown workflow engine %s", engine)
+	}
+	if !j.NoCopy {
+		return nil, errors.New("only jobs run with no copy can be run as a workflow")
+	}
+	if j.Durable {
+		return nil, errors.New("durable jobs cannot be run as a workflow")
+	}
+	if err := j.validateSidecars(); err != nil {
+		return nil, err
+	}
+	if err := j.validateNoCopy(); err != nil {
+		return nil, err
+	}
+
+	var objects []runtime.Object
+	if j.CreateNamespace {
+		namespace := j.newNamespace()
+		namespace.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		objects = append(objects, namespace)
+	}
+
+	// The shared default ClusterRoleBinding is updated in place by Create, so workflows get a binding of their own
+	if len(j.Rules) == 0 {
+		roleBinding := j.newClusterRoleBinding()
+		roleBinding.RoleRef.Name = defaultRoleName
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"))
+		objects = append(objects, roleBinding)
+	} else if j.NamespacedRBAC {
+		role := j.newRole(nil)
+		role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("Role"))
+		roleBinding := j.newRoleBinding(nil)
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("RoleBinding"))
+		objects = append(objects, role, roleBinding)
+	} else {
+		role := j.newClusterRole()
+		role.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"))
+		roleBinding := j.newClusterRoleBinding()
+		roleBinding.SetGroupVersionKind(rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"))
+		objects = append(objects, role, roleBinding)
+	}
+
+	if j.ServiceAccount == "" {
+		serviceAccount := j.newServiceAccount(nil)
+		serviceAccount.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ServiceAccount"))
+		objects = append(objects, serviceAccount)
+	}
+
+	cm, err := j.newConfigMap(nil)
+	if err != nil {
+		return nil, err
+	}
+	cm.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	objects = append(objects, cm)
+
+	if len(j.Secrets) > 0 {
+		secret := redactSecret(j.newSecret(nil))
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		objects = append(objects, secret)
+	}
+
+	var workflow *unstructured.Unstructured
+	switch engine {
+	case ArgoEngine:
+		workflow, err = j.newArgoWorkflow()
+	case TektonEngine:
+		workflow, err = j.newTektonTaskRun()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(objects, workflow), nil
+}
+
+// newWorkflowPodTemplate returns the template of the job pod run by a workflow step, in which the job container is
+// renamed to the given name
+func (j *Job[T]) newWorkflowPodTemplate(containerName string) (corev1.PodTemplateSpec, error) {
+	template := j.newPodTemplate(corev1.RestartPolicyNever)
+	container := &template.Spec.Containers[0]
+	container.Name = containerName
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.ResourceFieldRef != nil {
+			env.ValueFrom.ResourceFieldRef.ContainerName = containerName
+		}
+	}
+
+	if len(j.SecretsFrom) == 0 {
+		return template, nil
+	}
+
+	// Referenced secrets are projected into the secrets volume along with the job's own secrets
+	var sources []corev1.VolumeProjection
+	for _, ref := range j.SecretsFrom {
+		if i := strings.Index(ref, "/"); i != -1 {
+			if ref[:i] != j.Namespace {
+				return template, fmt.Errorf("secret %s must be in namespace %s to be used by a workflow", ref, j.Namespace)
+			}
+			ref = ref[i+1:]
+		}
+		sources = append(sources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ref},
+			},
+		})
+	}
+	if len(j.Secrets) > 0 {
+		sources = append(sources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: j.ID},
+			},
+		})
+	}
+
+	volume := corev1.Volume{
+		Name: "secrets",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}
+	if len(j.Secrets) > 0 {
+		for i := range template.Spec.Volumes {
+			if template.Spec.Volumes[i].Name == volume.Name {
+				template.Spec.Volumes[i] = volume
+			}
+		}
+	} else {
+		template.Spec.Volumes = append(template.Spec.Volumes, volume)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: secretsPath,
+			ReadOnly:  true,
+		})
+	}
+	return template, nil
+}
+
+// newArgoWorkflow returns an Argo Workflow running the job pod in a single step
+func (j *Job[T]) newArgoWorkflow() (*unstructured.Unstructured, error) {
+	template, err := j.newWorkflowPodTemplate(argoContainerName)
+	if err != nil {
+		return nil, err
+	}
+	pod := template.Spec
+
+	container, err := toUnstructured(&pod.Containers[0])
+	if err != nil {
+		return nil, err
+	}
+	delete(container, "name")
+	step := map[string]any{
+		"name":      "helmit",
+		"container": container,
+	}
+	if len(pod.Containers) > 1 {
+		sidecars, err := toUnstructuredList(pod.Containers[1:])
+		if err != nil {
+			return nil, err
+		}
+		step["sidecars"] = sidecars
+	}
+
+	podMetadata := map[string]any{
+		"labels": toStringMap(template.Labels),
+	}
+	if len(template.Annotations) > 0 {
+		podMetadata["annotations"] = toStringMap(template.Annotations)
+	}
+	spec := map[string]any{
+		"entrypoint":         "helmit",
+		"serviceAccountName": pod.ServiceAccountName,
+		"podMetadata":        podMetadata,
+		"templates":          []any{step},
+	}
+	if err := setPodSpecFields(spec, pod); err != nil {
+		return nil, err
+	}
+
+	workflow := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	workflow.SetAPIVersion("argoproj.io/v1alpha1")
+	workflow.SetKind("Workflow")
+	j.setWorkflowMeta(workflow)
+	return workflow, nil
+}
+
+// newTektonTaskRun returns a Tekton TaskRun running the job pod in a single step
+func (j *Job[T]) newTektonTaskRun() (*unstructured.Unstructured, error) {
+	template, err := j.newWorkflowPodTemplate(tektonContainerName)
+	if err != nil {
+		return nil, err
+	}
+	pod := template.Spec
+
+	// Tekton prefixes the names of step containers with "step-"
+	step, err := toUnstructured(&pod.Containers[0])
+	if err != nil {
+		return nil, err
+	}
+	step["name"] = strings.TrimPrefix(tektonContainerName, "step-")
+	renameTektonResources(step)
+	taskSpec := map[string]any{
+		"steps": []any{step},
+	}
+	if len(pod.Containers) > 1 {
+		sidecars, err := toUnstructuredList(pod.Containers[1:])
+		if err != nil {
+			return nil, err
+		}
+		for _, sidecar := range sidecars {
+			renameTektonResources(sidecar.(map[string]any))
+		}
+		taskSpec["sidecars"] = sidecars
+	}
+
+	podTemplate := make(map[string]any)
+	if err := setPodSpecFields(podTemplate, pod); err != nil {
+		return nil, err
+	}
+	spec := map[string]any{
+		"serviceAccountName": pod.ServiceAccountName,
+		"taskSpec":           taskSpec,
+		"podTemplate":        podTemplate,
+	}
+
+	taskRun := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	taskRun.SetAPIVersion("tekton.dev/v1")
+	taskRun.SetKind("TaskRun")
+	j.setWorkflowMeta(taskRun)
+	// Tekton propagates the TaskRun's labels and annotations to its pod
+	taskRun.SetLabels(template.Labels)
+	if len(template.Annotations) > 0 {
+		taskRun.SetAnnotations(template.Annotations)
+	}
+	return taskRun, nil
+}
+
+// renameTektonResources renames the resources of the given container to the computeResources of a Tekton step
+// or sidecar
+func renameTektonResources(container map[string]any) {
+	if resources, ok := container["resources"]; ok {
+		container["computeResources"] = resources
+		delete(container, "resources")
+	}
+}
+
+// setWorkflowMeta sets the name, namespace, and labels of the given workflow
+func (j *Job[T]) setWorkflowMeta(workflow *unstructured.Unstructured) {
+	workflow.SetName(j.ID)
+	workflow.SetNamespace(j.Namespace)
+	workflow.SetLabels(NewLabels(j.ID))
+}
+
+// setPodSpecFields sets the scheduling constraints and volumes of the given pod on the given workflow spec
+func setPodSpecFields(spec map[string]any, pod corev1.PodSpec) error {
+	fields, err := toUnstructured(&corev1.PodSpec{
+		NodeSelector:      pod.NodeSelector,
+		Tolerations:       pod.Tolerations,
+		Affinity:          pod.Affinity,
+		PriorityClassName: pod.PriorityClassName,
+		Volumes:           pod.Volumes,
+	})
+	if err != nil {
+		return err
+	}
+	delete(fields, "containers")
+	for key, value := range fields {
+		spec[key] = value
+	}
+	return nil
+}
+
+func toUnstructured(object any) (map[string]any, error) {
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+}
+
+func toUnstructuredList(containers []corev1.Container) ([]any, error) {
+	list := make([]any, 0, len(containers))
+	for i := range containers {
+		object, err := toUnstructured(&containers[i])
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, object)
+	}
+	return list, nil
+}
+
+func toStringMap(m map[string]string) map[string]any {
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
+
+// redactSecret replaces the values of the given secret with a placeholder
+func redactSecret(secret *corev1.Secret) *corev1.Secret {
+	secret.StringData = make(map[string]string)
+	for key := range secret.Data {
+		secret.StringData[key] = redactedSecret
+	}
+	secret.Data = nil
+	return secret
+}
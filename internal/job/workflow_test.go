@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestWorkflow(t *testing.T) {
+	j := &Job[any]{
+		ID:          "test",
+		Namespace:   "test",
+		Image:       "atomix/tests:latest",
+		Command:     []string{"/usr/local/bin/tests"},
+		NoCopy:      true,
+		Secrets:     map[string]string{"token": "abc"},
+		SecretsFrom: []string{"test/creds"},
+		ResourceEnv: map[string]string{"GOMAXPROCS": "limits.cpu"},
+		Sidecars: []corev1.Container{
+			{Name: "proxy", Image: "envoyproxy/envoy:v1.25.0"},
+		},
+	}
+
+	objects, err := j.Workflow(ArgoEngine)
+	require.NoError(t, err)
+	require.Len(t, objects, 5)
+	roleBinding := objects[0].(*rbacv1.ClusterRoleBinding)
+	assert.Equal(t, "test", roleBinding.Name)
+	assert.Equal(t, defaultRoleName, roleBinding.RoleRef.Name)
+	assert.Equal(t, "<redacted>", objects[3].(*corev1.Secret).StringData["token"])
+
+	workflow := objects[4].(*unstructured.Unstructured)
+	assert.Equal(t, "Workflow", workflow.GetKind())
+	assert.Equal(t, "test", workflow.GetNamespace())
+	entrypoint, _, _ := unstructured.NestedString(workflow.Object, "spec", "entrypoint")
+	assert.Equal(t, "helmit", entrypoint)
+	templates, _, _ := unstructured.NestedSlice(workflow.Object, "spec", "templates")
+	require.Len(t, templates, 1)
+	template := templates[0].(map[string]any)
+	image, _, _ := unstructured.NestedString(template, "container", "image")
+	assert.Equal(t, "atomix/tests:latest", image)
+	sidecars, _, _ := unstructured.NestedSlice(template, "sidecars")
+	assert.Len(t, sidecars, 1)
+	env, _, _ := unstructured.NestedSlice(template, "container", "env")
+	containerName, _, _ := unstructured.NestedString(env[0].(map[string]any), "valueFrom", "resourceFieldRef", "containerName")
+	assert.Equal(t, argoContainerName, containerName)
+
+	volumes, _, _ := unstructured.NestedSlice(workflow.Object, "spec", "volumes")
+	require.Len(t, volumes, 2)
+	sources, _, _ := unstructured.NestedSlice(volumes[1].(map[string]any), "projected", "sources")
+	require.Len(t, sources, 2)
+	name, _, _ := unstructured.NestedString(sources[0].(map[string]any), "secret", "name")
+	assert.Equal(t, "creds", name)
+	name, _, _ = unstructured.NestedString(sources[1].(map[string]any), "secret", "name")
+	assert.Equal(t, "test", name)
+
+	objects, err = j.Workflow(TektonEngine)
+	require.NoError(t, err)
+	taskRun := objects[len(objects)-1].(*unstructured.Unstructured)
+	assert.Equal(t, "TaskRun", taskRun.GetKind())
+	steps, _, _ := unstructured.NestedSlice(taskRun.Object, "spec", "taskSpec", "steps")
+	require.Len(t, steps, 1)
+	step := steps[0].(map[string]any)
+	assert.Equal(t, "job", step["name"])
+	assert.NotContains(t, step, "resources")
+	env, _, _ = unstructured.NestedSlice(step, "env")
+	containerName, _, _ = unstructured.NestedString(env[0].(map[string]any), "valueFrom", "resourceFieldRef", "containerName")
+	assert.Equal(t, tektonContainerName, containerName)
+	volumes, _, _ = unstructured.NestedSlice(taskRun.Object, "spec", "podTemplate", "volumes")
+	assert.Len(t, volumes, 2)
+
+	j.SecretsFrom = []string{"other/creds"}
+	_, err = j.Workflow(ArgoEngine)
+	assert.Error(t, err)
+	j.SecretsFrom = nil
+
+	j.Rules = []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}
+	j.NamespacedRBAC = true
+	objects, err = j.Workflow(ArgoEngine)
+	require.NoError(t, err)
+	assert.IsType(t, &rbacv1.Role{}, objects[0])
+	assert.IsType(t, &rbacv1.RoleBinding{}, objects[1])
+
+	j.NoCopy = false
+	_, err = j.Workflow(ArgoEngine)
+	assert.Error(t, err)
+}
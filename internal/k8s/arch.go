@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+)
+
+// ArchLabel is the well-known label identifying the CPU architecture of a node
+const ArchLabel = "kubernetes.io/arch"
+
+// DetectArch returns the CPU architecture of the nodes in the configured cluster
+func DetectArch(ctx context.Context) (string, error) {
+	config, err := GetConfig()
+	if err != nil {
+		return "", err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	return GetNodeArch(ctx, client)
+}
+
+// GetNodeArch returns the CPU architecture of the nodes in the cluster
+// An error is returned if the architecture cannot be determined or the nodes have different architectures.
+func GetNodeArch(ctx context.Context, client kubernetes.Interface) (string, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to detect the cluster architecture: %w", err)
+	}
+	archs := make(map[string]bool)
+	for _, node := range nodes.Items {
+		if arch, ok := node.Labels[ArchLabel]; ok {
+			archs[arch] = true
+		}
+	}
+	switch len(archs) {
+	case 0:
+		return "", errors.New("failed to detect the cluster architecture: no nodes are labeled with " + ArchLabel)
+	case 1:
+		for arch := range archs {
+			return arch, nil
+		}
+	}
+	names := make([]string, 0, len(archs))
+	for arch := range archs {
+		names = append(names, arch)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("cluster nodes have multiple architectures (%s)", strings.Join(names, ", "))
+}
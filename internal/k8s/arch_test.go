@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func newNode(name string, arch string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				ArchLabel: arch,
+			},
+		},
+	}
+}
+
+func TestGetNodeArch(t *testing.T) {
+	ctx := context.Background()
+	arch, err := GetNodeArch(ctx, fake.NewSimpleClientset(newNode("foo", "arm64"), newNode("bar", "arm64")))
+	assert.NoError(t, err)
+	assert.Equal(t, "arm64", arch)
+
+	_, err = GetNodeArch(ctx, fake.NewSimpleClientset(newNode("foo", "arm64"), newNode("bar", "amd64")))
+	assert.EqualError(t, err, "cluster nodes have multiple architectures (amd64, arm64)")
+
+	_, err = GetNodeArch(ctx, fake.NewSimpleClientset())
+	assert.Error(t, err)
+}
@@ -5,20 +5,158 @@
 package k8s
 
 import (
+	"os"
+	"strconv"
+
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+const (
+	// KubeconfigEnv is the environment variable from which the kubeconfig file selected by the user is read
+	KubeconfigEnv = "HELMIT_KUBECONFIG"
+	// ContextEnv is the environment variable from which the kubeconfig context selected by the user is read
+	ContextEnv = "HELMIT_KUBE_CONTEXT"
+	// QPSEnv is the environment variable from which the client-side rate limit for API requests is read
+	QPSEnv = "HELMIT_KUBE_QPS"
+	// BurstEnv is the environment variable from which the client-side burst limit for API requests is read
+	BurstEnv = "HELMIT_KUBE_BURST"
+	// PluginNamespaceEnv is the environment variable in which kubectl passes the current namespace to plugins
+	PluginNamespaceEnv = "KUBECTL_PLUGINS_CURRENT_NAMESPACE"
+)
+
+// SetConfig selects the kubeconfig file and context to use in place of the default configuration
+// The selection is stored in the environment so it's inherited by local processes started by the CLI.
+func SetConfig(kubeconfig, context string) error {
+	if kubeconfig != "" {
+		if err := os.Setenv(KubeconfigEnv, kubeconfig); err != nil {
+			return err
+		}
+	}
+	if context != "" {
+		if err := os.Setenv(ContextEnv, context); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRateLimits sets the client-side rate and burst limits for requests to the Kubernetes API server
+// Zero values leave the client defaults in place. Like the kubeconfig selection, the limits are stored in the
+// environment, from which they're also passed to job pods by GetRateLimitEnv.
+func SetRateLimits(qps float32, burst int) error {
+	if qps > 0 {
+		if err := os.Setenv(QPSEnv, strconv.FormatFloat(float64(qps), 'f', -1, 32)); err != nil {
+			return err
+		}
+	}
+	if burst > 0 {
+		if err := os.Setenv(BurstEnv, strconv.Itoa(burst)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRateLimitEnv returns the environment variables configuring the client-side rate limits, if set
+func GetRateLimitEnv() map[string]string {
+	env := make(map[string]string)
+	for _, name := range []string{QPSEnv, BurstEnv} {
+		if value := os.Getenv(name); value != "" {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// GetBurst returns the client-side burst limit for API requests, or zero if not set
+func GetBurst() int {
+	burst, _ := strconv.Atoi(os.Getenv(BurstEnv))
+	return burst
+}
+
 // GetConfig returns the Kubernetes REST API configuration
+// If a kubeconfig file or context was selected, the in-cluster configuration is ignored.
 func GetConfig() (*rest.Config, error) {
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		return config, nil
+	config, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+	if qps, err := strconv.ParseFloat(os.Getenv(QPSEnv), 32); err == nil && qps > 0 {
+		config.QPS = float32(qps)
+	}
+	if burst := GetBurst(); burst > 0 {
+		config.Burst = burst
 	}
+	return config, nil
+}
+
+func getConfig() (*rest.Config, error) {
+	if !isConfigSelected() {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+	}
+	return getClientConfig().ClientConfig()
+}
+
+// ClusterInfo describes the cluster targeted by the Kubernetes configuration
+type ClusterInfo struct {
+	// Context is the name of the kubeconfig context, or empty if the in-cluster configuration is used
+	Context string
+	// Server is the address of the cluster's API server
+	Server string
+}
+
+// GetClusterInfo returns the cluster targeted by the Kubernetes configuration
+func GetClusterInfo() (ClusterInfo, error) {
+	if !isConfigSelected() {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return ClusterInfo{Server: config.Host}, nil
+		}
+	}
+	clientConfig := getClientConfig()
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return ClusterInfo{}, err
+	}
+	info := ClusterInfo{
+		Context: os.Getenv(ContextEnv),
+		Server:  config.Host,
+	}
+	if info.Context == "" {
+		rawConfig, err := clientConfig.RawConfig()
+		if err != nil {
+			return ClusterInfo{}, err
+		}
+		info.Context = rawConfig.CurrentContext
+	}
+	return info, nil
+}
+
+// GetNamespace returns the namespace kubectl would use when none is given on the command line
+// The namespace passed to plugins by kubectl takes precedence over the namespace of the selected kubeconfig
+// context, which defaults to the default namespace.
+func GetNamespace() (string, error) {
+	if namespace := os.Getenv(PluginNamespaceEnv); namespace != "" {
+		return namespace, nil
+	}
+	namespace, _, err := getClientConfig().Namespace()
+	return namespace, err
+}
+
+func isConfigSelected() bool {
+	return os.Getenv(KubeconfigEnv) != "" || os.Getenv(ContextEnv) != ""
+}
 
-	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{},
+func getClientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = os.Getenv(KubeconfigEnv)
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{
+			CurrentContext: os.Getenv(ContextEnv),
+		},
 	)
-	return kubeconfig.ClientConfig()
 }
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://10.0.0.1:6443
+- name: production
+  cluster:
+    server: https://10.0.0.2:6443
+contexts:
+- name: staging
+  context:
+    cluster: staging
+- name: production
+  context:
+    cluster: production
+    namespace: atomix
+current-context: staging
+`
+
+func TestGetConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(path, []byte(testKubeconfig), 0600))
+	t.Setenv(KubeconfigEnv, "")
+	t.Setenv(ContextEnv, "")
+	t.Setenv(QPSEnv, "")
+	t.Setenv(BurstEnv, "")
+
+	assert.NoError(t, SetConfig(path, ""))
+	info, err := GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, ClusterInfo{Context: "staging", Server: "https://10.0.0.1:6443"}, info)
+
+	assert.NoError(t, SetConfig("", "production"))
+	info, err = GetClusterInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, ClusterInfo{Context: "production", Server: "https://10.0.0.2:6443"}, info)
+
+	config, err := GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.2:6443", config.Host)
+	assert.Equal(t, float32(0), config.QPS)
+	assert.Equal(t, 0, config.Burst)
+	assert.Empty(t, GetRateLimitEnv())
+
+	assert.NoError(t, SetRateLimits(20.5, 50))
+	config, err = GetConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, float32(20.5), config.QPS)
+	assert.Equal(t, 50, config.Burst)
+	assert.Equal(t, map[string]string{QPSEnv: "20.5", BurstEnv: "50"}, GetRateLimitEnv())
+}
+
+func TestGetNamespace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(path, []byte(testKubeconfig), 0600))
+	t.Setenv(KubeconfigEnv, "")
+	t.Setenv(ContextEnv, "")
+	t.Setenv(PluginNamespaceEnv, "")
+
+	assert.NoError(t, SetConfig(path, ""))
+	namespace, err := GetNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, "default", namespace)
+
+	assert.NoError(t, SetConfig("", "production"))
+	namespace, err = GetNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, "atomix", namespace)
+
+	t.Setenv(PluginNamespaceEnv, "onos")
+	namespace, err = GetNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, "onos", namespace)
+}
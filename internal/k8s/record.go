@@ -0,0 +1,249 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// RecordEnv is the environment variable naming a directory to which suites record their Kubernetes API
+	// interactions
+	RecordEnv = "HELMIT_RECORD"
+	// ReplayEnv is the environment variable naming a directory from which suites replay recorded Kubernetes API
+	// interactions instead of connecting to a cluster
+	ReplayEnv = "HELMIT_REPLAY"
+	// interactionsFile is the file in the recording directory to which interactions are written
+	interactionsFile = "interactions.jsonl"
+	// replayHost is the address of the API server to which requests are made when replaying interactions
+	replayHost = "http://helmit-replay"
+)
+
+// GetSuiteConfig returns the Kubernetes REST API configuration for the clients used by suites
+// If HELMIT_RECORD is set, the API interactions of the clients are recorded to the given directory. If
+// HELMIT_REPLAY is set, interactions are replayed from the given recording directory and no cluster is required.
+func GetSuiteConfig() (*rest.Config, error) {
+	if dir := os.Getenv(ReplayEnv); dir != "" {
+		transport, err := getReplayTransport(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &rest.Config{
+			Host:      replayHost,
+			Transport: transport,
+		}, nil
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if dir := os.Getenv(RecordEnv); dir != "" {
+		recording, err := getRecording(dir)
+		if err != nil {
+			return nil, err
+		}
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &recordingTransport{
+				transport: rt,
+				recording: recording,
+			}
+		})
+	}
+	return config, nil
+}
+
+// interaction is a recorded API request and its response
+type interaction struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// recordings and replays are shared by all clients in the process, keyed by directory
+var (
+	recordings = make(map[string]*recording)
+	replays    = make(map[string]*replayTransport)
+	recordMu   sync.Mutex
+)
+
+func getRecording(dir string) (*recording, error) {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	if recording, ok := recordings[dir]; ok {
+		return recording, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(filepath.Join(dir, interactionsFile))
+	if err != nil {
+		return nil, err
+	}
+	recording := &recording{
+		file: file,
+	}
+	recordings[dir] = recording
+	return recording, nil
+}
+
+// recording writes API interactions to the recording file
+type recording struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+func (r *recording) add(i interaction) {
+	bytes, err := json.Marshal(i)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.file.Write(append(bytes, '\n'))
+}
+
+// recordingTransport records the requests made through the wrapped transport and their responses
+type recordingTransport struct {
+	transport http.RoundTripper
+	recording *recording
+}
+
+func (t *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	// The interaction is recorded once the body is closed so streaming responses, e.g. watches, are not blocked
+	response.Body = &recordingBody{
+		ReadCloser: response.Body,
+		done: func(body []byte) {
+			t.recording.add(interaction{
+				Method:      request.Method,
+				URL:         request.URL.RequestURI(),
+				Status:      response.StatusCode,
+				ContentType: response.Header.Get("Content-Type"),
+				Body:        body,
+			})
+		},
+	}
+	return response, nil
+}
+
+// recordingBody is a response body that passes the bytes read from it to a function once it's closed
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func([]byte)
+	once sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.once.Do(func() {
+		b.done(b.buf.Bytes())
+	})
+	return b.ReadCloser.Close()
+}
+
+func getReplayTransport(dir string) (*replayTransport, error) {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	if replay, ok := replays[dir]; ok {
+		return replay, nil
+	}
+	file, err := os.Open(filepath.Join(dir, interactionsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recorded interactions: %w", err)
+	}
+	defer file.Close()
+
+	replay := &replayTransport{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var i interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded interactions: %w", err)
+		}
+		replay.interactions = append(replay.interactions, i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	replay.replayed = make([]bool, len(replay.interactions))
+	replays[dir] = replay
+	return replay, nil
+}
+
+// replayTransport responds to requests with recorded interactions
+// Each request is answered with the first interaction with the same method and URL that has not been replayed.
+// Once all matching interactions have been replayed, the last one is repeated, so polling loops that run more
+// iterations than were recorded still complete.
+type replayTransport struct {
+	interactions []interaction
+	replayed     []bool
+	mu           sync.Mutex
+}
+
+func (t *replayTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Body != nil {
+		_ = request.Body.Close()
+	}
+	url := request.URL.RequestURI()
+
+	t.mu.Lock()
+	match := -1
+	for i, interaction := range t.interactions {
+		if interaction.Method != request.Method || interaction.URL != url {
+			continue
+		}
+		match = i
+		if !t.replayed[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		t.replayed[match] = true
+	}
+	t.mu.Unlock()
+
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded interaction for %s %s", request.Method, url)
+	}
+	interaction := t.interactions[match]
+	header := make(http.Header)
+	if interaction.ContentType != "" {
+		header.Set("Content-Type", interaction.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(interaction.Body)),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       request,
+	}, nil
+}
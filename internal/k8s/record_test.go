@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/default/configmaps/foo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Data: map[string]string{
+				"foo": "bar",
+			},
+		})
+	}))
+	defer server.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: `+server.URL+`
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`), 0600))
+	dir := t.TempDir()
+	t.Setenv(KubeconfigEnv, kubeconfig)
+	t.Setenv(ContextEnv, "")
+	t.Setenv(RecordEnv, dir)
+	t.Setenv(ReplayEnv, "")
+
+	getConfigMap := func() (*corev1.ConfigMap, error) {
+		config, err := GetSuiteConfig()
+		assert.NoError(t, err)
+		client, err := kubernetes.NewForConfig(config)
+		assert.NoError(t, err)
+		return client.CoreV1().ConfigMaps("default").Get(context.Background(), "foo", metav1.GetOptions{})
+	}
+
+	configMap, err := getConfigMap()
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", configMap.Data["foo"])
+
+	// Replay the recorded interactions without the server
+	server.Close()
+	t.Setenv(RecordEnv, "")
+	t.Setenv(ReplayEnv, dir)
+	for i := 0; i < 2; i++ {
+		configMap, err = getConfigMap()
+		assert.NoError(t, err)
+		assert.Equal(t, "bar", configMap.Data["foo"])
+	}
+
+	config, err := GetSuiteConfig()
+	assert.NoError(t, err)
+	client, err := kubernetes.NewForConfig(config)
+	assert.NoError(t, err)
+	_, err = client.CoreV1().ConfigMaps("default").Get(context.Background(), "bar", metav1.GetOptions{})
+	assert.ErrorContains(t, err, "no recorded interaction for GET /api/v1/namespaces/default/configmaps/bar")
+}
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	"io"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const configFile = "config.json"
+
+// Process runs a job executable as a local process against the current Kubernetes configuration
+type Process[T any] struct {
+	ID                   string
+	Namespace            string
+	CreateNamespace      bool
+	DeleteNamespace      bool
+	NamespaceLabels      map[string]string
+	NamespaceAnnotations map[string]string
+	Executable           string
+	Context              string
+	Secrets              map[string]string
+	SecretsFrom          []string
+	Config               T
+}
+
+// Run runs the process, writing its output to the given writer and returning its exit code
+func (p *Process[T]) Run(ctx context.Context, output io.Writer, log logging.Logger) (int, error) {
+	config, err := k8s.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return 0, err
+	}
+
+	secrets, err := job.LoadSecretsFrom(ctx, client, p.Namespace, p.SecretsFrom, p.Secrets)
+	if err != nil {
+		return 0, err
+	}
+	p.Secrets = secrets
+
+	if p.CreateNamespace {
+		if err := p.createNamespace(ctx, client, log); err != nil {
+			return 0, err
+		}
+	}
+	if p.DeleteNamespace {
+		defer func() {
+			_ = p.deleteNamespace(context.Background(), client, log)
+		}()
+	}
+
+	dir, err := os.MkdirTemp("", p.ID)
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	configPath := filepath.Join(dir, "config")
+	if err := p.writeConfig(configPath, log); err != nil {
+		return 0, err
+	}
+	secretsPath := filepath.Join(dir, "secrets")
+	if err := p.writeSecrets(secretsPath, log); err != nil {
+		return 0, err
+	}
+
+	log.Logf("Running %s", p.Executable)
+	cmd := exec.CommandContext(ctx, p.Executable)
+	cmd.Dir = p.Context
+	cmd.Env = append(os.Environ(),
+		job.IDEnv+"="+p.ID,
+		job.ConfigPathEnv+"="+configPath,
+		job.SecretsPathEnv+"="+secretsPath)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 0, err
+	}
+	return 0, nil
+}
+
+// writeConfig writes the process configuration to the given directory
+func (p *Process[T]) writeConfig(path string, log logging.Logger) error {
+	log.Logf("Writing configuration to %s", path)
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(p.Config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(path, configFile), bytes, 0600)
+}
+
+// writeSecrets writes the process secrets to files in the given directory
+func (p *Process[T]) writeSecrets(path string, log logging.Logger) error {
+	log.Logf("Writing secrets to %s", path)
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return err
+	}
+	for name, value := range p.Secrets {
+		if err := os.WriteFile(filepath.Join(path, name), []byte(value), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Process[T]) createNamespace(ctx context.Context, client kubernetes.Interface, log logging.Logger) error {
+	namespace := job.NewNamespace(p.ID, p.Namespace, p.NamespaceLabels, p.NamespaceAnnotations)
+	log.Logf("Creating Namespace %s", namespace.Name)
+	if _, err := client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Process[T]) deleteNamespace(ctx context.Context, client kubernetes.Interface, log logging.Logger) error {
+	log.Logf("Deleting Namespace %s", p.Namespace)
+	if err := client.CoreV1().Namespaces().Delete(ctx, p.Namespace, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// Level is a console output verbosity level
+type Level int
+
+const (
+	// QuietLevel outputs only final results and errors
+	QuietLevel Level = iota - 1
+	// InfoLevel outputs task progress and results
+	InfoLevel
+	// VerboseLevel additionally outputs worker logs
+	VerboseLevel
+	// DebugLevel additionally outputs Kubernetes API operations
+	DebugLevel
+)
+
+const levelEnv = "HELMIT_LOG_LEVEL"
+
+// GetLevel returns the current output level
+func GetLevel() Level {
+	level, err := strconv.Atoi(os.Getenv(levelEnv))
+	if err != nil {
+		return InfoLevel
+	}
+	return Level(level)
+}
+
+// SetLevel sets the current output level
+func SetLevel(level Level) {
+	_ = os.Setenv(levelEnv, strconv.Itoa(int(level)))
+}
+
+// Enabled returns whether output at the level is enabled
+func (l Level) Enabled() bool {
+	return GetLevel() >= l
+}
+
+// NewWriter returns an io.Writer that writes to the given writer only when the given level is enabled
+func NewWriter(writer io.Writer, level Level) io.Writer {
+	return &levelWriter{
+		writer: writer,
+		level:  level,
+	}
+}
+
+type levelWriter struct {
+	writer io.Writer
+	level  Level
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	if !w.level.Enabled() {
+		return len(p), nil
+	}
+	return w.writer.Write(p)
+}
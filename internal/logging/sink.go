@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Sink is a destination for raw job output
+type Sink interface {
+	io.Closer
+	// Write writes a line of output from the given job to the sink
+	Write(job string, line string) error
+}
+
+// NewConsoleSink returns a Sink that writes indented job output to the given writer when the level is enabled
+func NewConsoleSink(writer io.Writer, level Level) Sink {
+	return &consoleSink{
+		writer: NewWriter(writer, level),
+	}
+}
+
+type consoleSink struct {
+	writer io.Writer
+}
+
+func (s *consoleSink) Write(job string, line string) error {
+	_, err := fmt.Fprintf(s.writer, "    %s\n", line)
+	return err
+}
+
+func (s *consoleSink) Close() error {
+	return nil
+}
+
+// NewFileSink returns a Sink that writes all job output to the given file, prefixed with the job ID
+func NewFileSink(path string) (Sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{
+		file: file,
+	}, nil
+}
+
+type fileSink struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+func (s *fileSink) Write(job string, line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintf(s.file, "%s %s\n", job, line)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// NewTeeSink returns a Sink that writes job output to all the given sinks
+func NewTeeSink(sinks ...Sink) Sink {
+	return teeSink(sinks)
+}
+
+type teeSink []Sink
+
+func (s teeSink) Write(job string, line string) error {
+	var err error
+	for _, sink := range s {
+		if e := sink.Write(job, line); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (s teeSink) Close() error {
+	var err error
+	for _, sink := range s {
+		if e := sink.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// NewSinkWriter returns an io.WriteCloser that writes each line of output from the given job to the sink
+// Closing the writer writes any final line not terminated by a newline; the sink itself is not closed.
+func NewSinkWriter(job string, sink Sink) io.WriteCloser {
+	return &sinkWriter{
+		job:  job,
+		sink: sink,
+	}
+}
+
+type sinkWriter struct {
+	job  string
+	sink Sink
+	buf  []byte
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if err := w.sink.Write(w.job, line); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *sinkWriter) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := string(w.buf)
+	w.buf = nil
+	return w.sink.Write(w.job, line)
+}
@@ -7,12 +7,16 @@ package logging
 import (
 	"fmt"
 	"github.com/fatih/color"
+	"io"
 	"os"
+	"sync"
 	"time"
 )
 
 var (
-	writer       = os.Stdout
+	writer       io.Writer = os.Stdout
+	recorder     io.Writer
+	writerMu     sync.RWMutex
 	runningColor = color.New(color.FgBlue)
 	successColor = color.New(color.FgGreen)
 	failureColor = color.New(color.FgRed, color.Bold)
@@ -25,29 +29,62 @@ const (
 	failureIcon = "✗"
 )
 
-const verboseEnv = "VERBOSE_LOGGING"
-
 // GetVerbose returns whether verbose logging is enabled
 func GetVerbose() bool {
-	verbose := os.Getenv(verboseEnv)
-	return verbose != ""
+	return VerboseLevel.Enabled()
 }
 
 // SetVerbose sets verbose logging
 func SetVerbose(verbose bool) {
 	if verbose {
-		_ = os.Setenv(verboseEnv, "true")
+		SetLevel(VerboseLevel)
 	} else {
-		_ = os.Unsetenv(verboseEnv)
+		SetLevel(InfoLevel)
 	}
 }
 
+// SetWriter sets the writer to which steps are logged, returning the previous writer
+// The writer may be replaced while steps are being logged, e.g. by the interactive benchmark UI.
+func SetWriter(w io.Writer) io.Writer {
+	writerMu.Lock()
+	defer writerMu.Unlock()
+	prev := writer
+	writer = w
+	return prev
+}
+
+// SetRecorder sets a writer to which steps are also logged, regardless of the writer set with SetWriter
+// Setting a nil recorder stops recording steps.
+func SetRecorder(w io.Writer) {
+	writerMu.Lock()
+	defer writerMu.Unlock()
+	recorder = w
+}
+
+// Print writes previously formatted step output to the writer to which steps are logged
+func Print(text string) {
+	writerMu.RLock()
+	w := writer
+	writerMu.RUnlock()
+	fmt.Fprint(w, text)
+}
+
+// getWriter returns the writer to which steps are logged, including the recorder if set
+func getWriter() io.Writer {
+	writerMu.RLock()
+	defer writerMu.RUnlock()
+	if recorder != nil {
+		return io.MultiWriter(writer, recorder)
+	}
+	return writer
+}
+
 // NewStep returns a new step
 func NewStep(job, name string, args ...interface{}) *Step {
 	return &Step{
 		job:     job,
 		message: fmt.Sprintf(name, args...),
-		verbose: GetVerbose(),
+		level:   GetLevel(),
 	}
 }
 
@@ -55,35 +92,70 @@ func NewStep(job, name string, args ...interface{}) *Step {
 type Step struct {
 	job     string
 	message string
-	verbose bool
+	level   Level
+	start   time.Time
+	steps   []Timing
+	mu      sync.Mutex
 }
 
 // Log logs a progress message
 func (s *Step) Log(message string) {
-	if s.verbose {
-		fmt.Fprintf(writer, "  %s %s %s\n", time.Now().Format(time.RFC3339), s.job, message)
+	if s.level >= DebugLevel {
+		fmt.Fprintf(getWriter(), "  %s %s %s\n", time.Now().Format(time.RFC3339), s.job, message)
 	}
 }
 
 // Logf logs a progress message
 func (s *Step) Logf(message string, args ...interface{}) {
-	if s.verbose {
-		fmt.Fprintf(writer, "  %s %s %s\n", time.Now().Format(time.RFC3339), s.job, fmt.Sprintf(message, args...))
+	if s.level >= DebugLevel {
+		fmt.Fprintf(getWriter(), "  %s %s %s\n", time.Now().Format(time.RFC3339), s.job, fmt.Sprintf(message, args...))
 	}
 }
 
+// Record records the timing of a sub-step of the step
+func (s *Step) Record(timing Timing) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, timing)
+}
+
 // Start starts the step
 func (s *Step) Start() {
-	runningColor.Fprintf(writer, "%s %s %s %s...\n", startIcon, time.Now().Format(time.RFC3339), s.job, s.message)
+	s.start = time.Now()
+	if s.level < InfoLevel {
+		return
+	}
+	runningColor.Fprintf(getWriter(), "%s %s %s %s...\n", startIcon, time.Now().Format(time.RFC3339), s.job, s.message)
 }
 
 // Complete completes the step
 func (s *Step) Complete() {
-	successColor.Fprintf(writer, "%s %s %s %s\n", successIcon, time.Now().Format(time.RFC3339), s.job, s.message)
+	s.recordTiming()
+	if s.level < InfoLevel {
+		return
+	}
+	successColor.Fprintf(getWriter(), "%s %s %s %s\n", successIcon, time.Now().Format(time.RFC3339), s.job, s.message)
 }
 
 // Fail fails the step with the given error
 func (s *Step) Fail(err error) {
-	failureColor.Fprintf(writer, "%s %s %s %s\n", failureIcon, time.Now().Format(time.RFC3339), s.job, s.message)
-	errorColor.Fprintf(writer, "  %s\n", err.Error())
+	s.recordTiming()
+	failureColor.Fprintf(getWriter(), "%s %s %s %s\n", failureIcon, time.Now().Format(time.RFC3339), s.job, s.message)
+	errorColor.Fprintf(getWriter(), "  %s\n", err.Error())
+}
+
+// recordTiming adds the timing of the step to the timings set with SetTimings, if any
+// Steps are only timed once, so a step that's failed after it's completed is not counted twice.
+func (s *Step) recordTiming() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timings == nil || s.start.IsZero() {
+		return
+	}
+	timings.record(Timing{
+		Name:     s.message,
+		Duration: time.Since(s.start),
+		Steps:    s.steps,
+	})
+	s.start = time.Time{}
 }
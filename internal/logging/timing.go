@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+var timings *Timings
+
+// Timing is the time spent in a step and each of its sub-steps
+type Timing struct {
+	Name     string
+	Duration time.Duration
+	Steps    []Timing
+}
+
+// TimingRecorder is a Logger that records the timing of the sub-steps of the step it logs
+type TimingRecorder interface {
+	Record(timing Timing)
+}
+
+// NewTimings returns a new record of step timings
+func NewTimings() *Timings {
+	return &Timings{}
+}
+
+// Timings records the timing of each step completed while it's set with SetTimings
+type Timings struct {
+	timings []Timing
+	mu      sync.Mutex
+}
+
+// Get returns the timings of the steps completed so far, in the order they completed
+func (t *Timings) Get() []Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timings := make([]Timing, len(t.timings))
+	copy(timings, t.timings)
+	return timings
+}
+
+func (t *Timings) record(timing Timing) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timings = append(t.timings, timing)
+}
+
+// SetTimings sets the record to which the timing of each completed or failed step is added
+// Setting nil timings stops recording step timings.
+func SetTimings(t *Timings) {
+	timings = t
+}
+
+// RecordTiming records the time since the given start of a sub-step with the given logger, if it records timings
+func RecordTiming(log Logger, name string, start time.Time) {
+	if recorder, ok := log.(TimingRecorder); ok {
+		recorder.Record(Timing{
+			Name:     name,
+			Duration: time.Since(start),
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package match
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// New creates a new Matcher for the given go test -run style patterns
+func New(patterns ...string) (*Matcher, error) {
+	var filters [][]*regexp.Regexp
+	for _, pattern := range patterns {
+		var filter []*regexp.Regexp
+		for _, elem := range split(pattern) {
+			re, err := regexp.Compile(elem)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+			}
+			filter = append(filter, re)
+		}
+		filters = append(filters, filter)
+	}
+	return &Matcher{
+		filters: filters,
+	}, nil
+}
+
+// Validate validates the given go test -run style patterns
+func Validate(patterns ...string) error {
+	_, err := New(patterns...)
+	return err
+}
+
+// Matcher matches slash-separated names against go test -run style patterns.
+// Each pattern is split into slash-separated elements, each of which is a regular
+// expression matched against the name at the corresponding level. Names nested
+// deeper than the elements of a pattern match the pattern, and names that match
+// all available elements of a longer pattern match as well so that parents of
+// matching subtests are run.
+type Matcher struct {
+	filters [][]*regexp.Regexp
+}
+
+// Match returns whether the given names match any of the patterns
+func (m *Matcher) Match(names ...string) bool {
+	if len(m.filters) == 0 {
+		return true
+	}
+	for _, filter := range m.filters {
+		if matchFilter(filter, names) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchFilter(filter []*regexp.Regexp, names []string) bool {
+	for i, name := range names {
+		if i >= len(filter) {
+			break
+		}
+		if !filter[i].MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// split splits the given pattern on slashes that are not escaped or
+// enclosed in brackets or parentheses, mirroring the go test -run flag
+func split(pattern string) []string {
+	var elems []string
+	var cs, cp int
+	var escaped bool
+	start := 0
+	for i := 0; i < len(pattern); i++ {
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch pattern[i] {
+		case '\\':
+			escaped = true
+		case '[':
+			cs++
+		case ']':
+			if cs > 0 {
+				cs--
+			}
+		case '(':
+			if cs == 0 {
+				cp++
+			}
+		case ')':
+			if cs == 0 && cp > 0 {
+				cp--
+			}
+		case '/':
+			if cs == 0 && cp == 0 {
+				elems = append(elems, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(elems, pattern[start:])
+}
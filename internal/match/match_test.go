@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package match
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []string{"Foo"}, split("Foo"))
+	assert.Equal(t, []string{"Foo", "Bar"}, split("Foo/Bar"))
+	assert.Equal(t, []string{"Foo", "", "Baz"}, split("Foo//Baz"))
+	assert.Equal(t, []string{"Foo", "[/]Bar"}, split("Foo/[/]Bar"))
+	assert.Equal(t, []string{"(Foo/Bar)", "Baz"}, split("(Foo/Bar)/Baz"))
+	assert.Equal(t, []string{`Foo\/Bar`, "Baz"}, split(`Foo\/Bar/Baz`))
+}
+
+func TestMatcher(t *testing.T) {
+	m, err := New()
+	assert.NoError(t, err)
+	assert.True(t, m.Match("FooSuite"))
+	assert.True(t, m.Match("FooSuite", "TestFoo"))
+
+	m, err = New("Suite$")
+	assert.NoError(t, err)
+	assert.True(t, m.Match("FooSuite"))
+	assert.True(t, m.Match("FooSuite", "TestFoo"))
+	assert.False(t, m.Match("Foo"))
+
+	m, err = New("Suite$/^TestFoo$")
+	assert.NoError(t, err)
+	assert.True(t, m.Match("FooSuite"))
+	assert.True(t, m.Match("FooSuite", "TestFoo"))
+	assert.True(t, m.Match("FooSuite", "TestFoo", "Bar"))
+	assert.False(t, m.Match("FooSuite", "TestBar"))
+	assert.False(t, m.Match("Foo", "TestFoo"))
+
+	m, err = New("FooSuite//Bar", "BarSuite/TestBar")
+	assert.NoError(t, err)
+	assert.True(t, m.Match("FooSuite", "TestFoo", "Bar"))
+	assert.True(t, m.Match("FooSuite", "TestBar", "Bar"))
+	assert.False(t, m.Match("FooSuite", "TestBar", "Baz"))
+	assert.True(t, m.Match("BarSuite", "TestBar", "Baz"))
+	assert.False(t, m.Match("BarSuite", "TestFoo"))
+
+	m, err = New("Suite$/TestFoo|TestBar")
+	assert.NoError(t, err)
+	assert.True(t, m.Match("FooSuite", "TestFoo"))
+	assert.True(t, m.Match("FooSuite", "TestBar"))
+	assert.False(t, m.Match("FooSuite", "TestBaz"))
+
+	_, err = New("Foo(")
+	assert.Error(t, err)
+	assert.Error(t, Validate("Foo/[Bar"))
+	assert.NoError(t, Validate("Foo/Bar"))
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package match
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// NewTags creates a new TagSelector for the given terms, e.g. "smoke" or "!slow"
+func NewTags(terms ...string) (*TagSelector, error) {
+	selector := &TagSelector{}
+	for _, term := range terms {
+		tag := strings.TrimPrefix(strings.TrimSpace(term), "!")
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag selector %q", term)
+		}
+		if strings.HasPrefix(strings.TrimSpace(term), "!") {
+			selector.exclude = append(selector.exclude, tag)
+		} else {
+			selector.include = append(selector.include, tag)
+		}
+	}
+	return selector, nil
+}
+
+// ValidateTags validates the given tag selector terms
+func ValidateTags(terms ...string) error {
+	_, err := NewTags(terms...)
+	return err
+}
+
+// TagSelector selects tagged tests by their tags.
+// Tags prefixed with '!' exclude the tests that have them, and the remaining tags include
+// the tests that have any of them. Tests are selected when they are not excluded and, if
+// any tags are included, they have at least one of the included tags.
+type TagSelector struct {
+	include []string
+	exclude []string
+}
+
+// Match returns whether a test with the given tags is selected
+func (s *TagSelector) Match(tags ...string) bool {
+	has := make(map[string]bool)
+	for _, tag := range tags {
+		has[tag] = true
+	}
+	for _, tag := range s.exclude {
+		if has[tag] {
+			return false
+		}
+	}
+	if len(s.include) == 0 {
+		return true
+	}
+	for _, tag := range s.include {
+		if has[tag] {
+			return true
+		}
+	}
+	return false
+}
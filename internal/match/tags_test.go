@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package match
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTagSelector(t *testing.T) {
+	s, err := NewTags()
+	assert.NoError(t, err)
+	assert.True(t, s.Match())
+	assert.True(t, s.Match("slow"))
+
+	s, err = NewTags("smoke", "!slow")
+	assert.NoError(t, err)
+	assert.True(t, s.Match("smoke"))
+	assert.True(t, s.Match("smoke", "raft"))
+	assert.False(t, s.Match("smoke", "slow"))
+	assert.False(t, s.Match("raft"))
+	assert.False(t, s.Match())
+
+	s, err = NewTags("smoke", "regression")
+	assert.NoError(t, err)
+	assert.True(t, s.Match("smoke"))
+	assert.True(t, s.Match("regression"))
+	assert.False(t, s.Match("nightly"))
+
+	s, err = NewTags("!slow")
+	assert.NoError(t, err)
+	assert.True(t, s.Match())
+	assert.True(t, s.Match("smoke"))
+	assert.False(t, s.Match("slow"))
+
+	assert.Error(t, ValidateTags("!"))
+	assert.Error(t, ValidateTags(""))
+	assert.Error(t, ValidateTags("smoke test"))
+	assert.NoError(t, ValidateTags("smoke", "!nightly-1.2"))
+}
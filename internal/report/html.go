@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	chartWidth   = 640
+	chartHeight  = 200
+	chartPadding = 40
+)
+
+const htmlStyle = `
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr.total { font-weight: bold; }
+.PASS { color: #1a7f37; } .FAIL { color: #cf222e; } .SKIP { color: #9a6700; }
+.error { color: #cf222e; }
+.chart { display: inline-block; margin-right: 1em; }
+.chart polyline { fill: none; stroke: #0969da; stroke-width: 2; }
+.chart line { stroke: #8c959f; }
+.chart text { font-size: 11px; fill: #57606a; }
+`
+
+var testTemplate = template.Must(template.New("tests").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Test Report: {{ .ID }}</title><style>` + htmlStyle + `</style></head>
+<body>
+<h1>Test Report: {{ .ID }}</h1>
+<p><strong>{{ len .Results }} tests</strong>, {{ .Count "PASS" }} passed, {{ .Count "FAIL" }} failed, {{ .Count "SKIP" }} skipped in {{ round .Duration }}</p>
+{{- with .Failures }}
+<h2>Failures</h2>
+<ul>
+{{- range . }}
+<li><code>{{ .Name }}</code>: {{ .Failure }}</li>
+{{- end }}
+</ul>
+{{- end }}
+<h2>Results</h2>
+<table>
+<tr><th>Test</th><th>Status</th><th>Duration</th></tr>
+{{- range .Results }}
+<tr><td><code>{{ .Name }}</code></td><td class="{{ .Status }}">{{ .Status }}</td><td>{{ .Duration }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+var benchmarkTemplate = template.Must(template.New("benchmark").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Benchmark Report: {{ .ID }}</title><style>` + htmlStyle + `</style></head>
+<body>
+<h1>Benchmark Report: {{ .ID }}</h1>
+<p>Ran <code>{{ .Benchmark }}</code> from suite <code>{{ .Suite }}</code> in {{ round .Duration }}</p>
+{{- range .Runs }}
+{{- if .Name }}
+<h2>{{ .Name }}</h2>
+{{- end }}
+{{- if .Error }}
+<p class="error"><strong>Failed:</strong> {{ .Error }}</p>
+{{- end }}
+{{- if .History }}
+<div>{{ throughputChart .History }}{{ latencyChart .History }}</div>
+{{- end }}
+{{- if .Workers }}
+<table>
+<tr><th>Worker</th><th>Iterations</th><th>Errors</th><th>Throughput</th><th>Mean Latency</th><th>Median Latency</th><th>95% Latency</th><th>99% Latency</th></tr>
+{{- range .Workers }}
+<tr><td>{{ .Worker }}</td><td>{{ .Iterations }}</td><td>{{ .Errors }}</td><td>{{ throughput .Report }}</td><td>{{ .MeanLatency }}</td><td>{{ .P50Latency }}</td><td>{{ .P95Latency }}</td><td>{{ .P99Latency }}</td></tr>
+{{- end }}
+{{- with .Total }}
+<tr class="total"><td>Total</td><td>{{ .Iterations }}</td><td>{{ .Errors }}</td><td>{{ throughput . }}</td><td>{{ .MeanLatency }}</td><td>{{ .P50Latency }}</td><td>{{ .P95Latency }}</td><td>{{ .P99Latency }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- with .StalledWorkers }}
+<p><strong>Stalled workers:</strong></p>
+<ul>
+{{- range . }}
+<li>Worker {{ .Worker }} stalled {{ .Stalls }} time(s) and was restarted {{ .Restarts }} time(s)</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}
+{{- with .Metadata }}
+{{- if not .IsEmpty }}
+<h2>Metadata</h2>
+<table>
+{{- with .HelmitVersion }}
+<tr><th>Helmit Version</th><td><code>{{ . }}</code></td></tr>
+{{- end }}
+{{- with .GitCommit }}
+<tr><th>Git Commit</th><td><code>{{ . }}</code></td></tr>
+{{- end }}
+{{- with .KubernetesVersion }}
+<tr><th>Kubernetes Version</th><td><code>{{ . }}</code></td></tr>
+{{- end }}
+{{- range .Nodes }}
+<tr><th>Nodes</th><td>{{ .String }}</td></tr>
+{{- end }}
+{{- range .Charts }}
+<tr><th>Chart</th><td>{{ .String }}</td></tr>
+{{- end }}
+{{- with .Args }}
+<tr><th>Arguments</th><td><code>{{ join . " " }}</code></td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Millisecond)
+	},
+	"throughput": func(report benchmark.Report) string {
+		return fmt.Sprintf("%.2f/sec", Throughput(report))
+	},
+	"throughputChart": func(history []BenchmarkSample) template.HTML {
+		values := make([]float64, len(history))
+		for i, sample := range history {
+			values[i] = sample.Throughput
+		}
+		return lineChart("Throughput", history, values, func(value float64) string {
+			return fmt.Sprintf("%.0f/sec", value)
+		})
+	},
+	"latencyChart": func(history []BenchmarkSample) template.HTML {
+		values := make([]float64, len(history))
+		for i, sample := range history {
+			values[i] = float64(sample.P99Latency)
+		}
+		return lineChart("99% Latency", history, values, func(value float64) string {
+			return time.Duration(value).Round(time.Microsecond).String()
+		})
+	},
+}
+
+// htmlRenderer renders reports as standalone HTML pages
+type htmlRenderer struct{}
+
+func (r *htmlRenderer) RenderTests(out io.Writer, report TestReport) error {
+	return testTemplate.Execute(out, report)
+}
+
+func (r *htmlRenderer) RenderBenchmark(out io.Writer, report BenchmarkReport) error {
+	return benchmarkTemplate.Execute(out, report)
+}
+
+// lineChart renders an SVG line chart of the given values over the elapsed time of the samples
+// The chart is built from numbers and the formatted axis labels only, which are escaped.
+func lineChart(title string, history []BenchmarkSample, values []float64, format func(float64) string) template.HTML {
+	var maxValue float64
+	for _, value := range values {
+		if value > maxValue {
+			maxValue = value
+		}
+	}
+	maxElapsed := history[len(history)-1].Elapsed
+	if maxValue == 0 {
+		maxValue = 1
+	}
+	if maxElapsed == 0 {
+		maxElapsed = time.Second
+	}
+
+	plotWidth := float64(chartWidth - 2*chartPadding)
+	plotHeight := float64(chartHeight - 2*chartPadding)
+	points := make([]string, len(values))
+	for i, value := range values {
+		x := chartPadding + float64(history[i].Elapsed)/float64(maxElapsed)*plotWidth
+		y := chartPadding + plotHeight - value/maxValue*plotHeight
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg class="chart" width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">`, chartWidth, chartHeight)
+	fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`, chartPadding, chartPadding/2, template.HTMLEscapeString(title))
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`, chartPadding, chartPadding, chartPadding, chartHeight-chartPadding)
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`, chartPadding, chartHeight-chartPadding, chartWidth-chartPadding, chartHeight-chartPadding)
+	fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`, chartPadding+4, chartPadding+12, template.HTMLEscapeString(format(maxValue)))
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`, chartWidth-chartPadding, chartHeight-chartPadding/2,
+		template.HTMLEscapeString(maxElapsed.Round(time.Second).String()))
+	fmt.Fprintf(&svg, `<polyline points="%s"/>`, strings.Join(points, " "))
+	svg.WriteString(`</svg>`)
+	return template.HTML(svg.String())
+}
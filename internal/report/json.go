@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonRenderer serializes reports as indented JSON
+type jsonRenderer struct{}
+
+func (r *jsonRenderer) RenderTests(out io.Writer, report TestReport) error {
+	return writeJSON(out, report)
+}
+
+func (r *jsonRenderer) RenderBenchmark(out io.Writer, report BenchmarkReport) error {
+	return writeJSON(out, report)
+}
+
+func writeJSON(out io.Writer, v any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSlowestTests is the maximum number of tests listed by duration in a test report
+const maxSlowestTests = 10
+
+var statusIcons = map[string]string{
+	TestPassed:  "✅",
+	TestFailed:  "❌",
+	TestSkipped: "⏭️",
+}
+
+// markdownRenderer renders reports as GitHub flavored Markdown
+type markdownRenderer struct{}
+
+func (r *markdownRenderer) RenderTests(out io.Writer, report TestReport) error {
+	writer := bufio.NewWriter(out)
+	fmt.Fprintf(writer, "# Test Report: %s\n\n", report.ID)
+	fmt.Fprintf(writer, "**%d tests**, %d passed, %d failed, %d skipped in %s\n\n", len(report.Results),
+		report.Count(TestPassed), report.Count(TestFailed), report.Count(TestSkipped), report.Duration.Round(time.Millisecond))
+
+	if failures := report.Failures(); len(failures) > 0 {
+		fmt.Fprintln(writer, "## Failures")
+		fmt.Fprintln(writer)
+		for _, result := range failures {
+			fmt.Fprintf(writer, "- `%s`: %s\n", result.Name, escapeMarkdown(result.Failure))
+		}
+		fmt.Fprintln(writer)
+	}
+
+	fmt.Fprintln(writer, "## Slowest Tests")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "| Test | Status | Duration |")
+	fmt.Fprintln(writer, "| --- | --- | ---: |")
+	for _, result := range getSlowestTests(report.Results) {
+		fmt.Fprintf(writer, "| `%s` | %s %s | %s |\n", result.Name, statusIcons[result.Status], result.Status, result.Duration)
+	}
+	return writer.Flush()
+}
+
+func (r *markdownRenderer) RenderBenchmark(out io.Writer, report BenchmarkReport) error {
+	writer := bufio.NewWriter(out)
+	fmt.Fprintf(writer, "# Benchmark Report: %s\n\n", report.ID)
+	fmt.Fprintf(writer, "Ran `%s` from suite `%s` in %s\n\n", report.Benchmark, report.Suite, report.Duration.Round(time.Second))
+
+	for _, run := range report.Runs {
+		if run.Name != "" {
+			fmt.Fprintf(writer, "## %s\n\n", escapeMarkdown(run.Name))
+		}
+		if run.Error != "" {
+			fmt.Fprintf(writer, "> **Failed:** %s\n\n", escapeMarkdown(run.Error))
+		}
+		if len(run.Workers) == 0 {
+			continue
+		}
+		fmt.Fprintln(writer, "| Worker | Iterations | Errors | Throughput | Mean Latency | Median Latency | 95% Latency | 99% Latency |")
+		fmt.Fprintln(writer, "| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: |")
+		for _, worker := range run.Workers {
+			writeMarkdownRow(writer, fmt.Sprint(worker.Worker), worker.Report.Iterations, worker.Report.Errors, Throughput(worker.Report),
+				worker.MeanLatency, worker.P50Latency, worker.P95Latency, worker.P99Latency)
+		}
+		writeMarkdownRow(writer, "**Total**", run.Total.Iterations, run.Total.Errors, Throughput(run.Total),
+			run.Total.MeanLatency, run.Total.P50Latency, run.Total.P95Latency, run.Total.P99Latency)
+		fmt.Fprintln(writer)
+		if stalled := run.StalledWorkers(); len(stalled) > 0 {
+			fmt.Fprintln(writer, "**Stalled workers:**")
+			fmt.Fprintln(writer)
+			for _, worker := range stalled {
+				fmt.Fprintf(writer, "- Worker %d stalled %d time(s) and was restarted %d time(s)\n", worker.Worker, worker.Stalls, worker.Restarts)
+			}
+			fmt.Fprintln(writer)
+		}
+	}
+	writeMarkdownMetadata(writer, report.Metadata)
+	return writer.Flush()
+}
+
+func writeMarkdownMetadata(writer io.Writer, metadata Metadata) {
+	if metadata.IsEmpty() {
+		return
+	}
+	fmt.Fprintln(writer, "## Metadata")
+	fmt.Fprintln(writer)
+	writeMarkdownField(writer, "Helmit version", metadata.HelmitVersion)
+	writeMarkdownField(writer, "Git commit", metadata.GitCommit)
+	writeMarkdownField(writer, "Kubernetes version", metadata.KubernetesVersion)
+	if len(metadata.Nodes) > 0 {
+		fmt.Fprintln(writer, "- **Nodes:**")
+		for _, nodes := range metadata.Nodes {
+			fmt.Fprintf(writer, "  - %s\n", escapeMarkdown(nodes.String()))
+		}
+	}
+	if len(metadata.Charts) > 0 {
+		fmt.Fprintln(writer, "- **Charts:**")
+		for _, chart := range metadata.Charts {
+			fmt.Fprintf(writer, "  - %s\n", escapeMarkdown(chart.String()))
+		}
+	}
+	if len(metadata.Args) > 0 {
+		fmt.Fprintf(writer, "- **Arguments:** `%s`\n", strings.Join(metadata.Args, " "))
+	}
+	fmt.Fprintln(writer)
+}
+
+func writeMarkdownField(writer io.Writer, name string, value string) {
+	if value != "" {
+		fmt.Fprintf(writer, "- **%s:** `%s`\n", name, value)
+	}
+}
+
+func writeMarkdownRow(writer io.Writer, name string, iterations, errors int, throughput float64, latencies ...time.Duration) {
+	fmt.Fprintf(writer, "| %s | %d | %d | %.2f/sec |", name, iterations, errors, throughput)
+	for _, latency := range latencies {
+		fmt.Fprintf(writer, " %s |", latency)
+	}
+	fmt.Fprintln(writer)
+}
+
+// escapeMarkdown escapes characters that would break a Markdown table or list item
+func escapeMarkdown(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// getSlowestTests returns the slowest of the given test results in order of duration
+func getSlowestTests(results []TestResult) []TestResult {
+	slowest := make([]TestResult, len(results))
+	copy(slowest, results)
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+	if len(slowest) > maxSlowestTests {
+		slowest = slowest[:maxSlowestTests]
+	}
+	return slowest
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"io"
+	"time"
+)
+
+// Format is the format in which a report is rendered
+type Format string
+
+const (
+	// MarkdownFormat renders reports as Markdown, e.g. for posting in pull request comments
+	MarkdownFormat Format = "md"
+	// HTMLFormat renders reports as standalone HTML pages with charts
+	HTMLFormat Format = "html"
+	// JSONFormat serializes reports as JSON, e.g. for storing the history of benchmark results
+	JSONFormat Format = "json"
+)
+
+// Formats are the supported report formats
+var Formats = []Format{MarkdownFormat, HTMLFormat, JSONFormat}
+
+// Renderer renders test and benchmark reports
+type Renderer interface {
+	// RenderTests renders a report of the given test results
+	RenderTests(out io.Writer, report TestReport) error
+	// RenderBenchmark renders a report of the given benchmark results
+	RenderBenchmark(out io.Writer, report BenchmarkReport) error
+}
+
+// NewRenderer returns a Renderer for the given format
+func NewRenderer(format Format) (Renderer, error) {
+	switch format {
+	case MarkdownFormat:
+		return &markdownRenderer{}, nil
+	case HTMLFormat:
+		return &htmlRenderer{}, nil
+	case JSONFormat:
+		return &jsonRenderer{}, nil
+	}
+	return nil, fmt.Errorf("unknown report format %q", format)
+}
+
+// TestReport is a summary of the results of a test run
+type TestReport struct {
+	ID string `json:"id"`
+	// Start is the time at which the tests were started
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Results  []TestResult  `json:"results,omitempty"`
+	// Timings are the times spent in each step of the run
+	Timings []Timing `json:"timings,omitempty"`
+}
+
+// Timing is the time spent in a step of a run, broken down into the time spent in each of its sub-steps
+type Timing struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Steps    []Timing      `json:"steps,omitempty"`
+}
+
+// Count returns the number of tests with the given status
+func (r TestReport) Count(status string) int {
+	var count int
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Failures returns the results of the failed tests
+func (r TestReport) Failures() []TestResult {
+	var failures []TestResult
+	for _, result := range r.Results {
+		if result.Status == TestFailed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+const (
+	// TestPassed is the status of a test that passed
+	TestPassed = "PASS"
+	// TestFailed is the status of a test that failed
+	TestFailed = "FAIL"
+	// TestSkipped is the status of a test that was skipped
+	TestSkipped = "SKIP"
+)
+
+// TestResult is the result of a single test
+type TestResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	// Failure is a digest of the failure message of a failed test
+	Failure string `json:"failure,omitempty"`
+}
+
+// BenchmarkReport is a summary of the results of a benchmark run
+type BenchmarkReport struct {
+	ID        string `json:"id"`
+	Suite     string `json:"suite"`
+	Benchmark string `json:"benchmark"`
+	// Start is the time at which the benchmark was started
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// Runs are the results of each run of the benchmark, one per combination of matrix parameters
+	Runs []BenchmarkRun `json:"runs,omitempty"`
+	// Metadata describes the environment in which the benchmark was run
+	Metadata Metadata `json:"metadata"`
+}
+
+// Metadata describes the environment in which a benchmark was run, so its results can be compared with the
+// results of runs in the same environment
+// Metadata that could not be determined is left empty.
+type Metadata struct {
+	HelmitVersion string `json:"helmitVersion,omitempty"`
+	// GitCommit is the commit of the benchmark package, suffixed with "-dirty" if the working tree has changes
+	GitCommit         string      `json:"gitCommit,omitempty"`
+	KubernetesVersion string      `json:"kubernetesVersion,omitempty"`
+	Nodes             []NodeGroup `json:"nodes,omitempty"`
+	// Charts are the Helm charts deployed in the benchmark's namespaces when the benchmark completed
+	Charts []Chart `json:"charts,omitempty"`
+	// Args are the package paths and flags with which the benchmark was run, with secret values redacted
+	Args []string `json:"args,omitempty"`
+}
+
+// IsEmpty returns whether no metadata was determined
+func (m Metadata) IsEmpty() bool {
+	return m.HelmitVersion == "" && m.GitCommit == "" && m.KubernetesVersion == "" &&
+		len(m.Nodes) == 0 && len(m.Charts) == 0 && len(m.Args) == 0
+}
+
+// NodeGroup is a group of cluster nodes of the same type
+type NodeGroup struct {
+	InstanceType string `json:"instanceType,omitempty"`
+	Arch         string `json:"arch,omitempty"`
+	CPU          string `json:"cpu,omitempty"`
+	Memory       string `json:"memory,omitempty"`
+	Count        int    `json:"count"`
+}
+
+// String returns the number of nodes and their type, e.g. "3 x m5.large (amd64, 2 CPU, 8Gi memory)"
+func (g NodeGroup) String() string {
+	instanceType := g.InstanceType
+	if instanceType == "" {
+		instanceType = "unknown"
+	}
+	return fmt.Sprintf("%d x %s (%s, %s CPU, %s memory)", g.Count, instanceType, g.Arch, g.CPU, g.Memory)
+}
+
+// Chart is a Helm chart deployed by a release
+type Chart struct {
+	Namespace  string `json:"namespace"`
+	Release    string `json:"release"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion,omitempty"`
+}
+
+// String returns the release and the chart it deploys, e.g. "default/my-map (atomix-1.2.0, app 1.2.0)"
+func (c Chart) String() string {
+	if c.AppVersion == "" {
+		return fmt.Sprintf("%s/%s (%s-%s)", c.Namespace, c.Release, c.Name, c.Version)
+	}
+	return fmt.Sprintf("%s/%s (%s-%s, app %s)", c.Namespace, c.Release, c.Name, c.Version, c.AppVersion)
+}
+
+// BenchmarkRun is the result of a single run of a benchmark
+type BenchmarkRun struct {
+	// Name identifies the run, e.g. by its matrix parameters, and is empty for a benchmark run once
+	Name    string            `json:"name,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Workers []BenchmarkWorker `json:"workers,omitempty"`
+	Total   benchmark.Report  `json:"total"`
+	// History is the total throughput and latency across workers over the course of the run
+	History []BenchmarkSample `json:"history,omitempty"`
+}
+
+// BenchmarkWorker is the final report of a single benchmark worker
+type BenchmarkWorker struct {
+	benchmark.Report
+	Worker int `json:"worker"`
+	// Stalls is the number of times the worker completed no iterations for the configured number of intervals
+	Stalls int `json:"stalls,omitempty"`
+	// Restarts is the number of times the worker was restarted after stalling
+	Restarts int `json:"restarts,omitempty"`
+}
+
+// StalledWorkers returns the workers that stalled during the run
+func (r BenchmarkRun) StalledWorkers() []BenchmarkWorker {
+	var workers []BenchmarkWorker
+	for _, worker := range r.Workers {
+		if worker.Stalls > 0 {
+			workers = append(workers, worker)
+		}
+	}
+	return workers
+}
+
+// BenchmarkSample is the total throughput and latency across workers at a point in a benchmark run
+type BenchmarkSample struct {
+	Elapsed    time.Duration `json:"elapsed"`
+	Throughput float64       `json:"throughput"`
+	P99Latency time.Duration `json:"p99Latency"`
+}
+
+// Throughput returns the number of iterations per second in the given report
+func Throughput(report benchmark.Report) float64 {
+	if report.Duration == 0 {
+		return 0
+	}
+	return float64(report.Iterations) / report.Duration.Seconds()
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testReport = TestReport{
+	ID:       "happy-panda",
+	Duration: 90 * time.Second,
+	Results: []TestResult{
+		{Name: "TestSuite/TestPut", Status: TestPassed, Duration: time.Second},
+		{Name: "TestSuite/TestGet", Status: TestFailed, Duration: 2 * time.Second, Failure: "expected 1 | got <2>"},
+		{Name: "TestSuite/TestWatch", Status: TestSkipped},
+	},
+}
+
+var benchmarkReport = BenchmarkReport{
+	ID:        "happy-panda",
+	Suite:     "MapBenchmarkSuite",
+	Benchmark: "BenchmarkPut",
+	Duration:  time.Minute,
+	Runs: []BenchmarkRun{
+		{
+			Name: "payloadSize=128",
+			Workers: []BenchmarkWorker{
+				{Worker: 0, Report: benchmark.Report{Iterations: 1000, Duration: time.Second, MeanLatency: time.Millisecond}},
+				{Worker: 1, Stalls: 2, Restarts: 1},
+			},
+			Total: benchmark.Report{Iterations: 1000, Duration: time.Second, MeanLatency: time.Millisecond},
+			History: []BenchmarkSample{
+				{Elapsed: 5 * time.Second, Throughput: 900, P99Latency: 2 * time.Millisecond},
+				{Elapsed: 10 * time.Second, Throughput: 1000, P99Latency: 3 * time.Millisecond},
+			},
+		},
+		{
+			Name:  "payloadSize=1024",
+			Error: "benchmark error rate exceeded",
+		},
+	},
+	Metadata: Metadata{
+		HelmitVersion:     "v1.2.0",
+		GitCommit:         "0123abc-dirty",
+		KubernetesVersion: "v1.26.1",
+		Nodes:             []NodeGroup{{InstanceType: "m5.large", Arch: "amd64", CPU: "2", Memory: "8Gi", Count: 3}},
+		Charts:            []Chart{{Namespace: "default", Release: "my-map", Name: "atomix", Version: "1.2.0", AppVersion: "1.2.0"}},
+		Args:              []string{"./cmd/benchmarks", "--workers=2", "--set-secret=redis.password=<redacted>"},
+	},
+}
+
+func TestNewRenderer(t *testing.T) {
+	for _, format := range Formats {
+		_, err := NewRenderer(format)
+		assert.NoError(t, err)
+	}
+	_, err := NewRenderer("pdf")
+	assert.Error(t, err)
+}
+
+func TestMarkdown(t *testing.T) {
+	renderer, err := NewRenderer(MarkdownFormat)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, renderer.RenderTests(&buf, testReport))
+	output := buf.String()
+	assert.Contains(t, output, "# Test Report: happy-panda")
+	assert.Contains(t, output, "**3 tests**, 1 passed, 1 failed, 1 skipped in 1m30s")
+	assert.Contains(t, output, "- `TestSuite/TestGet`: expected 1 \\| got <2>")
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("TestGet` | ❌")), bytes.Index(buf.Bytes(), []byte("TestPut` | ✅")))
+
+	buf.Reset()
+	assert.NoError(t, renderer.RenderBenchmark(&buf, benchmarkReport))
+	output = buf.String()
+	assert.Contains(t, output, "## payloadSize=128")
+	assert.Contains(t, output, "| 0 | 1000 | 0 | 1000.00/sec | 1ms |")
+	assert.Contains(t, output, "| **Total** | 1000 |")
+	assert.Contains(t, output, "> **Failed:** benchmark error rate exceeded")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("**Total**")))
+	assert.Contains(t, output, "- Worker 1 stalled 2 time(s) and was restarted 1 time(s)")
+	assert.Contains(t, output, "## Metadata")
+	assert.Contains(t, output, "- **Git commit:** `0123abc-dirty`")
+	assert.Contains(t, output, "  - 3 x m5.large (amd64, 2 CPU, 8Gi memory)")
+	assert.Contains(t, output, "  - default/my-map (atomix-1.2.0, app 1.2.0)")
+	assert.Contains(t, output, "- **Arguments:** `./cmd/benchmarks --workers=2 --set-secret=redis.password=<redacted>`")
+
+	buf.Reset()
+	assert.NoError(t, renderer.RenderBenchmark(&buf, BenchmarkReport{ID: "happy-panda"}))
+	assert.NotContains(t, buf.String(), "## Metadata")
+}
+
+func TestHTML(t *testing.T) {
+	renderer, err := NewRenderer(HTMLFormat)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, renderer.RenderTests(&buf, testReport))
+	output := buf.String()
+	assert.Contains(t, output, "<title>Test Report: happy-panda</title>")
+	assert.Contains(t, output, "expected 1 | got &lt;2&gt;")
+	assert.Contains(t, output, `<td class="FAIL">FAIL</td>`)
+
+	buf.Reset()
+	assert.NoError(t, renderer.RenderBenchmark(&buf, benchmarkReport))
+	output = buf.String()
+	assert.Contains(t, output, "<h2>payloadSize=128</h2>")
+	assert.Contains(t, output, "<svg")
+	assert.Contains(t, output, `<polyline points="320.0,52.0 600.0,40.0"/>`)
+	assert.Contains(t, output, "<td>1000.00/sec</td>")
+	assert.Contains(t, output, "benchmark error rate exceeded")
+	assert.Contains(t, output, "<li>Worker 1 stalled 2 time(s) and was restarted 1 time(s)</li>")
+	assert.Contains(t, output, "<tr><th>Kubernetes Version</th><td><code>v1.26.1</code></td></tr>")
+	assert.Contains(t, output, "<td><code>./cmd/benchmarks --workers=2 --set-secret=redis.password=&lt;redacted&gt;</code></td>")
+}
+
+func TestJSON(t *testing.T) {
+	renderer, err := NewRenderer(JSONFormat)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, renderer.RenderTests(&buf, testReport))
+	var tests TestReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &tests))
+	assert.Equal(t, testReport, tests)
+
+	buf.Reset()
+	assert.NoError(t, renderer.RenderBenchmark(&buf, benchmarkReport))
+	assert.Contains(t, buf.String(), `"p99Latency"`)
+	var benchmarks BenchmarkReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &benchmarks))
+	assert.Equal(t, benchmarkReport, benchmarks)
+}
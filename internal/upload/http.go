@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// TokenEnv is the environment variable from which the bearer token for uploads to HTTP endpoints is read
+const TokenEnv = "HELMIT_UPLOAD_TOKEN"
+
+// httpUploader uploads files with PUT requests to URLs under the destination URL
+type httpUploader struct {
+	destination *url.URL
+	client      *http.Client
+}
+
+func newHTTPUploader(destination *url.URL) (Uploader, error) {
+	return &httpUploader{
+		destination: destination,
+		client:      http.DefaultClient,
+	}, nil
+}
+
+func (u *httpUploader) Upload(ctx context.Context, name string, content io.Reader) error {
+	target := *u.destination
+	target.Path = path.Join("/", target.Path, name)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), content)
+	if err != nil {
+		return err
+	}
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if token := os.Getenv(TokenEnv); token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := u.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("PUT %s returned %s: %s", target.Redacted(), response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
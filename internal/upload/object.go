@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// commandUploader uploads files to an object store by streaming them to the store's CLI
+// The CLIs are configured with the user's credentials, so no credentials need to be passed to helmit.
+type commandUploader struct {
+	bucket  string
+	prefix  string
+	scheme  string
+	command string
+	args    []string
+}
+
+func newS3Uploader(destination *url.URL) (Uploader, error) {
+	return newCommandUploader(destination, "aws", "s3", "cp", "--only-show-errors", "-")
+}
+
+func newGCSUploader(destination *url.URL) (Uploader, error) {
+	return newCommandUploader(destination, "gsutil", "-q", "cp", "-")
+}
+
+func newCommandUploader(destination *url.URL, command string, args ...string) (Uploader, error) {
+	if destination.Host == "" {
+		return nil, fmt.Errorf("upload URL %s must include a bucket", destination)
+	}
+	return &commandUploader{
+		bucket:  destination.Host,
+		prefix:  strings.Trim(destination.Path, "/"),
+		scheme:  destination.Scheme,
+		command: command,
+		args:    args,
+	}, nil
+}
+
+// getObjectURL returns the URL of the named object
+func (u *commandUploader) getObjectURL(name string) string {
+	return fmt.Sprintf("%s://%s/%s", u.scheme, u.bucket, path.Join(u.prefix, name))
+}
+
+func (u *commandUploader) Upload(ctx context.Context, name string, content io.Reader) error {
+	if _, err := exec.LookPath(u.command); err != nil {
+		return fmt.Errorf("uploading to %s:// requires %s to be installed: %w", u.scheme, u.command, err)
+	}
+	args := append(append([]string{}, u.args...), u.getObjectURL(name))
+	cmd := exec.CommandContext(ctx, u.command, args...)
+	cmd.Stdin = content
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(output) > 0 {
+			return errors.New(strings.TrimSpace(string(output)))
+		}
+		return err
+	}
+	return nil
+}
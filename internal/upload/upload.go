@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Uploader uploads result files to a destination
+type Uploader interface {
+	// Upload uploads the given content to the named file under the destination
+	// Names are slash-separated paths relative to the destination.
+	Upload(ctx context.Context, name string, content io.Reader) error
+}
+
+// Backend returns an Uploader for the given destination URL
+type Backend func(destination *url.URL) (Uploader, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		"file":  newFileUploader,
+		"http":  newHTTPUploader,
+		"https": newHTTPUploader,
+		"s3":    newS3Uploader,
+		"gs":    newGCSUploader,
+	}
+)
+
+// Register registers the backend uploading to destination URLs with the given scheme
+// Registering a backend for a scheme that's already registered replaces the existing backend.
+func Register(scheme string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[scheme] = backend
+}
+
+// New returns an Uploader for the given destination
+// The destination is a URL whose scheme selects the backend, e.g. s3://bucket/path, or a local directory.
+func New(destination string) (Uploader, error) {
+	if !strings.Contains(destination, "://") {
+		return &fileUploader{dir: destination}, nil
+	}
+	u, err := url.Parse(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload URL %s: %w", destination, err)
+	}
+	backendsMu.RLock()
+	backend, ok := backends[u.Scheme]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported upload URL scheme %q: must be one of %s", u.Scheme, strings.Join(getSchemes(), ", "))
+	}
+	return backend(u)
+}
+
+// getSchemes returns the sorted schemes of the registered backends
+func getSchemes() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	schemes := make([]string, 0, len(backends))
+	for scheme := range backends {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// File uploads the local file at the given path to the named file under the destination
+func File(ctx context.Context, uploader Uploader, name string, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := uploader.Upload(ctx, name, f); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", file, err)
+	}
+	return nil
+}
+
+// Dir uploads the files in the local directory at the given path to the named directory under the destination
+func Dir(ctx context.Context, uploader Uploader, name string, dir string) error {
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		return File(ctx, uploader, path.Join(name, filepath.ToSlash(rel)), file)
+	})
+}
+
+// fileUploader copies files to a local directory, e.g. a mounted volume
+type fileUploader struct {
+	dir string
+}
+
+func newFileUploader(destination *url.URL) (Uploader, error) {
+	// Relative paths are parsed as a host followed by a path, e.g. file://./results
+	return &fileUploader{dir: filepath.FromSlash(destination.Host + destination.Path)}, nil
+}
+
+func (u *fileUploader) Upload(ctx context.Context, name string, content io.Reader) error {
+	file := filepath.Join(u.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
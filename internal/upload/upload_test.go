@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileUploader(t *testing.T) {
+	src := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "TestSuite"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(src, "TestSuite", "pods.log"), []byte("logs"), 0644))
+
+	dst := t.TempDir()
+	uploader, err := New(dst)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.NoError(t, uploader.Upload(ctx, "happy-panda/report.json", strings.NewReader("{}")))
+	assert.NoError(t, Dir(ctx, uploader, "happy-panda/artifacts", src))
+
+	bytes, err := os.ReadFile(filepath.Join(dst, "happy-panda", "report.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(bytes))
+	bytes, err = os.ReadFile(filepath.Join(dst, "happy-panda", "artifacts", "TestSuite", "pods.log"))
+	assert.NoError(t, err)
+	assert.Equal(t, "logs", string(bytes))
+
+	uploader, err = New("file://" + dst)
+	assert.NoError(t, err)
+	assert.Equal(t, dst, uploader.(*fileUploader).dir)
+}
+
+func TestHTTPUploader(t *testing.T) {
+	var mu sync.Mutex
+	uploads := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		uploads[r.URL.Path] = string(body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	uploader, err := New(server.URL + "/results")
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.Error(t, uploader.Upload(ctx, "happy-panda/report.json", strings.NewReader("{}")))
+
+	t.Setenv(TokenEnv, "secret")
+	assert.NoError(t, uploader.Upload(ctx, "happy-panda/report.json", strings.NewReader("{}")))
+	assert.Equal(t, map[string]string{"/results/happy-panda/report.json": "{}"}, uploads)
+}
+
+func TestObjectUploader(t *testing.T) {
+	uploader, err := New("s3://benchmarks/nightly/")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3://benchmarks/nightly/happy-panda/report.json", uploader.(*commandUploader).getObjectURL("happy-panda/report.json"))
+
+	uploader, err = New("gs://benchmarks")
+	assert.NoError(t, err)
+	assert.Equal(t, "gs://benchmarks/happy-panda/report.json", uploader.(*commandUploader).getObjectURL("happy-panda/report.json"))
+
+	_, err = New("s3:///nightly")
+	assert.Error(t, err)
+}
+
+func TestRegister(t *testing.T) {
+	_, err := New("azblob://benchmarks")
+	assert.Error(t, err)
+
+	Register("azblob", func(destination *url.URL) (Uploader, error) {
+		return &fileUploader{dir: destination.Host}, nil
+	})
+	uploader, err := New("azblob://benchmarks")
+	assert.NoError(t, err)
+	assert.Equal(t, "benchmarks", uploader.(*fileUploader).dir)
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"github.com/onosproject/helmit/internal/cli"
 	"os"
@@ -15,6 +16,10 @@ import (
 func main() {
 	cmd := cli.GetRootCommand()
 	if err := cmd.Execute(); err != nil {
+		var exitErr *cli.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"strings"
+	"time"
+)
+
+const (
+	// barrierLabel is the label identifying the ConfigMaps that store barriers, set to the name of the barrier
+	barrierLabel = "helmit.onosproject.org/barrier"
+	// barrierInfix separates the run ID from the barrier name in the names of the ConfigMaps that store barriers
+	barrierInfix = "-barrier-"
+	// barrierPollInterval is the interval at which workers waiting at a barrier check whether it has been reached
+	barrierPollInterval = time.Second
+)
+
+// Barrier blocks until n workers of the benchmark run have reached the barrier with the given name, or the context
+// is done
+// Barriers are shared by the workers of a run, so benchmarks can synchronize phases across workers, e.g. so all the
+// workers finish loading data before any of them starts measuring. Once reached, a barrier stays open, so each name
+// should only be used for one phase of a run. A worker restarted after reaching a barrier passes it right away.
+// Barrier names must be valid Kubernetes label values of at most 63 characters.
+func (suite *Suite) Barrier(ctx context.Context, name string, n int) error {
+	if suite.config.RunID == "" {
+		return fmt.Errorf("barrier %s can only be awaited by benchmark workers", name)
+	}
+	return newBarrier(suite.Clientset, suite.Namespace(), suite.config.RunID, name).await(ctx, job.GetID(), n)
+}
+
+func newBarrier(client kubernetes.Interface, namespace, runID, name string) *barrier {
+	return &barrier{
+		client:    client,
+		namespace: namespace,
+		runID:     runID,
+		name:      name,
+	}
+}
+
+// barrier is a barrier stored in a ConfigMap, to which each worker reaching the barrier adds a key
+type barrier struct {
+	client    kubernetes.Interface
+	namespace string
+	runID     string
+	name      string
+}
+
+func (b *barrier) getName() string {
+	return b.runID + barrierInfix + b.name
+}
+
+// await adds the given worker to the barrier and waits until n workers have reached it
+func (b *barrier) await(ctx context.Context, worker string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("barrier %s must be awaited by at least one worker", b.name)
+	}
+	// The barrier is named by its ConfigMap's name and labeled with its own name
+	if errs := validation.IsDNS1123Subdomain(b.getName()); len(errs) > 0 {
+		return fmt.Errorf("invalid barrier name %s: %s", b.name, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsValidLabelValue(b.name); len(errs) > 0 {
+		return fmt.Errorf("invalid barrier name %s: %s", b.name, strings.Join(errs, ", "))
+	}
+	if err := b.arrive(ctx, worker); err != nil {
+		return err
+	}
+	for {
+		configMap, err := b.client.CoreV1().ConfigMaps(b.namespace).Get(ctx, b.getName(), metav1.GetOptions{})
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if configMap != nil && len(configMap.Data) >= n {
+			return nil
+		}
+		select {
+		case <-time.After(barrierPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("barrier %s was not reached: %w", b.name, ctx.Err())
+		}
+	}
+}
+
+// arrive records that the given worker has reached the barrier
+func (b *barrier) arrive(ctx context.Context, worker string) error {
+	arrived := time.Now().UTC().Format(time.RFC3339Nano)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := b.client.CoreV1().ConfigMaps(b.namespace).Get(ctx, b.getName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			labels := job.NewLabels(b.runID)
+			labels[barrierLabel] = b.name
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      b.getName(),
+					Namespace: b.namespace,
+					Labels:    labels,
+				},
+				Data: map[string]string{
+					worker: arrived,
+				},
+			}
+			_, err = b.client.CoreV1().ConfigMaps(b.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Another worker created the barrier first, so retry adding this worker to it
+				return k8serrors.NewConflict(corev1.Resource("configmaps"), b.getName(), err)
+			}
+			return err
+		} else if err != nil {
+			return err
+		}
+		if _, ok := configMap.Data[worker]; ok {
+			return nil
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[worker] = arrived
+		_, err = b.client.CoreV1().ConfigMaps(b.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// deleteBarriers deletes the barriers of the given benchmark run
+func deleteBarriers(ctx context.Context, client kubernetes.Interface, namespace, runID string) error {
+	return deleteConfigMaps(ctx, client, namespace, fmt.Sprintf("%s=%s,%s", job.JobLabel, runID, barrierLabel))
+}
+
+// deleteConfigMaps deletes the ConfigMaps matching the given label selector
+func deleteConfigMaps(ctx context.Context, client kubernetes.Interface, namespace, selector string) error {
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return err
+	}
+	for _, configMap := range configMaps.Items {
+		err := client.CoreV1().ConfigMaps(namespace).Delete(ctx, configMap.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBarrier(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	// The barrier is not reached until all the workers have arrived
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	err := newBarrier(client, "test", "happy-panda", "loaded").await(timeoutCtx, "happy-panda-worker-0", 3)
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Workers that already arrived, e.g. restarted workers, are not counted twice
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			errs[worker] = newBarrier(client, "test", "happy-panda", "loaded").await(ctx, fmt.Sprintf("happy-panda-worker-%d", worker), 3)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps("test").Get(ctx, "happy-panda-barrier-loaded", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, configMap.Data, 3)
+	assert.Equal(t, "loaded", configMap.Labels[barrierLabel])
+
+	// A barrier stays open once reached
+	assert.NoError(t, newBarrier(client, "test", "happy-panda", "loaded").await(ctx, "happy-panda-worker-0", 3))
+
+	assert.Error(t, newBarrier(client, "test", "happy-panda", "Loaded_Data").await(ctx, "happy-panda-worker-0", 1))
+	assert.Error(t, newBarrier(client, "test", "happy-panda", strings.Repeat("loaded", 11)).await(ctx, "happy-panda-worker-0", 1))
+	assert.Error(t, newBarrier(client, "test", "happy-panda", "loaded").await(ctx, "happy-panda-worker-0", 0))
+
+	assert.NoError(t, deleteBarriers(ctx, client, "test", "happy-panda"))
+	configMaps, err := client.CoreV1().ConfigMaps("test").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+}
@@ -6,11 +6,13 @@ package benchmark
 
 import (
 	"context"
+	"github.com/onosproject/helmit/internal/job"
 	"github.com/onosproject/helmit/internal/k8s"
 	"github.com/onosproject/helmit/pkg/helm"
 	"github.com/onosproject/helmit/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"sync"
 )
 
 // BenchmarkingSuite is a suite of benchmarks
@@ -31,6 +33,8 @@ type BenchmarkingSuite interface {
 	Args() map[string]types.Value
 	// Helm returns the Helm client
 	Helm() *helm.Helm
+	// B returns the benchmark metrics recorder
+	B() *B
 }
 
 // SetupSuite is an interface for setting up a suite of benchmarks
@@ -77,6 +81,8 @@ type Suite struct {
 	restConfig *rest.Config
 	helm       *helm.Helm
 	args       map[string]types.Value
+	argsMu     sync.RWMutex
+	b          *B
 }
 
 // Init initializes the benchmark suite
@@ -89,8 +95,9 @@ func (suite *Suite) Init(config Config, secrets map[string]string) error {
 		args[key] = types.NewValue(value)
 	}
 	suite.args = args
+	suite.b = newB()
 
-	restConfig, err := k8s.GetConfig()
+	restConfig, err := k8s.GetSuiteConfig()
 	if err != nil {
 		return err
 	}
@@ -103,10 +110,13 @@ func (suite *Suite) Init(config Config, secrets map[string]string) error {
 	suite.Clientset = clientset
 
 	suite.helm = helm.NewClient(helm.Context{
-		Namespace:  config.Namespace,
-		WorkDir:    config.Context,
-		Values:     config.Values,
-		ValueFiles: config.ValueFiles,
+		ID:           job.GetID(),
+		Namespace:    config.Namespace,
+		WorkDir:      config.Context,
+		Values:       config.Values,
+		SecretValues: config.SecretValues,
+		ValueFiles:   config.ValueFiles,
+		Secrets:      secrets,
 	})
 	return nil
 }
@@ -126,6 +136,23 @@ func (suite *Suite) Helm() *helm.Helm {
 	return suite.helm
 }
 
+// ClientFor returns a Kubernetes client for the resources owned by the given release in the suite namespace
+func (suite *Suite) ClientFor(release string) *helm.ReleaseClient {
+	return helm.NewReleaseClient(suite.Clientset, suite.Namespace(), release)
+}
+
+// AwaitRelease waits for the workloads created by the given release to be ready
+// If the workloads are not ready before the context is done, a *helm.NotReadyError describing the unready
+// workloads, pending pods, and recent warning events is returned.
+func (suite *Suite) AwaitRelease(ctx context.Context, release string) error {
+	return suite.ClientFor(release).AwaitReady(ctx)
+}
+
+// B returns the benchmark metrics recorder
+func (suite *Suite) B() *B {
+	return suite.b
+}
+
 // Secret returns a test secret by name
 func (suite *Suite) Secret(name string) string {
 	return suite.secrets[name]
@@ -138,6 +165,8 @@ func (suite *Suite) Secrets() map[string]string {
 
 // Arg returns a test argument by name
 func (suite *Suite) Arg(name string) types.Value {
+	suite.argsMu.RLock()
+	defer suite.argsMu.RUnlock()
 	value, ok := suite.args[name]
 	if !ok {
 		return types.NewValue(nil)
@@ -147,7 +176,22 @@ func (suite *Suite) Arg(name string) types.Value {
 
 // Args returns the test arguments
 func (suite *Suite) Args() map[string]types.Value {
-	return suite.args
+	suite.argsMu.RLock()
+	defer suite.argsMu.RUnlock()
+	args := make(map[string]types.Value, len(suite.args))
+	for key, value := range suite.args {
+		args[key] = value
+	}
+	return args
+}
+
+// setArgs adds or replaces the given arguments while the benchmark is running
+func (suite *Suite) setArgs(args map[string]string) {
+	suite.argsMu.Lock()
+	defer suite.argsMu.Unlock()
+	for key, value := range args {
+		suite.args[key] = types.NewValue(value)
+	}
 }
 
 var _ BenchmarkingSuite = (*Suite)(nil)
@@ -5,8 +5,10 @@
 package benchmark
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetSuiteName(t *testing.T) {
@@ -17,3 +19,63 @@ func TestGetSuiteName(t *testing.T) {
 type benchmarkSuite struct {
 	Suite
 }
+
+func (s *benchmarkSuite) BenchmarkGet(ctx context.Context) error {
+	return nil
+}
+
+func (s *benchmarkSuite) BenchmarkGetAll(ctx context.Context) error {
+	return nil
+}
+
+func (s *benchmarkSuite) BenchmarkPut(ctx context.Context) error {
+	return nil
+}
+
+// BenchmarkHelper is not a benchmark since it does not take a context
+func (s *benchmarkSuite) BenchmarkHelper() {}
+
+func TestGetBenchmarks(t *testing.T) {
+	suite := &benchmarkSuite{}
+	benchmarks, err := getBenchmarks(suite, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BenchmarkGet", "BenchmarkGetAll", "BenchmarkPut"}, benchmarks)
+
+	// A pattern naming a benchmark matches only that benchmark
+	benchmarks, err = getBenchmarks(suite, "BenchmarkGet")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BenchmarkGet"}, benchmarks)
+
+	benchmarks, err = getBenchmarks(suite, "BenchmarkGet|BenchmarkPut")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BenchmarkGet", "BenchmarkGetAll", "BenchmarkPut"}, benchmarks)
+
+	benchmarks, err = getBenchmarks(suite, "All$")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BenchmarkGetAll"}, benchmarks)
+
+	_, err = getBenchmarks(suite, "BenchmarkHelper")
+	assert.Error(t, err)
+	_, err = getBenchmarks(suite, "BenchmarkDelete")
+	assert.Error(t, err)
+	_, err = getBenchmarks(suite, "Benchmark(")
+	assert.Error(t, err)
+}
+
+func TestNewReport(t *testing.T) {
+	report := newReport(nil, 3, time.Second)
+	assert.Equal(t, 0, report.Iterations)
+	assert.Equal(t, 3, report.Errors)
+	assert.Equal(t, time.Duration(0), report.MeanLatency)
+
+	var calls []time.Duration
+	for i := 100; i > 0; i-- {
+		calls = append(calls, time.Duration(i)*time.Millisecond)
+	}
+	report = newReport(calls, 1, time.Second)
+	assert.Equal(t, 100, report.Iterations)
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 50500*time.Microsecond, report.MeanLatency)
+	assert.Equal(t, 50*time.Millisecond, report.P50Latency)
+	assert.Equal(t, 99*time.Millisecond, report.P99Latency)
+}
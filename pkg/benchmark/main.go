@@ -8,17 +8,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/onosproject/helmit/internal/control"
 	"github.com/onosproject/helmit/internal/job"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
 	"math"
 	"os"
 	"reflect"
+	"regexp"
+	"runtime"
 	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
 
-const shutdownFile = "/tmp/shutdown"
-
 // Type is a benchmark job type
 type Type string
 
@@ -38,13 +44,23 @@ type Config struct {
 	Suite          string              `json:"suite,omitempty"`
 	Benchmark      string              `json:"benchmark,omitempty"`
 	Parallelism    int                 `json:"parallelism,omitempty"`
+	Rate           float64             `json:"rate,omitempty"`
 	ReportInterval time.Duration       `json:"reportInterval,omitempty"`
+	Warmup         time.Duration       `json:"warmup,omitempty"`
 	Timeout        time.Duration       `json:"timeout,omitempty"`
 	Context        string              `json:"context,omitempty"`
 	Values         map[string][]string `json:"values,omitempty"`
+	SecretValues   map[string][]string `json:"secretValues,omitempty"`
 	ValueFiles     map[string][]string `json:"valueFiles,omitempty"`
 	Args           map[string]string   `json:"args,omitempty"`
+	SampleRate     float64             `json:"sampleRate,omitempty"`
+	MaxSamples     int                 `json:"maxSamples,omitempty"`
+	ThinkTime      time.Duration       `json:"thinkTime,omitempty"`
+	Jitter         float64             `json:"jitter,omitempty"`
+	GOMAXPROCS     int                 `json:"gomaxprocs,omitempty"`
 	NoTeardown     bool                `json:"verbose,omitempty"`
+	// RunID identifies the benchmark run shared by its workers and tear down job, e.g. to scope barriers
+	RunID string `json:"runID,omitempty"`
 }
 
 // Main runs a benchmark
@@ -110,6 +126,10 @@ func getSuiteName(suite BenchmarkingSuite) string {
 }
 
 func runSetup(ctx context.Context, config Config, suite BenchmarkingSuite) error {
+	benchmarks, err := getBenchmarks(suite, config.Benchmark)
+	if err != nil {
+		return err
+	}
 	if setupSuite, ok := suite.(SetupSuite); ok {
 		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
 		defer cancel()
@@ -124,22 +144,84 @@ func runSetup(ctx context.Context, config Config, suite BenchmarkingSuite) error
 			return err
 		}
 	}
-	methodFinder := reflect.TypeOf(suite)
-	if setupMethod, ok := methodFinder.MethodByName("Setup" + config.Benchmark); ok {
-		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
-		defer cancel()
-		values := setupMethod.Func.Call([]reflect.Value{reflect.ValueOf(suite), reflect.ValueOf(ctx)})
-		if len(values) > 0 {
-			value := values[0]
-			if !value.IsNil() {
-				return value.Interface().(error)
-			}
+	for _, name := range benchmarks {
+		if err := callSuiteMethod(ctx, config, suite, "Setup"+name); err != nil {
+			return err
+		}
+	}
+	bytes, err := json.Marshal(Plan{Benchmarks: benchmarks})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bytes))
+	return nil
+}
+
+// Plan lists the benchmarks matched by the configured pattern, written to the setup job's logs for the
+// benchmark coordinator
+type Plan struct {
+	Benchmarks []string `json:"benchmarks"`
+}
+
+// getBenchmarks returns the names of the suite's benchmarks matching the given pattern
+// A pattern naming a benchmark matches only that benchmark. Other patterns are regular expressions matched against
+// the names of the suite's Benchmark* methods, and an empty pattern matches all the suite's benchmarks.
+func getBenchmarks(suite BenchmarkingSuite, pattern string) ([]string, error) {
+	t := reflect.TypeOf(suite)
+	if method, ok := t.MethodByName(pattern); ok && isBenchmark(method) {
+		return []string{pattern}, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid benchmark pattern %q: %s", pattern, err)
+	}
+	var benchmarks []string
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if isBenchmark(method) && re.MatchString(method.Name) {
+			benchmarks = append(benchmarks, method.Name)
+		}
+	}
+	if len(benchmarks) == 0 {
+		return nil, fmt.Errorf("no benchmarks in suite %s match %q", getSuiteName(suite), pattern)
+	}
+	return benchmarks, nil
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// isBenchmark returns whether the given suite method is a benchmark
+func isBenchmark(method reflect.Method) bool {
+	return strings.HasPrefix(method.Name, "Benchmark") &&
+		method.Type.NumIn() == 2 && method.Type.In(1) == contextType &&
+		method.Type.NumOut() == 1 && method.Type.Out(0) == errorType
+}
+
+// callSuiteMethod calls the suite method with the given name if it exists
+func callSuiteMethod(ctx context.Context, config Config, suite BenchmarkingSuite, name string) error {
+	method, ok := reflect.TypeOf(suite).MethodByName(name)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+	values := method.Func.Call([]reflect.Value{reflect.ValueOf(suite), reflect.ValueOf(ctx)})
+	if len(values) > 0 {
+		value := values[0]
+		if !value.IsNil() {
+			return value.Interface().(error)
 		}
 	}
 	return nil
 }
 
 func runWorker(ctx context.Context, config Config, suite BenchmarkingSuite) error {
+	gomaxprocs := setMaxProcs(config.GOMAXPROCS)
+	numCPU := runtime.NumCPU()
+
 	methodFinder := reflect.TypeOf(suite)
 	method, ok := methodFinder.MethodByName(config.Benchmark)
 	if !ok {
@@ -164,51 +246,147 @@ func runWorker(ctx context.Context, config Config, suite BenchmarkingSuite) erro
 		return values[0].Interface().(error)
 	}
 
-	shutdownCh := make(chan struct{})
+	worker := newWorker()
+	if err := worker.serve(); err != nil {
+		return err
+	}
+	defer worker.stop()
+
 	go func() {
 		awaitShutdown()
-		close(shutdownCh)
+		worker.shutdown()
 	}()
 
 	stopped := &atomic.Bool{}
-	results := make(chan time.Duration, 1000)
-	for i := 0; i < config.Parallelism; i++ {
-		go func() {
-			for !stopped.Load() {
-				start := time.Now()
-				if err := f(); err == nil {
-					results <- time.Since(start)
+	results := make(chan result, 1000)
+	iterate := func(start time.Time) {
+		err := f()
+		worker.beat(time.Now())
+		results <- result{
+			start:   start,
+			latency: time.Since(start),
+			err:     err,
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	scheduleCtx, cancelSchedule := context.WithCancel(ctx)
+	defer cancelSchedule()
+
+	// In fixed-rate mode, iterations are started on a schedule and latencies are measured from the scheduled time
+	var schedule *schedule
+	if config.Rate > 0 {
+		schedule = newSchedule(scheduleCtx, config.Rate, config.Parallelism)
+	}
+
+	// Each benchmark goroutine has its own stop channel so the parallelism can be changed while running
+	var routines []chan struct{}
+	setParallelism := func(parallelism int) {
+		for len(routines) > parallelism {
+			close(routines[len(routines)-1])
+			routines = routines[:len(routines)-1]
+		}
+		for len(routines) < parallelism {
+			stopCh := make(chan struct{})
+			routines = append(routines, stopCh)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if schedule != nil {
+					for {
+						select {
+						case start, ok := <-schedule.C:
+							if !ok || stopped.Load() {
+								return
+							}
+							iterate(start)
+						case <-stopCh:
+							return
+						}
+					}
+				}
+				// Latencies are measured from the end of the think time, so pauses only lower the throughput
+				thinker := newThinker(config.ThinkTime, config.Jitter)
+				for !stopped.Load() {
+					select {
+					case <-stopCh:
+						return
+					default:
+						iterate(time.Now())
+					}
+					if thinkTime := thinker.next(); thinkTime > 0 {
+						timer := time.NewTimer(thinkTime)
+						select {
+						case <-timer.C:
+						case <-stopCh:
+							timer.Stop()
+							return
+						case <-scheduleCtx.Done():
+							timer.Stop()
+							return
+						}
+					}
 				}
+			}()
+		}
+	}
+	setParallelism(config.Parallelism)
+
+	rate := config.Rate
+	configure := func(update WorkerConfig) error {
+		if update.Rate > 0 && schedule == nil {
+			return status.Error(codes.FailedPrecondition, "the rate cannot be set for workers that are not running at a fixed rate")
+		}
+		if len(update.Args) > 0 {
+			argsSetter, ok := suite.(interface{ setArgs(map[string]string) })
+			if !ok {
+				return status.Error(codes.FailedPrecondition, "the benchmark suite does not support changing arguments")
 			}
-		}()
+			argsSetter.setArgs(update.Args)
+		}
+		if update.Parallelism > 0 {
+			setParallelism(update.Parallelism)
+		}
+		if update.Rate > 0 {
+			schedule.setRate(scheduleCtx, update.Rate)
+			rate = update.Rate
+		}
+		return nil
 	}
 
+	// Discard results until the warm-up period has elapsed
+	var warmupCh <-chan time.Time
+	warmingUp := config.Warmup > 0
+	if warmingUp {
+		warmupCh = time.After(config.Warmup)
+	}
+
+	sampler := newSampler(config.SampleRate, config.MaxSamples)
 	ticker := time.NewTicker(config.ReportInterval)
 	start := time.Now()
 	var calls []time.Duration
+	var errors int
 	for {
 		select {
+		case <-warmupCh:
+			warmingUp = false
+			ticker.Reset(config.ReportInterval)
+			start = time.Now()
+			calls = []time.Duration{}
+			errors = 0
+			suite.B().reset()
+			sampler.flush()
 		case <-ticker.C:
-			sort.Slice(calls, func(i, j int) bool {
-				return calls[i] < calls[j]
-			})
-
-			// Calculate the total latency from latency results
-			var totalCallRTT time.Duration
-			for _, rtt := range calls {
-				totalCallRTT += rtt
+			if warmingUp {
+				continue
 			}
 
-			// Compute the report statistics
-			report := Report{
-				Iterations:  len(calls),
-				Duration:    time.Since(start),
-				MeanLatency: time.Duration(int64(totalCallRTT) / int64(len(calls))),
-				P50Latency:  calls[int(math.Max(float64(len(calls)/2)-1, 0))],
-				P75Latency:  calls[int(math.Max(float64(len(calls)-(len(calls)/4)-1), 0))],
-				P95Latency:  calls[int(math.Max(float64(len(calls)-(len(calls)/20)-1), 0))],
-				P99Latency:  calls[int(math.Max(float64(len(calls)-(len(calls)/100)-1), 0))],
-			}
+			report := newReport(calls, errors, time.Since(start))
+			report.TargetRate = rate
+			report.GOMAXPROCS = gomaxprocs
+			report.NumCPU = numCPU
+			report.Counters, report.Gauges = suite.B().snapshot()
+			report.Samples, report.SampleWeight = sampler.flush()
 
 			bytes, err := json.Marshal(&report)
 			if err != nil {
@@ -218,10 +396,40 @@ func runWorker(ctx context.Context, config Config, suite BenchmarkingSuite) erro
 
 			start = time.Now()
 			calls = []time.Duration{}
+			errors = 0
 		case result := <-results:
-			calls = append(calls, result)
-		case <-shutdownCh:
+			if warmingUp {
+				continue
+			}
+			if result.err != nil {
+				errors++
+			} else {
+				calls = append(calls, result.latency)
+			}
+			sampler.add(Sample{
+				Start:   result.start,
+				Latency: result.latency,
+				Error:   result.err != nil,
+			})
+		case request := <-worker.configCh:
+			request.errCh <- configure(request.config)
+		case <-worker.shutdownCh:
+			// Stop the benchmark goroutines and drain in-flight iterations before tearing down the worker
 			stopped.Store(true)
+			cancelSchedule()
+			drainCh := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(drainCh)
+			}()
+		drain:
+			for {
+				select {
+				case <-results:
+				case <-drainCh:
+					break drain
+				}
+			}
 			if tearDownWorker, ok := suite.(TearDownWorker); ok {
 				ctx, cancel := context.WithTimeout(ctx, config.Timeout)
 				defer cancel()
@@ -235,16 +443,11 @@ func runWorker(ctx context.Context, config Config, suite BenchmarkingSuite) erro
 }
 
 func runTearDown(ctx context.Context, config Config, suite BenchmarkingSuite) error {
-	methodFinder := reflect.TypeOf(suite)
-	if tearDownMethod, ok := methodFinder.MethodByName("TearDown" + config.Benchmark); ok {
-		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
-		defer cancel()
-		values := tearDownMethod.Func.Call([]reflect.Value{reflect.ValueOf(suite), reflect.ValueOf(ctx)})
-		if len(values) > 0 {
-			value := values[0]
-			if !value.IsNil() {
-				return value.Interface().(error)
-			}
+	// The suite is torn down even if the pattern matches no benchmarks, since the setup may have partially completed
+	benchmarks, _ := getBenchmarks(suite, config.Benchmark)
+	for _, name := range benchmarks {
+		if err := callSuiteMethod(ctx, config, suite, "TearDown"+name); err != nil {
+			return err
 		}
 	}
 	if tearDownBench, ok := suite.(TearDownBenchmark); ok {
@@ -261,30 +464,90 @@ func runTearDown(ctx context.Context, config Config, suite BenchmarkingSuite) er
 			return err
 		}
 	}
+	if config.RunID != "" {
+		client, err := kubernetes.NewForConfig(suite.Config())
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+		if err := deleteBarriers(ctx, client, config.Namespace, config.RunID); err != nil {
+			return fmt.Errorf("failed to delete barriers: %w", err)
+		}
+		if err := deleteShared(ctx, client, config.Namespace, config.RunID); err != nil {
+			return fmt.Errorf("failed to delete shared values: %w", err)
+		}
+	}
 	return nil
 }
 
+// result is the result of a single benchmark iteration
+type result struct {
+	start   time.Time
+	latency time.Duration
+	err     error
+}
+
+// newReport computes the report statistics from the given successful call latencies
+func newReport(calls []time.Duration, errors int, duration time.Duration) Report {
+	report := Report{
+		Iterations: len(calls),
+		Errors:     errors,
+		Duration:   duration,
+	}
+	if len(calls) == 0 {
+		return report
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i] < calls[j]
+	})
+
+	// Calculate the total latency from latency results
+	var totalCallRTT time.Duration
+	for _, rtt := range calls {
+		totalCallRTT += rtt
+	}
+
+	report.MeanLatency = time.Duration(int64(totalCallRTT) / int64(len(calls)))
+	report.P50Latency = calls[int(math.Max(float64(len(calls)/2)-1, 0))]
+	report.P75Latency = calls[int(math.Max(float64(len(calls)-(len(calls)/4)-1), 0))]
+	report.P95Latency = calls[int(math.Max(float64(len(calls)-(len(calls)/20)-1), 0))]
+	report.P99Latency = calls[int(math.Max(float64(len(calls)-(len(calls)/100)-1), 0))]
+	return report
+}
+
+// awaitShutdown waits for the worker to be released through the control endpoint of the helmit-runner
 func awaitShutdown() {
+	client := control.NewClient(fmt.Sprintf("localhost:%d", control.Port))
+	interval := control.GetPollInterval()
 	for {
-		if isShutdown() {
+		if shutdown, err := client.IsShutdown(context.Background()); err == nil && shutdown {
 			return
 		}
-		time.Sleep(time.Second)
+		time.Sleep(interval)
 	}
 }
 
-func isShutdown() bool {
-	info, err := os.Stat(shutdownFile)
-	return err == nil && !info.IsDir()
-}
-
 // Report is a JSON enabled struct for reporting benchmark statistics via worker logs
 type Report struct {
-	Iterations  int           `json:"iterations"`
-	Duration    time.Duration `json:"duration"`
-	MeanLatency time.Duration `json:"meanLatency"`
-	P50Latency  time.Duration `json:"p50Latency"`
-	P75Latency  time.Duration `json:"p75Latency"`
-	P95Latency  time.Duration `json:"p95Latency"`
-	P99Latency  time.Duration `json:"p99Latency"`
+	Iterations  int                `json:"iterations"`
+	Errors      int                `json:"errors,omitempty"`
+	Duration    time.Duration      `json:"duration"`
+	TargetRate  float64            `json:"targetRate,omitempty"`
+	MeanLatency time.Duration      `json:"meanLatency"`
+	P50Latency  time.Duration      `json:"p50Latency"`
+	P75Latency  time.Duration      `json:"p75Latency"`
+	P95Latency  time.Duration      `json:"p95Latency"`
+	P99Latency  time.Duration      `json:"p99Latency"`
+	Counters    map[string]float64 `json:"counters,omitempty"`
+	Gauges      map[string]float64 `json:"gauges,omitempty"`
+	// Samples are iterations sampled during the interval when sampling is enabled
+	Samples []Sample `json:"samples,omitempty"`
+	// SampleWeight is the number of iterations represented by each of the Samples
+	SampleWeight float64 `json:"sampleWeight,omitempty"`
+	// GOMAXPROCS is the effective GOMAXPROCS of the worker
+	GOMAXPROCS int `json:"gomaxprocs,omitempty"`
+	// NumCPU is the number of CPUs visible to the worker
+	NumCPU int `json:"numCPU,omitempty"`
 }
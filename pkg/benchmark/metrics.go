@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"sync"
+)
+
+// newB returns a new benchmark metrics recorder
+func newB() *B {
+	return &B{
+		counters: make(map[string]float64),
+		gauges:   make(map[string]float64),
+	}
+}
+
+// B records custom benchmark metrics
+// Metrics recorded by concurrent benchmark goroutines are aggregated and reported with each worker report.
+type B struct {
+	counters map[string]float64
+	gauges   map[string]float64
+	mu       sync.Mutex
+}
+
+// Record adds the given value to the named counter
+// Counters are summed across goroutines and workers and reset at each report interval.
+func (b *B) Record(name string, value float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counters[name] += value
+}
+
+// Gauge sets the named gauge to the given value
+// The most recent value of each gauge is reported, and gauges are averaged across workers.
+func (b *B) Gauge(name string, value float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.gauges[name] = value
+}
+
+// snapshot returns the current counters and gauges and resets the counters
+func (b *B) snapshot() (map[string]float64, map[string]float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var counters, gauges map[string]float64
+	if len(b.counters) > 0 {
+		counters = b.counters
+		b.counters = make(map[string]float64)
+	}
+	if len(b.gauges) > 0 {
+		gauges = make(map[string]float64)
+		for name, value := range b.gauges {
+			gauges[name] = value
+		}
+	}
+	return counters, gauges
+}
+
+// reset discards all recorded metrics
+func (b *B) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counters = make(map[string]float64)
+	b.gauges = make(map[string]float64)
+}
@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	b := newB()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Record("bytes", 2)
+		}()
+	}
+	wg.Wait()
+	b.Gauge("connections", 3)
+	b.Gauge("connections", 5)
+
+	counters, gauges := b.snapshot()
+	assert.Equal(t, 20.0, counters["bytes"])
+	assert.Equal(t, 5.0, gauges["connections"])
+
+	// Counters are reset after each snapshot while gauges retain their values
+	counters, gauges = b.snapshot()
+	assert.Nil(t, counters)
+	assert.Equal(t, 5.0, gauges["connections"])
+
+	b.reset()
+	counters, gauges = b.snapshot()
+	assert.Nil(t, counters)
+	assert.Nil(t, gauges)
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// CPULimitEnv is the environment variable to which the CPU limit of a worker's container is set, rounded up to
+// whole CPUs
+// When the container has no CPU limit, Kubernetes sets the variable to the allocatable CPUs of the node.
+const CPULimitEnv = "HELMIT_CPU_LIMIT"
+
+// setMaxProcs sets GOMAXPROCS for the worker and returns the effective value
+// If gomaxprocs is zero, GOMAXPROCS is set to the worker's CPU limit when it's lower than the number of CPUs
+// visible to the process, unless the GOMAXPROCS environment variable is set.
+func setMaxProcs(gomaxprocs int) int {
+	if gomaxprocs == 0 && os.Getenv("GOMAXPROCS") == "" {
+		gomaxprocs = getCPULimitProcs(os.Getenv(CPULimitEnv), runtime.NumCPU())
+	}
+	if gomaxprocs > 0 {
+		runtime.GOMAXPROCS(gomaxprocs)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// getCPULimitProcs returns the GOMAXPROCS for the given CPU limit, or zero if the limit does not constrain the
+// given number of CPUs
+func getCPULimitProcs(cpuLimit string, numCPU int) int {
+	limit, err := strconv.Atoi(cpuLimit)
+	if err != nil || limit <= 0 || limit >= numCPU {
+		return 0
+	}
+	return limit
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"testing"
+)
+
+func TestGetCPULimitProcs(t *testing.T) {
+	assert.Equal(t, 2, getCPULimitProcs("2", 8))
+	assert.Equal(t, 0, getCPULimitProcs("8", 8))
+	assert.Equal(t, 0, getCPULimitProcs("16", 8))
+	assert.Equal(t, 0, getCPULimitProcs("", 8))
+	assert.Equal(t, 0, getCPULimitProcs("0", 8))
+	assert.Equal(t, 0, getCPULimitProcs("2.5", 8))
+}
+
+func TestSetMaxProcs(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	t.Setenv("GOMAXPROCS", "")
+	t.Setenv(CPULimitEnv, "")
+	assert.Equal(t, 1, setMaxProcs(1))
+	assert.Equal(t, 1, setMaxProcs(0))
+
+	if runtime.NumCPU() > 1 {
+		runtime.GOMAXPROCS(runtime.NumCPU())
+		t.Setenv(CPULimitEnv, "1")
+		assert.Equal(t, 1, setMaxProcs(0))
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// ProfilePort is the port on which benchmark workers serve their runtime profiles
+// Profiles are served at /debug/pprof/ in the format of net/http/pprof, so they can be fetched with go tool pprof.
+const ProfilePort = 5002
+
+// ProfilePath is the path at which benchmark workers serve their runtime profiles
+const ProfilePath = "/debug/pprof/"
+
+// newProfileHandler returns a handler serving the runtime profiles of the worker
+// The handlers are registered on their own mux rather than http.DefaultServeMux, so they are not exposed by other
+// servers run by the benchmark suite.
+func newProfileHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ProfilePath, pprof.Index)
+	mux.HandleFunc(ProfilePath+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(ProfilePath+"profile", pprof.Profile)
+	mux.HandleFunc(ProfilePath+"symbol", pprof.Symbol)
+	mux.HandleFunc(ProfilePath+"trace", pprof.Trace)
+	return mux
+}
+
+// serveProfiles starts serving the worker's runtime profiles
+func (w *worker) serveProfiles() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", ProfilePort))
+	if err != nil {
+		return err
+	}
+	w.serveProfilesOn(lis)
+	return nil
+}
+
+func (w *worker) serveProfilesOn(lis net.Listener) {
+	w.profiles = &http.Server{Handler: newProfileHandler()}
+	go func() {
+		_ = w.profiles.Serve(lis)
+	}()
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestWorkerProfiles(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	worker := newWorker()
+	worker.serveProfilesOn(lis)
+	defer worker.stop()
+
+	response, err := http.Get(fmt.Sprintf("http://%s%sheap", lis.Addr(), ProfilePath))
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = http.Get(fmt.Sprintf("http://%s%sunknown", lis.Addr(), ProfilePath))
+	assert.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"time"
+)
+
+// schedule emits the intended start time of each iteration at a rate that can be changed while running
+type schedule struct {
+	// C is the channel on which iteration start times are emitted
+	C      <-chan time.Time
+	rateCh chan float64
+}
+
+// newSchedule returns a schedule on which the intended start time of each iteration is emitted at the given rate
+// Iterations that cannot be started on time are emitted with their original start times once a goroutine
+// becomes available, so latencies measured from the intended start time account for coordinated omission.
+func newSchedule(ctx context.Context, rate float64, burst int) *schedule {
+	ch := make(chan time.Time, burst)
+	rateCh := make(chan float64)
+	interval := getInterval(rate)
+	go func() {
+		defer close(ch)
+		next := time.Now()
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case rate := <-rateCh:
+				// Reschedule the pending iteration relative to the last emitted time at the new rate
+				next = next.Add(getInterval(rate) - interval)
+				interval = getInterval(rate)
+				if !timer.Stop() {
+					<-timer.C
+				}
+				timer.Reset(time.Until(next))
+				continue
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case ch <- next:
+			case <-ctx.Done():
+				return
+			}
+			next = next.Add(interval)
+			timer.Reset(time.Until(next))
+		}
+	}()
+	return &schedule{
+		C:      ch,
+		rateCh: rateCh,
+	}
+}
+
+// setRate changes the rate at which iterations are scheduled
+func (s *schedule) setRate(ctx context.Context, rate float64) {
+	select {
+	case s.rateCh <- rate:
+	case <-ctx.Done():
+	}
+}
+
+func getInterval(rate float64) time.Duration {
+	return time.Duration(float64(time.Second) / rate)
+}
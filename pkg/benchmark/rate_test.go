@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	schedule := newSchedule(ctx, 100, 1)
+
+	var times []time.Time
+	for i := 0; i < 5; i++ {
+		times = append(times, <-schedule.C)
+	}
+	for i := 1; i < len(times); i++ {
+		assert.Equal(t, 10*time.Millisecond, times[i].Sub(times[i-1]))
+	}
+
+	// Scheduled times are not shifted when the consumer falls behind
+	time.Sleep(50 * time.Millisecond)
+	next := <-schedule.C
+	assert.Equal(t, 10*time.Millisecond, next.Sub(times[len(times)-1]))
+
+	cancel()
+	for range schedule.C {
+	}
+}
+
+func TestScheduleSetRate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	schedule := newSchedule(ctx, 100, 1)
+
+	first := <-schedule.C
+	second := <-schedule.C
+	assert.Equal(t, 10*time.Millisecond, second.Sub(first))
+
+	schedule.setRate(ctx, 50)
+	third := <-schedule.C
+	fourth := <-schedule.C
+	assert.Equal(t, 20*time.Millisecond, fourth.Sub(third))
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Sample is a single benchmark iteration sampled for offline analysis
+type Sample struct {
+	Start   time.Time     `json:"start"`
+	Latency time.Duration `json:"latency"`
+	Error   bool          `json:"error,omitempty"`
+}
+
+// newSampler returns a sampler keeping each iteration with the given probability, up to the given number of
+// samples per report interval
+// If max is zero, sampling is disabled and the sampler is nil.
+func newSampler(rate float64, max int) *sampler {
+	if max <= 0 {
+		return nil
+	}
+	if rate <= 0 || rate > 1 {
+		rate = 1
+	}
+	return &sampler{
+		rate: rate,
+		max:  max,
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// sampler selects a bounded, uniformly distributed set of the iterations run during a report interval
+type sampler struct {
+	rate    float64
+	max     int
+	rand    *rand.Rand
+	samples []Sample
+	// candidates is the number of iterations selected by the sample rate during the interval
+	candidates int
+	// iterations is the number of iterations run during the interval
+	iterations int
+}
+
+// add offers the given iteration to the sampler
+func (s *sampler) add(sample Sample) {
+	if s == nil {
+		return
+	}
+	s.iterations++
+	if s.rate < 1 && s.rand.Float64() >= s.rate {
+		return
+	}
+	// Reservoir sampling keeps each candidate with equal probability once the reservoir is full
+	s.candidates++
+	if len(s.samples) < s.max {
+		s.samples = append(s.samples, sample)
+	} else if i := s.rand.Intn(s.candidates); i < s.max {
+		s.samples[i] = sample
+	}
+}
+
+// flush returns the samples selected during the interval and the number of iterations each sample represents,
+// and resets the sampler for the next interval
+func (s *sampler) flush() ([]Sample, float64) {
+	if s == nil {
+		return nil, 0
+	}
+	defer s.reset()
+	if len(s.samples) == 0 {
+		return nil, 0
+	}
+	return s.samples, float64(s.iterations) / float64(len(s.samples))
+}
+
+func (s *sampler) reset() {
+	s.samples = nil
+	s.candidates = 0
+	s.iterations = 0
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSampler(t *testing.T) {
+	var disabled *sampler
+	disabled.add(Sample{Latency: time.Millisecond})
+	samples, weight := disabled.flush()
+	assert.Nil(t, samples)
+	assert.Equal(t, float64(0), weight)
+	assert.Nil(t, newSampler(1, 0))
+
+	sampler := newSampler(1, 10)
+	for i := 0; i < 5; i++ {
+		sampler.add(Sample{Latency: time.Duration(i)})
+	}
+	samples, weight = sampler.flush()
+	assert.Len(t, samples, 5)
+	assert.Equal(t, float64(1), weight)
+
+	// The reservoir is bounded and each sample represents the iterations that were not kept
+	for i := 0; i < 1000; i++ {
+		sampler.add(Sample{Latency: time.Duration(i)})
+	}
+	samples, weight = sampler.flush()
+	assert.Len(t, samples, 10)
+	assert.Equal(t, float64(100), weight)
+
+	// The sampler is reset by flush
+	samples, weight = sampler.flush()
+	assert.Nil(t, samples)
+	assert.Equal(t, float64(0), weight)
+
+	sampler = newSampler(0.5, 10000)
+	for i := 0; i < 10000; i++ {
+		sampler.add(Sample{Latency: time.Duration(i)})
+	}
+	samples, weight = sampler.flush()
+	assert.InDelta(t, 5000, len(samples), 500)
+	assert.InDelta(t, 2, weight, 0.2)
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"strings"
+)
+
+const (
+	// sharedLabel is the label identifying the ConfigMaps that store the values shared by the workers of a run
+	sharedLabel = "helmit.onosproject.org/shared"
+	// sharedSuffix is appended to the run ID to name the ConfigMap storing the values shared by its workers
+	sharedSuffix = "-shared"
+)
+
+// SharedSet sets the value of the given key in the scratchpad shared by the workers of the benchmark run
+// Shared values let workers exchange generated IDs, leader addresses, or partition assignments. Keys must be valid
+// ConfigMap keys, and all the values shared in a run are stored together, so they're limited to about 1MiB in total.
+func (suite *Suite) SharedSet(ctx context.Context, key string, value string) error {
+	store, err := suite.getSharedStore(key)
+	if err != nil {
+		return err
+	}
+	return store.set(ctx, key, value)
+}
+
+// SharedGet returns the value of the given key in the scratchpad shared by the workers of the benchmark run, and
+// whether the key has been set
+func (suite *Suite) SharedGet(ctx context.Context, key string) (string, bool, error) {
+	store, err := suite.getSharedStore(key)
+	if err != nil {
+		return "", false, err
+	}
+	return store.get(ctx, key)
+}
+
+func (suite *Suite) getSharedStore(key string) (*sharedStore, error) {
+	if suite.config.RunID == "" {
+		return nil, fmt.Errorf("shared key %s can only be accessed by benchmark workers", key)
+	}
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid shared key %s: %s", key, strings.Join(errs, ", "))
+	}
+	return newSharedStore(suite.Clientset, suite.Namespace(), suite.config.RunID), nil
+}
+
+func newSharedStore(client kubernetes.Interface, namespace, runID string) *sharedStore {
+	return &sharedStore{
+		client:    client,
+		namespace: namespace,
+		runID:     runID,
+	}
+}
+
+// sharedStore stores the values shared by the workers of a benchmark run in a ConfigMap
+type sharedStore struct {
+	client    kubernetes.Interface
+	namespace string
+	runID     string
+}
+
+func (s *sharedStore) getName() string {
+	return s.runID + sharedSuffix
+}
+
+func (s *sharedStore) set(ctx context.Context, key string, value string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.getName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			labels := job.NewLabels(s.runID)
+			labels[sharedLabel] = "true"
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      s.getName(),
+					Namespace: s.namespace,
+					Labels:    labels,
+				},
+				Data: map[string]string{
+					key: value,
+				},
+			}
+			_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Another worker created the store first, so retry setting the value in it
+				return k8serrors.NewConflict(corev1.Resource("configmaps"), s.getName(), err)
+			}
+			return err
+		} else if err != nil {
+			return err
+		}
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[key] = value
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *sharedStore) get(ctx context.Context, key string) (string, bool, error) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.getName(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	value, ok := configMap.Data[key]
+	return value, ok, nil
+}
+
+// deleteShared deletes the values shared by the workers of the given benchmark run
+func deleteShared(ctx context.Context, client kubernetes.Interface, namespace, runID string) error {
+	return deleteConfigMaps(ctx, client, namespace, fmt.Sprintf("%s=%s,%s", job.JobLabel, runID, sharedLabel))
+}
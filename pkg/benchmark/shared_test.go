@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestSharedStore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	store := newSharedStore(client, "test", "happy-panda")
+
+	_, ok, err := store.get(ctx, "leader")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.set(ctx, "leader", "10.0.0.1:5678"))
+	assert.NoError(t, newSharedStore(client, "test", "happy-panda").set(ctx, "partitions", "1,2,3"))
+	value, ok, err := store.get(ctx, "leader")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1:5678", value)
+	value, ok, err = store.get(ctx, "partitions")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1,2,3", value)
+
+	// Values are scoped to the run
+	_, ok, err = newSharedStore(client, "test", "other-run").get(ctx, "leader")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.set(ctx, "leader", "10.0.0.2:5678"))
+	value, _, err = store.get(ctx, "leader")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.2:5678", value)
+
+	suite := &Suite{config: Config{RunID: "happy-panda"}}
+	_, err = suite.getSharedStore("not a key")
+	assert.Error(t, err)
+	_, err = (&Suite{}).getSharedStore("leader")
+	assert.Error(t, err)
+
+	assert.NoError(t, deleteShared(ctx, client, "test", "happy-panda"))
+	configMaps, err := client.CoreV1().ConfigMaps("test").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, configMaps.Items)
+}
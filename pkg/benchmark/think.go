@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"math/rand"
+	"time"
+)
+
+// newThinker returns a thinker for a benchmark goroutine pausing for the given think time between iterations,
+// randomized by up to the given fraction of the think time in either direction
+// If the think time is zero, the thinker is nil and goroutines do not pause between iterations.
+func newThinker(thinkTime time.Duration, jitter float64) *thinker {
+	if thinkTime <= 0 {
+		return nil
+	}
+	if jitter < 0 {
+		jitter = 0
+	} else if jitter > 1 {
+		jitter = 1
+	}
+	return &thinker{
+		thinkTime: thinkTime,
+		jitter:    jitter,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// thinker computes the intervals for which a benchmark goroutine pauses between iterations to model a client
+type thinker struct {
+	thinkTime time.Duration
+	jitter    float64
+	rand      *rand.Rand
+}
+
+// next returns the interval for which to pause before the next iteration
+func (t *thinker) next() time.Duration {
+	if t == nil {
+		return 0
+	}
+	if t.jitter == 0 {
+		return t.thinkTime
+	}
+	// The interval is uniformly distributed in [thinkTime*(1-jitter), thinkTime*(1+jitter)]
+	offset := (t.rand.Float64()*2 - 1) * t.jitter
+	return time.Duration(float64(t.thinkTime) * (1 + offset))
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestThinker(t *testing.T) {
+	var disabled *thinker
+	assert.Equal(t, time.Duration(0), disabled.next())
+	assert.Nil(t, newThinker(0, 0.2))
+
+	thinker := newThinker(10*time.Millisecond, 0)
+	assert.Equal(t, 10*time.Millisecond, thinker.next())
+
+	thinker = newThinker(10*time.Millisecond, 0.2)
+	var total time.Duration
+	for i := 0; i < 1000; i++ {
+		next := thinker.next()
+		assert.GreaterOrEqual(t, next, 8*time.Millisecond)
+		assert.LessOrEqual(t, next, 12*time.Millisecond)
+		total += next
+	}
+	assert.InDelta(t, float64(10*time.Millisecond), float64(total/1000), float64(500*time.Microsecond))
+}
@@ -0,0 +1,279 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WorkerPort is the port on which benchmark workers serve the worker gRPC services
+const WorkerPort = 5000
+
+const (
+	workerServiceName   = "onos.helmit.benchmark.Worker"
+	shutdownMethodName  = "Shutdown"
+	configureMethodName = "Configure"
+	heartbeatMethodName = "Heartbeat"
+)
+
+// WorkerConfig is a set of benchmark parameters that can be changed on a running worker
+// Zero values leave the corresponding parameter unchanged.
+type WorkerConfig struct {
+	// Parallelism is the number of goroutines running the benchmark in the worker
+	Parallelism int `json:"parallelism,omitempty"`
+	// Rate is the target rate of iterations per second for workers running at a fixed rate
+	Rate float64 `json:"rate,omitempty"`
+	// Args are benchmark arguments to add or replace
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// Heartbeat reports the progress of a running benchmark worker
+type Heartbeat struct {
+	// LastIteration is the time at which the worker last completed an iteration
+	LastIteration time.Time `json:"lastIteration,omitempty"`
+	// Iterations is the number of iterations the worker has completed
+	Iterations uint64 `json:"iterations"`
+}
+
+// workerServer is the server for the benchmark worker service
+type workerServer interface {
+	Shutdown(ctx context.Context, request *emptypb.Empty) (*emptypb.Empty, error)
+	Configure(ctx context.Context, request *wrapperspb.BytesValue) (*emptypb.Empty, error)
+	Heartbeat(ctx context.Context, request *emptypb.Empty) (*wrapperspb.BytesValue, error)
+}
+
+var workerServiceDesc = grpc.ServiceDesc{
+	ServiceName: workerServiceName,
+	HandlerType: (*workerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: shutdownMethodName,
+			Handler:    shutdownHandler,
+		},
+		{
+			MethodName: configureMethodName,
+			Handler:    configureHandler,
+		},
+		{
+			MethodName: heartbeatMethodName,
+			Handler:    heartbeatHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func shutdownHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := new(emptypb.Empty)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(workerServer).Shutdown(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: fmt.Sprintf("/%s/%s", workerServiceName, shutdownMethodName),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(workerServer).Shutdown(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, request, info, handler)
+}
+
+func configureHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := new(wrapperspb.BytesValue)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(workerServer).Configure(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: fmt.Sprintf("/%s/%s", workerServiceName, configureMethodName),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(workerServer).Configure(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, request, info, handler)
+}
+
+func heartbeatHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := new(emptypb.Empty)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(workerServer).Heartbeat(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: fmt.Sprintf("/%s/%s", workerServiceName, heartbeatMethodName),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(workerServer).Heartbeat(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, request, info, handler)
+}
+
+// ConfigureWorker pushes the given configuration to the running benchmark worker connected to by the given
+// client connection
+func ConfigureWorker(ctx context.Context, conn *grpc.ClientConn, config WorkerConfig) error {
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return conn.Invoke(ctx, fmt.Sprintf("/%s/%s", workerServiceName, configureMethodName), wrapperspb.Bytes(bytes), &emptypb.Empty{})
+}
+
+// ShutdownWorker requests the benchmark worker connected to by the given client connection to shut down
+func ShutdownWorker(ctx context.Context, conn *grpc.ClientConn) error {
+	return conn.Invoke(ctx, fmt.Sprintf("/%s/%s", workerServiceName, shutdownMethodName), &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+// GetWorkerHeartbeat returns the progress of the benchmark worker connected to by the given client connection
+func GetWorkerHeartbeat(ctx context.Context, conn *grpc.ClientConn) (Heartbeat, error) {
+	response := &wrapperspb.BytesValue{}
+	if err := conn.Invoke(ctx, fmt.Sprintf("/%s/%s", workerServiceName, heartbeatMethodName), &emptypb.Empty{}, response); err != nil {
+		return Heartbeat{}, err
+	}
+	var heartbeat Heartbeat
+	if err := json.Unmarshal(response.Value, &heartbeat); err != nil {
+		return Heartbeat{}, err
+	}
+	return heartbeat, nil
+}
+
+func newWorker() *worker {
+	return &worker{
+		server:     grpc.NewServer(),
+		health:     health.NewServer(),
+		shutdownCh: make(chan struct{}),
+		configCh:   make(chan configRequest),
+	}
+}
+
+// configRequest is a request to apply a configuration to the running benchmark
+type configRequest struct {
+	config WorkerConfig
+	errCh  chan<- error
+}
+
+// worker serves the health, shutdown, configuration, and heartbeat services and the runtime profiles for a
+// benchmark worker
+type worker struct {
+	server     *grpc.Server
+	profiles   *http.Server
+	health     *health.Server
+	shutdownCh chan struct{}
+	configCh   chan configRequest
+	once       sync.Once
+	// lastIteration is the time in Unix nanoseconds at which the last iteration completed
+	lastIteration atomic.Int64
+	iterations    atomic.Uint64
+}
+
+// serve starts serving the worker services and runtime profiles
+func (w *worker) serve() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", WorkerPort))
+	if err != nil {
+		return err
+	}
+	w.serveOn(lis)
+	return w.serveProfiles()
+}
+
+func (w *worker) serveOn(lis net.Listener) {
+	healthpb.RegisterHealthServer(w.server, w.health)
+	w.server.RegisterService(&workerServiceDesc, w)
+	w.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	go func() {
+		_ = w.server.Serve(lis)
+	}()
+}
+
+// Shutdown signals the worker to shut down
+func (w *worker) Shutdown(ctx context.Context, request *emptypb.Empty) (*emptypb.Empty, error) {
+	w.shutdown()
+	return &emptypb.Empty{}, nil
+}
+
+// Configure applies a configuration to the running benchmark
+func (w *worker) Configure(ctx context.Context, request *wrapperspb.BytesValue) (*emptypb.Empty, error) {
+	var config WorkerConfig
+	if err := json.Unmarshal(request.Value, &config); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if config.Parallelism < 0 || config.Rate < 0 {
+		return nil, status.Error(codes.InvalidArgument, "parallelism and rate must be positive")
+	}
+	errCh := make(chan error, 1)
+	select {
+	case w.configCh <- configRequest{config: config, errCh: errCh}:
+	case <-w.shutdownCh:
+		return nil, status.Error(codes.Unavailable, "worker is shutting down")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return nil, err
+		}
+		return &emptypb.Empty{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Heartbeat returns the progress of the running benchmark
+func (w *worker) Heartbeat(ctx context.Context, request *emptypb.Empty) (*wrapperspb.BytesValue, error) {
+	heartbeat := Heartbeat{
+		Iterations: w.iterations.Load(),
+	}
+	if lastIteration := w.lastIteration.Load(); lastIteration > 0 {
+		heartbeat.LastIteration = time.Unix(0, lastIteration)
+	}
+	bytes, err := json.Marshal(heartbeat)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return wrapperspb.Bytes(bytes), nil
+}
+
+// beat records an iteration completed at the given time
+func (w *worker) beat(t time.Time) {
+	w.lastIteration.Store(t.UnixNano())
+	w.iterations.Add(1)
+}
+
+func (w *worker) shutdown() {
+	w.once.Do(func() {
+		w.health.Shutdown()
+		close(w.shutdownCh)
+	})
+}
+
+// stop gracefully stops the worker server, draining in-flight requests
+func (w *worker) stop() {
+	w.server.GracefulStop()
+	if w.profiles != nil {
+		_ = w.profiles.Close()
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package benchmark
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWorkerShutdown(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	worker := newWorker()
+	worker.serveOn(lis)
+	defer worker.stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	response, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	assert.NoError(t, ShutdownWorker(context.Background(), conn))
+	<-worker.shutdownCh
+
+	response, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status)
+}
+
+func TestWorkerConfigure(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	worker := newWorker()
+	worker.serveOn(lis)
+	defer worker.stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	go func() {
+		request := <-worker.configCh
+		assert.Equal(t, 4, request.config.Parallelism)
+		assert.Equal(t, map[string]string{"keys": "100"}, request.config.Args)
+		request.errCh <- nil
+	}()
+	assert.NoError(t, ConfigureWorker(context.Background(), conn, WorkerConfig{
+		Parallelism: 4,
+		Args:        map[string]string{"keys": "100"},
+	}))
+
+	err = ConfigureWorker(context.Background(), conn, WorkerConfig{Parallelism: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	worker.shutdown()
+	err = ConfigureWorker(context.Background(), conn, WorkerConfig{Parallelism: 1})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestWorkerHeartbeat(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	worker := newWorker()
+	worker.serveOn(lis)
+	defer worker.stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	heartbeat, err := GetWorkerHeartbeat(context.Background(), conn)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), heartbeat.Iterations)
+	assert.True(t, heartbeat.LastIteration.IsZero())
+
+	now := time.Now()
+	worker.beat(now.Add(-time.Second))
+	worker.beat(now)
+	heartbeat, err = GetWorkerHeartbeat(context.Background(), conn)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), heartbeat.Iterations)
+	assert.True(t, now.Equal(heartbeat.LastIteration))
+}
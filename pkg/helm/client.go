@@ -5,6 +5,7 @@
 package helm
 
 import (
+	"github.com/onosproject/helmit/internal/k8s"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
 	"log"
@@ -13,7 +14,22 @@ import (
 	"sync"
 )
 
-var settings = cli.New()
+var settings = newSettings()
+
+// newSettings returns the Helm settings, applying the kubeconfig file and context selected by the user
+func newSettings() *cli.EnvSettings {
+	settings := cli.New()
+	if kubeconfig := os.Getenv(k8s.KubeconfigEnv); kubeconfig != "" {
+		settings.KubeConfig = kubeconfig
+	}
+	if context := os.Getenv(k8s.ContextEnv); context != "" {
+		settings.KubeContext = context
+	}
+	if burst := k8s.GetBurst(); burst > 0 {
+		settings.BurstLimit = burst
+	}
+	return settings
+}
 
 var namespaces = make(map[string]*action.Configuration)
 var namespacesMu = &sync.Mutex{}
@@ -24,13 +40,50 @@ func NewClient(context Context) *Helm {
 		panic(err)
 	}
 	return &Helm{
-		context: context,
+		context:  context,
+		releases: newReleaseRegistry(),
 	}
 }
 
 // Helm is a Helm client
 type Helm struct {
-	context Context
+	context  Context
+	prefix   bool
+	releases *releaseRegistry
+}
+
+// WithPrefix returns a copy of the client that prefixes the names of the releases it manages with the job ID
+// Prefixing allows suites running concurrently in the same namespace to install the same charts without their
+// release names conflicting. Release values and value files set for the job are still keyed by the unprefixed
+// release name. The copy shares the releases tracked by the client.
+func (helm *Helm) WithPrefix() *Helm {
+	return &Helm{
+		context:  helm.context,
+		prefix:   true,
+		releases: helm.releases,
+	}
+}
+
+// Untracked returns a copy of the client that does not track the releases it installs
+// Releases installed by an untracked client, e.g. fixtures shared by several suites, are not uninstalled by
+// UninstallAll.
+func (helm *Helm) Untracked() *Helm {
+	if helm == nil {
+		return nil
+	}
+	return &Helm{
+		context: helm.context,
+		prefix:  helm.prefix,
+	}
+}
+
+// ReleaseName returns the name of the Helm release managed by the client for the given release name
+// If prefixing is enabled and the client is running in a job, the name is prefixed with the job ID.
+func (helm *Helm) ReleaseName(release string) string {
+	if helm.prefix && helm.context.ID != "" {
+		return helm.context.ID + "-" + release
+	}
+	return release
 }
 
 // Namespace returns the Helm namespace
@@ -43,19 +96,50 @@ func (helm *Helm) Repo() *RepoCmd {
 	return newRepoCmd(helm.context)
 }
 
+// RepoAdd creates a new command for adding a Helm chart repository
+func (helm *Helm) RepoAdd(name string, url string) *RepoAddCmd {
+	return newRepoAdd(helm.context, name, url)
+}
+
+// RepoUpdate creates a new command for updating the indexes of the named Helm chart repositories
+// If no names are provided, all configured repositories are updated.
+func (helm *Helm) RepoUpdate(names ...string) *RepoUpdateCmd {
+	return newRepoUpdate(helm.context, names...)
+}
+
 // Install creates a new command for installing a Helm chart
 func (helm *Helm) Install(release string, chart string) *InstallCmd {
-	return newInstallCmd(helm.context, release, chart)
+	cmd := newInstallCmd(helm.context, helm.ReleaseName(release), chart)
+	cmd.key = release
+	cmd.releases = helm.releases
+	return cmd
 }
 
 // Upgrade creates a new command for upgrading a Helm chart release
 func (helm *Helm) Upgrade(release string, chart string) *UpgradeCmd {
-	return newUpgradeCmd(helm.context, release, chart)
+	cmd := newUpgradeCmd(helm.context, helm.ReleaseName(release), chart)
+	cmd.key = release
+	cmd.releases = helm.releases
+	return cmd
+}
+
+// Template creates a new command for rendering a Helm chart's templates without installing the chart
+func (helm *Helm) Template(release string, chart string) *TemplateCmd {
+	cmd := newTemplateCmd(helm.context, helm.ReleaseName(release), chart)
+	cmd.key = release
+	return cmd
+}
+
+// Lint creates a new command for linting the Helm chart at the given path
+func (helm *Helm) Lint(chart string) *LintCmd {
+	return newLintCmd(helm.context, chart)
 }
 
 // Uninstall creates a new command for uninstalling a Helm chart release
 func (helm *Helm) Uninstall(release string) *UninstallCmd {
-	return newUninstall(helm.context, release)
+	cmd := newUninstall(helm.context, helm.ReleaseName(release))
+	cmd.releases = helm.releases
+	return cmd
 }
 
 // getConfig gets the Helm configuration for the given namespace
@@ -5,19 +5,35 @@
 package helm
 
 import (
-	"encoding/csv"
+	"bytes"
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/strvals"
+	"net/url"
+	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	k8syaml "sigs.k8s.io/yaml"
 	"strings"
+	"text/template"
 )
 
+// valueVarRegex matches ${NAME} placeholders in release values
+var valueVarRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.\-]*)\}`)
+
+// valueTemplateSuffix is the file name suffix of values files that are rendered as Go templates
+// Other values files are loaded as is, so they may contain template expressions evaluated by the chart, e.g. with tpl.
+const valueTemplateSuffix = ".gotmpl"
+
 // Context is a Helm context
 type Context struct {
+	// ID is the ID of the job in which the client is running, with which release names are prefixed when
+	// prefixing is enabled
+	ID string
+
 	// Namespace is the Helm namespace
 	Namespace string
 
@@ -29,27 +45,134 @@ type Context struct {
 
 	// ValueFiles is a mapping of release value files
 	ValueFiles map[string][]string
+
+	// Secrets is a mapping of secrets with which to resolve placeholders in release values
+	Secrets map[string]string
+
+	// SecretValues is a mapping of release values set from secrets, each in the format {path}={secret}
+	SecretValues map[string][]string
 }
 
 func (c *Context) getReleaseValues(release string, defaultValues map[string]any, defaultFiles []string) (map[string]any, error) {
-	var valueFiles []string
+	overrides := make(map[string]any)
 	for _, valueFile := range append(defaultFiles, c.ValueFiles[release]...) {
-		absPath, err := filepath.Abs(valueFile)
+		values, err := c.readValueFile(valueFile)
+		if err != nil {
+			return nil, err
+		}
+		overrides = mergeMaps(overrides, values)
+	}
+
+	for _, value := range c.Values[release] {
+		value, err := c.expandValue(value)
 		if err != nil {
 			return nil, err
 		}
-		valueFiles = append(valueFiles, absPath)
+		if err := strvals.ParseInto(value, overrides); err != nil {
+			return nil, fmt.Errorf("failed parsing --set data: %w", err)
+		}
 	}
 
-	opts := &values.Options{
-		ValueFiles: valueFiles,
-		Values:     c.Values[release],
+	// Secret values are set as literal strings, so they're not subject to placeholder expansion or type inference
+	for _, value := range c.SecretValues[release] {
+		err := strvals.ParseIntoFile(value, overrides, func(name []rune) (interface{}, error) {
+			secret, ok := c.Secrets[string(name)]
+			if !ok {
+				return nil, fmt.Errorf("unknown secret %q", string(name))
+			}
+			return secret, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing --set-secret data: %w", err)
+		}
 	}
-	overrides, err := opts.MergeValues(getter.All(settings))
+	return mergeValues(defaultValues, overrides)
+}
+
+// readValueFile reads the given values file, which may be a local path or a URL supported by the Helm getters
+// Values files with the .gotmpl suffix are rendered as templates, resolving placeholders from secrets and environment
+// variables.
+func (c *Context) readValueFile(path string) (map[string]any, error) {
+	data, err := readFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return mergeValues(defaultValues, overrides)
+
+	if strings.HasSuffix(path, valueTemplateSuffix) {
+		tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, c.getTemplateData()); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		data = buf.Bytes()
+	}
+
+	values := make(map[string]any)
+	if err := k8syaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// readFile reads the given file from a URL with the getter for its scheme, or otherwise from the local file system
+func readFile(path string) ([]byte, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	g, err := getter.All(settings).ByScheme(u.Scheme)
+	if err != nil {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		return os.ReadFile(absPath)
+	}
+	data, err := g.Get(path, getter.WithURL(path))
+	if err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+// getTemplateData returns the data with which to render values file templates
+func (c *Context) getTemplateData() map[string]any {
+	secrets := make(map[string]string)
+	for key, value := range c.Secrets {
+		secrets[key] = value
+	}
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			env[key] = value
+		}
+	}
+	return map[string]any{
+		"Secrets": secrets,
+		"Env":     env,
+	}
+}
+
+// expandValue replaces ${NAME} placeholders in the given value with the named secret or environment variable
+func (c *Context) expandValue(value string) (string, error) {
+	var err error
+	expanded := valueVarRegex.ReplaceAllStringFunc(value, func(match string) string {
+		name := valueVarRegex.FindStringSubmatch(match)[1]
+		if secret, ok := c.Secrets[name]; ok {
+			return secret
+		}
+		if env, ok := os.LookupEnv(name); ok {
+			return env
+		}
+		if err == nil {
+			err = fmt.Errorf("no secret or environment variable %s found for value %s", name, value)
+		}
+		return match
+	})
+	return expanded, err
 }
 
 func mergeValues(a, b map[string]any) (map[string]any, error) {
@@ -79,57 +202,96 @@ func mergeMaps(a, b map[string]any) map[string]any {
 	return out
 }
 
-// getValue gets the value for the given path
-func getValue(config map[string]any, path []string) any {
-	names, key := getPathAndKey(path)
-	parent := getMap(config, names)
-	return parent[key]
-}
+// pathEnd marks the end of a value path parsed by parsePath
+type pathEnd struct{}
 
-// getMap gets the map at the given path
-func getMap(parent map[string]any, path []string) map[string]any {
-	if len(path) == 0 {
-		return parent
+// parsePath parses a value path, e.g. "a.b[0].c" or "labels.app\.kubernetes\.io/name", into its map keys and list
+// indices
+// Paths are parsed with Helm's strvals parser, so they're interpreted exactly as by helm install --set.
+func parsePath(path string) ([]any, error) {
+	if strings.ContainsAny(path, "=,") {
+		return nil, fmt.Errorf("invalid value path %q", path)
 	}
-	child, ok := parent[path[0]]
-	if !ok {
-		return make(map[string]any)
+	parsed := make(map[string]any)
+	err := strvals.ParseIntoFile(path+"=0", parsed, func([]rune) (any, error) {
+		return pathEnd{}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid value path %q: %w", path, err)
 	}
-	return getMap(child.(map[string]any), path[1:])
-}
 
-// setKey sets a key in a map
-func setKey(config map[string]any, path []string, value any) {
-	names, key := getPathAndKey(path)
-	parent := getMapRef(config, names)
-	parent[key] = value
+	// The parsed values form a single chain of maps and lists ending in the pathEnd marker
+	var elems []any
+	var node any = parsed
+	for {
+		switch value := node.(type) {
+		case map[string]any:
+			if len(value) != 1 {
+				return nil, fmt.Errorf("invalid value path %q", path)
+			}
+			for key, child := range value {
+				elems = append(elems, key)
+				node = child
+			}
+		case []any:
+			elems = append(elems, len(value)-1)
+			node = value[len(value)-1]
+		case pathEnd:
+			return elems, nil
+		default:
+			return nil, fmt.Errorf("invalid value path %q", path)
+		}
+	}
 }
 
-// getMapRef gets the given map reference
-func getMapRef(parent map[string]any, path []string) map[string]any {
-	if len(path) == 0 {
-		return parent
-	}
-	child, ok := parent[path[0]]
-	if !ok {
-		child = make(map[string]any)
-		parent[path[0]] = child
+// getValue gets the value at the given path, returning nil if the path does not exist
+func getValue(values map[string]any, path []any) any {
+	var node any = values
+	for _, elem := range path {
+		switch key := elem.(type) {
+		case string:
+			m, ok := node.(map[string]any)
+			if !ok {
+				return nil
+			}
+			node = m[key]
+		case int:
+			list, ok := node.([]any)
+			if !ok || key >= len(list) {
+				return nil
+			}
+			node = list[key]
+		}
 	}
-	return getMapRef(child.(map[string]any), path[1:])
+	return node
 }
 
-func getPathNames(path string) []string {
-	r := csv.NewReader(strings.NewReader(path))
-	r.Comma = '.'
-	names, err := r.Read()
-	if err != nil {
-		panic(err)
-	}
-	return names
+// setValue sets the value at the given path, creating maps and extending lists along the path as needed
+func setValue(values map[string]any, path []any, value any) {
+	setPathValue(values, path, value)
 }
 
-func getPathAndKey(path []string) ([]string, string) {
-	return path[:len(path)-1], path[len(path)-1]
+func setPathValue(node any, path []any, value any) any {
+	if len(path) == 0 {
+		return value
+	}
+	switch key := path[0].(type) {
+	case string:
+		m, ok := node.(map[string]any)
+		if !ok {
+			m = make(map[string]any)
+		}
+		m[key] = setPathValue(m[key], path[1:], value)
+		return m
+	case int:
+		list, _ := node.([]any)
+		for len(list) <= key {
+			list = append(list, nil)
+		}
+		list[key] = setPathValue(list[key], path[1:], value)
+		return list
+	}
+	return node
 }
 
 func normalize(value any) (any, error) {
@@ -64,3 +64,122 @@ func TestReleaseValues(t *testing.T) {
 	assert.Equal(t, "foo", values["d"].(map[string]any)["e"])
 	assert.Equal(t, "baz", values["d"].(map[string]any)["f"])
 }
+
+func TestReleaseValueTemplates(t *testing.T) {
+	t.Setenv("HELMIT_TEST_USER", "admin")
+	t.Setenv("HELMIT_TEST_HOST", "db.example.com")
+	context := Context{
+		Values: map[string][]string{
+			"foo": {
+				"database.host=${HELMIT_TEST_HOST}",
+				"database.token=${token}",
+			},
+		},
+		ValueFiles: map[string][]string{
+			"foo": {
+				"context_test_templates.yaml.gotmpl",
+			},
+		},
+		Secrets: map[string]string{
+			"password": "secret",
+			"token":    "abc123",
+		},
+	}
+	values, err := context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", values["credentials"].(map[string]any)["password"])
+	assert.Equal(t, "admin", values["credentials"].(map[string]any)["user"])
+	assert.Equal(t, "db.example.com", values["database"].(map[string]any)["host"])
+	assert.Equal(t, "abc123", values["database"].(map[string]any)["token"])
+
+	context.Values["foo"] = []string{"database.host=${HELMIT_TEST_MISSING}"}
+	_, err = context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.Error(t, err)
+
+	context.Values["foo"] = nil
+	context.Secrets = nil
+	_, err = context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.Error(t, err)
+}
+
+func TestReleaseValueLiteralTemplates(t *testing.T) {
+	context := Context{
+		ValueFiles: map[string][]string{
+			"foo": {
+				"context_test_literal.yaml",
+			},
+		},
+	}
+	values, err := context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "{{ .Release.Name }}-db", values["database"].(map[string]any)["host"])
+}
+
+func TestValuePaths(t *testing.T) {
+	path, err := parsePath("a.b[1].c")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"a", "b", 1, "c"}, path)
+
+	path, err = parsePath(`podLabels.app\.kubernetes\.io/name`)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"podLabels", "app.kubernetes.io/name"}, path)
+
+	_, err = parsePath("a[x]")
+	assert.Error(t, err)
+	_, err = parsePath("a=b")
+	assert.Error(t, err)
+	_, err = parsePath("a,b")
+	assert.Error(t, err)
+
+	values := map[string]any{
+		"a": map[string]any{
+			"b": []any{"foo"},
+		},
+	}
+	setValue(values, []any{"a", "b", 1, "c"}, 3)
+	setValue(values, []any{"podLabels", "app.kubernetes.io/name"}, "store")
+	assert.Equal(t, "foo", getValue(values, []any{"a", "b", 0}))
+	assert.Equal(t, 3, getValue(values, []any{"a", "b", 1, "c"}))
+	assert.Equal(t, "store", getValue(values, []any{"podLabels", "app.kubernetes.io/name"}))
+	assert.Nil(t, getValue(values, []any{"a", "b", 2}))
+	assert.Nil(t, getValue(values, []any{"a", "c", "d"}))
+
+	context := Context{
+		Values: map[string][]string{
+			"foo": {
+				"servers[0].port=8080,servers[0].name=http",
+				`podLabels.app\.kubernetes\.io/name=store`,
+				"args={--debug,--verbose}",
+			},
+		},
+	}
+	values, err = context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), getValue(values, []any{"servers", 0, "port"}))
+	assert.Equal(t, "http", getValue(values, []any{"servers", 0, "name"}))
+	assert.Equal(t, "store", getValue(values, []any{"podLabels", "app.kubernetes.io/name"}))
+	assert.Equal(t, []any{"--debug", "--verbose"}, getValue(values, []any{"args"}))
+}
+
+func TestSecretReleaseValues(t *testing.T) {
+	context := Context{
+		Values: map[string][]string{
+			"foo": {"database.password=plain"},
+		},
+		SecretValues: map[string][]string{
+			"foo": {"database.password=set-secret-0", "database.port=set-secret-1"},
+		},
+		Secrets: map[string]string{
+			"set-secret-0": "p@ss,word=${x}",
+			"set-secret-1": "5432",
+		},
+	}
+	values, err := context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "p@ss,word=${x}", values["database"].(map[string]any)["password"])
+	assert.Equal(t, "5432", values["database"].(map[string]any)["port"])
+
+	context.Secrets = nil
+	_, err = context.getReleaseValues("foo", map[string]any{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"helm.sh/helm/v3/pkg/chart"
+	"io"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"log"
+	"time"
+)
+
+const crdPollInterval = 500 * time.Millisecond
+
+// loadCRDs decodes the CustomResourceDefinitions in the crds/ directories of the given chart and its dependencies
+func loadCRDs(chart *chart.Chart) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, object := range chart.CRDObjects() {
+		decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(object.File.Data), 4096)
+		for {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := decoder.Decode(crd); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("failed to decode CRD %s: %w", object.Filename, err)
+			}
+			// Skip empty documents, e.g. a leading or trailing document separator
+			if crd.Name == "" && crd.Kind == "" {
+				continue
+			}
+			if crd.APIVersion != apiextensionsv1.SchemeGroupVersion.String() || crd.Kind != "CustomResourceDefinition" {
+				return nil, fmt.Errorf("failed to decode CRD %s: unsupported object %s %s", object.Filename, crd.APIVersion, crd.Kind)
+			}
+			crds = append(crds, crd)
+		}
+	}
+	return crds, nil
+}
+
+// createCRDs creates the given CustomResourceDefinitions, returning the names of the CRDs that were created
+// As with helm install, CRDs that already exist are left unchanged.
+func createCRDs(ctx context.Context, client apiextensions.Interface, crds []*apiextensionsv1.CustomResourceDefinition) ([]string, error) {
+	var names []string
+	for _, crd := range crds {
+		if _, err := client.ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{}); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				log.Printf("CRD %s is already present. Skipping.", crd.Name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to install CRD %s: %w", crd.Name, err)
+		}
+		names = append(names, crd.Name)
+	}
+	return names, nil
+}
+
+// awaitCRDs waits for the named CustomResourceDefinitions to be established
+// If a CRD's names are not accepted by the API server, an error is returned immediately.
+func awaitCRDs(ctx context.Context, client apiextensions.Interface, names []string) error {
+	for _, name := range names {
+		for {
+			established, err := isCRDEstablished(ctx, client, name)
+			if err != nil {
+				return err
+			}
+			if established {
+				break
+			}
+			select {
+			case <-time.After(crdPollInterval):
+			case <-ctx.Done():
+				return fmt.Errorf("CRD %s not established: %w", name, ctx.Err())
+			}
+		}
+	}
+	return nil
+}
+
+// isCRDEstablished returns whether the named CustomResourceDefinition is established
+func isCRDEstablished(ctx context.Context, client apiextensions.Interface, name string) (bool, error) {
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, condition := range crd.Status.Conditions {
+		switch condition.Type {
+		case apiextensionsv1.Established:
+			if condition.Status == apiextensionsv1.ConditionTrue {
+				return true, nil
+			}
+		case apiextensionsv1.NamesAccepted:
+			if condition.Status == apiextensionsv1.ConditionFalse {
+				return false, fmt.Errorf("CRD %s names not accepted: %s", name, condition.Message)
+			}
+		}
+	}
+	return false, nil
+}
+
+// installCRDs installs the CRDs in the chart's crds/ directories, waiting for them to be established if WaitForCRDs
+// is set on the command
+func (cmd *InstallCmd) installCRDs(ctx context.Context, chart *chart.Chart) error {
+	crds, err := loadCRDs(chart)
+	if err != nil || len(crds) == 0 {
+		return err
+	}
+
+	restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := apiextensions.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cmd.timeout)
+	defer cancel()
+	names, err := createCRDs(ctx, client, crds)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	if cmd.waitForCRDs {
+		if err := awaitCRDs(ctx, client, names); err != nil {
+			return err
+		}
+	}
+
+	// Reset the discovery cache and REST mapper so the release's resources can reference the new CRDs
+	discoveryClient, err := settings.RESTClientGetter().ToDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	discoveryClient.Invalidate()
+	mapper, err := settings.RESTClientGetter().ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	if resettable, ok := mapper.(meta.ResettableRESTMapper); ok {
+		resettable.Reset()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+const testCRDs = `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: bars.example.com
+`
+
+const testDependencyCRDs = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: bazs.example.com
+`
+
+func TestLoadCRDs(t *testing.T) {
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "parent"},
+		Files: []*chart.File{
+			{Name: "crds/crds.yaml", Data: []byte(testCRDs)},
+			{Name: "README.md", Data: []byte("# parent")},
+		},
+	}
+	parent.AddDependency(&chart.Chart{
+		Metadata: &chart.Metadata{Name: "child"},
+		Files: []*chart.File{
+			{Name: "crds/baz.yaml", Data: []byte(testDependencyCRDs)},
+		},
+	})
+
+	crds, err := loadCRDs(parent)
+	assert.NoError(t, err)
+	assert.Len(t, crds, 3)
+	assert.Equal(t, "foos.example.com", crds[0].Name)
+	assert.Equal(t, "bars.example.com", crds[1].Name)
+	assert.Equal(t, "bazs.example.com", crds[2].Name)
+
+	invalid := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "invalid"},
+		Files: []*chart.File{
+			{Name: "crds/foo.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo\n")},
+		},
+	}
+	_, err = loadCRDs(invalid)
+	assert.Error(t, err)
+}
+
+func TestCreateCRDs(t *testing.T) {
+	client := fake.NewSimpleClientset(newCRD("foos.example.com", apiextensionsv1.ConditionTrue))
+	names, err := createCRDs(context.Background(), client, []*apiextensionsv1.CustomResourceDefinition{
+		newCRD("foos.example.com", ""),
+		newCRD("bars.example.com", ""),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bars.example.com"}, names)
+}
+
+func TestAwaitCRDs(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newCRD("foos.example.com", apiextensionsv1.ConditionTrue),
+		newCRD("bars.example.com", apiextensionsv1.ConditionFalse))
+	assert.NoError(t, awaitCRDs(context.Background(), client, []string{"foos.example.com"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := awaitCRDs(ctx, client, []string{"foos.example.com", "bars.example.com"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	rejected := newCRD("bazs.example.com", "")
+	rejected.Status.Conditions = append(rejected.Status.Conditions, apiextensionsv1.CustomResourceDefinitionCondition{
+		Type:    apiextensionsv1.NamesAccepted,
+		Status:  apiextensionsv1.ConditionFalse,
+		Message: "\"bazs\" is already in use",
+	})
+	client = fake.NewSimpleClientset(rejected)
+	assert.Error(t, awaitCRDs(context.Background(), client, []string{"bazs.example.com"}))
+}
+
+func newCRD(name string, established apiextensionsv1.ConditionStatus) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	if established != "" {
+		crd.Status.Conditions = append(crd.Status.Conditions, apiextensionsv1.CustomResourceDefinitionCondition{
+			Type:   apiextensionsv1.Established,
+			Status: established,
+		})
+	}
+	return crd
+}
@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+)
+
+const (
+	// resourcePolicyAnnotation is the annotation with which charts tell Helm to keep resources when uninstalling
+	resourcePolicyAnnotation = "helm.sh/resource-policy"
+	// keepResourcePolicy is the resource policy with which Helm keeps resources when uninstalling
+	keepResourcePolicy  = "keep"
+	deletedPollInterval = time.Second
+)
+
+// ResourceStatus identifies a resource owned by a release
+type ResourceStatus struct {
+	Kind string
+	Name string
+	UID  types.UID
+}
+
+func (s ResourceStatus) String() string {
+	return fmt.Sprintf("%s %s", s.Kind, s.Name)
+}
+
+// NotDeletedError is returned when a release's resources are not deleted before the context is done
+type NotDeletedError struct {
+	Namespace string
+	Release   string
+	// Resources are the resources that still exist
+	Resources []ResourceStatus
+	err       error
+}
+
+func (e *NotDeletedError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "release %s/%s not deleted: %s", e.Namespace, e.Release, e.err)
+	for _, resource := range e.Resources {
+		fmt.Fprintf(&b, "\n  %s", resource)
+	}
+	return b.String()
+}
+
+// Unwrap returns the context error that ended the wait
+func (e *NotDeletedError) Unwrap() error {
+	return e.err
+}
+
+// Resources returns the deployments, stateful sets, daemon sets, jobs, services, config maps, secrets, service
+// accounts, and pods owned by the release, and its persistent volume claims if claims is set
+// Resources that the chart tells Helm to keep on uninstall are not included.
+func (c *ReleaseClient) Resources(ctx context.Context, claims bool) ([]ResourceStatus, error) {
+	var resources []ResourceStatus
+	addResources := func(kind string, objects []metav1.Object) {
+		for _, object := range objects {
+			if object.GetAnnotations()[resourcePolicyAnnotation] == keepResourcePolicy {
+				continue
+			}
+			resources = append(resources, ResourceStatus{
+				Kind: kind,
+				Name: object.GetName(),
+				UID:  object.GetUID(),
+			})
+		}
+	}
+
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("deployment", toObjects(deployments))
+
+	statefulSets, err := c.StatefulSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("statefulset", toObjects(statefulSets))
+
+	daemonSets, err := c.DaemonSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("daemonset", toObjects(daemonSets))
+
+	jobs, err := c.Jobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("job", toObjects(jobs))
+
+	services, err := c.Services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("service", toObjects(services))
+
+	configMaps, err := c.ConfigMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("configmap", toObjects(configMaps))
+
+	secrets, err := c.Secrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("secret", toObjects(secrets))
+
+	serviceAccounts, err := c.ServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("serviceaccount", toObjects(serviceAccounts))
+
+	pods, err := c.Pods(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addResources("pod", toObjects(pods))
+
+	if claims {
+		claims, err := c.PersistentVolumeClaims(ctx)
+		if err != nil {
+			return nil, err
+		}
+		addResources("persistentvolumeclaim", toObjects(claims))
+	}
+	return resources, nil
+}
+
+// DeletePersistentVolumeClaims deletes the given persistent volume claims
+// Helm does not delete the claims created from stateful set volume claim templates, so they must be deleted to
+// reinstall the release with empty volumes.
+func (c *ReleaseClient) DeletePersistentVolumeClaims(ctx context.Context, resources []ResourceStatus) error {
+	for _, resource := range resources {
+		if resource.Kind != "persistentvolumeclaim" {
+			continue
+		}
+		err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).Delete(ctx, resource.Name, metav1.DeleteOptions{
+			Preconditions: metav1.NewUIDPreconditions(string(resource.UID)),
+		})
+		if err != nil && !k8serrors.IsNotFound(err) && !k8serrors.IsConflict(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AwaitDeleted waits for the given resources, returned by Resources before the release was uninstalled, to be
+// removed from the API
+// A resource that has been recreated with the same name, e.g. by a new release, is considered deleted. If the
+// context is done before the resources are deleted, a *NotDeletedError listing the remaining resources is returned.
+func (c *ReleaseClient) AwaitDeleted(ctx context.Context, resources []ResourceStatus) error {
+	for {
+		remaining, err := c.getRemainingResources(ctx, resources)
+		if err == nil && len(remaining) == 0 {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if err == nil {
+			resources = remaining
+		}
+		select {
+		case <-time.After(deletedPollInterval):
+		case <-ctx.Done():
+			return &NotDeletedError{
+				Namespace: c.namespace,
+				Release:   c.release,
+				Resources: resources,
+				err:       ctx.Err(),
+			}
+		}
+	}
+}
+
+// getRemainingResources returns the given resources that still exist
+func (c *ReleaseClient) getRemainingResources(ctx context.Context, resources []ResourceStatus) ([]ResourceStatus, error) {
+	uids := make(map[string]map[types.UID]bool)
+	var remaining []ResourceStatus
+	for _, resource := range resources {
+		if _, ok := uids[resource.Kind]; !ok {
+			objects, err := c.listObjects(ctx, resource.Kind)
+			if err != nil {
+				return nil, err
+			}
+			uids[resource.Kind] = make(map[types.UID]bool)
+			for _, object := range objects {
+				uids[resource.Kind][object.GetUID()] = true
+			}
+		}
+		if uids[resource.Kind][resource.UID] {
+			remaining = append(remaining, resource)
+		}
+	}
+	return remaining, nil
+}
+
+// listObjects lists the objects of the given kind in the release's namespace, whether or not they're owned by
+// the release, since the owners of a release's pods and claims may already have been deleted
+func (c *ReleaseClient) listObjects(ctx context.Context, kind string) ([]metav1.Object, error) {
+	options := metav1.ListOptions{}
+	switch kind {
+	case "deployment":
+		list, err := c.client.AppsV1().Deployments(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "statefulset":
+		list, err := c.client.AppsV1().StatefulSets(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "daemonset":
+		list, err := c.client.AppsV1().DaemonSets(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "job":
+		list, err := c.client.BatchV1().Jobs(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "service":
+		list, err := c.client.CoreV1().Services(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "configmap":
+		list, err := c.client.CoreV1().ConfigMaps(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "secret":
+		list, err := c.client.CoreV1().Secrets(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "serviceaccount":
+		list, err := c.client.CoreV1().ServiceAccounts(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "pod":
+		list, err := c.client.CoreV1().Pods(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	case "persistentvolumeclaim":
+		list, err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		return toObjects(list.Items), nil
+	}
+	return nil, fmt.Errorf("unknown resource kind %s", kind)
+}
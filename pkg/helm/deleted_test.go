@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func TestAwaitDeleted(t *testing.T) {
+	kept := &corev1.Secret{ObjectMeta: newReleaseMeta("foo-credentials", "foo-secret", "foo", "")}
+	kept.Annotations[resourcePolicyAnnotation] = keepResourcePolicy
+	client := fake.NewSimpleClientset(
+		&appsv1.StatefulSet{ObjectMeta: newReleaseMeta("foo-db", "foo-statefulset", "foo", "")},
+		&corev1.Pod{ObjectMeta: newReleaseMeta("foo-db-0", "foo-pod", "", "foo-statefulset")},
+		&corev1.PersistentVolumeClaim{ObjectMeta: newReleaseMeta("data-foo-db-0", "foo-claim", "", "foo-statefulset")},
+		&corev1.Service{ObjectMeta: newReleaseMeta("foo", "foo-service", "foo", "")},
+		&corev1.Service{ObjectMeta: newReleaseMeta("bar", "bar-service", "bar", "")},
+		kept,
+	)
+	ctx := context.Background()
+	releaseClient := NewReleaseClient(client, "test", "foo")
+
+	resources, err := releaseClient.Resources(ctx, false)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []ResourceStatus{
+		{Kind: "statefulset", Name: "foo-db", UID: "foo-statefulset"},
+		{Kind: "service", Name: "foo", UID: "foo-service"},
+		{Kind: "pod", Name: "foo-db-0", UID: "foo-pod"},
+	}, resources)
+
+	resources, err = releaseClient.Resources(ctx, true)
+	assert.NoError(t, err)
+	assert.Len(t, resources, 4)
+	assert.Contains(t, resources, ResourceStatus{Kind: "persistentvolumeclaim", Name: "data-foo-db-0", UID: "foo-claim"})
+
+	// Uninstalling the release deletes the stateful set and service, but not the pod or the claim
+	assert.NoError(t, client.AppsV1().StatefulSets("test").Delete(ctx, "foo-db", metav1.DeleteOptions{}))
+	assert.NoError(t, client.CoreV1().Services("test").Delete(ctx, "foo", metav1.DeleteOptions{}))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	err = releaseClient.AwaitDeleted(timeoutCtx, resources)
+	cancel()
+	var notDeleted *NotDeletedError
+	if assert.True(t, errors.As(err, &notDeleted)) {
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ElementsMatch(t, []ResourceStatus{
+			{Kind: "pod", Name: "foo-db-0", UID: "foo-pod"},
+			{Kind: "persistentvolumeclaim", Name: "data-foo-db-0", UID: "foo-claim"},
+		}, notDeleted.Resources)
+		assert.Contains(t, err.Error(), "pod foo-db-0")
+	}
+
+	// A pod recreated with the same name is not the release's pod
+	assert.NoError(t, client.CoreV1().Pods("test").Delete(ctx, "foo-db-0", metav1.DeleteOptions{}))
+	_, err = client.CoreV1().Pods("test").Create(ctx, &corev1.Pod{ObjectMeta: newReleaseMeta("foo-db-0", "new-pod", "", "")}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, releaseClient.DeletePersistentVolumeClaims(ctx, resources))
+	assert.NoError(t, releaseClient.AwaitDeleted(ctx, resources))
+
+	_, err = client.CoreV1().Secrets("test").Get(ctx, "foo-credentials", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = client.CoreV1().Services("test").Get(ctx, "bar", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
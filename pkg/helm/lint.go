@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/lint/support"
+	"strings"
+)
+
+func newLintCmd(context Context, chart string) *LintCmd {
+	return &LintCmd{
+		context:   context,
+		namespace: context.Namespace,
+		chart:     chart,
+		values:    make(map[string]any),
+	}
+}
+
+// LintCmd is a command for linting a local Helm chart
+type LintCmd struct {
+	context    Context
+	namespace  string
+	chart      string
+	strict     bool
+	values     map[string]any
+	valueFiles []string
+	err        error
+}
+
+// Namespace sets the namespace with which to render the chart's templates
+func (cmd *LintCmd) Namespace(namespace string) *LintCmd {
+	cmd.namespace = namespace
+	return cmd
+}
+
+// Strict fails the lint on warnings as well as errors
+func (cmd *LintCmd) Strict() *LintCmd {
+	cmd.strict = true
+	return cmd
+}
+
+// Set sets a Helm chart value override
+// The path is interpreted as by helm lint --set. An invalid path fails the lint.
+func (cmd *LintCmd) Set(path string, value any) *LintCmd {
+	elems, err := parsePath(path)
+	if err != nil {
+		if cmd.err == nil {
+			cmd.err = err
+		}
+		return cmd
+	}
+	setValue(cmd.values, elems, value)
+	return cmd
+}
+
+// Values adds values files with which to lint the chart
+func (cmd *LintCmd) Values(files ...string) *LintCmd {
+	cmd.valueFiles = append(cmd.valueFiles, files...)
+	return cmd
+}
+
+// Do lints the chart, returning a *LintFailure if the chart has errors, or warnings in strict mode
+func (cmd *LintCmd) Do(ctx context.Context) error {
+	result, err := cmd.Get(ctx)
+	if err != nil {
+		return err
+	}
+	var failures []LintMessage
+	for _, message := range result.Messages {
+		if message.Severity == LintError || (cmd.strict && message.Severity == LintWarning) {
+			failures = append(failures, message)
+		}
+	}
+	if len(failures) > 0 {
+		return &LintFailure{
+			Chart:    cmd.chart,
+			Messages: failures,
+		}
+	}
+	return nil
+}
+
+// Get lints the chart and returns all the messages reported by the linter
+func (cmd *LintCmd) Get(ctx context.Context) (*LintResult, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	values, err := cmd.context.getReleaseValues("", cmd.values, cmd.valueFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	lint := action.NewLint()
+	lint.Namespace = cmd.namespace
+	lint.Strict = cmd.strict
+	lint.WithSubcharts = true
+	result := lint.Run([]string{cmd.chart}, values)
+	if result.TotalChartsLinted == 0 {
+		// The chart could not be loaded
+		if len(result.Errors) > 0 {
+			return nil, result.Errors[0]
+		}
+		return nil, fmt.Errorf("failed to lint chart %s", cmd.chart)
+	}
+
+	lintResult := &LintResult{
+		Chart: cmd.chart,
+	}
+	for _, message := range result.Messages {
+		lintResult.Messages = append(lintResult.Messages, LintMessage{
+			Severity: getLintSeverity(message.Severity),
+			Path:     message.Path,
+			Message:  message.Err.Error(),
+		})
+	}
+	return lintResult, nil
+}
+
+// LintSeverity is the severity of a lint message
+type LintSeverity string
+
+const (
+	// LintInfo is the severity of informational messages, e.g. a missing icon
+	LintInfo LintSeverity = "INFO"
+	// LintWarning is the severity of messages for charts that do not meet conventions but will likely work
+	LintWarning LintSeverity = "WARNING"
+	// LintError is the severity of messages for charts that will likely not work
+	LintError LintSeverity = "ERROR"
+)
+
+func getLintSeverity(severity int) LintSeverity {
+	switch severity {
+	case support.ErrorSev:
+		return LintError
+	case support.WarningSev:
+		return LintWarning
+	default:
+		return LintInfo
+	}
+}
+
+// LintMessage is a message reported by the chart linter
+type LintMessage struct {
+	Severity LintSeverity
+	// Path is the path of the chart file the message refers to
+	Path    string
+	Message string
+}
+
+func (m LintMessage) String() string {
+	return fmt.Sprintf("[%s] %s: %s", m.Severity, m.Path, m.Message)
+}
+
+// LintResult is the result of linting a chart
+type LintResult struct {
+	Chart    string
+	Messages []LintMessage
+}
+
+// LintFailure is returned when a chart fails linting
+type LintFailure struct {
+	Chart string
+	// Messages are the messages that failed the lint
+	Messages []LintMessage
+}
+
+func (e *LintFailure) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "chart %s failed linting", e.Chart)
+	for _, message := range e.Messages {
+		fmt.Fprintf(&b, "\n  %s", message)
+	}
+	return b.String()
+}
@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	readyPollInterval = time.Second
+	maxWarningEvents  = 10
+)
+
+// WorkloadStatus is the readiness of a workload
+type WorkloadStatus struct {
+	Kind    string
+	Name    string
+	Ready   int32
+	Desired int32
+}
+
+func (s WorkloadStatus) String() string {
+	return fmt.Sprintf("%s %s: %d/%d ready", s.Kind, s.Name, s.Ready, s.Desired)
+}
+
+// PodStatus is the status of a pod that is not ready
+type PodStatus struct {
+	Name    string
+	Phase   corev1.PodPhase
+	Reason  string
+	Message string
+}
+
+func (s PodStatus) String() string {
+	status := fmt.Sprintf("pod %s: %s", s.Name, s.Phase)
+	if s.Reason != "" {
+		status = fmt.Sprintf("%s (%s)", status, s.Reason)
+	}
+	if s.Message != "" {
+		status = fmt.Sprintf("%s: %s", status, s.Message)
+	}
+	return status
+}
+
+// Event is a warning event for a workload or pod that is not ready
+type Event struct {
+	Kind    string
+	Name    string
+	Reason  string
+	Message string
+	Time    time.Time
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s %s %s: %s", e.Time.Format(time.RFC3339), e.Kind, e.Name, e.Reason, e.Message)
+}
+
+// NotReadyError is returned when a release's workloads do not become ready before the context is done
+type NotReadyError struct {
+	Namespace string
+	Release   string
+	// Workloads are the deployments, stateful sets, and daemon sets that are not ready
+	Workloads []WorkloadStatus
+	// Pods are the pods that are not ready, with the reason they're not ready
+	Pods []PodStatus
+	// Events are the most recent warning events for the workloads and pods that are not ready
+	Events []Event
+	err    error
+}
+
+func (e *NotReadyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "release %s/%s not ready: %s", e.Namespace, e.Release, e.err)
+	for _, workload := range e.Workloads {
+		fmt.Fprintf(&b, "\n  %s", workload)
+	}
+	for _, pod := range e.Pods {
+		fmt.Fprintf(&b, "\n  %s", pod)
+	}
+	if len(e.Events) > 0 {
+		fmt.Fprintf(&b, "\n  recent warning events:")
+		for _, event := range e.Events {
+			fmt.Fprintf(&b, "\n    %s", event)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the context error that ended the wait
+func (e *NotReadyError) Unwrap() error {
+	return e.err
+}
+
+// AwaitReady waits for all the deployments, stateful sets, and daemon sets owned by the release to be ready
+// If the context is done before the workloads are ready, a *NotReadyError describing the unready workloads,
+// the pods that are not ready, and recent warning events is returned.
+func (c *ReleaseClient) AwaitReady(ctx context.Context) error {
+	for {
+		workloads, err := c.getUnreadyWorkloads(ctx)
+		if err == nil && len(workloads) == 0 {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-time.After(readyPollInterval):
+		case <-ctx.Done():
+			return c.diagnose(ctx.Err())
+		}
+	}
+}
+
+// getUnreadyWorkloads returns the status of the release's workloads that are not ready
+func (c *ReleaseClient) getUnreadyWorkloads(ctx context.Context) ([]WorkloadStatus, error) {
+	var workloads []WorkloadStatus
+	deployments, err := c.Deployments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, deployment := range deployments {
+		if status, ready := getDeploymentStatus(deployment); !ready {
+			workloads = append(workloads, status)
+		}
+	}
+
+	statefulSets, err := c.StatefulSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range statefulSets {
+		if status, ready := getStatefulSetStatus(statefulSet); !ready {
+			workloads = append(workloads, status)
+		}
+	}
+
+	daemonSets, err := c.DaemonSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, daemonSet := range daemonSets {
+		if status, ready := getDaemonSetStatus(daemonSet); !ready {
+			workloads = append(workloads, status)
+		}
+	}
+	return workloads, nil
+}
+
+func getDeploymentStatus(deployment appsv1.Deployment) (WorkloadStatus, bool) {
+	status := WorkloadStatus{
+		Kind:    "deployment",
+		Name:    deployment.Name,
+		Ready:   deployment.Status.AvailableReplicas,
+		Desired: getReplicas(deployment.Spec.Replicas),
+	}
+	return status, deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == status.Desired &&
+		deployment.Status.AvailableReplicas == status.Desired
+}
+
+func getStatefulSetStatus(statefulSet appsv1.StatefulSet) (WorkloadStatus, bool) {
+	status := WorkloadStatus{
+		Kind:    "statefulset",
+		Name:    statefulSet.Name,
+		Ready:   statefulSet.Status.ReadyReplicas,
+		Desired: getReplicas(statefulSet.Spec.Replicas),
+	}
+	return status, statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.ReadyReplicas == status.Desired
+}
+
+func getDaemonSetStatus(daemonSet appsv1.DaemonSet) (WorkloadStatus, bool) {
+	status := WorkloadStatus{
+		Kind:    "daemonset",
+		Name:    daemonSet.Name,
+		Ready:   daemonSet.Status.NumberReady,
+		Desired: daemonSet.Status.DesiredNumberScheduled,
+	}
+	return status, daemonSet.Status.ObservedGeneration >= daemonSet.Generation &&
+		daemonSet.Status.NumberReady == status.Desired
+}
+
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// diagnose returns a NotReadyError describing why the release is not ready
+// The diagnosis is best effort, using a new context since the wait context is done.
+func (c *ReleaseClient) diagnose(err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	diagnosis := &NotReadyError{
+		Namespace: c.namespace,
+		Release:   c.release,
+		err:       err,
+	}
+	diagnosis.Workloads, _ = c.getUnreadyWorkloads(ctx)
+
+	objects := make(map[string]bool)
+	for _, workload := range diagnosis.Workloads {
+		objects[workload.Name] = true
+	}
+	if pods, err := c.Pods(ctx); err == nil {
+		for _, pod := range pods {
+			if status, ready := getPodStatus(pod); !ready {
+				diagnosis.Pods = append(diagnosis.Pods, status)
+				objects[pod.Name] = true
+			}
+		}
+	}
+	diagnosis.Events = getWarningEvents(ctx, c.client, c.namespace, objects)
+	return diagnosis
+}
+
+// getPodStatus returns the status of the given pod, and whether the pod is ready
+func getPodStatus(pod corev1.Pod) (PodStatus, bool) {
+	status := PodStatus{
+		Name:    pod.Name,
+		Phase:   pod.Status.Phase,
+		Reason:  pod.Status.Reason,
+		Message: pod.Status.Message,
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return status, true
+	}
+	for _, condition := range pod.Status.Conditions {
+		switch condition.Type {
+		case corev1.PodReady:
+			if condition.Status == corev1.ConditionTrue {
+				return status, true
+			}
+		case corev1.PodScheduled:
+			if condition.Status == corev1.ConditionFalse {
+				status.Reason = condition.Reason
+				status.Message = condition.Message
+				return status, false
+			}
+		}
+	}
+	// Report the first container that is waiting or has terminated, e.g. with ImagePullBackOff or CrashLoopBackOff
+	var containerStatuses []corev1.ContainerStatus
+	containerStatuses = append(containerStatuses, pod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, pod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		if waiting := containerStatus.State.Waiting; waiting != nil && waiting.Reason != "" {
+			status.Reason = waiting.Reason
+			status.Message = fmt.Sprintf("container %s: %s", containerStatus.Name, waiting.Message)
+			break
+		}
+		if terminated := containerStatus.LastTerminationState.Terminated; terminated != nil && !containerStatus.Ready {
+			status.Reason = terminated.Reason
+			status.Message = fmt.Sprintf("container %s exited with code %d", containerStatus.Name, terminated.ExitCode)
+			break
+		}
+	}
+	return status, false
+}
+
+// getWarningEvents returns the most recent warning events for the named objects in the given namespace
+func getWarningEvents(ctx context.Context, client kubernetes.Interface, namespace string, objects map[string]bool) []Event {
+	if len(objects) == 0 {
+		return nil
+	}
+	list, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + corev1.EventTypeWarning,
+	})
+	if err != nil {
+		return nil
+	}
+	var events []Event
+	for _, event := range list.Items {
+		if event.Type != corev1.EventTypeWarning || !objects[event.InvolvedObject.Name] {
+			continue
+		}
+		timestamp := event.LastTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = event.EventTime.Time
+		}
+		events = append(events, Event{
+			Kind:    strings.ToLower(event.InvolvedObject.Kind),
+			Name:    event.InvolvedObject.Name,
+			Reason:  event.Reason,
+			Message: event.Message,
+			Time:    timestamp,
+		})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	if len(events) > maxWarningEvents {
+		events = events[len(events)-maxWarningEvents:]
+	}
+	return events
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+	"time"
+)
+
+func TestAwaitReady(t *testing.T) {
+	replicas := int32(1)
+	ready := &appsv1.Deployment{
+		ObjectMeta: newReleaseMeta("foo", "foo-deployment", "foo", ""),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			UpdatedReplicas:   1,
+			AvailableReplicas: 1,
+		},
+	}
+	client := fake.NewSimpleClientset(ready)
+	assert.NoError(t, NewReleaseClient(client, "test", "foo").AwaitReady(context.Background()))
+}
+
+func TestAwaitReadyDiagnosis(t *testing.T) {
+	replicas := int32(1)
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: newReleaseMeta("foo", "foo-deployment", "foo", ""),
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+			},
+		},
+		&appsv1.ReplicaSet{ObjectMeta: newReleaseMeta("foo-1", "foo-replicaset", "", "foo-deployment")},
+		&corev1.Pod{
+			ObjectMeta: newReleaseMeta("foo-1-a", "foo-pod", "", "foo-replicaset"),
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "foo",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{
+								Reason:  "ImagePullBackOff",
+								Message: "Back-off pulling image \"foo:missing\"",
+							},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-1-a.1",
+				Namespace: "test",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: "foo-1-a",
+			},
+			Type:          corev1.EventTypeWarning,
+			Reason:        "Failed",
+			Message:       "Failed to pull image \"foo:missing\"",
+			LastTimestamp: metav1.Now(),
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := NewReleaseClient(client, "test", "foo").AwaitReady(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var notReady *NotReadyError
+	assert.True(t, errors.As(err, &notReady))
+	assert.Len(t, notReady.Workloads, 1)
+	assert.Equal(t, "foo", notReady.Workloads[0].Name)
+	assert.Equal(t, int32(0), notReady.Workloads[0].Ready)
+	assert.Len(t, notReady.Pods, 1)
+	assert.Equal(t, "foo-1-a", notReady.Pods[0].Name)
+	assert.Equal(t, "ImagePullBackOff", notReady.Pods[0].Reason)
+	assert.Len(t, notReady.Events, 1)
+	assert.Equal(t, "Failed", notReady.Events[0].Reason)
+	assert.Contains(t, err.Error(), "ImagePullBackOff")
+}
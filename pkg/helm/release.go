@@ -14,6 +14,7 @@ import (
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/kubernetes"
 	"os"
 	"time"
 )
@@ -25,6 +26,7 @@ func newReleaseCmd[T any](cmd T, context Context, release string, chart string)
 		context:   context,
 		namespace: context.Namespace,
 		release:   release,
+		key:       release,
 		chart:     chart,
 		values:    make(map[string]any),
 		timeout:   defaultTimeout,
@@ -37,6 +39,7 @@ type ReleaseCmd[T any] struct {
 	context    Context
 	namespace  string
 	release    string
+	key        string
 	chart      string
 	version    string
 	repoURL    string
@@ -47,9 +50,12 @@ type ReleaseCmd[T any] struct {
 	verify     bool
 	dryRun     bool
 	wait       bool
+	waitReady  bool
 	timeout    time.Duration
 	values     map[string]any
 	valueFiles []string
+	releases   *releaseRegistry
+	err        error
 	cmd        T
 }
 
@@ -113,6 +119,33 @@ func (cmd *ReleaseCmd[T]) Wait() T {
 	return cmd.cmd
 }
 
+// WaitReady configures the command to wait for all the release's workloads to be ready before returning Get or
+// Do calls
+// Unlike Wait, if the workloads are not ready before the timeout, a *NotReadyError describing the unready
+// workloads and pods is returned.
+func (cmd *ReleaseCmd[T]) WaitReady() T {
+	cmd.waitReady = true
+	return cmd.cmd
+}
+
+// awaitReady waits for the release's workloads to be ready if WaitReady is set
+func (cmd *ReleaseCmd[T]) awaitReady(ctx context.Context) error {
+	if !cmd.waitReady || cmd.dryRun {
+		return nil
+	}
+	config, err := settings.RESTClientGetter().ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, cmd.timeout)
+	defer cancel()
+	return NewReleaseClient(client, cmd.namespace, cmd.release).AwaitReady(ctx)
+}
+
 // Timeout sets the installation timeout
 func (cmd *ReleaseCmd[T]) Timeout(timeout time.Duration) T {
 	cmd.timeout = timeout
@@ -120,8 +153,17 @@ func (cmd *ReleaseCmd[T]) Timeout(timeout time.Duration) T {
 }
 
 // Set sets a Helm chart value override
+// The path is interpreted as by helm install --set, so it may contain list indices, e.g. "servers[0].port", and
+// escaped dots, e.g. "podLabels.app\.kubernetes\.io/name". An invalid path fails the install or upgrade.
 func (cmd *ReleaseCmd[T]) Set(path string, value interface{}) T {
-	setKey(cmd.values, getPathNames(path), value)
+	elems, err := parsePath(path)
+	if err != nil {
+		if cmd.err == nil {
+			cmd.err = err
+		}
+		return cmd.cmd
+	}
+	setValue(cmd.values, elems, value)
 	return cmd.cmd
 }
 
@@ -175,6 +217,33 @@ func newInstallCmd(context Context, release string, chart string) *InstallCmd {
 // InstallCmd is a command for installing a Helm chart
 type InstallCmd struct {
 	*ReleaseCmd[*InstallCmd]
+	includeCRDs bool
+	waitForCRDs bool
+	requires    []string
+}
+
+// IncludeCRDs configures the command to install the CRDs in the chart's crds/ directories before installing the
+// release, rather than leaving them to Helm
+// CRDs that already exist are left unchanged. IncludeCRDs has no effect if SkipCRDs is set.
+func (cmd *InstallCmd) IncludeCRDs() *InstallCmd {
+	cmd.includeCRDs = true
+	return cmd
+}
+
+// WaitForCRDs configures the command to wait for the chart's CRDs to be established before installing the release
+// This avoids races where the release or the test creates custom resources the API server does not yet recognize.
+// WaitForCRDs implies IncludeCRDs.
+func (cmd *InstallCmd) WaitForCRDs() *InstallCmd {
+	cmd.includeCRDs = true
+	cmd.waitForCRDs = true
+	return cmd
+}
+
+// Requires declares the releases that must be installed before this release when installed with InstallAll
+// Releases are named as they're passed to Install, without any prefix.
+func (cmd *InstallCmd) Requires(releases ...string) *InstallCmd {
+	cmd.requires = append(cmd.requires, releases...)
+	return cmd
 }
 
 // Do runs the command
@@ -202,6 +271,7 @@ func (cmd *InstallCmd) Get(ctx context.Context) (*Release, error) {
 
 // run runs the command
 func (cmd *InstallCmd) run(ctx context.Context) (*release.Release, error) {
+	defer writeTiming("install", cmd.release, time.Now())
 	config, err := getConfig(cmd.namespace)
 	if err != nil {
 		return nil, err
@@ -221,6 +291,10 @@ func (cmd *InstallCmd) run(ctx context.Context) (*release.Release, error) {
 	install.DryRun = cmd.dryRun
 	install.Timeout = cmd.timeout
 
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+
 	chart, err := cmd.loadChart(install.ChartPathOptions)
 	if err != nil {
 		return nil, err
@@ -231,11 +305,28 @@ func (cmd *InstallCmd) run(ctx context.Context) (*release.Release, error) {
 		return nil, err
 	}
 
-	values, err := cmd.context.getReleaseValues(cmd.release, cmd.values, cmd.valueFiles)
+	if cmd.includeCRDs && !cmd.skipCRDs && !cmd.dryRun {
+		if err := cmd.installCRDs(ctx, chart); err != nil {
+			return nil, err
+		}
+		install.SkipCRDs = true
+	}
+
+	values, err := cmd.context.getReleaseValues(cmd.key, cmd.values, cmd.valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	release, err := install.RunWithContext(ctx, chart, values)
 	if err != nil {
 		return nil, err
 	}
-	return install.RunWithContext(ctx, chart, values)
+	if !cmd.dryRun {
+		cmd.releases.add(cmd.namespace, cmd.release)
+	}
+	if err := cmd.awaitReady(ctx); err != nil {
+		return nil, err
+	}
+	return release, nil
 }
 
 func newUpgradeCmd(context Context, release string, chart string) *UpgradeCmd {
@@ -281,6 +372,7 @@ func (cmd *UpgradeCmd) Get(ctx context.Context) (*Release, error) {
 
 // run runs the command
 func (cmd *UpgradeCmd) run(ctx context.Context) (*release.Release, error) {
+	defer writeTiming("upgrade", cmd.release, time.Now())
 	config, err := getConfig(cmd.namespace)
 	if err != nil {
 		return nil, err
@@ -300,6 +392,10 @@ func (cmd *UpgradeCmd) run(ctx context.Context) (*release.Release, error) {
 	upgrade.Wait = cmd.wait
 	upgrade.Timeout = cmd.timeout
 
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+
 	chart, err := cmd.loadChart(upgrade.ChartPathOptions)
 	if err != nil {
 		return nil, err
@@ -310,11 +406,21 @@ func (cmd *UpgradeCmd) run(ctx context.Context) (*release.Release, error) {
 		return nil, err
 	}
 
-	values, err := cmd.context.getReleaseValues(cmd.release, cmd.values, cmd.valueFiles)
+	values, err := cmd.context.getReleaseValues(cmd.key, cmd.values, cmd.valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	release, err := upgrade.RunWithContext(ctx, cmd.release, chart, values)
 	if err != nil {
 		return nil, err
 	}
-	return upgrade.RunWithContext(ctx, cmd.release, chart, values)
+	if cmd.install && !cmd.dryRun {
+		cmd.releases.add(cmd.namespace, cmd.release)
+	}
+	if err := cmd.awaitReady(ctx); err != nil {
+		return nil, err
+	}
+	return release, nil
 }
 
 func newUninstall(context Context, release string) *UninstallCmd {
@@ -328,11 +434,14 @@ func newUninstall(context Context, release string) *UninstallCmd {
 
 // UninstallCmd is a command for uninstalling a Helm chart release
 type UninstallCmd struct {
-	context   Context
-	namespace string
-	release   string
-	wait      bool
-	timeout   time.Duration
+	context         Context
+	namespace       string
+	release         string
+	wait            bool
+	waitForDeletion bool
+	deleteClaims    bool
+	timeout         time.Duration
+	releases        *releaseRegistry
 }
 
 // Namespace sets the namespace in which to run the command
@@ -347,6 +456,24 @@ func (cmd *UninstallCmd) Wait() *UninstallCmd {
 	return cmd
 }
 
+// WaitForDeletion configures the command to wait for all the resources owned by the release, including the pods
+// created by its workloads, to be removed from the API before returning
+// Helm returns once it has requested the deletion of the release's resources, so reinstalling a release with the
+// same name can fail while they're terminating. If the resources are not deleted before the timeout, a
+// *NotDeletedError listing the remaining resources is returned.
+func (cmd *UninstallCmd) WaitForDeletion() *UninstallCmd {
+	cmd.waitForDeletion = true
+	return cmd
+}
+
+// DeletePersistentVolumeClaims configures the command to delete the persistent volume claims created from the
+// volume claim templates of the release's stateful sets, which Helm does not delete
+// With WaitForDeletion, the command also waits for the claims to be removed from the API.
+func (cmd *UninstallCmd) DeletePersistentVolumeClaims() *UninstallCmd {
+	cmd.deleteClaims = true
+	return cmd
+}
+
 // Timeout sets the command timeout
 func (cmd *UninstallCmd) Timeout(timeout time.Duration) *UninstallCmd {
 	cmd.timeout = timeout
@@ -355,16 +482,51 @@ func (cmd *UninstallCmd) Timeout(timeout time.Duration) *UninstallCmd {
 
 // Do runs the command
 func (cmd *UninstallCmd) Do(ctx context.Context) error {
+	defer writeTiming("uninstall", cmd.release, time.Now())
 	config, err := getConfig(cmd.namespace)
 	if err != nil {
 		return err
 	}
 
+	// The release's resources are listed before uninstalling it, since the pods and claims owned by its workloads
+	// can't be attributed to it once the workloads have been deleted
+	var client *ReleaseClient
+	var resources []ResourceStatus
+	if cmd.waitForDeletion || cmd.deleteClaims {
+		restConfig, err := settings.RESTClientGetter().ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		clientset, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return err
+		}
+		client = NewReleaseClient(clientset, cmd.namespace, cmd.release)
+		resources, err = client.Resources(ctx, cmd.deleteClaims)
+		if err != nil {
+			return err
+		}
+	}
+
 	uninstall := action.NewUninstall(config)
 	uninstall.Wait = cmd.wait
 	uninstall.Timeout = cmd.timeout
-	_, err = uninstall.Run(cmd.release)
-	return err
+	if _, err := uninstall.Run(cmd.release); err != nil {
+		return err
+	}
+	cmd.releases.remove(cmd.namespace, cmd.release)
+
+	if cmd.deleteClaims {
+		if err := client.DeletePersistentVolumeClaims(ctx, resources); err != nil {
+			return err
+		}
+	}
+	if cmd.waitForDeletion {
+		ctx, cancel := context.WithTimeout(ctx, cmd.timeout)
+		defer cancel()
+		return client.AwaitDeleted(ctx, resources)
+	}
+	return nil
 }
 
 // Release is a release configuration
@@ -375,10 +537,15 @@ type Release struct {
 }
 
 // Get gets a value from the release
+// The path is interpreted as by helm install --set. If the path is invalid or does not exist, the value is nil.
 func (r *Release) Get(path string) Value {
+	var value any
+	if elems, err := parsePath(path); err == nil {
+		value = getValue(r.values, elems)
+	}
 	return Value{
 		Path:  path,
-		Value: types.NewValue(getValue(r.values, getPathNames(path))),
+		Value: types.NewValue(value),
 	}
 }
 
@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+func newReleaseRegistry() *releaseRegistry {
+	return &releaseRegistry{}
+}
+
+// releaseRegistry tracks the releases installed by a client in the order in which they were installed
+type releaseRegistry struct {
+	releases []*Release
+	mu       sync.Mutex
+}
+
+func (r *releaseRegistry) add(namespace string, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, release := range r.releases {
+		if release.Namespace == namespace && release.Name == name {
+			return
+		}
+	}
+	r.releases = append(r.releases, &Release{
+		Namespace: namespace,
+		Name:      name,
+	})
+}
+
+func (r *releaseRegistry) remove(namespace string, name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, release := range r.releases {
+		if release.Namespace == namespace && release.Name == name {
+			r.releases = append(r.releases[:i], r.releases[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *releaseRegistry) list() []*Release {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	releases := make([]*Release, len(r.releases))
+	copy(releases, r.releases)
+	return releases
+}
+
+// Releases returns the releases installed by the client that have not been uninstalled, in the order in which
+// they were installed
+// The returned releases carry only their namespace and name.
+func (helm *Helm) Releases() []*Release {
+	if helm == nil {
+		return nil
+	}
+	return helm.releases.list()
+}
+
+// UninstallAll uninstalls the releases installed by the client in the reverse of the order in which they were
+// installed
+// Releases that were already uninstalled, e.g. by another client, are ignored.
+func (helm *Helm) UninstallAll(ctx context.Context) error {
+	var errs []string
+	releases := helm.Releases()
+	for i := len(releases) - 1; i >= 0; i-- {
+		release := releases[i]
+		cmd := newUninstall(helm.context, release.Name).Namespace(release.Namespace).Wait()
+		cmd.releases = helm.releases
+		if err := cmd.Do(ctx); err != nil {
+			if !errors.Is(err, driver.ErrReleaseNotFound) {
+				errs = append(errs, fmt.Sprintf("failed to uninstall release %s: %s", release.Name, err))
+				continue
+			}
+			helm.releases.remove(release.Namespace, release.Name)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// InstallAll installs the releases of the given install commands concurrently, installing each release once the
+// releases it Requires have been installed
+// The progress of each install is written to stdout. If a release fails to install, the releases that require it
+// are not installed while the remaining installs run to completion, and an error describing all the releases that
+// were not installed is returned.
+func (helm *Helm) InstallAll(ctx context.Context, releases ...*InstallCmd) error {
+	return installAll(ctx, releases, (*InstallCmd).Do, os.Stdout)
+}
+
+func installAll(ctx context.Context, cmds []*InstallCmd, install func(*InstallCmd, context.Context) error, out io.Writer) error {
+	if err := checkRequires(cmds); err != nil {
+		return err
+	}
+
+	done := make(map[string]chan struct{})
+	indexes := make(map[string]int)
+	for i, cmd := range cmds {
+		done[cmd.key] = make(chan struct{})
+		indexes[cmd.key] = i
+	}
+
+	var mu sync.Mutex
+	printf := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(out, format, args...)
+	}
+
+	errs := make([]error, len(cmds))
+	var wg sync.WaitGroup
+	for i, cmd := range cmds {
+		wg.Add(1)
+		go func(i int, cmd *InstallCmd) {
+			defer wg.Done()
+			defer close(done[cmd.key])
+			for _, dependency := range cmd.requires {
+				select {
+				case <-done[dependency]:
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					printf("Cancelled release %s: %s\n", cmd.release, errs[i])
+					return
+				}
+				if errs[indexes[dependency]] != nil {
+					errs[i] = fmt.Errorf("required release %s was not installed", dependency)
+					printf("Skipped release %s: %s\n", cmd.release, errs[i])
+					return
+				}
+			}
+			printf("Installing release %s\n", cmd.release)
+			start := time.Now()
+			if err := install(cmd, ctx); err != nil {
+				errs[i] = err
+				printf("Failed to install release %s: %s\n", cmd.release, err)
+				return
+			}
+			printf("Installed release %s (%.2fs)\n", cmd.release, time.Since(start).Seconds())
+		}(i, cmd)
+	}
+	wg.Wait()
+
+	var messages []string
+	for i, err := range errs {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("failed to install release %s: %s", cmds[i].release, err))
+		}
+	}
+	if len(messages) > 0 {
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// checkRequires checks that the given install commands install each release once and that the releases they
+// require are installed by the commands without a dependency cycle
+func checkRequires(cmds []*InstallCmd) error {
+	requires := make(map[string][]string)
+	for _, cmd := range cmds {
+		if _, ok := requires[cmd.key]; ok {
+			return fmt.Errorf("release %s is installed more than once", cmd.key)
+		}
+		requires[cmd.key] = cmd.requires
+	}
+
+	checked := make(map[string]bool)
+	var check func(name string, path []string) error
+	check = func(name string, path []string) error {
+		for _, parent := range path {
+			if parent == name {
+				return fmt.Errorf("release dependency cycle: %s", strings.Join(append(path, name), " -> "))
+			}
+		}
+		if checked[name] {
+			return nil
+		}
+		for _, dependency := range requires[name] {
+			if _, ok := requires[dependency]; !ok {
+				return fmt.Errorf("release %s requires release %s, which is not being installed", name, dependency)
+			}
+			if err := check(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		checked[name] = true
+		return nil
+	}
+	for _, cmd := range cmds {
+		if err := check(cmd.key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
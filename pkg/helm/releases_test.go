@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestReleasePrefix(t *testing.T) {
+	client := &Helm{
+		context:  Context{ID: "test-abc", Namespace: "test"},
+		releases: newReleaseRegistry(),
+	}
+	assert.Equal(t, "foo", client.ReleaseName("foo"))
+
+	prefixed := client.WithPrefix()
+	assert.Equal(t, "test-abc-foo", prefixed.ReleaseName("foo"))
+	install := prefixed.Install("foo", "chart")
+	assert.Equal(t, "test-abc-foo", install.release)
+	assert.Equal(t, "foo", install.key)
+	assert.Equal(t, "test-abc-foo", prefixed.Uninstall("foo").release)
+
+	// Release names are not prefixed outside a job
+	unprefixed := (&Helm{context: Context{Namespace: "test"}}).WithPrefix()
+	assert.Equal(t, "foo", unprefixed.ReleaseName("foo"))
+}
+
+func TestReleaseRegistry(t *testing.T) {
+	client := &Helm{
+		context:  Context{Namespace: "test"},
+		releases: newReleaseRegistry(),
+	}
+	prefixed := client.WithPrefix()
+	untracked := client.Untracked()
+	assert.Nil(t, untracked.releases)
+
+	client.releases.add("test", "foo")
+	prefixed.releases.add("test", "bar")
+	prefixed.releases.add("test", "bar")
+	untracked.releases.add("test", "baz")
+	client.releases.add("other", "foo")
+
+	releases := client.Releases()
+	assert.Len(t, releases, 3)
+	assert.Equal(t, "test", releases[0].Namespace)
+	assert.Equal(t, "foo", releases[0].Name)
+	assert.Equal(t, "bar", releases[1].Name)
+	assert.Equal(t, "other", releases[2].Namespace)
+	assert.Equal(t, releases, prefixed.Releases())
+	assert.Empty(t, untracked.Releases())
+
+	client.releases.remove("test", "foo")
+	releases = client.Releases()
+	assert.Len(t, releases, 2)
+	assert.Equal(t, "bar", releases[0].Name)
+	assert.Equal(t, "foo", releases[1].Name)
+	assert.Equal(t, "other", releases[1].Namespace)
+
+	var nilClient *Helm
+	assert.Empty(t, nilClient.Releases())
+	assert.Nil(t, nilClient.Untracked())
+}
+
+func TestInstallAll(t *testing.T) {
+	client := &Helm{
+		context:  Context{Namespace: "test"},
+		releases: newReleaseRegistry(),
+	}
+
+	var mu sync.Mutex
+	var installed []string
+	install := func(cmd *InstallCmd, ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, dependency := range cmd.requires {
+			assert.Contains(t, installed, dependency)
+		}
+		if cmd.key == "broken" {
+			return errors.New("install failed")
+		}
+		installed = append(installed, cmd.key)
+		return nil
+	}
+
+	out := &bytes.Buffer{}
+	err := installAll(context.Background(), []*InstallCmd{
+		client.Install("worker-1", "chart").Requires("controller"),
+		client.Install("worker-2", "chart").Requires("controller", "database"),
+		client.Install("controller", "chart").Requires("database"),
+		client.Install("database", "chart"),
+	}, install, out)
+	assert.NoError(t, err)
+	assert.Len(t, installed, 4)
+	assert.Equal(t, "database", installed[0])
+	assert.Equal(t, "controller", installed[1])
+	assert.Contains(t, out.String(), "Installing release worker-1\n")
+	assert.Contains(t, out.String(), "Installed release worker-2 (")
+
+	installed = nil
+	out.Reset()
+	err = installAll(context.Background(), []*InstallCmd{
+		client.Install("broken", "chart"),
+		client.Install("worker", "chart").Requires("broken"),
+		client.Install("other", "chart"),
+	}, install, out)
+	assert.EqualError(t, err, "failed to install release broken: install failed; "+
+		"failed to install release worker: required release broken was not installed")
+	assert.Equal(t, []string{"other"}, installed)
+	assert.Contains(t, out.String(), "Failed to install release broken: install failed\n")
+	assert.Contains(t, out.String(), "Skipped release worker: required release broken was not installed\n")
+
+	installed = nil
+	err = installAll(context.Background(), []*InstallCmd{
+		client.Install("a", "chart").Requires("b"),
+		client.Install("b", "chart").Requires("c"),
+		client.Install("c", "chart").Requires("a"),
+	}, install, out)
+	assert.EqualError(t, err, "release dependency cycle: a -> b -> c -> a")
+	assert.Empty(t, installed)
+
+	err = installAll(context.Background(), []*InstallCmd{
+		client.Install("a", "chart").Requires("b"),
+	}, install, out)
+	assert.EqualError(t, err, "release a requires release b, which is not being installed")
+
+	err = installAll(context.Background(), []*InstallCmd{
+		client.Install("a", "chart"),
+		client.WithPrefix().Install("a", "chart"),
+	}, install, out)
+	assert.EqualError(t, err, "release a is installed more than once")
+}
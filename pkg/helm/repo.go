@@ -34,6 +34,12 @@ func (repo *RepoCmd) Add(name string, url string) *RepoAddCmd {
 	return newRepoAdd(repo.context, name, url)
 }
 
+// Update creates a Helm repository update command
+// If no names are provided, all configured repositories are updated.
+func (repo *RepoCmd) Update(names ...string) *RepoUpdateCmd {
+	return newRepoUpdate(repo.context, names...)
+}
+
 // Remove creates a Helm repository remove command
 func (repo *RepoCmd) Remove(name string) *RepoRemoveCmd {
 	return newRepoRemove(repo.context, name)
@@ -79,25 +85,11 @@ func (cmd *RepoAddCmd) Do(ctx context.Context) error {
 	}
 
 	// Acquire a file lock for process synchronization
-	repoFileExt := filepath.Ext(repoFile)
-	var lockPath string
-	if len(repoFileExt) > 0 && len(repoFileExt) < len(repoFile) {
-		lockPath = strings.TrimSuffix(repoFile, repoFileExt) + ".lock"
-	} else {
-		lockPath = repoFile + ".lock"
-	}
-	fileLock := flock.New(lockPath)
-	lockCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	locked, err := fileLock.TryLockContext(lockCtx, time.Second)
-	if err == nil && locked {
-		defer func() {
-			_ = fileLock.Unlock()
-		}()
-	}
+	unlock, err := lockRepoFile(repoFile)
 	if err != nil {
 		return err
 	}
+	defer unlock()
 
 	b, err := os.ReadFile(repoFile)
 	if err != nil && !os.IsNotExist(err) {
@@ -137,6 +129,59 @@ func (cmd *RepoAddCmd) Do(ctx context.Context) error {
 	return err
 }
 
+func newRepoUpdate(context Context, names ...string) *RepoUpdateCmd {
+	return &RepoUpdateCmd{
+		context: context,
+		names:   names,
+	}
+}
+
+// RepoUpdateCmd is a Helm repository update command
+type RepoUpdateCmd struct {
+	context Context
+	names   []string
+}
+
+// Do runs the Helm repository update command
+func (cmd *RepoUpdateCmd) Do(ctx context.Context) error {
+	repoFile := settings.RepositoryConfig
+
+	f, err := repo.LoadFile(repoFile)
+	if os.IsNotExist(err) || len(f.Repositories) == 0 {
+		return errors.New("no repositories configured")
+	}
+
+	var entries []*repo.Entry
+	if len(cmd.names) == 0 {
+		entries = f.Repositories
+	} else {
+		for _, name := range cmd.names {
+			entry := f.Get(name)
+			if entry == nil {
+				return errors.Errorf("no repo named %q found", name)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chartRepo, err := repo.NewChartRepository(entry, getter.All(settings))
+		if err != nil {
+			return err
+		}
+		if settings.RepositoryCache != "" {
+			chartRepo.CachePath = settings.RepositoryCache
+		}
+		if _, err := chartRepo.DownloadIndexFile(); err != nil {
+			return errors.Wrapf(err, "failed to update the %q chart repository (%s)", entry.Name, entry.URL)
+		}
+	}
+	return nil
+}
+
 func newRepoRemove(context Context, names ...string) *RepoRemoveCmd {
 	return &RepoRemoveCmd{
 		context: context,
@@ -189,3 +234,27 @@ func removeRepoCache(root, name string) error {
 	}
 	return os.Remove(idx)
 }
+
+// lockRepoFile acquires a file lock on the given repository file, returning a function to release the lock
+func lockRepoFile(repoFile string) (func(), error) {
+	repoFileExt := filepath.Ext(repoFile)
+	var lockPath string
+	if len(repoFileExt) > 0 && len(repoFileExt) < len(repoFile) {
+		lockPath = strings.TrimSuffix(repoFile, repoFileExt) + ".lock"
+	} else {
+		lockPath = repoFile + ".lock"
+	}
+	fileLock := flock.New(lockPath)
+	lockCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	locked, err := fileLock.TryLockContext(lockCtx, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return func() {}, nil
+	}
+	return func() {
+		_ = fileLock.Unlock()
+	}, nil
+}
@@ -0,0 +1,330 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ReleaseNameAnnotation is the annotation Helm applies to resources to record the release that created them
+	ReleaseNameAnnotation = "meta.helm.sh/release-name"
+	// ReleaseNamespaceAnnotation is the annotation Helm applies to resources to record the namespace of the
+	// release that created them
+	ReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// NewReleaseClient returns a client for the resources owned by the given release
+func NewReleaseClient(client kubernetes.Interface, namespace string, release string) *ReleaseClient {
+	return &ReleaseClient{
+		client:    client,
+		namespace: namespace,
+		release:   release,
+	}
+}
+
+// ReleaseClient is a Kubernetes client whose List and Get calls are filtered to the resources owned by a release
+// Resources are owned by a release if they were created by Helm for the release, or if they are pods created by
+// a workload owned by the release. Get returns a NotFound error for resources not owned by the release.
+type ReleaseClient struct {
+	client    kubernetes.Interface
+	namespace string
+	release   string
+}
+
+// Namespace returns the namespace of the release
+func (c *ReleaseClient) Namespace() string {
+	return c.namespace
+}
+
+// Release returns the name of the release
+func (c *ReleaseClient) Release() string {
+	return c.release
+}
+
+// isOwned returns whether the given object was created by Helm for the release
+func (c *ReleaseClient) isOwned(object metav1.Object) bool {
+	annotations := object.GetAnnotations()
+	return annotations[ReleaseNameAnnotation] == c.release && annotations[ReleaseNamespaceAnnotation] == c.namespace
+}
+
+// Deployments lists the deployments owned by the release
+func (c *ReleaseClient) Deployments(ctx context.Context) ([]appsv1.Deployment, error) {
+	list, err := c.client.AppsV1().Deployments(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// Deployment gets a deployment owned by the release
+func (c *ReleaseClient) Deployment(ctx context.Context, name string) (*appsv1.Deployment, error) {
+	object, err := c.client.AppsV1().Deployments(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, appsv1.Resource("deployments"), name, c.isOwned)
+}
+
+// StatefulSets lists the stateful sets owned by the release
+func (c *ReleaseClient) StatefulSets(ctx context.Context) ([]appsv1.StatefulSet, error) {
+	list, err := c.client.AppsV1().StatefulSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// StatefulSet gets a stateful set owned by the release
+func (c *ReleaseClient) StatefulSet(ctx context.Context, name string) (*appsv1.StatefulSet, error) {
+	object, err := c.client.AppsV1().StatefulSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, appsv1.Resource("statefulsets"), name, c.isOwned)
+}
+
+// DaemonSets lists the daemon sets owned by the release
+func (c *ReleaseClient) DaemonSets(ctx context.Context) ([]appsv1.DaemonSet, error) {
+	list, err := c.client.AppsV1().DaemonSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// DaemonSet gets a daemon set owned by the release
+func (c *ReleaseClient) DaemonSet(ctx context.Context, name string) (*appsv1.DaemonSet, error) {
+	object, err := c.client.AppsV1().DaemonSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, appsv1.Resource("daemonsets"), name, c.isOwned)
+}
+
+// Jobs lists the jobs owned by the release
+func (c *ReleaseClient) Jobs(ctx context.Context) ([]batchv1.Job, error) {
+	list, err := c.client.BatchV1().Jobs(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// Job gets a job owned by the release
+func (c *ReleaseClient) Job(ctx context.Context, name string) (*batchv1.Job, error) {
+	object, err := c.client.BatchV1().Jobs(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, batchv1.Resource("jobs"), name, c.isOwned)
+}
+
+// Services lists the services owned by the release
+func (c *ReleaseClient) Services(ctx context.Context) ([]corev1.Service, error) {
+	list, err := c.client.CoreV1().Services(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// Service gets a service owned by the release
+func (c *ReleaseClient) Service(ctx context.Context, name string) (*corev1.Service, error) {
+	object, err := c.client.CoreV1().Services(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, corev1.Resource("services"), name, c.isOwned)
+}
+
+// ConfigMaps lists the config maps owned by the release
+func (c *ReleaseClient) ConfigMaps(ctx context.Context) ([]corev1.ConfigMap, error) {
+	list, err := c.client.CoreV1().ConfigMaps(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// ConfigMap gets a config map owned by the release
+func (c *ReleaseClient) ConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error) {
+	object, err := c.client.CoreV1().ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, corev1.Resource("configmaps"), name, c.isOwned)
+}
+
+// Secrets lists the secrets owned by the release
+func (c *ReleaseClient) Secrets(ctx context.Context) ([]corev1.Secret, error) {
+	list, err := c.client.CoreV1().Secrets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// Secret gets a secret owned by the release
+func (c *ReleaseClient) Secret(ctx context.Context, name string) (*corev1.Secret, error) {
+	object, err := c.client.CoreV1().Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, corev1.Resource("secrets"), name, c.isOwned)
+}
+
+// ServiceAccounts lists the service accounts owned by the release
+func (c *ReleaseClient) ServiceAccounts(ctx context.Context) ([]corev1.ServiceAccount, error) {
+	list, err := c.client.CoreV1().ServiceAccounts(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, c.isOwned), nil
+}
+
+// ServiceAccount gets a service account owned by the release
+func (c *ReleaseClient) ServiceAccount(ctx context.Context, name string) (*corev1.ServiceAccount, error) {
+	object, err := c.client.CoreV1().ServiceAccounts(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	return getOwned(object, err, corev1.Resource("serviceaccounts"), name, c.isOwned)
+}
+
+// PersistentVolumeClaims lists the persistent volume claims owned by the release
+// Claims created from stateful set volume claim templates are owned by the stateful set rather than by Helm,
+// so they're included if the stateful set is owned by the release.
+func (c *ReleaseClient) PersistentVolumeClaims(ctx context.Context) ([]corev1.PersistentVolumeClaim, error) {
+	list, err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	owned, err := c.isOwnedByWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, owned), nil
+}
+
+// PersistentVolumeClaim gets a persistent volume claim owned by the release
+func (c *ReleaseClient) PersistentVolumeClaim(ctx context.Context, name string) (*corev1.PersistentVolumeClaim, error) {
+	object, err := c.client.CoreV1().PersistentVolumeClaims(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	owned, err := c.isOwnedByWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return getOwned(object, nil, corev1.Resource("persistentvolumeclaims"), name, owned)
+}
+
+// Pods lists the pods owned by the release, including pods created by the release's workloads
+func (c *ReleaseClient) Pods(ctx context.Context) ([]corev1.Pod, error) {
+	list, err := c.client.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	owned, err := c.isOwnedByWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterOwned(list.Items, owned), nil
+}
+
+// Pod gets a pod owned by the release
+func (c *ReleaseClient) Pod(ctx context.Context, name string) (*corev1.Pod, error) {
+	object, err := c.client.CoreV1().Pods(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	owned, err := c.isOwnedByWorkload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return getOwned(object, nil, corev1.Resource("pods"), name, owned)
+}
+
+// isOwnedByWorkload returns a function that checks whether an object is owned by the release, either directly
+// or through an owner reference to one of the release's workloads
+func (c *ReleaseClient) isOwnedByWorkload(ctx context.Context) (func(metav1.Object) bool, error) {
+	owners := make(map[types.UID]bool)
+	addOwners := func(objects []metav1.Object) {
+		for _, object := range objects {
+			if c.isOwned(object) || isOwnedBy(object, owners) {
+				owners[object.GetUID()] = true
+			}
+		}
+	}
+
+	deployments, err := c.client.AppsV1().Deployments(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addOwners(toObjects(deployments.Items))
+
+	// Replica sets are added after deployments so those created by the release's deployments are included
+	replicaSets, err := c.client.AppsV1().ReplicaSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addOwners(toObjects(replicaSets.Items))
+
+	statefulSets, err := c.client.AppsV1().StatefulSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addOwners(toObjects(statefulSets.Items))
+
+	daemonSets, err := c.client.AppsV1().DaemonSets(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addOwners(toObjects(daemonSets.Items))
+
+	jobs, err := c.client.BatchV1().Jobs(c.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addOwners(toObjects(jobs.Items))
+
+	return func(object metav1.Object) bool {
+		return c.isOwned(object) || isOwnedBy(object, owners)
+	}, nil
+}
+
+// isOwnedBy returns whether the given object has an owner reference to any of the given owners
+func isOwnedBy(object metav1.Object, owners map[types.UID]bool) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
+
+// toObjects returns the object metadata of the given list items
+func toObjects[T any, P interface {
+	*T
+	metav1.Object
+}](items []T) []metav1.Object {
+	objects := make([]metav1.Object, 0, len(items))
+	for i := range items {
+		objects = append(objects, P(&items[i]))
+	}
+	return objects
+}
+
+// filterOwned returns the list items that are owned by the release
+func filterOwned[T any, P interface {
+	*T
+	metav1.Object
+}](items []T, owned func(metav1.Object) bool) []T {
+	filtered := make([]T, 0, len(items))
+	for i := range items {
+		if owned(P(&items[i])) {
+			filtered = append(filtered, items[i])
+		}
+	}
+	return filtered
+}
+
+// getOwned returns the given object if it is owned by the release, otherwise a NotFound error
+func getOwned[T metav1.Object](object T, err error, resource schema.GroupResource, name string, owned func(metav1.Object) bool) (T, error) {
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+	if !owned(object) {
+		var empty T
+		return empty, k8serrors.NewNotFound(resource, name)
+	}
+	return object, nil
+}
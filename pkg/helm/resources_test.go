@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func newReleaseMeta(name string, uid string, release string, owner string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "test",
+		UID:       types.UID(uid),
+	}
+	if release != "" {
+		meta.Annotations = map[string]string{
+			ReleaseNameAnnotation:      release,
+			ReleaseNamespaceAnnotation: "test",
+		}
+	}
+	if owner != "" {
+		meta.OwnerReferences = []metav1.OwnerReference{{UID: types.UID(owner)}}
+	}
+	return meta
+}
+
+func TestReleaseClient(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{ObjectMeta: newReleaseMeta("foo", "foo-deployment", "foo", "")},
+		&appsv1.Deployment{ObjectMeta: newReleaseMeta("bar", "bar-deployment", "bar", "")},
+		&appsv1.ReplicaSet{ObjectMeta: newReleaseMeta("foo-1", "foo-replicaset", "", "foo-deployment")},
+		&appsv1.ReplicaSet{ObjectMeta: newReleaseMeta("bar-1", "bar-replicaset", "", "bar-deployment")},
+		&appsv1.StatefulSet{ObjectMeta: newReleaseMeta("foo-db", "foo-statefulset", "foo", "")},
+		&corev1.Pod{ObjectMeta: newReleaseMeta("foo-1-a", "foo-pod", "", "foo-replicaset")},
+		&corev1.Pod{ObjectMeta: newReleaseMeta("foo-db-0", "foo-db-pod", "", "foo-statefulset")},
+		&corev1.Pod{ObjectMeta: newReleaseMeta("bar-1-a", "bar-pod", "", "bar-replicaset")},
+		&corev1.Pod{ObjectMeta: newReleaseMeta("other", "other-pod", "", "")},
+		&corev1.PersistentVolumeClaim{ObjectMeta: newReleaseMeta("data-foo-db-0", "foo-pvc", "", "foo-statefulset")},
+		&corev1.Service{ObjectMeta: newReleaseMeta("foo", "foo-service", "foo", "")},
+		&corev1.Service{ObjectMeta: newReleaseMeta("other", "other-service", "", "")},
+	)
+
+	ctx := context.Background()
+	releaseClient := NewReleaseClient(client, "test", "foo")
+
+	deployments, err := releaseClient.Deployments(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, deployments, 1)
+	assert.Equal(t, "foo", deployments[0].Name)
+
+	deployment, err := releaseClient.Deployment(ctx, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", deployment.Name)
+
+	_, err = releaseClient.Deployment(ctx, "bar")
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	services, err := releaseClient.Services(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, "foo", services[0].Name)
+
+	pods, err := releaseClient.Pods(ctx)
+	assert.NoError(t, err)
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"foo-1-a", "foo-db-0"}, names)
+
+	_, err = releaseClient.Pod(ctx, "bar-1-a")
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	claims, err := releaseClient.PersistentVolumeClaims(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+}
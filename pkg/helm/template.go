@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"log"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+const sourcePrefix = "# Source: "
+
+func newTemplateCmd(context Context, release string, chart string) *TemplateCmd {
+	cmd := &TemplateCmd{}
+	cmd.ReleaseCmd = newReleaseCmd[*TemplateCmd](cmd, context, release, chart)
+	return cmd
+}
+
+// TemplateCmd is a command for rendering a Helm chart's templates locally, without installing the chart
+// Templates are rendered as by helm template, using the default capabilities rather than those of the cluster.
+type TemplateCmd struct {
+	*ReleaseCmd[*TemplateCmd]
+	includeCRDs bool
+}
+
+// IncludeCRDs includes the CRDs in the chart's crds/ directories in the rendered manifests
+func (cmd *TemplateCmd) IncludeCRDs() *TemplateCmd {
+	cmd.includeCRDs = true
+	return cmd
+}
+
+// Get renders the chart's templates and returns the rendered manifests
+func (cmd *TemplateCmd) Get(ctx context.Context) (Manifests, error) {
+	if cmd.err != nil {
+		return nil, cmd.err
+	}
+
+	// Rendering replaces the configuration's clients, so a configuration is created for each command
+	// rather than using the shared configuration for the namespace
+	config := &action.Configuration{}
+	if err := config.Init(settings.RESTClientGetter(), cmd.namespace, "memory", log.Printf); err != nil {
+		return nil, err
+	}
+
+	install := action.NewInstall(config)
+	install.Namespace = cmd.namespace
+	install.Version = cmd.version
+	install.Username = cmd.username
+	install.Password = cmd.password
+	install.RepoURL = cmd.repoURL
+	install.ReleaseName = cmd.release
+	install.Verify = cmd.verify
+	install.IncludeCRDs = cmd.includeCRDs && !cmd.skipCRDs
+	install.DryRun = true
+	install.Replace = true
+	install.ClientOnly = true
+
+	chart, err := cmd.loadChart(install.ChartPathOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := isChartInstallable(chart)
+	if !valid {
+		return nil, err
+	}
+
+	values, err := cmd.context.getReleaseValues(cmd.key, cmd.values, cmd.valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	release, err := install.RunWithContext(ctx, chart, values)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := parseManifests(release.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range release.Hooks {
+		hookManifests, err := parseManifests(fmt.Sprintf("%s%s\n%s", sourcePrefix, hook.Path, hook.Manifest))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, hookManifests...)
+	}
+	return manifests, nil
+}
+
+// parseManifests parses the objects in the given rendered release manifest
+func parseManifests(manifest string) (Manifests, error) {
+	docs := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var manifests Manifests
+	for _, key := range keys {
+		doc := docs[key]
+		var template string
+		for _, line := range strings.Split(doc, "\n") {
+			if strings.HasPrefix(line, sourcePrefix) {
+				template = strings.TrimPrefix(line, sourcePrefix)
+				break
+			}
+		}
+		object := make(map[string]any)
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", template, err)
+		}
+		// Skip templates that rendered no object, e.g. because of a conditional
+		if len(object) == 0 {
+			continue
+		}
+		manifests = append(manifests, Manifest{
+			Template:     template,
+			Unstructured: &unstructured.Unstructured{Object: object},
+		})
+	}
+	return manifests, nil
+}
+
+// Manifests are the Kubernetes objects rendered from a chart's templates
+type Manifests []Manifest
+
+// Kind returns the manifests for objects of the given kind
+func (m Manifests) Kind(kind string) Manifests {
+	var manifests Manifests
+	for _, manifest := range m {
+		if manifest.GetKind() == kind {
+			manifests = append(manifests, manifest)
+		}
+	}
+	return manifests
+}
+
+// Get returns the manifest for the object with the given kind and name, or nil if no such object was rendered
+func (m Manifests) Get(kind string, name string) *Manifest {
+	for i, manifest := range m {
+		if manifest.GetKind() == kind && manifest.GetName() == name {
+			return &m[i]
+		}
+	}
+	return nil
+}
+
+// Manifest is a Kubernetes object rendered from a chart template
+type Manifest struct {
+	// Template is the path of the template from which the object was rendered
+	Template string
+	*unstructured.Unstructured
+}
+
+// Into converts the rendered object into the given typed object, e.g. an *appsv1.Deployment
+func (m *Manifest) Into(object any) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(m.Object, object)
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testChart = map[string]string{
+	"Chart.yaml": `apiVersion: v2
+name: foo
+version: 0.1.0
+icon: https://example.com/icon.png
+`,
+	"values.yaml": `image: foo:latest
+replicas: 1
+config:
+  enabled: false
+`,
+	"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  labels:
+    app: {{ .Chart.Name }}
+spec:
+  replicas: {{ .Values.replicas }}
+  selector:
+    matchLabels:
+      app: {{ .Chart.Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Chart.Name }}
+    spec:
+      containers:
+        - name: foo
+          image: {{ .Values.image }}
+`,
+	"templates/config.yaml": `{{- if .Values.config.enabled }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  namespace: {{ .Release.Namespace }}
+{{- end }}
+`,
+	"templates/hook.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-test
+  annotations:
+    helm.sh/hook: test
+spec:
+  containers:
+    - name: test
+      image: {{ .Values.image }}
+`,
+}
+
+func writeTestChart(t *testing.T, files map[string]string) string {
+	dir := filepath.Join(t.TempDir(), "foo")
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	}
+	return dir
+}
+
+func TestTemplate(t *testing.T) {
+	chart := writeTestChart(t, testChart)
+	helm := &Helm{context: Context{Namespace: "test"}}
+
+	manifests, err := helm.Template("bar", chart).Get(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, manifests, 2)
+	assert.Nil(t, manifests.Get("ConfigMap", "bar-config"))
+	assert.Len(t, manifests.Kind("Pod"), 1)
+	assert.Equal(t, "foo/templates/hook.yaml", manifests.Kind("Pod")[0].Template)
+
+	manifest := manifests.Get("Deployment", "bar")
+	assert.NotNil(t, manifest)
+	assert.Equal(t, "foo/templates/deployment.yaml", manifest.Template)
+	assert.Equal(t, "foo", manifest.GetLabels()["app"])
+
+	manifests, err = helm.Template("bar", chart).
+		Set("replicas", 3).
+		Set("image", "foo:v1").
+		Set("config.enabled", true).
+		Get(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, manifests, 3)
+	config := manifests.Get("ConfigMap", "bar-config")
+	assert.NotNil(t, config)
+	assert.Equal(t, map[string]any{"namespace": "test"}, config.Object["data"])
+
+	var deployment appsv1.Deployment
+	assert.NoError(t, manifests.Get("Deployment", "bar").Into(&deployment))
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	assert.Equal(t, "foo:v1", deployment.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = helm.Template("bar", chart).Set("servers[", 1).Get(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLint(t *testing.T) {
+	helm := &Helm{context: Context{Namespace: "test"}}
+	assert.NoError(t, helm.Lint(writeTestChart(t, testChart)).Do(context.Background()))
+
+	files := make(map[string]string)
+	for name, data := range testChart {
+		files[name] = data
+	}
+	files["templates/broken.yaml"] = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: broken
+data:
+  value: {{ .Values.missing.value }}
+`
+	chart := writeTestChart(t, files)
+
+	result, err := helm.Lint(chart).Get(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Messages)
+
+	err = helm.Lint(chart).Do(context.Background())
+	var failure *LintFailure
+	assert.True(t, errors.As(err, &failure))
+	assert.Equal(t, LintError, failure.Messages[0].Severity)
+
+	// Setting the missing value fixes the chart
+	assert.NoError(t, helm.Lint(chart).Set("missing.value", "foo").Do(context.Background()))
+
+	_, err = helm.Lint(filepath.Join(t.TempDir(), "missing")).Get(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"time"
+)
+
+// timingMarker is written to the output of helmit jobs once each release operation completes, so the CLI can
+// report the time spent in Helm
+const timingMarker = "=== HELM"
+
+// writeTiming writes the time since the given start of an operation on the given release to the output of the
+// running job, if any
+func writeTiming(operation string, release string, start time.Time) {
+	if job.GetID() == "" {
+		return
+	}
+	fmt.Printf("%s %s %s (%.2fs)\n", timingMarker, operation, release, time.Since(start).Seconds())
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"context"
+	"io"
+)
+
+const (
+	// BeforeCluster is the name of the symbol a Go plugin exports to run a hook before the cluster is used
+	BeforeCluster = "BeforeCluster"
+	// AfterCluster is the name of the symbol a Go plugin exports to run a hook once the run has torn down
+	AfterCluster = "AfterCluster"
+)
+
+// Env is the environment in which a provisioning hook is run
+type Env struct {
+	// Command is the helmit command being run, e.g. test
+	Command string
+	// ID is the ID of the test, benchmark, or job being run
+	ID string
+	// Namespace is the namespace in which the command runs its jobs
+	Namespace string
+	// Failed indicates whether the run failed, and is only set for after-cluster hooks
+	Failed bool
+	// Out is the writer to which the hook writes its output, which is printed to the console
+	Out io.Writer
+}
+
+// Func is a provisioning hook exported by a Go plugin
+// Plugins export either a function with this signature or a variable of this type named BeforeCluster or
+// AfterCluster. Hooks are run by the helmit CLI, not in the job pods, so they may provision the cluster the
+// command runs against or any external infrastructure the tests or benchmarks depend on.
+type Func func(ctx context.Context, env Env) error
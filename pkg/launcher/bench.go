@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launcher
+
+import (
+	"context"
+	"errors"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/cli"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"time"
+)
+
+// defaultMaxWorkers is the maximum number of workers run by default when TargetP99 is set
+const defaultMaxWorkers = 10
+
+// BenchSpec is the specification for a benchmark job
+type BenchSpec struct {
+	Spec
+	// Suite is the name of the benchmark suite to run
+	Suite string
+	// Benchmark is the name of the benchmark to run
+	Benchmark string
+	// Workers is the number of worker pods to run, defaulting to 1
+	Workers int
+	// Parallelism is the number of goroutines to run per worker, defaulting to 1
+	Parallelism int
+	// Rate is the total number of iterations per second across all workers, or 0 to run as fast as possible
+	Rate float64
+	// Iterations is the number of iterations after which to stop the benchmark
+	Iterations int
+	// Duration is the duration after which to stop the benchmark, excluding the Warmup period
+	Duration time.Duration
+	// Warmup is the period for which to run the benchmark before recording statistics
+	Warmup time.Duration
+	// ReportInterval is the interval at which workers report statistics, defaulting to 10 seconds
+	ReportInterval time.Duration
+	// MaxErrorRate is the maximum fraction of iterations that may fail before the benchmark fails, or 0 for no limit
+	MaxErrorRate float64
+	// TargetP99 is the 99th percentile latency up to which to add workers, or 0 to run a fixed number of Workers
+	TargetP99 time.Duration
+	// MaxWorkers is the maximum number of workers to run when TargetP99 is set, defaulting to 10
+	MaxWorkers int
+	// StopOnSLOBreach is a comma-separated list of SLO expressions, e.g. p99<100ms,errorRate<1%, on the breach of
+	// which to stop the benchmark and fail
+	StopOnSLOBreach string
+	// SLOWindow is the window over which metrics are aggregated for StopOnSLOBreach, defaulting to the ReportInterval
+	SLOWindow time.Duration
+	// WorkerStartBatch is the maximum number of workers to start at once, or 0 to start all workers at once
+	WorkerStartBatch int
+	// WorkerStartTimeout is the time allowed for each batch of workers to start, or 0 for no limit
+	WorkerStartTimeout time.Duration
+	// StallIntervals is the number of report intervals without iterations after which a worker is flagged as
+	// stalled, or 0 to disable stall detection
+	StallIntervals int
+	// RestartStalled indicates whether to delete and recreate workers flagged as stalled
+	RestartStalled bool
+	// OnReport is an optional function called with each report received from a worker
+	OnReport func(worker int, report benchmark.Report)
+}
+
+// BenchResult is the result of a benchmark job
+type BenchResult struct {
+	// ID is the unique ID of the benchmark job
+	ID string
+	// Namespace is the namespace in which the benchmark was run
+	Namespace string
+	// Reports is the last report received from each worker, indexed by worker
+	Reports []benchmark.Report
+}
+
+// Iterations returns the total number of iterations completed by all workers
+func (r *BenchResult) Iterations() int {
+	var iterations int
+	for _, report := range r.Reports {
+		iterations += report.Iterations
+	}
+	return iterations
+}
+
+// Errors returns the total number of iterations that failed across all workers
+func (r *BenchResult) Errors() int {
+	var errors int
+	for _, report := range r.Reports {
+		errors += report.Errors
+	}
+	return errors
+}
+
+// Throughput returns the total number of iterations per second across all workers
+func (r *BenchResult) Throughput() float64 {
+	var throughput float64
+	for _, report := range r.Reports {
+		if report.Duration > 0 {
+			throughput += float64(report.Iterations) / (float64(report.Duration) / float64(time.Second))
+		}
+	}
+	return throughput
+}
+
+// Bench builds and runs the benchmark described by the given spec, returning the result once the benchmark is complete
+// The benchmark runs until the Iterations or Duration limit is reached or the context is canceled, after which the
+// workers are shut down and the benchmark is torn down. The Benchmark must match a single benchmark of the Suite.
+// If the benchmark fails, e.g. because it exceeded the MaxErrorRate or breached its SLO, the error is returned
+// with the result.
+func Bench(ctx context.Context, spec BenchSpec) (*BenchResult, error) {
+	if spec.Suite == "" {
+		return nil, errors.New("must specify a benchmark Suite to run")
+	}
+	if spec.Workers == 0 {
+		spec.Workers = 1
+	}
+	if spec.Parallelism == 0 {
+		spec.Parallelism = 1
+	}
+	if spec.ReportInterval == 0 {
+		spec.ReportInterval = 10 * time.Second
+	}
+	if spec.TargetP99 > 0 && spec.MaxWorkers == 0 {
+		spec.MaxWorkers = defaultMaxWorkers
+	}
+
+	id := newID()
+	if err := spec.validate(id); err != nil {
+		return nil, err
+	}
+
+	artifacts, err := spec.prepare(id, func(log logging.Logger) *build.Builder {
+		return build.Benchmarks(log, spec.Suite)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer artifacts.Remove()
+
+	config := benchmark.Config{
+		Namespace:      spec.Namespace,
+		Suite:          spec.Suite,
+		Benchmark:      spec.Benchmark,
+		Parallelism:    spec.Parallelism,
+		Rate:           spec.Rate / float64(spec.Workers),
+		ReportInterval: spec.ReportInterval,
+		Warmup:         spec.Warmup,
+		Timeout:        spec.Timeout,
+		Context:        spec.getContext(),
+		Values:         spec.Values,
+		ValueFiles:     spec.getValueFiles(),
+		Args:           spec.Args,
+		NoTeardown:     spec.NoTeardown,
+	}
+	benchJob := newJob(spec.Spec, id, artifacts, config)
+
+	reports, err := cli.RunBenchmark(ctx, *benchJob, cli.BenchmarkOptions{
+		Workers:            spec.Workers,
+		Iterations:         spec.Iterations,
+		Duration:           spec.Duration,
+		MaxErrorRate:       spec.MaxErrorRate,
+		TargetP99:          spec.TargetP99,
+		MaxWorkers:         spec.MaxWorkers,
+		SLO:                spec.StopOnSLOBreach,
+		SLOWindow:          spec.SLOWindow,
+		WorkerStartBatch:   spec.WorkerStartBatch,
+		WorkerStartTimeout: spec.WorkerStartTimeout,
+		StallIntervals:     spec.StallIntervals,
+		RestartStalled:     spec.RestartStalled,
+		Output:             spec.Output,
+		OnReport:           spec.OnReport,
+	})
+	if reports == nil {
+		return nil, err
+	}
+	return &BenchResult{
+		ID:        id,
+		Namespace: spec.Namespace,
+		Reports:   reports,
+	}, err
+}
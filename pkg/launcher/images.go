@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launcher
+
+import (
+	"github.com/onosproject/helmit/internal/images"
+)
+
+const (
+	// DefaultImage is the image used to run jobs built from Go packages
+	DefaultImage = images.DefaultRunner
+	// DefaultBuilderImage is the image used to build and run jobs inside the cluster
+	DefaultBuilderImage = images.DefaultBuilder
+)
+
+// Archs are the CPU architectures for which the runner and builder images are published
+var Archs = images.Archs
+
+// SetRunnerImage overrides the image used to run jobs built from Go packages
+// The image may be a repository, e.g. to use a mirror of the published images, to which the tag for the version
+// and architecture is appended, or a reference with a tag or digest, which is used as is for all architectures.
+func SetRunnerImage(image string) {
+	images.SetRunner(image)
+}
+
+// SetBuilderImage overrides the image used to build and run jobs inside the cluster
+// The image may be a repository or a reference with a tag or digest, as with SetRunnerImage.
+func SetBuilderImage(image string) {
+	images.SetBuilder(image)
+}
+
+// BuilderImage returns the image used to build and run jobs inside the cluster for the given CPU architecture
+func BuilderImage(arch string) string {
+	return images.Builder(arch)
+}
+
+// RunnerImage returns the image used to run jobs built from Go packages for the given CPU architecture
+func RunnerImage(arch string) string {
+	return images.Runner(arch)
+}
+
+// ImageVersion returns the version of the runner and builder images matching this version of helmit
+// Images are published for each release, so release builds of helmit, or programs depending on a release of
+// helmit, run the images of that release. Development builds run the latest images.
+func ImageVersion() string {
+	return images.Version()
+}
+
+// HasTagOrDigest returns whether the given image reference includes a tag or digest
+func HasTagOrDigest(image string) bool {
+	return images.HasTagOrDigest(image)
+}
+
+// GetRepository returns the repository of the given image reference, without its tag or digest
+func GetRepository(image string) string {
+	return images.GetRepository(image)
+}
@@ -0,0 +1,291 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package launcher provides an API for starting helmit test and benchmark jobs from Go programs.
+package launcher
+
+import (
+	"context"
+	"errors"
+	petname "github.com/dustinkirkland/golang-petname"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/logging"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Minute
+
+// Spec is the specification shared by all helmit jobs
+type Spec struct {
+	// Packages is the set of Go packages from which to build the job binary
+	// If no packages are specified, the Image must contain the job binary.
+	Packages []string
+	// Tags is a list of build tags with which to build the Packages
+	Tags []string
+	// LDFlags are linker flags with which to build the Packages
+	LDFlags string
+	// Race indicates whether to build the Packages with the race detector enabled
+	Race bool
+	// NoBuildCache indicates whether to always rebuild the Packages rather than reusing a cached binary
+	NoBuildCache bool
+	// BuildInCluster indicates whether to copy the module source to the job pod and build the Packages there
+	BuildInCluster bool
+	// Image is the image to run, defaulting to the RunnerImage for the Arch when Packages are specified, or
+	// the BuilderImage when BuildInCluster is set
+	Image string
+	// Arch is the CPU architecture for which to build the Packages, defaulting to the architecture of the
+	// cluster's nodes
+	Arch string
+	// ImagePullPolicy is the pull policy for the Image
+	ImagePullPolicy corev1.PullPolicy
+	// NodeSelector is a set of node labels to which to constrain the job pods
+	NodeSelector map[string]string
+	// Tolerations are the taints the job pods tolerate
+	Tolerations []corev1.Toleration
+	// Affinity is the scheduling affinity of the job pods
+	Affinity *corev1.Affinity
+	// PriorityClassName is the name of the PriorityClass to assign the job pods
+	PriorityClassName string
+	// Namespace is the namespace in which to run the job
+	Namespace string
+	// CreateNamespace indicates whether to create the Namespace for the job
+	CreateNamespace bool
+	// NamespaceLabels are labels to apply to the Namespace created for the job
+	NamespaceLabels map[string]string
+	// NamespaceAnnotations are annotations to apply to the Namespace created for the job
+	NamespaceAnnotations map[string]string
+	// ServiceAccount is the name of an existing service account with which to run the job
+	ServiceAccount string
+	// Rules are the RBAC policy rules granted to the job in place of cluster-admin
+	Rules []rbacv1.PolicyRule
+	// NamespacedRBAC indicates whether to grant the Rules with a namespaced Role
+	NamespacedRBAC bool
+	// Sidecars are additional containers to run alongside the job in the job pods
+	Sidecars []corev1.Container
+	// SidecarVolumes are additional volumes to add to the job pods for use by the Sidecars
+	SidecarVolumes []corev1.Volume
+	// Labels are labels to apply to the job pods
+	Labels map[string]string
+	// Annotations are annotations to apply to the job pods
+	Annotations map[string]string
+	// Context is a local directory to copy into the job's working directory
+	Context string
+	// Values are Helm chart value overrides keyed by release name
+	Values map[string][]string
+	// ValueFiles are local Helm values files keyed by release name
+	ValueFiles map[string][]string
+	// Secrets are secrets to pass to the job pods
+	Secrets map[string]string
+	// SecretsFrom are existing Kubernetes secrets in the format [{namespace}/]{name} whose keys to pass to the
+	// job pods
+	SecretsFrom []string
+	// Args are named arguments to pass to the job
+	Args map[string]string
+	// Timeout is the job timeout
+	Timeout time.Duration
+	// PollInterval is the interval at which to poll the control endpoint of the job pods
+	PollInterval time.Duration
+	// ReadyTimeout is the time to wait for the control endpoint of the job pods to become reachable
+	ReadyTimeout time.Duration
+	// NoTeardown indicates whether to leave releases installed when the job completes
+	NoTeardown bool
+	// Output is a writer to which to write the raw output of job pods
+	Output io.Writer
+}
+
+// SetLog sets the writer to which the progress of jobs is written, defaulting to stdout
+// Progress is written as the helmit tool writes it, so the writer is shared by all jobs.
+func SetLog(w io.Writer) {
+	logging.SetWriter(w)
+}
+
+// validate checks the spec and applies defaults
+func (s *Spec) validate(id string) error {
+	if len(s.Packages) == 0 && s.Image == "" {
+		return errors.New("must specify either Packages or an Image to run")
+	}
+	if len(s.Packages) > 0 {
+		if s.Arch == "" {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			arch, err := k8s.DetectArch(ctx)
+			if err != nil {
+				return err
+			}
+			s.Arch = arch
+		}
+		if s.Image == "" {
+			if s.BuildInCluster {
+				s.Image = BuilderImage(s.Arch)
+			} else {
+				s.Image = RunnerImage(s.Arch)
+			}
+		}
+	}
+	if s.ImagePullPolicy == "" {
+		s.ImagePullPolicy = corev1.PullIfNotPresent
+	}
+	if s.Namespace == "" {
+		if s.CreateNamespace {
+			s.Namespace = id
+		} else {
+			s.Namespace = "default"
+		}
+	}
+	if s.Timeout == 0 {
+		s.Timeout = defaultTimeout
+	}
+	if s.Output == nil {
+		s.Output = io.Discard
+	}
+	if s.Context != "" {
+		path, err := filepath.Abs(s.Context)
+		if err != nil {
+			return err
+		}
+		s.Context = path
+	}
+	return nil
+}
+
+// artifacts are the local artifacts from which a job binary is run or built
+type artifacts struct {
+	executable string
+	source     *job.Source
+	remove     func() error
+}
+
+// Remove removes the local artifacts
+func (a artifacts) Remove() error {
+	if a.remove == nil {
+		return nil
+	}
+	return a.remove()
+}
+
+// prepare builds the job binary with the builder returned for the given logger, logging the build as a step of
+// the job with the given ID, and returns the artifacts to copy to the job
+// If the spec does not specify any packages, prepare returns empty artifacts. If BuildInCluster is set,
+// prepare generates the job's main package and returns the source to be built in the job pod.
+func (s *Spec) prepare(id string, getBuilder func(log logging.Logger) *build.Builder) (artifacts, error) {
+	if len(s.Packages) == 0 {
+		return artifacts{}, nil
+	}
+	step := logging.NewStep(id, "Preparing artifacts")
+	step.Start()
+	artifacts, err := s.build(id, getBuilder(step))
+	if err != nil {
+		step.Fail(err)
+		return artifacts, err
+	}
+	step.Complete()
+	return artifacts, nil
+}
+
+// build builds the job binary with the given builder, returning the artifacts to copy to the job
+// If BuildInCluster is set, build generates the job's main package and returns the source to be built in the
+// job pod.
+func (s *Spec) build(id string, builder *build.Builder) (artifacts, error) {
+	options := build.Options{
+		Tags:    s.Tags,
+		LDFlags: s.LDFlags,
+		Race:    s.Race,
+		NoCache: s.NoBuildCache,
+	}
+	builder = builder.Arch(s.Arch).Options(options)
+	if s.BuildInCluster {
+		source, err := builder.Generate(s.Packages...)
+		if err != nil {
+			return artifacts{}, err
+		}
+		return artifacts{
+			source: &job.Source{
+				Dir:   source.Dir,
+				Main:  source.Main,
+				Flags: options.Flags(),
+			},
+			remove: source.Remove,
+		}, nil
+	}
+	executable := filepath.Join(os.TempDir(), "helmit", id)
+	if err := builder.Build(executable, s.Packages...); err != nil {
+		return artifacts{}, err
+	}
+	return artifacts{
+		executable: executable,
+		remove: func() error {
+			return os.RemoveAll(executable)
+		},
+	}, nil
+}
+
+// getContext returns the path at which the job context is available in the job pod
+func (s *Spec) getContext() string {
+	if s.Context == "" {
+		return ""
+	}
+	return filepath.Join(job.HomeDir, job.ContextDir)
+}
+
+// getValueFiles returns the paths at which the values files are available in the job pod
+func (s *Spec) getValueFiles() map[string][]string {
+	if len(s.ValueFiles) == 0 {
+		return nil
+	}
+	valueFiles := make(map[string][]string)
+	for release, releaseFiles := range s.ValueFiles {
+		var absFiles []string
+		for _, releaseFile := range releaseFiles {
+			absFiles = append(absFiles, filepath.Join(job.HomeDir, filepath.Base(releaseFile)))
+		}
+		valueFiles[release] = absFiles
+	}
+	return valueFiles
+}
+
+// newJob returns a job for the spec with the given ID, artifacts, and configuration
+func newJob[T any](spec Spec, id string, artifacts artifacts, config T) *job.Job[T] {
+	return &job.Job[T]{
+		ID:                   id,
+		Namespace:            spec.Namespace,
+		CreateNamespace:      spec.CreateNamespace,
+		DeleteNamespace:      spec.CreateNamespace && !spec.NoTeardown,
+		NamespaceLabels:      spec.NamespaceLabels,
+		NamespaceAnnotations: spec.NamespaceAnnotations,
+		ServiceAccount:       spec.ServiceAccount,
+		Rules:                spec.Rules,
+		NamespacedRBAC:       spec.NamespacedRBAC,
+		Image:                spec.Image,
+		ImagePullPolicy:      spec.ImagePullPolicy,
+		NodeSelector:         spec.NodeSelector,
+		Tolerations:          spec.Tolerations,
+		Affinity:             spec.Affinity,
+		PriorityClassName:    spec.PriorityClassName,
+		Sidecars:             spec.Sidecars,
+		SidecarVolumes:       spec.SidecarVolumes,
+		Labels:               spec.Labels,
+		Annotations:          spec.Annotations,
+		Executable:           artifacts.executable,
+		Source:               artifacts.source,
+		Context:              spec.Context,
+		ValueFiles:           spec.ValueFiles,
+		Secrets:              spec.Secrets,
+		SecretsFrom:          spec.SecretsFrom,
+		PollInterval:         spec.PollInterval,
+		ReadyTimeout:         spec.ReadyTimeout,
+		Config:               config,
+	}
+}
+
+// newID generates a unique job ID
+func newID() string {
+	return petname.Generate(2, "-")
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launcher
+
+import (
+	"context"
+	"github.com/onosproject/helmit/pkg/benchmark"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+	"time"
+)
+
+func TestSpecDefaults(t *testing.T) {
+	spec := Spec{Packages: []string{"./test"}, Arch: "arm64"}
+	assert.NoError(t, spec.validate("test-id"))
+	assert.Equal(t, "onosproject/helmit-runner:latest-arm64", spec.Image)
+	assert.Equal(t, corev1.PullIfNotPresent, spec.ImagePullPolicy)
+	assert.Equal(t, "default", spec.Namespace)
+	assert.Equal(t, defaultTimeout, spec.Timeout)
+	assert.NotNil(t, spec.Output)
+
+	spec = Spec{Image: "test-image", CreateNamespace: true}
+	assert.NoError(t, spec.validate("test-id"))
+	assert.Equal(t, "test-image", spec.Image)
+	assert.Equal(t, "test-id", spec.Namespace)
+
+	spec = Spec{ValueFiles: map[string][]string{"foo": {"./values/foo.yaml"}}}
+	assert.Equal(t, map[string][]string{"foo": {"/home/helmit/foo.yaml"}}, spec.getValueFiles())
+}
+
+func TestInvalidSpec(t *testing.T) {
+	ctx := context.Background()
+	_, err := Test(ctx, TestSpec{})
+	assert.Error(t, err)
+	_, err = Test(ctx, TestSpec{Spec: Spec{Image: "test-image"}, Tests: []string{"("}})
+	assert.Error(t, err)
+	_, err = Bench(ctx, BenchSpec{Spec: Spec{Image: "test-image"}})
+	assert.Error(t, err)
+	_, err = Bench(ctx, BenchSpec{Spec: Spec{Image: "test-image"}, Suite: "test", StopOnSLOBreach: "p99"})
+	assert.Error(t, err)
+	_, err = Bench(ctx, BenchSpec{Spec: Spec{Image: "test-image"}, Suite: "test", WorkerStartTimeout: time.Minute})
+	assert.Error(t, err)
+}
+
+func TestBenchResult(t *testing.T) {
+	result := &BenchResult{
+		Reports: []benchmark.Report{
+			{Iterations: 100, Errors: 1, Duration: time.Second},
+			{Iterations: 300, Errors: 2, Duration: 2 * time.Second},
+		},
+	}
+	assert.Equal(t, 400, result.Iterations())
+	assert.Equal(t, 3, result.Errors())
+	assert.Equal(t, 250.0, result.Throughput())
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package launcher
+
+import (
+	"context"
+	"github.com/onosproject/helmit/internal/build"
+	"github.com/onosproject/helmit/internal/cli"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/logging"
+	"github.com/onosproject/helmit/internal/match"
+	"github.com/onosproject/helmit/pkg/test"
+	"path/filepath"
+	"time"
+)
+
+// TestSpec is the specification for a test job
+type TestSpec struct {
+	Spec
+	// Suites are regular expressions filtering the names of test suites, defaulting to "TestSuite$"
+	Suites []string
+	// Tests are regular expressions filtering the names of tests, defaulting to ".*/^Test"
+	Tests []string
+	// Methods are regular expressions filtering the names of test suite methods, defaulting to "^Test"
+	Methods []string
+	// ArtifactsDir is a local directory to which to collect test artifacts
+	ArtifactsDir string
+	// Verbose enables verbose test output
+	Verbose bool
+}
+
+// TestResult is the result of a test job
+type TestResult struct {
+	// ID is the unique ID of the test job
+	ID string
+	// Namespace is the namespace in which the tests were run
+	Namespace string
+	// ExitCode is the exit code of the test process
+	ExitCode int
+	// Duration is the time taken to run the tests
+	Duration time.Duration
+}
+
+// Passed returns whether the tests passed
+func (r *TestResult) Passed() bool {
+	return r.ExitCode == 0
+}
+
+// Test builds and runs the tests described by the given spec, returning the result once the tests are complete
+// If the context is canceled before the tests complete, the test job is deleted and the context error is returned.
+func Test(ctx context.Context, spec TestSpec) (*TestResult, error) {
+	if len(spec.Suites) == 0 {
+		spec.Suites = []string{"TestSuite$"}
+	}
+	if len(spec.Tests) == 0 {
+		spec.Tests = []string{".*/^Test"}
+	}
+	if len(spec.Methods) == 0 {
+		spec.Methods = []string{"^Test"}
+	}
+	for _, patterns := range [][]string{spec.Suites, spec.Tests, spec.Methods} {
+		if err := match.Validate(patterns...); err != nil {
+			return nil, err
+		}
+	}
+
+	id := newID()
+	if err := spec.validate(id); err != nil {
+		return nil, err
+	}
+
+	artifacts, err := spec.prepare(id, func(log logging.Logger) *build.Builder {
+		return build.Tests(log, spec.Suites...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer artifacts.Remove()
+
+	config := test.Config{
+		Namespace:  spec.Namespace,
+		Suites:     spec.Suites,
+		Tests:      spec.Tests,
+		Methods:    spec.Methods,
+		Context:    spec.getContext(),
+		Values:     spec.Values,
+		ValueFiles: spec.getValueFiles(),
+		Args:       spec.Args,
+		Timeout:    spec.Timeout,
+		Verbose:    spec.Verbose,
+		NoTeardown: spec.NoTeardown,
+	}
+	if spec.ArtifactsDir != "" {
+		config.ArtifactsDir = filepath.Join(job.HomeDir, job.ArtifactsDir)
+	}
+
+	job := newJob(spec.Spec, id, artifacts, config)
+	job.Hold = spec.ArtifactsDir != ""
+
+	start := time.Now()
+	code, err := cli.RunTests(ctx, *job, spec.ArtifactsDir, spec.Output)
+	if err != nil {
+		return nil, err
+	}
+	return &TestResult{
+		ID:        id,
+		Namespace: spec.Namespace,
+		ExitCode:  code,
+		Duration:  time.Since(start),
+	}, nil
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// GRPC creates a probe for the gRPC server at the given address
+// The probe succeeds once the server's standard health service reports the probed service as serving. By default,
+// the health of the server as a whole is checked over an insecure connection.
+func GRPC(address string) *GRPCProbe {
+	return &GRPCProbe{
+		prober:  newProber(address),
+		address: address,
+		options: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	}
+}
+
+// GRPCProbe is a probe that retries a gRPC health check until the service is serving
+type GRPCProbe struct {
+	prober
+	address string
+	service string
+	options []grpc.DialOption
+}
+
+// Service sets the name of the service whose health to check
+func (p *GRPCProbe) Service(service string) *GRPCProbe {
+	p.service = service
+	return p
+}
+
+// DialOptions sets the options with which to connect to the server, replacing the default insecure credentials
+func (p *GRPCProbe) DialOptions(options ...grpc.DialOption) *GRPCProbe {
+	p.options = options
+	return p
+}
+
+// Interval sets the initial interval between attempts, which doubles after each failed attempt up to maxInterval
+func (p *GRPCProbe) Interval(interval time.Duration, maxInterval time.Duration) *GRPCProbe {
+	p.interval = interval
+	p.maxInterval = maxInterval
+	return p
+}
+
+// Timeout sets the time allowed for each attempt
+func (p *GRPCProbe) Timeout(timeout time.Duration) *GRPCProbe {
+	p.timeout = timeout
+	return p
+}
+
+// Within retries the probe until it succeeds or the given timeout has elapsed
+// If the probe does not succeed in time, an *Error describing the failed attempts is returned.
+func (p *GRPCProbe) Within(timeout time.Duration) error {
+	return within(timeout, p.Await)
+}
+
+// Await retries the probe until it succeeds or the context is done
+// If the probe does not succeed in time, an *Error describing the failed attempts is returned.
+func (p *GRPCProbe) Await(ctx context.Context) error {
+	return p.run(ctx, p.attempt)
+}
+
+func (p *GRPCProbe) attempt(ctx context.Context) error {
+	conn, err := grpc.DialContext(ctx, p.address, p.options...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.service})
+	if err != nil {
+		return err
+	}
+	if response.Status != healthpb.HealthCheckResponse_SERVING {
+		if p.service == "" {
+			return fmt.Errorf("server is %s", response.Status)
+		}
+		return fmt.Errorf("service %s is %s", p.service, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize is the maximum number of bytes of a response body read by an HTTP probe
+const maxBodySize = 64 * 1024
+
+// maxBodyExcerpt is the maximum number of bytes of a response body included in failures
+const maxBodyExcerpt = 200
+
+// HTTP creates a probe for the given URL
+// By default, the probe succeeds once a GET request to the URL returns a 2xx status.
+func HTTP(url string) *HTTPProbe {
+	return &HTTPProbe{
+		prober: newProber(url),
+		url:    url,
+		method: http.MethodGet,
+		header: make(http.Header),
+		client: http.DefaultClient,
+	}
+}
+
+// HTTPProbe is a probe that retries an HTTP request until the response meets its expectations
+type HTTPProbe struct {
+	prober
+	url          string
+	method       string
+	header       http.Header
+	client       *http.Client
+	statuses     []int
+	bodyContains string
+}
+
+// Method sets the method of the probe's requests
+func (p *HTTPProbe) Method(method string) *HTTPProbe {
+	p.method = method
+	return p
+}
+
+// Header adds a header to the probe's requests
+func (p *HTTPProbe) Header(name string, value string) *HTTPProbe {
+	p.header.Add(name, value)
+	return p
+}
+
+// Client sets the HTTP client with which to send the probe's requests
+func (p *HTTPProbe) Client(client *http.Client) *HTTPProbe {
+	p.client = client
+	return p
+}
+
+// ExpectStatus sets the response statuses with which the probe succeeds
+func (p *HTTPProbe) ExpectStatus(statuses ...int) *HTTPProbe {
+	p.statuses = statuses
+	return p
+}
+
+// ExpectBody sets a string the response body must contain for the probe to succeed
+func (p *HTTPProbe) ExpectBody(contains string) *HTTPProbe {
+	p.bodyContains = contains
+	return p
+}
+
+// Interval sets the initial interval between attempts, which doubles after each failed attempt up to maxInterval
+func (p *HTTPProbe) Interval(interval time.Duration, maxInterval time.Duration) *HTTPProbe {
+	p.interval = interval
+	p.maxInterval = maxInterval
+	return p
+}
+
+// Timeout sets the time allowed for each attempt
+func (p *HTTPProbe) Timeout(timeout time.Duration) *HTTPProbe {
+	p.timeout = timeout
+	return p
+}
+
+// Within retries the probe until it succeeds or the given timeout has elapsed
+// If the probe does not succeed in time, an *Error describing the failed attempts is returned.
+func (p *HTTPProbe) Within(timeout time.Duration) error {
+	return within(timeout, p.Await)
+}
+
+// Await retries the probe until it succeeds or the context is done
+// If the probe does not succeed in time, an *Error describing the failed attempts is returned.
+func (p *HTTPProbe) Await(ctx context.Context) error {
+	return p.run(ctx, p.attempt)
+}
+
+func (p *HTTPProbe) attempt(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, p.method, p.url, nil)
+	if err != nil {
+		return err
+	}
+	for name, values := range p.header {
+		request.Header[name] = values
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+
+	if !p.expectStatus(response.StatusCode) {
+		return fmt.Errorf("unexpected status %s%s", response.Status, formatBody(body))
+	}
+	if p.bodyContains != "" && !strings.Contains(string(body), p.bodyContains) {
+		return fmt.Errorf("response body does not contain %q%s", p.bodyContains, formatBody(body))
+	}
+	return nil
+}
+
+// expectStatus returns whether the given status meets the probe's expectations
+func (p *HTTPProbe) expectStatus(status int) bool {
+	if len(p.statuses) == 0 {
+		return status >= 200 && status < 300
+	}
+	for _, expected := range p.statuses {
+		if status == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBody formats an excerpt of a response body for inclusion in a failure
+func formatBody(body []byte) string {
+	excerpt := strings.TrimSpace(string(body))
+	if excerpt == "" {
+		return ""
+	}
+	if len(excerpt) > maxBodyExcerpt {
+		excerpt = excerpt[:maxBodyExcerpt] + "..."
+	}
+	return fmt.Sprintf(": %q", excerpt)
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInterval    = 250 * time.Millisecond
+	defaultMaxInterval = 5 * time.Second
+	defaultTimeout     = 5 * time.Second
+)
+
+// Error is returned when a probe does not succeed before its deadline
+type Error struct {
+	// Target is the URL or address that was probed
+	Target string
+	// Attempts is the number of times the target was probed
+	Attempts int
+	// Elapsed is the time spent probing the target
+	Elapsed time.Duration
+	// Failures are the distinct reasons the probe failed, in the order they were first observed
+	Failures []string
+	err      error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "probe %s failed after %d attempt(s) in %s: %s", e.Target, e.Attempts, e.Elapsed.Round(time.Millisecond), e.err)
+	if len(e.Failures) > 0 {
+		fmt.Fprintf(&b, "\n  observed failures:")
+		for _, failure := range e.Failures {
+			fmt.Fprintf(&b, "\n    %s", failure)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap returns the context error that ended the probe
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// prober is the retry policy shared by probes
+type prober struct {
+	target      string
+	interval    time.Duration
+	maxInterval time.Duration
+	timeout     time.Duration
+}
+
+func newProber(target string) prober {
+	return prober{
+		target:      target,
+		interval:    defaultInterval,
+		maxInterval: defaultMaxInterval,
+		timeout:     defaultTimeout,
+	}
+}
+
+// run calls the given function until it succeeds or the context is done, backing off exponentially between
+// attempts
+// Each attempt is bounded by the attempt timeout. If the context is done first, an *Error describing the attempts
+// is returned.
+func (p prober) run(ctx context.Context, attempt func(ctx context.Context) error) error {
+	start := time.Now()
+	interval := p.interval
+	probeErr := &Error{Target: p.target}
+	seen := make(map[string]bool)
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err := attempt(attemptCtx)
+		cancel()
+		probeErr.Attempts++
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() == nil {
+			if failure := err.Error(); !seen[failure] {
+				seen[failure] = true
+				probeErr.Failures = append(probeErr.Failures, failure)
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			probeErr.Elapsed = time.Since(start)
+			probeErr.err = ctx.Err()
+			return probeErr
+		}
+		interval *= 2
+		if interval > p.maxInterval {
+			interval = p.maxInterval
+		}
+	}
+}
+
+// within runs the probe with a context bounded by the given timeout
+func within(timeout time.Duration, await func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return await(ctx)
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTP(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("starting"))
+			return
+		}
+		_, _ = w.Write([]byte("ready"))
+	}))
+	defer server.Close()
+
+	err := HTTP(server.URL).
+		ExpectStatus(http.StatusOK).
+		ExpectBody("ready").
+		Interval(time.Millisecond, 10*time.Millisecond).
+		Within(5 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), requests.Load())
+}
+
+func TestHTTPFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("starting"))
+	}))
+	defer server.Close()
+
+	err := HTTP(server.URL).
+		Interval(time.Millisecond, 10*time.Millisecond).
+		Within(100 * time.Millisecond)
+	var probeErr *Error
+	assert.True(t, errors.As(err, &probeErr))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, server.URL, probeErr.Target)
+	assert.Greater(t, probeErr.Attempts, 1)
+	assert.Equal(t, []string{`unexpected status 503 Service Unavailable: "starting"`}, probeErr.Failures)
+	assert.Contains(t, err.Error(), "observed failures")
+}
+
+func TestHTTPExpectBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "bar", r.Header.Get("X-Foo"))
+		_, _ = w.Write([]byte("not yet"))
+	}))
+	defer server.Close()
+
+	err := HTTP(server.URL).
+		Method(http.MethodPost).
+		Header("X-Foo", "bar").
+		ExpectBody("ready").
+		Interval(time.Millisecond, 10*time.Millisecond).
+		Within(50 * time.Millisecond)
+	var probeErr *Error
+	assert.True(t, errors.As(err, &probeErr))
+	assert.Equal(t, []string{`response body does not contain "ready": "not yet"`}, probeErr.Failures)
+}
+
+func TestGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("foo", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	err = GRPC(lis.Addr().String()).
+		Service("foo").
+		Interval(time.Millisecond, 10*time.Millisecond).
+		Within(50 * time.Millisecond)
+	var probeErr *Error
+	assert.True(t, errors.As(err, &probeErr))
+	assert.Equal(t, []string{"service foo is NOT_SERVING"}, probeErr.Failures)
+
+	healthServer.SetServingStatus("foo", healthpb.HealthCheckResponse_SERVING)
+	err = GRPC(lis.Addr().String()).
+		Service("foo").
+		Interval(time.Millisecond, 10*time.Millisecond).
+		Within(5 * time.Second)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package run
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
+	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/pkg/helm"
+	"github.com/onosproject/helmit/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"os"
+	"time"
+)
+
+// Config is a job configuration
+type Config struct {
+	Namespace    string              `json:"namespace,omitempty"`
+	Args         map[string]string   `json:"args,omitempty"`
+	Context      string              `json:"context,omitempty"`
+	Values       map[string][]string `json:"values,omitempty"`
+	SecretValues map[string][]string `json:"secretValues,omitempty"`
+	ValueFiles   map[string][]string `json:"valueFiles,omitempty"`
+	Timeout      time.Duration       `json:"timeout,omitempty"`
+}
+
+// Func is a function to run as a job
+type Func func(ctx context.Context, job *Job) error
+
+// Main runs the given function as a job, exiting with a non-zero exit code if the function fails
+func Main(f Func) {
+	if err := run(f); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func run(f Func) error {
+	var config Config
+	if err := job.LoadConfig(&config); err != nil {
+		return err
+	}
+	secrets, err := job.LoadSecrets()
+	if err != nil {
+		return err
+	}
+
+	j, err := newJob(config, secrets)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+	return f(ctx, j)
+}
+
+func newJob(config Config, secrets map[string]string) (*Job, error) {
+	args := make(map[string]types.Value)
+	for key, value := range config.Args {
+		args[key] = types.NewValue(value)
+	}
+
+	restConfig, err := k8s.GetSuiteConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		Clientset:  clientset,
+		config:     config,
+		secrets:    secrets,
+		restConfig: restConfig,
+		args:       args,
+		helm: helm.NewClient(helm.Context{
+			ID:           job.GetID(),
+			Namespace:    config.Namespace,
+			WorkDir:      config.Context,
+			Values:       config.Values,
+			SecretValues: config.SecretValues,
+			ValueFiles:   config.ValueFiles,
+			Secrets:      secrets,
+		}),
+	}, nil
+}
+
+// Job provides the environment of a job run by the helmit run command
+type Job struct {
+	*kubernetes.Clientset
+	config     Config
+	secrets    map[string]string
+	restConfig *rest.Config
+	helm       *helm.Helm
+	args       map[string]types.Value
+}
+
+// Namespace returns the job namespace
+func (j *Job) Namespace() string {
+	return j.config.Namespace
+}
+
+// Config returns the Kubernetes REST configuration
+func (j *Job) Config() *rest.Config {
+	return j.restConfig
+}
+
+// Helm returns the Helm client
+func (j *Job) Helm() *helm.Helm {
+	return j.helm
+}
+
+// Secret returns a job secret by name
+func (j *Job) Secret(name string) string {
+	return j.secrets[name]
+}
+
+// Secrets returns the injected secrets
+func (j *Job) Secrets() map[string]string {
+	return j.secrets
+}
+
+// Arg returns a job argument by name
+func (j *Job) Arg(name string) types.Value {
+	value, ok := j.args[name]
+	if !ok {
+		return types.NewValue(nil)
+	}
+	return value
+}
+
+// Args returns the job arguments
+func (j *Job) Args() map[string]types.Value {
+	return j.args
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// getArtifactsDir returns the artifacts directory for the given test name
+func getArtifactsDir(config Config, name string) string {
+	return filepath.Join(config.ArtifactsDir, filepath.FromSlash(name))
+}
+
+// copyArtifact copies the given file or directory into the artifacts directory
+func copyArtifact(src, dir string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(dir, filepath.Base(src))
+	if !info.IsDir() {
+		return copyFile(src, dst, info.Mode())
+	}
+	return copyTree(src, dst)
+}
+
+// copyTree copies the contents of the src directory to the dst directory
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, relPath), os.ModePerm)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, filepath.Join(dst, relPath), info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// collectArtifacts writes the logs of all pods and the events in the namespace to the given directory
+func collectArtifacts(ctx context.Context, client kubernetes.Interface, namespace, dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if err := collectLogs(ctx, client, pod, container.Name, filepath.Join(dir, "pods", pod.Name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+
+	file, err := os.Create(filepath.Join(dir, "events.txt"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	for _, event := range events.Items {
+		fmt.Fprintf(file, "%s\t%s\t%s/%s\t%s\t%s\n",
+			event.LastTimestamp.Format(time.RFC3339), event.Type,
+			event.InvolvedObject.Kind, event.InvolvedObject.Name,
+			event.Reason, event.Message)
+	}
+	return file.Close()
+}
+
+// collectLogs writes the logs of the given pod container to the given directory
+func collectLogs(ctx context.Context, client kubernetes.Interface, pod corev1.Pod, container, dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	stream, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+	}).Stream(ctx)
+	if err != nil {
+		// Containers that have not yet started have no logs to collect
+		return nil
+	}
+	defer stream.Close()
+
+	file, err := os.Create(filepath.Join(dir, container+".log"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, stream); err != nil {
+		return err
+	}
+	return file.Close()
+}
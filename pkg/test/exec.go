@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/onosproject/helmit/internal/k8s"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// ExecResult is the result of a command executed in a pod
+type ExecResult struct {
+	// Stdout is the standard output of the command
+	Stdout string
+	// Stderr is the standard error of the command
+	Stderr string
+	// ExitCode is the exit code of the command
+	ExitCode int
+}
+
+// Exec executes the given command in a container of the named pod in the suite namespace
+// If the container is empty, the command is executed in the pod's default container. A command that exits with a
+// non-zero status is not an error; its exit code is returned in the result along with its output.
+func (suite *Suite) Exec(ctx context.Context, pod string, container string, cmd ...string) (ExecResult, error) {
+	var stdout, stderr bytes.Buffer
+	err := k8s.Exec(ctx, suite.restConfig, suite.Clientset, suite.Namespace(), pod, container, cmd, nil, &stdout, &stderr)
+	result := ExecResult{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+	if err != nil {
+		var exitErr utilexec.ExitError
+		if !errors.As(err, &exitErr) {
+			return result, err
+		}
+		result.ExitCode = exitErr.ExitStatus()
+	}
+	return result, nil
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/helmit/pkg/helm"
+	"strings"
+	"sync"
+)
+
+// Fixture is a Helm release shared by all the test suites that require it
+// Fixtures are installed once per namespace the first time they're required and uninstalled in reverse
+// dependency order once all suites have been run.
+type Fixture struct {
+	// Name is the name of the fixture and of its Helm release
+	Name string
+	// Chart is the chart from which to install the release
+	Chart string
+	// RepoURL is the URL of the repository containing the chart
+	RepoURL string
+	// Version is the version of the chart to install
+	Version string
+	// Values is a mapping of value paths to values to set on the release
+	Values map[string]any
+	// Requires is the names of the fixtures that must be installed before this fixture
+	Requires []string
+}
+
+// RegisterFixture registers a fixture that suites can require by name
+func RegisterFixture(fixture Fixture) {
+	fixtures.register(fixture)
+}
+
+var fixtures = newFixtureRegistry()
+
+func newFixtureRegistry() *fixtureRegistry {
+	return &fixtureRegistry{
+		fixtures:  make(map[string]Fixture),
+		installed: make(map[fixtureKey]*helm.Helm),
+		install:   installFixture,
+		uninstall: uninstallFixture,
+	}
+}
+
+type fixtureKey struct {
+	namespace string
+	name      string
+}
+
+// fixtureRegistry tracks registered fixtures and the fixtures installed in each namespace
+type fixtureRegistry struct {
+	fixtures  map[string]Fixture
+	installed map[fixtureKey]*helm.Helm
+	order     []fixtureKey
+	install   func(ctx context.Context, client *helm.Helm, fixture Fixture) error
+	uninstall func(ctx context.Context, client *helm.Helm, fixture Fixture) error
+	mu        sync.Mutex
+}
+
+func (r *fixtureRegistry) register(fixture Fixture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures[fixture.Name] = fixture
+}
+
+// require installs the named fixtures and their dependencies in the given namespace if they're not already installed
+func (r *fixtureRegistry) require(ctx context.Context, namespace string, client *helm.Helm, names ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		if err := r.requireLocked(ctx, namespace, client, name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fixtureRegistry) requireLocked(ctx context.Context, namespace string, client *helm.Helm, name string, path []string) error {
+	for _, parent := range path {
+		if parent == name {
+			return fmt.Errorf("fixture dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+	}
+
+	key := fixtureKey{namespace: namespace, name: name}
+	if _, ok := r.installed[key]; ok {
+		return nil
+	}
+
+	fixture, ok := r.fixtures[name]
+	if !ok {
+		return fmt.Errorf("unknown fixture %s", name)
+	}
+	for _, dependency := range fixture.Requires {
+		if err := r.requireLocked(ctx, namespace, client, dependency, append(path, name)); err != nil {
+			return err
+		}
+	}
+
+	if err := r.install(ctx, client, fixture); err != nil {
+		return fmt.Errorf("failed to install fixture %s: %w", name, err)
+	}
+	r.installed[key] = client
+	r.order = append(r.order, key)
+	return nil
+}
+
+// tearDown uninstalls all installed fixtures in the reverse of the order in which they were installed
+func (r *fixtureRegistry) tearDown(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []string
+	for i := len(r.order) - 1; i >= 0; i-- {
+		key := r.order[i]
+		if err := r.uninstall(ctx, r.installed[key], r.fixtures[key.name]); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to uninstall fixture %s: %s", key.name, err))
+		}
+		delete(r.installed, key)
+	}
+	r.order = nil
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func installFixture(ctx context.Context, client *helm.Helm, fixture Fixture) error {
+	cmd := client.Install(fixture.Name, fixture.Chart).Wait()
+	if fixture.RepoURL != "" {
+		cmd.RepoURL(fixture.RepoURL)
+	}
+	if fixture.Version != "" {
+		cmd.Version(fixture.Version)
+	}
+	for path, value := range fixture.Values {
+		cmd.Set(path, value)
+	}
+	return cmd.Do(ctx)
+}
+
+func uninstallFixture(ctx context.Context, client *helm.Helm, fixture Fixture) error {
+	return client.Uninstall(fixture.Name).Wait().Do(ctx)
+}
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"github.com/onosproject/helmit/pkg/helm"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	var installed, uninstalled []string
+	registry := newFixtureRegistry()
+	registry.install = func(ctx context.Context, client *helm.Helm, fixture Fixture) error {
+		installed = append(installed, fixture.Name)
+		return nil
+	}
+	registry.uninstall = func(ctx context.Context, client *helm.Helm, fixture Fixture) error {
+		uninstalled = append(uninstalled, fixture.Name)
+		return nil
+	}
+	registry.register(Fixture{Name: "controller"})
+	registry.register(Fixture{Name: "raft", Requires: []string{"controller"}})
+	registry.register(Fixture{Name: "cache", Requires: []string{"controller"}})
+	registry.register(Fixture{Name: "foo", Requires: []string{"bar"}})
+	registry.register(Fixture{Name: "bar", Requires: []string{"foo"}})
+
+	ctx := context.Background()
+	assert.NoError(t, registry.require(ctx, "test", nil, "raft"))
+	assert.NoError(t, registry.require(ctx, "test", nil, "raft", "cache"))
+	assert.Equal(t, []string{"controller", "raft", "cache"}, installed)
+
+	assert.NoError(t, registry.require(ctx, "other", nil, "controller"))
+	assert.Equal(t, []string{"controller", "raft", "cache", "controller"}, installed)
+
+	assert.Error(t, registry.require(ctx, "test", nil, "unknown"))
+	assert.EqualError(t, registry.require(ctx, "test", nil, "foo"), "fixture dependency cycle: foo -> bar -> foo")
+
+	assert.NoError(t, registry.tearDown(ctx))
+	assert.Equal(t, []string{"controller", "cache", "raft", "controller"}, uninstalled)
+	assert.Empty(t, registry.installed)
+
+	assert.NoError(t, registry.require(ctx, "test", nil, "controller"))
+	assert.Equal(t, "controller", installed[len(installed)-1])
+}
@@ -5,8 +5,10 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"github.com/onosproject/helmit/internal/job"
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -14,19 +16,32 @@ import (
 
 // Config is a test configuration
 type Config struct {
-	Namespace  string              `json:"namespace,omitempty"`
-	Suites     []string            `json:"suites,omitempty"`
-	Tests      []string            `json:"tests,omitempty"`
-	Methods    []string            `json:"methods,omitempty"`
-	Verbose    bool                `json:"verbose,omitempty"`
-	Args       map[string]string   `json:"args,omitempty"`
-	Context    string              `json:"context,omitempty"`
-	Values     map[string][]string `json:"values,omitempty"`
-	ValueFiles map[string][]string `json:"valueFiles,omitempty"`
-	Timeout    time.Duration       `json:"timeout,omitempty"`
-	NoTeardown bool                `json:"noTeardown,omitempty"`
+	Namespace       string              `json:"namespace,omitempty"`
+	Suites          []string            `json:"suites,omitempty"`
+	Tests           []string            `json:"tests,omitempty"`
+	Methods         []string            `json:"methods,omitempty"`
+	Tags            []string            `json:"tags,omitempty"`
+	Verbose         bool                `json:"verbose,omitempty"`
+	Args            map[string]string   `json:"args,omitempty"`
+	Context         string              `json:"context,omitempty"`
+	Values          map[string][]string `json:"values,omitempty"`
+	SecretValues    map[string][]string `json:"secretValues,omitempty"`
+	ValueFiles      map[string][]string `json:"valueFiles,omitempty"`
+	Timeout         time.Duration       `json:"timeout,omitempty"`
+	NoTeardown      bool                `json:"noTeardown,omitempty"`
+	ArtifactsDir    string              `json:"artifactsDir,omitempty"`
+	CollectFixtures bool                `json:"collectFixtures,omitempty"`
+	Iterations      int                 `json:"iterations,omitempty"`
+	UntilFailure    bool                `json:"untilFailure,omitempty"`
+	FailFast        bool                `json:"failFast,omitempty"`
 }
 
+// maxIterations is the number of iterations run until failure when the number of iterations is not limited
+const maxIterations = math.MaxInt32
+
+// iterationMarker is written to the test output at the start of each iteration when the tests are repeated
+const iterationMarker = "=== ITERATION"
+
 // Main runs a test
 func Main(suites []TestingSuite) {
 	var config Config
@@ -40,9 +55,17 @@ func Main(suites []TestingSuite) {
 		os.Exit(1)
 	}
 
+	if config.ArtifactsDir != "" {
+		if err := os.MkdirAll(config.ArtifactsDir, os.ModePerm); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
 	var tests []testing.InternalTest
-	for _, suite := range suites {
-		name := getSuiteName(suite)
+	names := getTestNames(suites)
+	for i, suite := range suites {
+		name := names[i]
 		if isRunnable(name, config.Tests) {
 			tests = append(tests, func(suite TestingSuite) testing.InternalTest {
 				return testing.InternalTest{
@@ -55,11 +78,74 @@ func Main(suites []TestingSuite) {
 		}
 	}
 
+	iterations := getIterations(config)
+	tests = repeatTests(tests, config, iterations)
+
 	// Hack to enable verbose testing.
-	os.Args = []string{
-		os.Args[0],
-		"-test.v",
-	}
+	os.Args = append([]string{os.Args[0]}, getTestFlags(config, iterations)...)
 
 	testing.Main(func(_, _ string) (bool, error) { return true, nil }, tests, nil, nil)
 }
+
+// getTestFlags returns the flags with which to run the testing package
+func getTestFlags(config Config, iterations int) []string {
+	flags := []string{"-test.v"}
+	// Repeated runs are delegated to the testing package, which runs all the suites once per iteration
+	if iterations > 1 {
+		flags = append(flags, fmt.Sprintf("-test.count=%d", iterations))
+	}
+	// Once a test fails, the testing package starts no further tests, skipping the remaining methods and suites
+	if config.FailFast || (iterations > 1 && config.UntilFailure) {
+		flags = append(flags, "-test.failfast")
+	}
+	return flags
+}
+
+// getIterations returns the number of times to run the suites
+func getIterations(config Config) int {
+	if config.UntilFailure && config.Iterations <= 0 {
+		return maxIterations
+	}
+	if config.Iterations <= 0 {
+		return 1
+	}
+	return config.Iterations
+}
+
+// repeatTests wraps the given suites to mark the start of each iteration in the output and uninstall shared
+// fixtures once the last suite has been run in the last iteration
+// When running until failure or failing fast, no suites are run after a suite fails, so fixtures are also
+// uninstalled then.
+func repeatTests(tests []testing.InternalTest, config Config, iterations int) []testing.InternalTest {
+	iteration := 0
+	for i := range tests {
+		f := tests[i].F
+		first, last := i == 0, i == len(tests)-1
+		tests[i].F = func(t *testing.T) {
+			if first {
+				iteration++
+				if iterations > 1 {
+					fmt.Printf("%s %d\n", iterationMarker, iteration)
+				}
+			}
+			if !config.NoTeardown {
+				defer func() {
+					if (last && iteration == iterations) || ((config.UntilFailure || config.FailFast) && t.Failed()) {
+						tearDownFixtures(t, config)
+					}
+				}()
+			}
+			f(t)
+		}
+	}
+	return tests
+}
+
+// tearDownFixtures uninstalls the fixtures installed by the suites
+func tearDownFixtures(t *testing.T, config Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	if err := fixtures.tearDown(ctx); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultTailLines = 50
+
+// NewEventRecorder creates a new EventRecorder for the given namespace
+func NewEventRecorder(client kubernetes.Interface, namespace string) *EventRecorder {
+	return &EventRecorder{
+		client:    client,
+		namespace: namespace,
+		tailLines: defaultTailLines,
+	}
+}
+
+// EventRecorder records the state of a namespace for diagnosing test failures
+type EventRecorder struct {
+	client    kubernetes.Interface
+	namespace string
+	tailLines int64
+}
+
+// Record writes the events, pod statuses, and recent container logs in the namespace to the given writer
+func (r *EventRecorder) Record(ctx context.Context, writer io.Writer) error {
+	if err := r.recordEvents(ctx, writer); err != nil {
+		return err
+	}
+	pods, err := r.client.CoreV1().Pods(r.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	r.recordPods(pods.Items, writer)
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			r.recordLogs(ctx, pod, container.Name, writer)
+		}
+	}
+	return nil
+}
+
+func (r *EventRecorder) recordEvents(ctx context.Context, writer io.Writer) error {
+	events, err := r.client.CoreV1().Events(r.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+	fmt.Fprintf(writer, "Events in namespace %s:\n", r.namespace)
+	for _, event := range events.Items {
+		fmt.Fprintf(writer, "  %s\t%s\t%s/%s\t%s\t%s\n",
+			event.LastTimestamp.Format(time.RFC3339), event.Type,
+			event.InvolvedObject.Kind, event.InvolvedObject.Name,
+			event.Reason, event.Message)
+	}
+	return nil
+}
+
+func (r *EventRecorder) recordPods(pods []corev1.Pod, writer io.Writer) {
+	fmt.Fprintf(writer, "Pods in namespace %s:\n", r.namespace)
+	for _, pod := range pods {
+		fmt.Fprintf(writer, "  %s\t%s\n", pod.Name, pod.Status.Phase)
+		for _, status := range pod.Status.ContainerStatuses {
+			fmt.Fprintf(writer, "    %s\tready=%t\trestarts=%d\t%s\n",
+				status.Name, status.Ready, status.RestartCount, getContainerState(status.State))
+		}
+	}
+}
+
+func (r *EventRecorder) recordLogs(ctx context.Context, pod corev1.Pod, container string, writer io.Writer) {
+	stream, err := r.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &r.tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return
+	}
+	defer stream.Close()
+
+	fmt.Fprintf(writer, "Logs for %s/%s:\n", pod.Name, container)
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		fmt.Fprintf(writer, "  %s\n", scanner.Text())
+	}
+}
+
+func getContainerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return strings.TrimSpace(fmt.Sprintf("Waiting %s %s", state.Waiting.Reason, state.Waiting.Message))
+	case state.Terminated != nil:
+		return strings.TrimSpace(fmt.Sprintf("Terminated %s (exit code %d) %s",
+			state.Terminated.Reason, state.Terminated.ExitCode, state.Terminated.Message))
+	}
+	return "Unknown"
+}
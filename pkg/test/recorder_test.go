@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestEventRecorder(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "test",
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "bar",
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{
+								Reason: "ImagePullBackOff",
+							},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo.1",
+				Namespace: "test",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: "foo",
+			},
+			Type:    corev1.EventTypeWarning,
+			Reason:  "Failed",
+			Message: "Failed to pull image",
+		})
+
+	var buf bytes.Buffer
+	assert.NoError(t, NewEventRecorder(client, "test").Record(context.Background(), &buf))
+	assert.Contains(t, buf.String(), "Pod/foo")
+	assert.Contains(t, buf.String(), "Failed to pull image")
+	assert.Contains(t, buf.String(), "Waiting ImagePullBackOff")
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fixturesArtifactsDir is the name of the directory to which the temporary files of a failed test are copied
+// in the test's artifacts
+const fixturesArtifactsDir = "fixtures"
+
+// tempDirs manages the temporary directories of the tests in a suite
+// Each test's directory is nested under its parent test's directory, so the directory of a test method
+// includes the directories of its subtests.
+type tempDirs struct {
+	root string
+}
+
+// get returns the temporary directory for the named test, creating it if necessary
+func (d *tempDirs) get(name string) (string, error) {
+	if d.root == "" {
+		root, err := os.MkdirTemp("", "helmit-")
+		if err != nil {
+			return "", err
+		}
+		d.root = root
+	}
+	dir := filepath.Join(d.root, filepath.FromSlash(name))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// collect copies the temporary files of the named test and its subtests to the given directory
+func (d *tempDirs) collect(name string, dir string) error {
+	if d.root == "" {
+		return nil
+	}
+	src := filepath.Join(d.root, filepath.FromSlash(name))
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return copyTree(src, filepath.Join(dir, fixturesArtifactsDir))
+}
+
+// remove removes the temporary directories of all the tests
+func (d *tempDirs) remove() error {
+	if d.root == "" {
+		return nil
+	}
+	err := os.RemoveAll(d.root)
+	d.root = ""
+	return err
+}
+
+// TempDir returns a temporary directory for the current test
+// The directory is the same for every call within a test and is removed when the suite is torn down, unless
+// teardown is disabled.
+func (suite *Suite) TempDir() string {
+	dir, err := suite.tempDirs.get(suite.T().Name())
+	suite.Require().NoError(err)
+	return dir
+}
+
+// WriteFixture writes a file with the given name and contents to the current test's temporary directory,
+// returning the path to the file
+func (suite *Suite) WriteFixture(name string, bytes []byte) string {
+	path := filepath.Join(suite.TempDir(), filepath.FromSlash(name))
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	suite.Require().NoError(os.WriteFile(path, bytes, 0644))
+	return path
+}
+
+func (suite *Suite) getTempDirs() *tempDirs {
+	return &suite.tempDirs
+}
+
+// tempDirSuite is implemented by suites that embed Suite
+type tempDirSuite interface {
+	getTempDirs() *tempDirs
+}
+
+// collectFixtures copies the temporary files of a failed test to the test's artifacts if enabled
+func collectFixtures(t *testing.T, suite TestingSuite, config Config) {
+	dirs, ok := suite.(tempDirSuite)
+	if !ok || !config.CollectFixtures || config.ArtifactsDir == "" {
+		return
+	}
+	if err := dirs.getTempDirs().collect(t.Name(), getArtifactsDir(config, t.Name())); err != nil {
+		t.Logf("failed to collect fixtures: %s", err)
+	}
+}
+
+// removeTempDirs removes the temporary directories of the tests in the given suite
+func removeTempDirs(t *testing.T, suite TestingSuite) {
+	if dirs, ok := suite.(tempDirSuite); ok {
+		if err := dirs.getTempDirs().remove(); err != nil {
+			t.Logf("failed to remove temporary files: %s", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Open Networking Foundation <info@opennetworking.org>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTempDirs(t *testing.T) {
+	artifactsDir := t.TempDir()
+	config := Config{
+		ArtifactsDir:    artifactsDir,
+		CollectFixtures: true,
+	}
+	suite := &testSuite{}
+	suite.SetT(t)
+
+	dir := suite.TempDir()
+	assert.Equal(t, dir, suite.TempDir())
+	path := suite.WriteFixture("config/app.yaml", []byte("replicas: 3"))
+	assert.Equal(t, filepath.Join(dir, "config", "app.yaml"), path)
+
+	t.Run("Sub", func(t *testing.T) {
+		suite.SetT(t)
+		subDir := suite.TempDir()
+		assert.Equal(t, filepath.Join(dir, "Sub"), subDir)
+		suite.WriteFixture("sub.txt", []byte("sub"))
+	})
+	suite.SetT(t)
+
+	collectFixtures(t, suite, config)
+	bytes, err := os.ReadFile(filepath.Join(artifactsDir, t.Name(), fixturesArtifactsDir, "config", "app.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "replicas: 3", string(bytes))
+	bytes, err = os.ReadFile(filepath.Join(artifactsDir, t.Name(), fixturesArtifactsDir, "Sub", "sub.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "sub", string(bytes))
+
+	removeTempDirs(t, suite)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -5,18 +5,23 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"github.com/onosproject/helmit/internal/job"
 	"github.com/onosproject/helmit/internal/k8s"
+	"github.com/onosproject/helmit/internal/match"
 	"github.com/onosproject/helmit/pkg/helm"
 	"github.com/onosproject/helmit/pkg/types"
 	"github.com/stretchr/testify/suite"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"path"
 	"reflect"
-	"regexp"
 	"runtime/debug"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestingSuite is a suite of tests
@@ -42,6 +47,8 @@ type TestingSuite interface {
 	Args() map[string]types.Value
 	// Helm returns the Helm client
 	Helm() *helm.Helm
+	// Artifact stores the given file or directory as an artifact of the current test
+	Artifact(path string)
 	// Run runs a subtest
 	Run(name string, f func()) bool
 	// RunSuite runs a sub-suite
@@ -76,6 +83,39 @@ type TearDownTest interface {
 	TearDownTest()
 }
 
+// SuiteTimeout has a SuiteTimeout method, which overrides the
+// timeout for the suite.
+type SuiteTimeout interface {
+	// SuiteTimeout returns the maximum duration for which the suite may run
+	SuiteTimeout() time.Duration
+}
+
+// MethodTimeout has a MethodTimeout method, which overrides the
+// timeout for individual tests in the suite.
+type MethodTimeout interface {
+	// MethodTimeout returns the timeout for the given test method, or zero to use the default timeout
+	MethodTimeout(method string) time.Duration
+}
+
+// TestOrder has a TestOrder method, which orders the tests in the suite.
+type TestOrder interface {
+	// TestOrder returns the names of tests that must run in the given order before the suite's other tests
+	// Each test in the order depends on the tests before it, and is skipped if any of them fails.
+	TestOrder() []string
+}
+
+// Tags has a Tags method, which tags all the tests in the suite.
+type Tags interface {
+	// Tags returns the tags of the suite's tests
+	Tags() []string
+}
+
+// MethodTags has a MethodTags method, which tags individual tests in the suite.
+type MethodTags interface {
+	// MethodTags returns the tags of the given test method in addition to the suite's tags
+	MethodTags(method string) []string
+}
+
 // Suite is the base for a test suite
 type Suite struct {
 	suite.Suite
@@ -86,6 +126,7 @@ type Suite struct {
 	helm       *helm.Helm
 	args       map[string]types.Value
 	ctx        context.Context
+	tempDirs   tempDirs
 }
 
 // Init initializes the test suite
@@ -99,7 +140,7 @@ func (suite *Suite) Init(config Config, secrets map[string]string) {
 	}
 	suite.args = args
 
-	restConfig, err := k8s.GetConfig()
+	restConfig, err := k8s.GetSuiteConfig()
 	suite.NoError(err)
 	suite.restConfig = restConfig
 
@@ -108,10 +149,13 @@ func (suite *Suite) Init(config Config, secrets map[string]string) {
 	suite.Clientset = clientset
 
 	suite.helm = helm.NewClient(helm.Context{
-		Namespace:  config.Namespace,
-		WorkDir:    config.Context,
-		Values:     config.Values,
-		ValueFiles: config.ValueFiles,
+		ID:           job.GetID(),
+		Namespace:    config.Namespace,
+		WorkDir:      config.Context,
+		Values:       config.Values,
+		SecretValues: config.SecretValues,
+		ValueFiles:   config.ValueFiles,
+		Secrets:      secrets,
 	})
 }
 
@@ -138,11 +182,19 @@ func (suite *Suite) SetConfig(config *rest.Config) {
 
 // Config returns the Kubernetes REST configuration
 func (suite *Suite) Config() *rest.Config {
-	restConfig, err := rest.InClusterConfig()
-	if err != nil {
-		suite.T().Fatal(err)
-	}
-	return restConfig
+	return suite.restConfig
+}
+
+// ClientFor returns a Kubernetes client for the resources owned by the given release in the suite namespace
+func (suite *Suite) ClientFor(release string) *helm.ReleaseClient {
+	return helm.NewReleaseClient(suite.Clientset, suite.Namespace(), release)
+}
+
+// AwaitRelease waits for the workloads created by the given release to be ready
+// If the workloads are not ready before the context is done, a *helm.NotReadyError describing the unready
+// workloads, pending pods, and recent warning events is returned.
+func (suite *Suite) AwaitRelease(ctx context.Context, release string) error {
+	return suite.ClientFor(release).AwaitReady(ctx)
 }
 
 // SetHelm sets the Helm client
@@ -179,6 +231,25 @@ func (suite *Suite) Args() map[string]types.Value {
 	return suite.args
 }
 
+// Requires installs the named fixtures in the suite namespace if they have not already been installed
+// Fixtures are shared by all suites in the namespace and uninstalled once all suites have been run, so they're
+// not tracked by the suite's Helm client.
+func (suite *Suite) Requires(names ...string) {
+	ctx := suite.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	suite.Require().NoError(fixtures.require(ctx, suite.Namespace(), suite.Helm().Untracked(), names...))
+}
+
+// Artifact stores the given file or directory as an artifact of the current test
+func (suite *Suite) Artifact(path string) {
+	if suite.config.ArtifactsDir == "" {
+		return
+	}
+	suite.NoError(copyArtifact(path, getArtifactsDir(suite.config, suite.T().Name())))
+}
+
 // Run runs a test function
 func (suite *Suite) Run(name string, subtest func()) bool {
 	parentT := suite.T()
@@ -189,11 +260,15 @@ func (suite *Suite) Run(name string, subtest func()) bool {
 	defer suite.SetT(parentT)
 	parentCtx := suite.Context()
 	defer suite.SetContext(parentCtx)
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
 	return parentT.Run(name, func(t *testing.T) {
 		suite.SetT(t)
-		ctx, cancel := context.WithTimeout(context.Background(), suite.config.Timeout)
+		ctx, cancel := context.WithTimeout(parentCtx, suite.config.Timeout)
 		defer cancel()
 		suite.SetContext(ctx)
+		defer failOnTimeout(t, ctx, suite.config.Timeout)
 		subtest()
 	})
 }
@@ -216,6 +291,10 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 	defer recoverAndFailOnPanic(t)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	suiteTimeout := getSuiteTimeout(suite)
+	if suiteTimeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), suiteTimeout)
+	}
 	defer cancel()
 
 	suite.SetT(t)
@@ -225,13 +304,25 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 	var suiteSetupDone bool
 
 	methodFinder := reflect.TypeOf(suite)
-	for i := 0; i < methodFinder.NumMethod(); i++ {
-		method := methodFinder.Method(i)
+	methods, ordered, err := getTestMethods(t, suite, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
 
-		if !isRunnable(method.Name, config.Methods) {
+	var failed string
+	for i, method := range methods {
+		if ctx.Err() != nil {
+			// Report the tests the suite timed out before running, so the results list every test
+			t.Run(method.Name, func(t *testing.T) {
+				t.Errorf("not run because %s timed out after %s", getSuiteName(suite), suiteTimeout)
+			})
 			continue
 		}
-		if !isTestRunnable(t, method.Name, config.Tests) {
+		if i < ordered && failed != "" {
+			suite.Run(method.Name, func() {
+				suite.T().Skipf("skipped because %s failed", failed)
+			})
 			continue
 		}
 
@@ -242,7 +333,7 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 			suiteSetupDone = true
 		}
 
-		suite.Run(method.Name, func() {
+		passed := suite.Run(method.Name, func() {
 			t := suite.T()
 			defer recoverAndFailOnPanic(t)
 			defer func() {
@@ -253,9 +344,20 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 				if tearDownTest, ok := suite.(TearDownTest); ok {
 					tearDownTest.TearDownTest()
 				}
+				if r != nil || t.Failed() {
+					recordEvents(t, suite)
+					collectFixtures(t, suite, config)
+				}
 				failOnPanic(t, r)
 			}()
 
+			if timeout := getMethodTimeout(suite, method.Name); timeout > 0 {
+				ctx, cancel := context.WithTimeout(suite.Context(), timeout)
+				defer cancel()
+				suite.SetContext(ctx)
+				defer failOnTimeout(t, ctx, timeout)
+			}
+
 			if setupTest, ok := suite.(SetupTest); ok {
 				setupTest.SetupTest()
 			}
@@ -265,6 +367,24 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 
 			method.Func.Call([]reflect.Value{reflect.ValueOf(suite)})
 		})
+		if i < ordered && !passed {
+			failed = method.Name
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Errorf("%s timed out after %s", getSuiteName(suite), suiteTimeout)
+
+		// Replace the expired suite context to allow the suite to be torn down
+		ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+		defer cancel()
+		suite.SetContext(ctx)
+	}
+
+	if suiteSetupDone && config.ArtifactsDir != "" {
+		if err := collectSuiteArtifacts(suite, t.Name(), config); err != nil {
+			t.Logf("failed to collect artifacts: %s", err)
+		}
 	}
 
 	if suiteSetupDone && !config.NoTeardown {
@@ -272,10 +392,80 @@ func run(t *testing.T, suite TestingSuite, config Config, secrets map[string]str
 			if tearDownSuite, ok := suite.(TearDownSuite); ok {
 				tearDownSuite.TearDownSuite()
 			}
+			uninstallReleases(t, suite, config)
+			removeTempDirs(t, suite)
 		}()
 	}
 }
 
+// uninstallReleases uninstalls the releases installed by the suite that were not uninstalled when it was torn down
+func uninstallReleases(t *testing.T, suite TestingSuite, config Config) {
+	if len(suite.Helm().Releases()) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	if err := suite.Helm().UninstallAll(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+// recordEvents logs the state of the suite namespace to the given test
+func recordEvents(t *testing.T, suite TestingSuite) {
+	client, err := newClient()
+	if err != nil {
+		t.Logf("failed to record events: %s", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	var buf bytes.Buffer
+	if err := NewEventRecorder(client, suite.Namespace()).Record(ctx, &buf); err != nil {
+		t.Logf("failed to record events: %s", err)
+		return
+	}
+	t.Log(buf.String())
+}
+
+// collectSuiteArtifacts collects the pod logs and events in the suite namespace
+func collectSuiteArtifacts(suite TestingSuite, name string, config Config) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return collectArtifacts(ctx, client, suite.Namespace(), getArtifactsDir(config, name))
+}
+
+func newClient() (kubernetes.Interface, error) {
+	config, err := k8s.GetSuiteConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+func getSuiteTimeout(suite TestingSuite) time.Duration {
+	if suiteTimeout, ok := suite.(SuiteTimeout); ok {
+		return suiteTimeout.SuiteTimeout()
+	}
+	return 0
+}
+
+func getMethodTimeout(suite TestingSuite, method string) time.Duration {
+	if methodTimeout, ok := suite.(MethodTimeout); ok {
+		return methodTimeout.MethodTimeout(method)
+	}
+	return 0
+}
+
+func failOnTimeout(t *testing.T, ctx context.Context, timeout time.Duration) {
+	if ctx.Err() == context.DeadlineExceeded {
+		t.Errorf("%s timed out after %s", t.Name(), timeout)
+	}
+}
+
 func recoverAndFailOnPanic(t *testing.T) {
 	r := recover()
 	failOnPanic(t, r)
@@ -288,47 +478,136 @@ func failOnPanic(t *testing.T, r interface{}) {
 	}
 }
 
+// getTestMethods returns the test methods of the suite to run, in the order in which to run them
+// The tests listed by the suite's TestOrder run first, in the given order, followed by the suite's other tests in
+// alphabetical order. The number of ordered tests at the start of the returned methods is also returned.
+func getTestMethods(t *testing.T, suite TestingSuite, config Config) ([]reflect.Method, int, error) {
+	methodFinder := reflect.TypeOf(suite)
+	isTest := func(method reflect.Method) bool {
+		return isRunnable(method.Name, config.Methods) && isTestRunnable(t, method.Name, config.Tests) && isTestMethod(method) &&
+			isTagged(getTestTags(suite, method.Name), config.Tags)
+	}
+
+	var methods []reflect.Method
+	inOrder := make(map[string]bool)
+	if testOrder, ok := suite.(TestOrder); ok {
+		for _, name := range testOrder.TestOrder() {
+			method, ok := methodFinder.MethodByName(name)
+			if !ok || !isTestMethod(method) {
+				return nil, 0, fmt.Errorf("%s: TestOrder includes unknown test %s", getSuiteName(suite), name)
+			}
+			if inOrder[name] {
+				return nil, 0, fmt.Errorf("%s: TestOrder includes test %s more than once", getSuiteName(suite), name)
+			}
+			inOrder[name] = true
+			if isTest(method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	ordered := len(methods)
+
+	for i := 0; i < methodFinder.NumMethod(); i++ {
+		method := methodFinder.Method(i)
+		if !inOrder[method.Name] && isTest(method) {
+			methods = append(methods, method)
+		}
+	}
+	return methods, ordered, nil
+}
+
+// isTestMethod returns whether the given method's signature is runnable as a test
+func isTestMethod(method reflect.Method) bool {
+	return method.Type.NumIn() == 1 && method.Type.NumOut() == 0
+}
+
+// getTestTags returns the tags of the given test method, including the tags of its suite
+// Suites are tagged with a "tags" struct tag on an embedded field, e.g. the test.Suite, or by implementing Tags, and
+// individual tests by implementing MethodTags.
+func getTestTags(suite TestingSuite, method string) []string {
+	var tags []string
+	t := getSuiteType(suite)
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if value, ok := field.Tag.Lookup("tags"); ok && field.Anonymous {
+				for _, tag := range strings.Split(value, ",") {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						tags = append(tags, tag)
+					}
+				}
+			}
+		}
+	}
+	if suiteTags, ok := suite.(Tags); ok {
+		tags = append(tags, suiteTags.Tags()...)
+	}
+	if methodTags, ok := suite.(MethodTags); ok {
+		tags = append(tags, methodTags.MethodTags(method)...)
+	}
+	return tags
+}
+
 func getSuiteName(suite TestingSuite) string {
+	return getSuiteType(suite).Name()
+}
+
+func getSuiteType(suite TestingSuite) reflect.Type {
 	t := reflect.TypeOf(suite)
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
 	}
-	return t.Name()
+	return t
 }
 
-func isRunnable(name string, patterns []string) bool {
-	if len(patterns) == 0 {
-		return true
+// getTestNames returns the names of the top-level tests that run the given suites
+func getTestNames(suites []TestingSuite) []string {
+	pkgPaths := make([]string, len(suites))
+	names := make([]string, len(suites))
+	for i, suite := range suites {
+		t := getSuiteType(suite)
+		pkgPaths[i] = t.PkgPath()
+		names[i] = t.Name()
 	}
-	return matchesPatterns([]string{name}, patterns)
+	return qualifySuiteNames(pkgPaths, names)
 }
 
-func isTestRunnable(t *testing.T, name string, patterns []string) bool {
-	if len(patterns) == 0 {
-		return true
+// qualifySuiteNames qualifies the given suite names with the base names of their packages when the suites
+// come from more than one package, so suites with the same name in different packages can be told apart
+func qualifySuiteNames(pkgPaths []string, names []string) []string {
+	qualified := make([]string, len(names))
+	copy(qualified, names)
+	for _, pkgPath := range pkgPaths {
+		if pkgPath != pkgPaths[0] {
+			for i, name := range names {
+				qualified[i] = path.Base(pkgPaths[i]) + "." + name
+			}
+			break
+		}
 	}
-	names := append(strings.Split(t.Name(), "/"), name)
-	return matchesPatterns(names, patterns)
+	return qualified
 }
 
-func matchesPatterns(names []string, patterns []string) bool {
-	for _, pattern := range patterns {
-		if matchesPattern(names, pattern) {
-			return true
-		}
+func isRunnable(name string, patterns []string) bool {
+	matcher, err := match.New(patterns...)
+	if err != nil {
+		return false
 	}
-	return false
+	return matcher.Match(name)
 }
 
-func matchesPattern(names []string, pattern string) bool {
-	patterns := strings.Split(pattern, "/")
-	for i, name := range names {
-		if i+1 > len(patterns) {
-			break
-		}
-		if ok, _ := regexp.MatchString(patterns[i], name); !ok {
-			return false
-		}
+func isTagged(tags []string, selector []string) bool {
+	tagSelector, err := match.NewTags(selector...)
+	if err != nil {
+		return false
+	}
+	return tagSelector.Match(tags...)
+}
+
+func isTestRunnable(t *testing.T, name string, patterns []string) bool {
+	matcher, err := match.New(patterns...)
+	if err != nil {
+		return false
 	}
-	return true
+	return matcher.Match(append(strings.Split(t.Name(), "/"), name)...)
 }
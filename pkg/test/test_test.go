@@ -16,6 +16,12 @@ func TestGetSuiteName(t *testing.T) {
 	assert.Equal(t, "subTestSuite", getSuiteName(&subTestSuite{}))
 }
 
+func TestGetTestNames(t *testing.T) {
+	assert.Equal(t, []string{"testSuite", "subTestSuite"}, getTestNames([]TestingSuite{&testSuite{}, &subTestSuite{}}))
+	assert.Equal(t, []string{"tests.TestSuite", "more-tests.TestSuite"},
+		qualifySuiteNames([]string{"example.com/cmd/tests", "example.com/cmd/more-tests"}, []string{"TestSuite", "TestSuite"}))
+}
+
 func TestPatterns(t *testing.T) {
 	assert.True(t, isRunnable("FooSuite", []string{}))
 	assert.True(t, isRunnable("FooSuite", []string{"FooSuite"}))
@@ -35,6 +41,85 @@ func TestPatterns(t *testing.T) {
 	assert.False(t, isTestRunnable(t, "TestFoo", []string{"TestBar"}))
 }
 
+func TestTimeouts(t *testing.T) {
+	assert.Equal(t, time.Duration(0), getSuiteTimeout(&testSuite{}))
+	assert.Equal(t, time.Duration(0), getMethodTimeout(&testSuite{}, "TestFoo"))
+	assert.Equal(t, time.Hour, getSuiteTimeout(&timeoutTestSuite{}))
+	assert.Equal(t, time.Second, getMethodTimeout(&timeoutTestSuite{}, "TestFoo"))
+	assert.Equal(t, time.Duration(0), getMethodTimeout(&timeoutTestSuite{}, "TestBar"))
+}
+
+func TestTags(t *testing.T) {
+	assert.Empty(t, getTestTags(&testSuite{}, "TestTest"))
+	assert.Equal(t, []string{"raft", "smoke", "atomix"}, getTestTags(&taggedTestSuite{}, "TestFast"))
+	assert.Equal(t, []string{"raft", "smoke", "atomix", "slow"}, getTestTags(&taggedTestSuite{}, "TestSlow"))
+	assert.True(t, isTagged(nil, nil))
+	assert.True(t, isTagged([]string{"smoke"}, []string{"smoke", "!slow"}))
+	assert.False(t, isTagged([]string{"smoke", "slow"}, []string{"smoke", "!slow"}))
+}
+
+func TestIterations(t *testing.T) {
+	assert.Equal(t, 1, getIterations(Config{}))
+	assert.Equal(t, 5, getIterations(Config{Iterations: 5}))
+	assert.Equal(t, 5, getIterations(Config{Iterations: 5, UntilFailure: true}))
+	assert.Equal(t, maxIterations, getIterations(Config{UntilFailure: true}))
+
+	var runs []string
+	tests := repeatTests([]testing.InternalTest{
+		{Name: "FooSuite", F: func(t *testing.T) { runs = append(runs, "FooSuite") }},
+		{Name: "BarSuite", F: func(t *testing.T) { runs = append(runs, "BarSuite") }},
+	}, Config{NoTeardown: true}, 2)
+	for i := 0; i < 2; i++ {
+		for _, test := range tests {
+			test.F(t)
+		}
+	}
+	assert.Equal(t, []string{"FooSuite", "BarSuite", "FooSuite", "BarSuite"}, runs)
+}
+
+func TestTestFlags(t *testing.T) {
+	assert.Equal(t, []string{"-test.v"}, getTestFlags(Config{}, 1))
+	assert.Equal(t, []string{"-test.v", "-test.failfast"}, getTestFlags(Config{FailFast: true}, 1))
+	assert.Equal(t, []string{"-test.v", "-test.count=5"}, getTestFlags(Config{Iterations: 5}, 5))
+	assert.Equal(t, []string{"-test.v", "-test.count=5", "-test.failfast"}, getTestFlags(Config{Iterations: 5, UntilFailure: true}, 5))
+}
+
+func TestGetTestMethods(t *testing.T) {
+	getNames := func(suite TestingSuite, config Config) ([]string, int) {
+		methods, ordered, err := getTestMethods(t, suite, config)
+		assert.NoError(t, err)
+		var names []string
+		for _, method := range methods {
+			names = append(names, method.Name)
+		}
+		return names, ordered
+	}
+
+	config := Config{Methods: []string{"^Test"}}
+	names, ordered := getNames(&testSuite{}, config)
+	assert.Equal(t, []string{"TestSubSuite", "TestSubTest", "TestTest"}, names)
+	assert.Equal(t, 0, ordered)
+
+	names, ordered = getNames(&orderedTestSuite{order: []string{"TestInstall", "TestUpgrade", "TestUninstall"}}, config)
+	assert.Equal(t, []string{"TestInstall", "TestUpgrade", "TestUninstall", "TestAudit"}, names)
+	assert.Equal(t, 3, ordered)
+
+	config.Tests = []string{"TestGetTestMethods/TestUpgrade", "TestGetTestMethods/TestUninstall"}
+	names, ordered = getNames(&orderedTestSuite{order: []string{"TestInstall", "TestUpgrade", "TestUninstall"}}, config)
+	assert.Equal(t, []string{"TestUpgrade", "TestUninstall"}, names)
+	assert.Equal(t, 2, ordered)
+
+	names, _ = getNames(&taggedTestSuite{}, Config{Methods: []string{"^Test"}, Tags: []string{"smoke", "!slow"}})
+	assert.Equal(t, []string{"TestFast"}, names)
+	names, _ = getNames(&testSuite{}, Config{Methods: []string{"^Test"}, Tags: []string{"smoke"}})
+	assert.Empty(t, names)
+
+	_, _, err := getTestMethods(t, &orderedTestSuite{order: []string{"TestInstall", "TestDowngrade"}}, config)
+	assert.EqualError(t, err, "orderedTestSuite: TestOrder includes unknown test TestDowngrade")
+	_, _, err = getTestMethods(t, &orderedTestSuite{order: []string{"TestInstall", "TestInstall"}}, config)
+	assert.EqualError(t, err, "orderedTestSuite: TestOrder includes test TestInstall more than once")
+}
+
 func TestSuite(t *testing.T) {
 	config := Config{
 		Namespace: "foo",
@@ -160,3 +245,54 @@ func (t *testSuite) TearDownSuite() {
 type subTestSuite struct {
 	testSuite
 }
+
+type timeoutTestSuite struct {
+	Suite
+}
+
+func (t *timeoutTestSuite) SuiteTimeout() time.Duration {
+	return time.Hour
+}
+
+func (t *timeoutTestSuite) MethodTimeout(method string) time.Duration {
+	if method == "TestFoo" {
+		return time.Second
+	}
+	return 0
+}
+
+type orderedTestSuite struct {
+	Suite
+	order []string
+}
+
+func (t *orderedTestSuite) TestOrder() []string {
+	return t.order
+}
+
+func (t *orderedTestSuite) TestAudit() {}
+
+func (t *orderedTestSuite) TestInstall() {}
+
+func (t *orderedTestSuite) TestUninstall() {}
+
+func (t *orderedTestSuite) TestUpgrade() {}
+
+type taggedTestSuite struct {
+	Suite `tags:"raft, smoke"`
+}
+
+func (t *taggedTestSuite) Tags() []string {
+	return []string{"atomix"}
+}
+
+func (t *taggedTestSuite) MethodTags(method string) []string {
+	if method == "TestSlow" {
+		return []string{"slow"}
+	}
+	return nil
+}
+
+func (t *taggedTestSuite) TestFast() {}
+
+func (t *taggedTestSuite) TestSlow() {}
@@ -7,6 +7,8 @@ package types
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // NewValue creates a new value
@@ -21,6 +23,19 @@ type Value struct {
 	value any
 }
 
+// IsSet returns whether the value is set
+func (v Value) IsSet() bool {
+	return v.value != nil
+}
+
+// Or returns the given default value if the value is not set
+func (v Value) Or(value any) Value {
+	if v.value == nil {
+		return NewValue(value)
+	}
+	return v
+}
+
 // String returns the value as a string
 func (v Value) String() string {
 	if v.value == nil {
@@ -122,3 +137,41 @@ func (v Value) Float64() float64 {
 	}
 	return f
 }
+
+// Duration returns the value as a time.Duration
+func (v Value) Duration() time.Duration {
+	if v.value == nil {
+		return 0
+	}
+	if d, ok := v.value.(time.Duration); ok {
+		return d
+	}
+	d, err := time.ParseDuration(fmt.Sprint(v.value))
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// StringSlice returns the value as a slice of strings
+// String values are split on commas.
+func (v Value) StringSlice() []string {
+	switch value := v.value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return value
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, elem := range value {
+			values = append(values, fmt.Sprint(elem))
+		}
+		return values
+	default:
+		s := fmt.Sprint(value)
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, ",")
+	}
+}
@@ -7,6 +7,7 @@ package types
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestValue(t *testing.T) {
@@ -21,4 +22,17 @@ func TestValue(t *testing.T) {
 	assert.Equal(t, uint64(1), NewValue("1").Uint64())
 	assert.Equal(t, float32(1), NewValue("1.0").Float32())
 	assert.Equal(t, float64(1), NewValue("1.0").Float64())
+	assert.Equal(t, time.Minute, NewValue("1m").Duration())
+	assert.Equal(t, []string{"foo", "bar"}, NewValue("foo,bar").StringSlice())
+	assert.Nil(t, NewValue("").StringSlice())
+	assert.Nil(t, NewValue(nil).StringSlice())
+}
+
+func TestValueDefault(t *testing.T) {
+	assert.False(t, NewValue(nil).IsSet())
+	assert.True(t, NewValue("").IsSet())
+	assert.Equal(t, 10, NewValue(nil).Or(10).Int())
+	assert.Equal(t, 5, NewValue("5").Or(10).Int())
+	assert.Equal(t, time.Second, NewValue(nil).Or(time.Second).Duration())
+	assert.Equal(t, 0, NewValue(nil).Int())
 }